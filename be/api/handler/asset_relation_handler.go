@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/asset_relation"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type AssetRelationHandler struct {
+	service *service.AssetRelationService
+}
+
+func NewAssetRelationHandler(service *service.AssetRelationService) *AssetRelationHandler {
+	return &AssetRelationHandler{service: service}
+}
+
+// AssetRelation godoc
+// @Summary      Create asset relation
+// @Description  Link the asset to another asset (depends-on, connected-to, installed-in)
+// @Tags         AssetRelations
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        relation   body    dto.CreateAssetRelationRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/assets/{id}/relations [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssetRelationHandler) Create(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	assetId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert assetId to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when convert assetId to int64")
+	}
+	var request dto.CreateAssetRelationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	relation, err := h.service.Create(userId, assetId, request.ToAssetId, request.RelationType)
+	if err != nil {
+		log.Error("Happened error when create asset relation. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, utils.ConvertAssetRelationToResponse(relation)))
+}
+
+// AssetRelation godoc
+// @Summary      Delete asset relation
+// @Description  Delete asset relation via id
+// @Tags         AssetRelations
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/asset-relations/{id} [DELETE]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssetRelationHandler) Delete(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	if err := h.service.Delete(userId, id); err != nil {
+		log.Error("Happened error when delete asset relation. Error", err)
+		pkg.PanicExeption(constant.UnknownError, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// AssetRelation godoc
+// @Summary      Get asset graph
+// @Description  Get the neighborhood of an asset up to depth N (default 1, max 5)
+// @Tags         AssetRelations
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        request   query    dto.AssetGraphRequest   false  "depth"
+// @param Authorization header string true "Authorization"
+// @Router       /api/assets/{id}/graph [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssetRelationHandler) GetGraph(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	assetId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert assetId to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when convert assetId to int64")
+	}
+	var request dto.AssetGraphRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	graph, err := h.service.GetGraph(userId, assetId, request.Depth)
+	if err != nil {
+		log.Error("Happened error when get asset graph. Error", err)
+		pkg.PanicExeption(constant.UnknownError, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, graph))
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"BE_Manage_device/config"
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/asset_template"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type AssetTemplateHandler struct {
+	service *service.AssetTemplateService
+}
+
+func NewAssetTemplateHandler(service *service.AssetTemplateService) *AssetTemplateHandler {
+	return &AssetTemplateHandler{service: service}
+}
+
+// AssetTemplate godoc
+// @Summary      Create asset template
+// @Description  Create a reusable asset template for the company
+// @Tags         AssetTemplates
+// @Accept       json
+// @Produce      json
+// @Param        template   body    dto.AssetTemplateRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/asset-templates [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssetTemplateHandler) Create(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.AssetTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	template, err := h.service.Create(userId, request)
+	if err != nil {
+		log.Error("Happened error when create asset template. Error", err.Error())
+		pkg.PanicExeption(constant.UnknownError, "Happened error when create asset template. Error: "+err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, utils.ConvertAssetTemplateToResponse(template)))
+}
+
+// AssetTemplate godoc
+// @Summary      Get all asset templates
+// @Description  Get all asset templates of the company
+// @Tags         AssetTemplates
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/asset-templates [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssetTemplateHandler) GetAll(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	templates, err := h.service.GetAll(userId)
+	if err != nil {
+		log.Error("Happened error when get all asset templates. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get all asset templates")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertAssetTemplatesToResponses(templates)))
+}
+
+// AssetTemplate godoc
+// @Summary      Get asset template
+// @Description  Get asset template via id
+// @Tags         AssetTemplates
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/asset-templates/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssetTemplateHandler) GetById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	template, err := h.service.GetById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get asset template. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertAssetTemplateToResponse(template)))
+}
+
+// AssetTemplate godoc
+// @Summary      Update asset template
+// @Description  Update asset template via id
+// @Tags         AssetTemplates
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        template   body    dto.AssetTemplateRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/asset-templates/{id} [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssetTemplateHandler) Update(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	var request dto.AssetTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	template, err := h.service.Update(userId, id, request)
+	if err != nil {
+		log.Error("Happened error when update asset template. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when update asset template. Error: "+err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertAssetTemplateToResponse(template)))
+}
+
+// AssetTemplate godoc
+// @Summary      Delete asset template
+// @Description  Delete asset template via id
+// @Tags         AssetTemplates
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/asset-templates/{id} [DELETE]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssetTemplateHandler) Delete(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	if err := h.service.Delete(userId, id); err != nil {
+		log.Error("Happened error when delete asset template. Error", err)
+		pkg.PanicExeption(constant.UnknownError, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// AssetTemplate godoc
+// @Summary      Create assets from template
+// @Description  Create one asset per serial number from a template in a single transaction
+// @Tags         AssetTemplates
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        request   body    dto.CreateAssetsFromTemplateRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/asset-templates/{id}/assets [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssetTemplateHandler) CreateAssets(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	var request dto.CreateAssetsFromTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	assets, err := h.service.CreateAssets(userId, id, request.DepartmentId, request.PurchaseDate, request.SerialNumbers, request.RedirectUrl)
+	if err != nil {
+		log.Error("Happened error when create assets from template. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when create assets from template. Error: "+err.Error())
+	}
+	assetsResponse := []dto.AssetResponse{}
+	for _, a := range assets {
+		asset, err := h.service.GetAssetById(userId, a.Id)
+		if err != nil {
+			log.Error("Happened error when get asset by id. Error", err.Error())
+			pkg.PanicExeption(constant.UnknownError, "Happened error when get asset by id")
+		}
+		assetsResponse = append(assetsResponse, utils.ConvertAssetToResponse(*asset))
+	}
+	config.Rdb.Del(config.Ctx, "assets:all")
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, assetsResponse))
+}
@@ -7,11 +7,13 @@ import (
 	"BE_Manage_device/internal/domain/entity"
 	"BE_Manage_device/internal/domain/filter"
 	service "BE_Manage_device/internal/service/asset"
+	reliabilityS "BE_Manage_device/internal/service/reliability"
 
 	"BE_Manage_device/pkg"
 	"BE_Manage_device/pkg/utils"
 	"bytes"
 	"encoding/csv"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -28,11 +30,12 @@ const (
 )
 
 type AssetsHandler struct {
-	service *service.AssetsService
+	service            *service.AssetsService
+	reliabilityService *reliabilityS.ReliabilityService
 }
 
-func NewAssetsHandler(service *service.AssetsService) *AssetsHandler {
-	return &AssetsHandler{service: service}
+func NewAssetsHandler(service *service.AssetsService, reliabilityService *reliabilityS.ReliabilityService) *AssetsHandler {
+	return &AssetsHandler{service: service, reliabilityService: reliabilityService}
 }
 
 // Asset godoc
@@ -168,6 +171,8 @@ func (h *AssetsHandler) Create(c *gin.Context) {
 				LocationName: asset.Department.Location.LocationName,
 			},
 		},
+		Room: utils.ConvertLocationToResponse(asset.Room),
+		Tags: utils.ConvertTagsToResponses(asset.Tags),
 	}
 	if asset.OnwerUser != nil {
 		assetResponse.Owner = dto.OwnerResponse{
@@ -304,6 +309,8 @@ func (h *AssetsHandler) Update(c *gin.Context) {
 				LocationName: asset.Department.Location.LocationName,
 			},
 		},
+		Room: utils.ConvertLocationToResponse(asset.Room),
+		Tags: utils.ConvertTagsToResponses(asset.Tags),
 	}
 	if asset.OnwerUser != nil {
 		assetResponse.Owner = dto.OwnerResponse{
@@ -366,6 +373,8 @@ func (h *AssetsHandler) GetAssetById(c *gin.Context) {
 				LocationName: asset.Department.Location.LocationName,
 			},
 		},
+		Room: utils.ConvertLocationToResponse(asset.Room),
+		Tags: utils.ConvertTagsToResponses(asset.Tags),
 	}
 	if asset.OnwerUser != nil {
 		assetResponse.Owner = dto.OwnerResponse{
@@ -425,6 +434,8 @@ func (h *AssetsHandler) GetAllAsset(c *gin.Context) {
 					LocationName: asset.Department.Location.LocationName,
 				},
 			},
+			Room: utils.ConvertLocationToResponse(asset.Room),
+			Tags: utils.ConvertTagsToResponses(asset.Tags),
 		}
 		if asset.OnwerUser != nil {
 			assetResponse.Owner = dto.OwnerResponse{
@@ -479,7 +490,7 @@ func (h *AssetsHandler) DeleteAsset(c *gin.Context) {
 
 // Asset godoc
 // @Summary Retired assets
-// @Description Retired assets
+// @Description Retired assets, the response warns about assets that still depend on it. Returns 202 with the approval request when an approval workflow is configured
 // @Tags Assets
 // @Accept json
 // @Produce json
@@ -513,11 +524,33 @@ func (h *AssetsHandler) UpdateAssetRetired(c *gin.Context) {
 		pkg.PanicExeption(constant.InvalidRequest, err.Error())
 		return
 	}
-	asset, err := h.service.UpdateAssetRetired(userId, assetId, request.ResidualValue)
+	_, instance, err := h.service.UpdateAssetRetired(userId, assetId, request.ResidualValue)
 	if err != nil {
 		pkg.PanicExeption(constant.UnknownError, "Happened error when retired assets")
 	}
-	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, asset))
+	if instance != nil {
+		c.JSON(http.StatusAccepted, pkg.BuildReponseSuccess(http.StatusAccepted, constant.Success, utils.ConvertWorkflowInstanceToResponse(instance)))
+		return
+	}
+	asset, err := h.service.GetAssetById(userId, assetId)
+	if err != nil {
+		log.Error("Happened error when get asset by id. Error", err.Error())
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get asset by id")
+	}
+	dependents, err := h.service.GetDependentAssets(assetId)
+	if err != nil {
+		log.Error("Happened error when get dependent assets. Error", err.Error())
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get dependent assets")
+	}
+	response := dto.RetireAssetResponse{
+		AssetResponse: utils.ConvertAssetToResponse(*asset),
+		Dependents:    dependents,
+	}
+	if len(dependents) > 0 {
+		response.Warning = fmt.Sprintf("%v asset(s) still depend on this asset", len(dependents))
+	}
+	config.Rdb.Del(config.Ctx, "assets:all")
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, response))
 }
 
 // Asset godoc
@@ -541,7 +574,7 @@ func (h *AssetsHandler) FilterAsset(c *gin.Context) {
 		log.Error("Happened error when mapping query to filter. Error", err)
 		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
 	}
-	data, err := h.service.Filter(userId, filter.AssetName, filter.Status, filter.CategoryId, filter.Cost, filter.SerialNumber, filter.Email, filter.DepartmentId)
+	data, err := h.service.Filter(userId, filter.AssetName, filter.Status, filter.CategoryId, filter.Cost, filter.SerialNumber, filter.Email, filter.DepartmentId, filter.LocationId, filter.Tags, filter.TagMode)
 	if err != nil {
 		log.Error("Happened error when filter asset. Error", err)
 		pkg.PanicExeption(constant.UnknownError, "Happened error when filter asset")
@@ -570,7 +603,7 @@ func (h *AssetsHandler) FilterAssetDashboard(c *gin.Context) {
 		log.Error("Happened error when mapping query to filter. Error", err)
 		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
 	}
-	summary, assets, err := h.service.ApplyFilterDashBoard(userId, filter.CategoryId, filter.DepartmentId, filter.Status, filter.Export)
+	summary, assets, err := h.service.ApplyFilterDashBoard(userId, filter.Status, filter.CategoryId, filter.DepartmentId, filter.LocationId, filter.Tags, filter.TagMode, filter.Export)
 	if err != nil {
 		log.Error("Happened error when filter asset. Error", err)
 		pkg.PanicExeption(constant.UnknownError, "Happened error when filter asset")
@@ -609,6 +642,16 @@ func (h *AssetsHandler) FilterAssetDashboard(c *gin.Context) {
 			}
 		}
 	}
+	assetIds := make([]int64, 0, len(assets))
+	for _, a := range assets {
+		assetIds = append(assetIds, a.Id)
+	}
+	// Lỗi khi tính độ tin cậy không làm hỏng dashboard
+	if reliability, err := h.reliabilityService.GetSummary(userId, assetIds); err != nil {
+		log.Error("Happened error when get reliability summary. Error", err)
+	} else {
+		summary.Reliability = reliability
+	}
 	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, summary))
 }
 
@@ -709,6 +752,8 @@ func (h *AssetsHandler) GetAssetsByCateOfDepartment(c *gin.Context) {
 					LocationName: asset.Department.Location.LocationName,
 				},
 			},
+			Room: utils.ConvertLocationToResponse(asset.Room),
+			Tags: utils.ConvertTagsToResponses(asset.Tags),
 		}
 		if asset.OnwerUser != nil {
 			assetResponse.Owner = dto.OwnerResponse{
@@ -768,6 +813,8 @@ func (h *AssetsHandler) GetAllAssetNotHaveMaintenance(c *gin.Context) {
 					LocationName: asset.Department.Location.LocationName,
 				},
 			},
+			Room: utils.ConvertLocationToResponse(asset.Room),
+			Tags: utils.ConvertTagsToResponses(asset.Tags),
 		}
 		if asset.OnwerUser != nil {
 			assetResponse.Owner = dto.OwnerResponse{
@@ -781,3 +828,137 @@ func (h *AssetsHandler) GetAllAssetNotHaveMaintenance(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, assetsResponse))
 }
+
+// Asset godoc
+// @Summary Clone asset
+// @Description Create copies of an asset with new serial numbers, each copy gets its own QR code, assignment and log
+// @Tags Assets
+// @Accept json
+// @Produce json
+// @Param		id	path		string				true	"id"
+// @Param        request   body    dto.CloneAssetRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router /api/assets/{id}/clone [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssetsHandler) Clone(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	idStr := c.Param("id")
+	assetId, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert assetId to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when convert assetId to int64")
+	}
+	var request dto.CloneAssetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	assetCheck, err := h.service.GetAssetById(userId, assetId)
+	if err != nil {
+		pkg.PanicExeption(constant.DataNotFound, "Can't find asset")
+	}
+	err = h.service.CheckPermissionForManager(userId, assetCheck.DepartmentId)
+	if err != nil {
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+		return
+	}
+	assets, err := h.service.Clone(userId, assetId, request.SerialNumbers, request.RedirectUrl)
+	if err != nil {
+		log.Error("Happened error when clone asset. Error", err.Error())
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when clone asset. Error: "+err.Error())
+	}
+	assetsResponse := []dto.AssetResponse{}
+	for _, a := range assets {
+		asset, err := h.service.GetAssetById(userId, a.Id)
+		if err != nil {
+			log.Error("Happened error when get asset by id. Error", err.Error())
+			pkg.PanicExeption(constant.UnknownError, "Happened error when get asset by id")
+		}
+		assetsResponse = append(assetsResponse, utils.ConvertAssetToResponse(*asset))
+	}
+	config.Rdb.Del(config.Ctx, "assets:all")
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, assetsResponse))
+}
+
+// Asset godoc
+// @Summary Move asset to room
+// @Description Place an asset in a room of the location tree, the move is recorded in the asset log
+// @Tags Assets
+// @Accept json
+// @Produce json
+// @Param		id	path		string				true	"id"
+// @Param        request   body    dto.MoveAssetRoomRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router /api/assets/{id}/room [PATCH]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssetsHandler) MoveRoom(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	idStr := c.Param("id")
+	assetId, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert assetId to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when convert assetId to int64")
+	}
+	var request dto.MoveAssetRoomRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	assetCheck, err := h.service.GetAssetById(userId, assetId)
+	if err != nil {
+		pkg.PanicExeption(constant.DataNotFound, "Can't find asset")
+	}
+	err = h.service.CheckPermissionForManager(userId, assetCheck.DepartmentId)
+	if err != nil {
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+		return
+	}
+	asset, err := h.service.MoveRoom(userId, assetId, request.RoomId)
+	if err != nil {
+		log.Error("Happened error when move asset to room. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	config.Rdb.Del(config.Ctx, "assets:all")
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertAssetToResponse(*asset)))
+}
+
+// Asset godoc
+// @Summary Get dependent assets
+// @Description Get assets that depend on or are installed in this asset, useful before retiring it
+// @Tags Assets
+// @Accept json
+// @Produce json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router /api/assets/{id}/dependents [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssetsHandler) GetDependentAssets(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	idStr := c.Param("id")
+	assetId, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert assetId to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when convert assetId to int64")
+	}
+	if _, err := h.service.GetAssetById(userId, assetId); err != nil {
+		pkg.PanicExeption(constant.DataNotFound, "Can't find asset")
+	}
+	dependents, err := h.service.GetDependentAssets(assetId)
+	if err != nil {
+		log.Error("Happened error when get dependent assets. Error", err.Error())
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get dependent assets")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, dependents))
+}
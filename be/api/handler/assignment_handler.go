@@ -10,6 +10,7 @@ import (
 	"BE_Manage_device/pkg/utils"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
@@ -75,7 +76,7 @@ func (h *AssignmentHandler) Update(c *gin.Context) {
 		pkg.PanicExeption(constant.InvalidRequest, "Request must contain exactly one of userId or departmentId")
 		return
 	}
-	assignmentUpdated, err := h.service.Update(userId, assignmentId, request.UserId, request.DepartmentId)
+	assignmentUpdated, err := h.service.Update(userId, assignmentId, request.UserId, request.DepartmentId, request.Reason)
 	if err != nil {
 		log.Error("Happened error when update assignment. Error", err)
 		pkg.PanicExeption(constant.UnknownError, "Happened error when update assignment.")
@@ -84,6 +85,35 @@ func (h *AssignmentHandler) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, assignResponse))
 }
 
+// Assignment godoc
+// @Summary      Bulk update assignments
+// @Description  Reassign many assets to one user or department in one operation. Assets are chosen by assetIds or filter. All assets are validated first; if any is rejected nothing is changed. Returns the result of each asset
+// @Tags         Assignments
+// @Accept       json
+// @Produce      json
+// @Param        assignment   body    dto.BulkAssignmentRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/assignments/bulk [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssignmentHandler) BulkUpdate(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.BulkAssignmentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	res, err := h.service.BulkUpdate(userId, request)
+	if err != nil {
+		log.Error("Happened error when bulk update assignments. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
 // Assignment godoc
 // @Summary Get all assign with filter
 // @Description Get all assign have permission
@@ -142,3 +172,90 @@ func (h *AssignmentHandler) GetAssignmentById(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, assignment))
 }
+
+// Assignment godoc
+// @Summary Get custody history of asset
+// @Description Who held the asset during [from, to). Use from=D and to=D+1 day to find the holder on date D
+// @Tags Assignment
+// @Accept json
+// @Produce json
+// @Param		id	path		int				true	"asset_id"
+// @Param		from	query		string				false	"RFC3339 time"
+// @Param		to	query		string				false	"RFC3339 time"
+// @param Authorization header string true "Authorization"
+// @Router /api/assignments/history/assets/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssignmentHandler) GetAssetHistories(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	from, to := h.parsePeriod(c)
+	histories, err := h.service.GetAssetHistories(userId, id, from, to)
+	if err != nil {
+		log.Error("Happened error when get custody history of asset. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get custody history of asset")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, histories))
+}
+
+// Assignment godoc
+// @Summary Get custody history of user
+// @Description Which assets the user held during [from, to)
+// @Tags Assignment
+// @Accept json
+// @Produce json
+// @Param		id	path		int				true	"user_id"
+// @Param		from	query		string				false	"RFC3339 time"
+// @Param		to	query		string				false	"RFC3339 time"
+// @param Authorization header string true "Authorization"
+// @Router /api/assignments/history/users/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *AssignmentHandler) GetUserHistories(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	from, to := h.parsePeriod(c)
+	histories, err := h.service.GetUserHistories(userId, id, from, to)
+	if err != nil {
+		log.Error("Happened error when get custody history of user. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get custody history of user")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, histories))
+}
+
+func (h *AssignmentHandler) parseId(c *gin.Context) int64 {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert id to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest)
+	}
+	return id
+}
+
+func (h *AssignmentHandler) parsePeriod(c *gin.Context) (*time.Time, *time.Time) {
+	from := h.parseTimeQuery(c, "from")
+	to := h.parseTimeQuery(c, "to")
+	if from != nil && to != nil && !from.Before(*to) {
+		pkg.PanicExeption(constant.InvalidRequest, "from must be before to")
+	}
+	return from, to
+}
+
+func (h *AssignmentHandler) parseTimeQuery(c *gin.Context, key string) *time.Time {
+	value := c.Query(key)
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Error("Happened error when parse time. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Time must be in RFC3339 format")
+	}
+	return &t
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/calendar_feeds"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type CalendarFeedHandler struct {
+	service *service.CalendarFeedService
+}
+
+func NewCalendarFeedHandler(service *service.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{service: service}
+}
+
+// CalendarFeed godoc
+// @Summary      Create calendar feed
+// @Description  Create a personal (no departmentId) or department ICS feed. The returned url contains the secret token and is only shown once
+// @Tags         CalendarFeeds
+// @Accept       json
+// @Produce      json
+// @Param        feed   body    dto.CreateCalendarFeedRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/calendar-feeds [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *CalendarFeedHandler) Create(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.CreateCalendarFeedRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	res, err := h.service.Create(userId, request)
+	if err != nil {
+		log.Error("Happened error when create calendar feed. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, res))
+}
+
+// CalendarFeed godoc
+// @Summary      Get calendar feeds
+// @Description  Get own calendar feeds (admin gets all feeds of the company)
+// @Tags         CalendarFeeds
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/calendar-feeds [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *CalendarFeedHandler) GetAll(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	feeds, err := h.service.GetAll(userId)
+	if err != nil {
+		log.Error("Happened error when get calendar feeds. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get calendar feeds")
+	}
+	res := []dto.CalendarFeedResponse{}
+	for _, feed := range feeds {
+		res = append(res, utils.ConvertCalendarFeedToResponse(feed))
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// CalendarFeed godoc
+// @Summary      Revoke calendar feed
+// @Description  Revoke a calendar feed, its url stops working immediately
+// @Tags         CalendarFeeds
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/calendar-feeds/{id} [DELETE]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *CalendarFeedHandler) Revoke(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	if err := h.service.Revoke(userId, id); err != nil {
+		log.Error("Happened error when revoke calendar feed. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// CalendarFeed godoc
+// @Summary      Get ICS feed
+// @Description  iCalendar feed of upcoming maintenance schedules and warranty expiries, authenticated by the token in the url. Each response is a full replacement of the calendar: deleted schedules are simply left out
+// @Tags         CalendarFeeds
+// @Produce      text/calendar
+// @Param		token	path		string				true	"token"
+// @Router       /api/calendar-feeds/ics/{token} [GET]
+func (h *CalendarFeedHandler) GetICS(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	data, err := h.service.Render(token)
+	if err != nil {
+		log.Error("Happened error when render calendar feed. Error", err)
+		c.String(http.StatusNotFound, "calendar feed not found")
+		return
+	}
+	c.Header("Content-Disposition", "inline; filename=calendar.ics")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", data)
+}
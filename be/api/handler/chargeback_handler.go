@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/chargeback"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type ChargebackHandler struct {
+	service *service.ChargebackService
+}
+
+func NewChargebackHandler(service *service.ChargebackService) *ChargebackHandler {
+	return &ChargebackHandler{service: service}
+}
+
+var chargebackExportHeader = []string{"Department ID", "Department", "Asset ID", "Asset", "Serial Number", "Days Held", "Depreciation", "Maintenance", "Total"}
+
+// Chargeback godoc
+// @Summary      Compute department chargeback
+// @Description  Allocate depreciation and maintenance costs of the period to departments, prorated by days held. Recomputing a period replaces the stored result
+// @Tags         Chargebacks
+// @Accept       json
+// @Produce      json
+// @Param        request   body    dto.ComputeChargebackRequest   true  "Period"
+// @param Authorization header string true "Authorization"
+// @Router       /api/chargebacks/compute [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ChargebackHandler) Compute(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.ComputeChargebackRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	res, err := h.service.Compute(userId, request.Month, request.Year)
+	if err != nil {
+		log.Error("Happened error when compute chargeback. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// Chargeback godoc
+// @Summary      Get department chargeback of a period
+// @Description  Get the stored chargeback summary per department
+// @Tags         Chargebacks
+// @Accept       json
+// @Produce      json
+// @Param        request   query    dto.ChargebackFilterRequest   true  "Period"
+// @param Authorization header string true "Authorization"
+// @Router       /api/chargebacks [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ChargebackHandler) GetByPeriod(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.ChargebackFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	res, err := h.service.GetByPeriod(userId, request.Month, request.Year, request.DepartmentId)
+	if err != nil {
+		log.Error("Happened error when get chargeback. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get chargeback")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// Chargeback godoc
+// @Summary      Get chargeback lines of a period
+// @Description  Get the stored chargeback detail per asset and department
+// @Tags         Chargebacks
+// @Accept       json
+// @Produce      json
+// @Param        request   query    dto.ChargebackFilterRequest   true  "Period"
+// @param Authorization header string true "Authorization"
+// @Router       /api/chargebacks/lines [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ChargebackHandler) GetLines(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.ChargebackFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	res, err := h.service.GetLines(userId, request.Month, request.Year, request.DepartmentId)
+	if err != nil {
+		log.Error("Happened error when get chargeback lines. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get chargeback lines")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// Chargeback godoc
+// @Summary      Export chargeback of a period
+// @Description  Export chargeback lines as CSV (default) or XLSX
+// @Tags         Chargebacks
+// @Accept       json
+// @Produce      octet-stream
+// @Param        request   query    dto.ChargebackFilterRequest   true  "Period and format"
+// @param Authorization header string true "Authorization"
+// @Router       /api/chargebacks/export [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ChargebackHandler) Export(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.ChargebackFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	lines, err := h.service.GetLinesForExport(userId, request.Month, request.Year, request.DepartmentId)
+	if err != nil {
+		log.Error("Happened error when export chargeback. Error", err)
+		pkg.PanicExeption(constant.StatusForbidden, err.Error())
+	}
+	fileName := fmt.Sprintf("chargeback-%d-%02d", request.Year, request.Month)
+	if request.Format == "xlsx" {
+		data, err := GenerateChargebackXLSX(lines)
+		if err != nil {
+			log.Error("Happened error when generate xlsx. Error", err)
+			pkg.PanicExeption(constant.UnknownError, "Happened error when generate xlsx")
+		}
+		c.Header("Content-Disposition", "attachment; filename="+fileName+".xlsx")
+		c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", data)
+		return
+	}
+	data, err := GenerateChargebackCSV(lines)
+	if err != nil {
+		log.Error("Happened error when generate csv. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when generate csv")
+	}
+	c.Header("Content-Disposition", "attachment; filename="+fileName+".csv")
+	c.Data(http.StatusOK, "text/csv", data)
+}
+
+func GenerateChargebackCSV(lines []*dto.ChargebackLineResponse) ([]byte, error) {
+	var b bytes.Buffer
+	writer := csv.NewWriter(&b)
+	writer.Write(chargebackExportHeader)
+	for _, l := range lines {
+		writer.Write([]string{
+			strconv.FormatInt(l.DepartmentId, 10), l.DepartmentName,
+			strconv.FormatInt(l.AssetId, 10), l.AssetName, l.SerialNumber,
+			strconv.FormatFloat(l.DaysHeld, 'f', 2, 64),
+			strconv.FormatFloat(l.DepreciationAmount, 'f', 2, 64),
+			strconv.FormatFloat(l.MaintenanceAmount, 'f', 2, 64),
+			strconv.FormatFloat(l.TotalAmount, 'f', 2, 64),
+		})
+	}
+	writer.Flush()
+	return b.Bytes(), writer.Error()
+}
+
+func GenerateChargebackXLSX(lines []*dto.ChargebackLineResponse) ([]byte, error) {
+	rows := make([][]interface{}, 0, len(lines))
+	for _, l := range lines {
+		rows = append(rows, []interface{}{
+			l.DepartmentId, l.DepartmentName, l.AssetId, l.AssetName, l.SerialNumber,
+			l.DaysHeld, l.DepreciationAmount, l.MaintenanceAmount, l.TotalAmount,
+		})
+	}
+	return utils.GenerateXLSX("Chargeback", chargebackExportHeader, rows)
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"BE_Manage_device/config"
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/department_restructures"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type DepartmentRestructureHandler struct {
+	service *service.DepartmentRestructureService
+}
+
+func NewDepartmentRestructureHandler(service *service.DepartmentRestructureService) *DepartmentRestructureHandler {
+	return &DepartmentRestructureHandler{service: service}
+}
+
+// DepartmentRestructure godoc
+// @Summary      Create department restructure
+// @Description  Create a draft merge, split or rename of a department and preview the users, assets, assignments and pending requests that will be moved
+// @Tags         Departments
+// @Accept       json
+// @Produce      json
+// @Param        restructure   body    dto.CreateDepartmentRestructureRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/departments/restructures [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *DepartmentRestructureHandler) Create(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.CreateDepartmentRestructureRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	restructure, err := h.service.Create(userId, request)
+	if err != nil {
+		log.Error("Happened error when create department restructure. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, restructure))
+}
+
+// DepartmentRestructure godoc
+// @Summary      Get department restructures
+// @Description  Get department restructures of the company
+// @Tags         Departments
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/departments/restructures [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *DepartmentRestructureHandler) GetAll(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	restructures, err := h.service.GetAll(userId)
+	if err != nil {
+		log.Error("Happened error when get department restructures. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get department restructures")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, restructures))
+}
+
+// DepartmentRestructure godoc
+// @Summary      Get department restructure
+// @Description  Get department restructure. A draft returns the preview computed from current data, an applied one returns the moved records
+// @Tags         Departments
+// @Accept       json
+// @Produce      json
+// @Param		id	path		int				true	"restructure_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/departments/restructures/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *DepartmentRestructureHandler) GetById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	restructure, err := h.service.GetById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get department restructure. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get department restructure")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, restructure))
+}
+
+// DepartmentRestructure godoc
+// @Summary      Apply department restructure
+// @Description  Apply a draft restructure in one transaction with asset logs. It can be undone until undoDeadline
+// @Tags         Departments
+// @Accept       json
+// @Produce      json
+// @Param		id	path		int				true	"restructure_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/departments/restructures/{id}/apply [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *DepartmentRestructureHandler) Apply(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	restructure, err := h.service.Apply(userId, id)
+	if err != nil {
+		log.Error("Happened error when apply department restructure. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	h.clearDepartmentCache(userId)
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, restructure))
+}
+
+// DepartmentRestructure godoc
+// @Summary      Undo department restructure
+// @Description  Move back the records that haven't changed since the restructure was applied
+// @Tags         Departments
+// @Accept       json
+// @Produce      json
+// @Param		id	path		int				true	"restructure_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/departments/restructures/{id}/undo [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *DepartmentRestructureHandler) Undo(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	restructure, err := h.service.Undo(userId, id)
+	if err != nil {
+		log.Error("Happened error when undo department restructure. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	h.clearDepartmentCache(userId)
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, restructure))
+}
+
+// clearDepartmentCache xoá cache danh sách phòng ban của công ty
+func (h *DepartmentRestructureHandler) clearDepartmentCache(userId int64) {
+	companyId, err := h.service.GetCompanyId(userId)
+	if err != nil {
+		log.Error("Happened error when get company id. Error: ", err.Error())
+		return
+	}
+	config.Rdb.Del(config.Ctx, fmt.Sprintf("%v:%v", cacheKeyDepartment, companyId))
+}
+
+func (h *DepartmentRestructureHandler) parseId(c *gin.Context, key string) int64 {
+	id, err := strconv.ParseInt(c.Param(key), 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert id to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest)
+	}
+	return id
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/handovers"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type HandoverHandler struct {
+	service *service.HandoverService
+}
+
+func NewHandoverHandler(service *service.HandoverService) *HandoverHandler {
+	return &HandoverHandler{service: service}
+}
+
+// Handover godoc
+// @Summary      Get my handovers
+// @Description  Get asset handovers assigned to the current user
+// @Tags         Handovers
+// @Accept       json
+// @Produce      json
+// @Param		status	query		string				false	"Pending, Accepted, Superseded"
+// @param Authorization header string true "Authorization"
+// @Router       /api/handovers/mine [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *HandoverHandler) GetMine(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var status *string
+	if value := c.Query("status"); value != "" {
+		status = &value
+	}
+	handovers, err := h.service.GetMine(userId, status)
+	if err != nil {
+		log.Error("Happened error when get handovers. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get handovers")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, handovers))
+}
+
+// Handover godoc
+// @Summary      Get handovers
+// @Description  Get asset handovers of the company
+// @Tags         Handovers
+// @Accept       json
+// @Produce      json
+// @Param		status	query		string				false	"Pending, Accepted, Superseded"
+// @Param		assetId	query		int				false	"asset_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/handovers [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *HandoverHandler) GetAll(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.HandoverFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	handovers, err := h.service.Filter(userId, request)
+	if err != nil {
+		log.Error("Happened error when get handovers. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get handovers")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, handovers))
+}
+
+// Handover godoc
+// @Summary      Get handover
+// @Description  Get asset handover, visible to the recipient and users who can assign assets
+// @Tags         Handovers
+// @Accept       json
+// @Produce      json
+// @Param		id	path		int				true	"handover_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/handovers/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *HandoverHandler) GetById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	handover, err := h.service.GetById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get handover. Error", err)
+		pkg.PanicExeption(constant.UnknownError, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, handover))
+}
+
+// Handover godoc
+// @Summary      Accept handover
+// @Description  Recipient acknowledges receipt of the asset with a drawn signature and its condition. A signed PDF receipt is generated and linked to the assignment
+// @Tags         Handovers
+// @Accept       json
+// @Produce      json
+// @Param        handover   body    dto.AcceptHandoverRequest   true  "Data"
+// @Param		id	path		int				true	"handover_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/handovers/{id}/accept [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *HandoverHandler) Accept(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	var request dto.AcceptHandoverRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	handover, err := h.service.Accept(userId, id, c.ClientIP(), request)
+	if err != nil {
+		log.Error("Happened error when accept handover. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, handover))
+}
+
+func (h *HandoverHandler) parseId(c *gin.Context, key string) int64 {
+	id, err := strconv.ParseInt(c.Param(key), 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert id to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest)
+	}
+	return id
+}
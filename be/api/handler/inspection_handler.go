@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/inspections"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type InspectionHandler struct {
+	service *service.InspectionService
+}
+
+func NewInspectionHandler(service *service.InspectionService) *InspectionHandler {
+	return &InspectionHandler{service: service}
+}
+
+// InspectionTemplate godoc
+// @Summary      Create inspection template
+// @Description  Create a pass/fail checklist template for a category with its inspection interval
+// @Tags         Inspections
+// @Accept       json
+// @Produce      json
+// @Param        template   body    dto.InspectionTemplateRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/inspection-templates [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *InspectionHandler) CreateTemplate(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.InspectionTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	template, err := h.service.CreateTemplate(userId, request)
+	if err != nil {
+		log.Error("Happened error when create inspection template. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, utils.ConvertInspectionTemplateToResponse(template)))
+}
+
+// InspectionTemplate godoc
+// @Summary      Get inspection templates
+// @Description  Get inspection templates of the company
+// @Tags         Inspections
+// @Accept       json
+// @Produce      json
+// @Param        filter   query    dto.InspectionTemplateFilterRequest   false  "Filter"
+// @param Authorization header string true "Authorization"
+// @Router       /api/inspection-templates [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *InspectionHandler) GetTemplates(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.InspectionTemplateFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	templates, err := h.service.GetTemplates(userId, request.CategoryId)
+	if err != nil {
+		log.Error("Happened error when get inspection templates. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get inspection templates")
+	}
+	res := []dto.InspectionTemplateResponse{}
+	for _, template := range templates {
+		res = append(res, utils.ConvertInspectionTemplateToResponse(template))
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// InspectionTemplate godoc
+// @Summary      Get inspection template
+// @Description  Get inspection template by id
+// @Tags         Inspections
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/inspection-templates/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *InspectionHandler) GetTemplateById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	template, err := h.service.GetTemplateById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get inspection template. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertInspectionTemplateToResponse(template)))
+}
+
+// InspectionTemplate godoc
+// @Summary      Update inspection template
+// @Description  Update inspection template and replace its items, past inspections keep their results
+// @Tags         Inspections
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        template   body    dto.InspectionTemplateRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/inspection-templates/{id} [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *InspectionHandler) UpdateTemplate(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	var request dto.InspectionTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	template, err := h.service.UpdateTemplate(userId, id, request)
+	if err != nil {
+		log.Error("Happened error when update inspection template. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertInspectionTemplateToResponse(template)))
+}
+
+// InspectionTemplate godoc
+// @Summary      Delete inspection template
+// @Description  Delete inspection template. Templates already used by inspections are deactivated instead
+// @Tags         Inspections
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/inspection-templates/{id} [DELETE]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *InspectionHandler) DeleteTemplate(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	if err := h.service.DeleteTemplate(userId, id); err != nil {
+		log.Error("Happened error when delete inspection template. Error", err)
+		pkg.PanicExeption(constant.UnknownError, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// Inspection godoc
+// @Summary      Record inspection
+// @Description  Record an inspection of the asset with item results, condition grade (A-E) and signatures. A failed item creates a maintenance schedule from tomorrow and notifies the asset manager
+// @Tags         Inspections
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"asset id"
+// @Param        inspection   body    dto.CreateInspectionRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/assets/{id}/inspections [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *InspectionHandler) Create(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	assetId := h.parseId(c)
+	var request dto.CreateInspectionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	inspection, err := h.service.Create(userId, assetId, request)
+	if err != nil {
+		log.Error("Happened error when create inspection. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, utils.ConvertInspectionToResponse(inspection)))
+}
+
+// Inspection godoc
+// @Summary      Get inspections of asset
+// @Description  Get inspection history of the asset, newest first
+// @Tags         Inspections
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"asset id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/assets/{id}/inspections [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *InspectionHandler) GetByAsset(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	assetId := h.parseId(c)
+	inspections, err := h.service.GetByAsset(userId, assetId)
+	if err != nil {
+		log.Error("Happened error when get inspections. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	res := []dto.InspectionResponse{}
+	for _, inspection := range inspections {
+		res = append(res, utils.ConvertInspectionToResponse(inspection))
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// Inspection godoc
+// @Summary      Get inspection
+// @Description  Get inspection by id with item results
+// @Tags         Inspections
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/inspections/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *InspectionHandler) GetById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	inspection, err := h.service.GetById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get inspection. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertInspectionToResponse(inspection)))
+}
+
+// Inspection godoc
+// @Summary      Get assets overdue for inspection
+// @Description  Assets whose last inspection (or acquisition when never inspected) is older than the template interval
+// @Tags         Inspections
+// @Accept       json
+// @Produce      json
+// @Param        filter   query    dto.InspectionOverdueRequest   false  "Filter"
+// @param Authorization header string true "Authorization"
+// @Router       /api/inspections/overdue [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *InspectionHandler) GetOverdue(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.InspectionOverdueRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	res, err := h.service.GetOverdue(userId, request)
+	if err != nil {
+		log.Error("Happened error when get overdue inspections. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get overdue inspections")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+func (h *InspectionHandler) parseId(c *gin.Context) int64 {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	return id
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	service "BE_Manage_device/internal/service/issue_tickets"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type IssueTicketHandler struct {
+	service *service.IssueTicketService
+}
+
+func NewIssueTicketHandler(service *service.IssueTicketService) *IssueTicketHandler {
+	return &IssueTicketHandler{service: service}
+}
+
+// IssueTicket godoc
+// @Summary      Report issue on asset
+// @Description  The asset holder or anyone in the company who scanned the asset QR code opens an issue ticket. The department's asset manager is notified
+// @Tags         IssueTickets
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param		id	path		string				true	"asset id"
+// @Param        title formData string true "Title"
+// @Param        description formData string false "Description"
+// @Param        severity formData string false "low, medium, high or critical"
+// @Param        photos formData file false "Photos"
+// @param Authorization header string true "Authorization"
+// @Router       /api/assets/{id}/issue-tickets [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *IssueTicketHandler) Create(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	assetId := h.parseId(c)
+	var request dto.CreateIssueTicketRequest
+	if err := c.ShouldBind(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	var photos []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		photos = form.File["photos"]
+	}
+	ticket, err := h.service.Create(userId, assetId, request, photos)
+	if err != nil {
+		log.Error("Happened error when create issue ticket. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, utils.ConvertIssueTicketToResponse(ticket)))
+}
+
+// IssueTicket godoc
+// @Summary      Filter issue tickets
+// @Description  Managers see tickets of the company (or their department), other users see tickets they reported or on assets they hold
+// @Tags         IssueTickets
+// @Accept       json
+// @Produce      json
+// @Param        filter   query    dto.IssueTicketFilterRequest   false  "Filter"
+// @param Authorization header string true "Authorization"
+// @Router       /api/issue-tickets [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *IssueTicketHandler) Filter(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.IssueTicketFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	tickets, err := h.service.Filter(userId, request)
+	if err != nil {
+		log.Error("Happened error when filter issue tickets. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when filter issue tickets")
+	}
+	res := []dto.IssueTicketResponse{}
+	for _, ticket := range tickets {
+		res = append(res, utils.ConvertIssueTicketToResponse(ticket))
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// IssueTicket godoc
+// @Summary      Get issue ticket
+// @Description  Get issue ticket by id with photos and comments
+// @Tags         IssueTickets
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/issue-tickets/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *IssueTicketHandler) GetById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	ticket, err := h.service.GetById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get issue ticket. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	h.respond(c, ticket)
+}
+
+// IssueTicket godoc
+// @Summary      Comment on issue ticket
+// @Description  Add a comment to the issue ticket
+// @Tags         IssueTickets
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        comment   body    dto.CreateIssueTicketCommentRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/issue-tickets/{id}/comments [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *IssueTicketHandler) AddComment(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	var request dto.CreateIssueTicketCommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	ticket, err := h.service.AddComment(userId, id, request.Content)
+	if err != nil {
+		log.Error("Happened error when comment on issue ticket. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	h.respond(c, ticket)
+}
+
+// IssueTicket godoc
+// @Summary      Triage issue ticket
+// @Description  Change severity, assignee or status (triaged, resolved, rejected, closed) of the issue ticket
+// @Tags         IssueTickets
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        triage   body    dto.TriageIssueTicketRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/issue-tickets/{id}/triage [PATCH]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *IssueTicketHandler) Triage(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	var request dto.TriageIssueTicketRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	ticket, err := h.service.Triage(userId, id, request)
+	if err != nil {
+		log.Error("Happened error when triage issue ticket. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	h.respond(c, ticket)
+}
+
+// IssueTicket godoc
+// @Summary      Convert issue ticket to maintenance
+// @Description  Create a maintenance schedule from the issue ticket. If it starts today the asset moves to "Under Maintenance" immediately
+// @Tags         IssueTickets
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        schedule   body    dto.ConvertIssueTicketRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/issue-tickets/{id}/convert [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *IssueTicketHandler) Convert(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	var request dto.ConvertIssueTicketRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	ticket, err := h.service.Convert(userId, id, request)
+	if err != nil {
+		log.Error("Happened error when convert issue ticket. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	h.respond(c, ticket)
+}
+
+func (h *IssueTicketHandler) respond(c *gin.Context, ticket *entity.IssueTicket) {
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertIssueTicketToResponse(ticket)))
+}
+
+func (h *IssueTicketHandler) parseId(c *gin.Context) int64 {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	return id
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/ldap"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type LdapHandler struct {
+	service *service.LdapService
+}
+
+func NewLdapHandler(service *service.LdapService) *LdapHandler {
+	return &LdapHandler{service: service}
+}
+
+// Ldap godoc
+// @Summary      Get directory configuration
+// @Description  Get LDAP/Active Directory configuration of the company. The bind password is never returned
+// @Tags         Ldap
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/company/ldap [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *LdapHandler) GetConfig(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	config, err := h.service.GetConfig(userId)
+	if err != nil {
+		log.Error("Happened error when get directory configuration. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get directory configuration")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, config))
+}
+
+// Ldap godoc
+// @Summary      Save directory configuration
+// @Description  Create or update LDAP configuration: connection, filters, attribute maps, group to role mapping, scheduled sync and bind login. The connection is tested when sync or login is enabled
+// @Tags         Ldap
+// @Accept       json
+// @Produce      json
+// @Param        ldap   body    dto.LdapConfigRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/company/ldap [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *LdapHandler) SaveConfig(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.LdapConfigRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	config, err := h.service.SaveConfig(userId, request, c.ClientIP())
+	if err != nil {
+		log.Error("Happened error when save directory configuration. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, config))
+}
+
+// Ldap godoc
+// @Summary      Synchronize directory
+// @Description  Start a directory sync in background. Follow the returned report until its status is no longer Running
+// @Tags         Ldap
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/company/ldap/sync [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *LdapHandler) Sync(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	report, err := h.service.Sync(userId)
+	if err != nil {
+		log.Error("Happened error when synchronize directory. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusAccepted, pkg.BuildReponseSuccess(http.StatusAccepted, constant.Success, report))
+}
+
+// Ldap godoc
+// @Summary      Get sync reports
+// @Description  Get the latest directory sync reports of the company
+// @Tags         Ldap
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/company/ldap/sync-reports [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *LdapHandler) GetReports(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	reports, err := h.service.GetReports(userId)
+	if err != nil {
+		log.Error("Happened error when get sync reports. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get sync reports")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, reports))
+}
+
+// Ldap godoc
+// @Summary      Get sync report
+// @Description  Get a directory sync report with errors of skipped entries
+// @Tags         Ldap
+// @Accept       json
+// @Produce      json
+// @Param		id	path		int				true	"report_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/company/ldap/sync-reports/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *LdapHandler) GetReportById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert id to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest)
+	}
+	report, err := h.service.GetReportById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get sync report. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, report))
+}
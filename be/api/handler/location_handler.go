@@ -4,6 +4,7 @@ import (
 	"BE_Manage_device/constant"
 	"BE_Manage_device/internal/domain/dto"
 	service "BE_Manage_device/internal/service/location"
+	"BE_Manage_device/pkg/utils"
 
 	"BE_Manage_device/pkg"
 	"net/http"
@@ -36,22 +37,23 @@ func NewLocationHandler(service *service.LocationService) *LocationHandler {
 // @Security JWT
 func (h *LocationHandler) Create(c *gin.Context) {
 	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
 	var request dto.CreateLocationRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		log.Error("Happened error when mapping request from FE. Error", err)
 		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
 	}
-	location, err := h.service.Create(request.LocationName)
+	location, err := h.service.Create(userId, request.LocationName, request.LocationType, request.ParentId, request.Latitude, request.Longitude)
 	if err != nil {
 		log.Error("Happened error when create location. Error", err)
-		pkg.PanicExeption(constant.UnknownError)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when create location. Error: "+err.Error())
 	}
 	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, location))
 }
 
 // User godoc
 // @Summary      Get all location
-// @Description  Get all location
+// @Description  Get all location of the company
 // @Tags         Locations
 // @Accept       json
 // @Produce      json
@@ -63,7 +65,8 @@ func (h *LocationHandler) Create(c *gin.Context) {
 // @Security JWT
 func (h *LocationHandler) GetAll(c *gin.Context) {
 	defer pkg.PanicHandler(c)
-	locations, err := h.service.GetAll()
+	userId := utils.GetUserIdFromContext(c)
+	locations, err := h.service.GetAll(userId)
 	if err != nil {
 		log.Error("Happened error when get all location. Error", err)
 		pkg.PanicExeption(constant.UnknownError, "Happened error when get all location")
@@ -86,6 +89,7 @@ func (h *LocationHandler) GetAll(c *gin.Context) {
 // @Security JWT
 func (h *LocationHandler) Delete(c *gin.Context) {
 	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
 	id := c.Param("id")
 	IdConvert, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
@@ -93,10 +97,97 @@ func (h *LocationHandler) Delete(c *gin.Context) {
 		pkg.PanicExeption(constant.UnknownError, "Happened error when get id via path")
 	}
 
-	err = h.service.Delete(IdConvert)
+	err = h.service.Delete(userId, IdConvert)
 	if err != nil {
 		log.Error("Happened error when delete location. Error", err)
 		pkg.PanicExeption(constant.UnknownError, err.Error())
 	}
 	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
 }
+
+// User godoc
+// @Summary      Get location tree
+// @Description  Get location tree (site > building > floor > room) of the company
+// @Tags         Locations
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/locations/tree [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *LocationHandler) GetTree(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	tree, err := h.service.GetTree(userId)
+	if err != nil {
+		log.Error("Happened error when get location tree. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get location tree")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, tree))
+}
+
+// User godoc
+// @Summary      Update location
+// @Description  Update location name and coordinates via id
+// @Tags         Locations
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        location   body    dto.UpdateLocationRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/locations/{id} [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *LocationHandler) Update(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	var request dto.UpdateLocationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	location, err := h.service.Update(userId, id, request.LocationName, request.Latitude, request.Longitude)
+	if err != nil {
+		log.Error("Happened error when update location. Error", err)
+		pkg.PanicExeption(constant.UnknownError, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, location))
+}
+
+// User godoc
+// @Summary      Get dashboard by location level
+// @Description  Count assets for every location of a level (site, building, floor, room), including its sub-locations
+// @Tags         Locations
+// @Accept       json
+// @Produce      json
+// @Param        request   query    dto.LocationDashboardRequest   true  "level"
+// @param Authorization header string true "Authorization"
+// @Router       /api/locations/dashboard [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *LocationHandler) Dashboard(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.LocationDashboardRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	summaries, err := h.service.CountAssetsByLevel(userId, request.LocationType)
+	if err != nil {
+		log.Error("Happened error when get dashboard by location. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, summaries))
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/maintenance_plans"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type MaintenancePlanHandler struct {
+	service *service.MaintenancePlanService
+}
+
+func NewMaintenancePlanHandler(service *service.MaintenancePlanService) *MaintenancePlanHandler {
+	return &MaintenancePlanHandler{service: service}
+}
+
+// MaintenancePlan godoc
+// @Summary      Create maintenance plan
+// @Description  Create a recurring maintenance plan for an asset or a category. Recurrence is an RRULE subset (FREQ, INTERVAL, COUNT, UNTIL) or intervalValue + intervalUnit
+// @Tags         MaintenancePlans
+// @Accept       json
+// @Produce      json
+// @Param        plan   body    dto.MaintenancePlanRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/maintenance-plans [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MaintenancePlanHandler) Create(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.MaintenancePlanRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	plan, err := h.service.Create(userId, request)
+	if err != nil {
+		log.Error("Happened error when create maintenance plan. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, utils.ConvertMaintenancePlanToResponse(plan)))
+}
+
+// MaintenancePlan godoc
+// @Summary      Get all maintenance plans
+// @Description  Get all maintenance plans of the company
+// @Tags         MaintenancePlans
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/maintenance-plans [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MaintenancePlanHandler) GetAll(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	plans, err := h.service.GetAll(userId)
+	if err != nil {
+		log.Error("Happened error when get maintenance plans. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get maintenance plans")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertMaintenancePlansToResponses(plans)))
+}
+
+// MaintenancePlan godoc
+// @Summary      Get maintenance plan
+// @Description  Get maintenance plan by id with its next occurrences
+// @Tags         MaintenancePlans
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/maintenance-plans/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MaintenancePlanHandler) GetById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	plan, err := h.service.GetById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get maintenance plan. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertMaintenancePlanToResponse(plan)))
+}
+
+// MaintenancePlan godoc
+// @Summary      Update maintenance plan
+// @Description  Update maintenance plan, future occurrences (from tomorrow) are regenerated, past ones are kept
+// @Tags         MaintenancePlans
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        plan   body    dto.MaintenancePlanRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/maintenance-plans/{id} [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MaintenancePlanHandler) Update(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	var request dto.MaintenancePlanRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	plan, err := h.service.Update(userId, id, request)
+	if err != nil {
+		log.Error("Happened error when update maintenance plan. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertMaintenancePlanToResponse(plan)))
+}
+
+// MaintenancePlan godoc
+// @Summary      Delete maintenance plan
+// @Description  Delete maintenance plan and its future occurrences
+// @Tags         MaintenancePlans
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/maintenance-plans/{id} [DELETE]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MaintenancePlanHandler) Delete(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	if err := h.service.Delete(userId, id); err != nil {
+		log.Error("Happened error when delete maintenance plan. Error", err)
+		pkg.PanicExeption(constant.UnknownError, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// MaintenancePlan godoc
+// @Summary      Generate maintenance schedules
+// @Description  Materialize upcoming maintenance schedules of the plan now instead of waiting for the cron job
+// @Tags         MaintenancePlans
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/maintenance-plans/{id}/generate [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MaintenancePlanHandler) Generate(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	res, err := h.service.Generate(userId, id)
+	if err != nil {
+		log.Error("Happened error when generate maintenance schedules. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+func (h *MaintenancePlanHandler) parseId(c *gin.Context) int64 {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	return id
+}
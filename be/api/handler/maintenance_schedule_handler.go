@@ -25,7 +25,7 @@ func NewMaintenanceSchedulesHandler(service *service.MaintenanceSchedulesService
 
 // Maintenance Schedules godoc
 // @Summary      Create maintenanceSchedules
-// @Description  Create maintenanceSchedules
+// @Description  Create maintenanceSchedules. Returns 202 with the approval request when an approval workflow is configured
 // @Tags         MaintenanceSchedules
 // @Accept       json
 // @Produce      json
@@ -53,11 +53,15 @@ func (h *MaintenanceSchedulesHandler) Create(c *gin.Context) {
 		log.Error("Happened error start date >= end date .")
 		pkg.PanicExeption(constant.InvalidRequest, "Happened error start date > end date.")
 	}
-	maintenance, err := h.service.Create(userId, request.AssetId, request.StartDate, request.EndDate)
+	maintenance, instance, err := h.service.Create(userId, request.AssetId, request.StartDate, request.EndDate, request.Cost)
 	if err != nil {
 		log.Error("Happened error when create maintenance. Error", err)
 		pkg.PanicExeption(constant.UnknownError, "Happened error when create maintenance.")
 	}
+	if instance != nil {
+		c.JSON(http.StatusAccepted, pkg.BuildReponseSuccess(http.StatusAccepted, constant.Success, utils.ConvertWorkflowInstanceToResponse(instance)))
+		return
+	}
 	MaintenanceScheduleRes := utils.ConvertMaintenanceSchedulesToResponses(maintenance)
 	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, MaintenanceScheduleRes))
 }
@@ -129,7 +133,7 @@ func (h *MaintenanceSchedulesHandler) Update(c *gin.Context) {
 		log.Error("End date must be after start date.")
 		pkg.PanicExeption(constant.InvalidRequest, "End date must be after start date.")
 	}
-	maintenance, err := h.service.Update(userId, id, request.StartDate, request.EndDate)
+	maintenance, err := h.service.Update(userId, id, request.StartDate, request.EndDate, request.Cost)
 	if err != nil {
 		log.Error("Happened error when create maintenance. Error", err)
 		pkg.PanicExeption(constant.UnknownError, "Happened error when update maintenance.")
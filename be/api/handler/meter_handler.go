@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/meters"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type MeterHandler struct {
+	service *service.MeterService
+}
+
+func NewMeterHandler(service *service.MeterService) *MeterHandler {
+	return &MeterHandler{service: service}
+}
+
+// Meter godoc
+// @Summary      Create meter
+// @Description  Create a usage meter (page count, mileage, engine hours...) for a category with optional maintenance thresholds
+// @Tags         Meters
+// @Accept       json
+// @Produce      json
+// @Param        meter   body    dto.MeterDefinitionRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/meters [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MeterHandler) CreateMeter(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.MeterDefinitionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	meter, err := h.service.CreateMeter(userId, request)
+	if err != nil {
+		log.Error("Happened error when create meter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, utils.ConvertMeterDefinitionToResponse(meter)))
+}
+
+// Meter godoc
+// @Summary      Get meters
+// @Description  Get meters of the company
+// @Tags         Meters
+// @Accept       json
+// @Produce      json
+// @Param        filter   query    dto.MeterFilterRequest   false  "Filter"
+// @param Authorization header string true "Authorization"
+// @Router       /api/meters [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MeterHandler) GetMeters(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.MeterFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	meters, err := h.service.GetMeters(userId, request.CategoryId)
+	if err != nil {
+		log.Error("Happened error when get meters. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get meters")
+	}
+	res := []dto.MeterDefinitionResponse{}
+	for _, meter := range meters {
+		res = append(res, utils.ConvertMeterDefinitionToResponse(meter))
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// Meter godoc
+// @Summary      Get meter
+// @Description  Get meter by id with its thresholds
+// @Tags         Meters
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/meters/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MeterHandler) GetMeterById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	meter, err := h.service.GetMeterById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get meter. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertMeterDefinitionToResponse(meter)))
+}
+
+// Meter godoc
+// @Summary      Update meter
+// @Description  Update meter name, unit and active state
+// @Tags         Meters
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        meter   body    dto.UpdateMeterDefinitionRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/meters/{id} [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MeterHandler) UpdateMeter(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	var request dto.UpdateMeterDefinitionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	meter, err := h.service.UpdateMeter(userId, id, request)
+	if err != nil {
+		log.Error("Happened error when update meter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertMeterDefinitionToResponse(meter)))
+}
+
+// Meter godoc
+// @Summary      Add meter threshold
+// @Description  Add a usage-based maintenance threshold, e.g. every 10,000 km
+// @Tags         Meters
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"meter id"
+// @Param        threshold   body    dto.MeterThresholdRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/meters/{id}/thresholds [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MeterHandler) AddThreshold(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	var request dto.MeterThresholdRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	threshold, err := h.service.AddThreshold(userId, id, request)
+	if err != nil {
+		log.Error("Happened error when add meter threshold. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, utils.ConvertMeterThresholdToResponse(threshold)))
+}
+
+// Meter godoc
+// @Summary      Update meter threshold
+// @Description  Update a usage-based maintenance threshold
+// @Tags         Meters
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"threshold id"
+// @Param        threshold   body    dto.MeterThresholdRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/meter-thresholds/{id} [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MeterHandler) UpdateThreshold(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	var request dto.MeterThresholdRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	threshold, err := h.service.UpdateThreshold(userId, id, request)
+	if err != nil {
+		log.Error("Happened error when update meter threshold. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertMeterThresholdToResponse(threshold)))
+}
+
+// Meter godoc
+// @Summary      Delete meter threshold
+// @Description  Delete a usage-based maintenance threshold
+// @Tags         Meters
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"threshold id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/meter-thresholds/{id} [DELETE]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MeterHandler) DeleteThreshold(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	if err := h.service.DeleteThreshold(userId, id); err != nil {
+		log.Error("Happened error when delete meter threshold. Error", err)
+		pkg.PanicExeption(constant.UnknownError, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// Meter godoc
+// @Summary      Submit meter reading
+// @Description  Submit a meter reading of the asset. Readings must not decrease; crossing a threshold creates a maintenance schedule and notifies the asset manager
+// @Tags         Meters
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"asset id"
+// @Param        reading   body    dto.CreateMeterReadingRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/assets/{id}/meter-readings [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MeterHandler) CreateReading(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	assetId := h.parseId(c, "id")
+	var request dto.CreateMeterReadingRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	reading, err := h.service.CreateReading(userId, assetId, request)
+	if err != nil {
+		log.Error("Happened error when create meter reading. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, utils.ConvertMeterReadingToResponse(reading)))
+}
+
+// Meter godoc
+// @Summary      Get meter readings of asset
+// @Description  Get meter readings of the asset, newest first
+// @Tags         Meters
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"asset id"
+// @Param        filter   query    dto.MeterReadingFilterRequest   false  "Filter"
+// @param Authorization header string true "Authorization"
+// @Router       /api/assets/{id}/meter-readings [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *MeterHandler) GetReadings(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	assetId := h.parseId(c, "id")
+	var request dto.MeterReadingFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	readings, err := h.service.GetReadings(userId, assetId, request.MeterId)
+	if err != nil {
+		log.Error("Happened error when get meter readings. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	res := []dto.MeterReadingResponse{}
+	for _, reading := range readings {
+		res = append(res, utils.ConvertMeterReadingToResponse(reading))
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+func (h *MeterHandler) parseId(c *gin.Context, name string) int64 {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	return id
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/offboarding"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type OffboardingHandler struct {
+	service *service.OffboardingService
+}
+
+func NewOffboardingHandler(service *service.OffboardingService) *OffboardingHandler {
+	return &OffboardingHandler{service: service}
+}
+
+// Offboarding godoc
+// @Summary      Start offboarding
+// @Description  Start offboarding of a user: lists held assets as a return checklist and their open transfer requests. Calling again refreshes the checklist of the open offboarding
+// @Tags         Offboarding
+// @Accept       json
+// @Produce      json
+// @Param        offboarding   body    dto.StartOffboardingRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/offboardings [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *OffboardingHandler) Start(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.StartOffboardingRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	offboarding, err := h.service.Start(userId, request.UserId)
+	if err != nil {
+		log.Error("Happened error when start offboarding. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, offboarding))
+}
+
+// Offboarding godoc
+// @Summary      Get offboardings
+// @Description  Get offboardings of the company
+// @Tags         Offboarding
+// @Accept       json
+// @Produce      json
+// @Param		status	query		string				false	"In Progress, Completed, Cancelled"
+// @param Authorization header string true "Authorization"
+// @Router       /api/offboardings [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *OffboardingHandler) GetAll(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var status *string
+	if value := c.Query("status"); value != "" {
+		status = &value
+	}
+	offboardings, err := h.service.GetAll(userId, status)
+	if err != nil {
+		log.Error("Happened error when get offboardings. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get offboardings")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, offboardings))
+}
+
+// Offboarding godoc
+// @Summary      Get offboarding
+// @Description  Get offboarding with return checklist and open transfer requests of the user
+// @Tags         Offboarding
+// @Accept       json
+// @Produce      json
+// @Param		id	path		int				true	"offboarding_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/offboardings/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *OffboardingHandler) GetById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	offboarding, err := h.service.GetById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get offboarding. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get offboarding")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, offboarding))
+}
+
+// Offboarding godoc
+// @Summary      Check return checklist item
+// @Description  Mark an asset of the return checklist as Returned, Missing or back to Pending
+// @Tags         Offboarding
+// @Accept       json
+// @Produce      json
+// @Param        item   body    dto.UpdateOffboardingItemRequest   true  "Data"
+// @Param		id	path		int				true	"offboarding_id"
+// @Param		itemId	path		int				true	"item_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/offboardings/{id}/items/{itemId} [PATCH]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *OffboardingHandler) UpdateItem(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	itemId := h.parseId(c, "itemId")
+	var request dto.UpdateOffboardingItemRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	offboarding, err := h.service.UpdateItem(userId, id, itemId, request)
+	if err != nil {
+		log.Error("Happened error when update offboarding item. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, offboarding))
+}
+
+// Offboarding godoc
+// @Summary      Complete offboarding
+// @Description  Reassign every held asset to a user or a department pool, cancel open transfer requests, deactivate the account and revoke its sessions in one transaction, then sign the summary
+// @Tags         Offboarding
+// @Accept       json
+// @Produce      json
+// @Param        offboarding   body    dto.CompleteOffboardingRequest   true  "Data"
+// @Param		id	path		int				true	"offboarding_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/offboardings/{id}/complete [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *OffboardingHandler) Complete(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	var request dto.CompleteOffboardingRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	offboarding, err := h.service.Complete(userId, id, request)
+	if err != nil {
+		log.Error("Happened error when complete offboarding. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, offboarding))
+}
+
+// Offboarding godoc
+// @Summary      Cancel offboarding
+// @Description  Cancel an offboarding in progress
+// @Tags         Offboarding
+// @Accept       json
+// @Produce      json
+// @Param		id	path		int				true	"offboarding_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/offboardings/{id}/cancel [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *OffboardingHandler) Cancel(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	offboarding, err := h.service.Cancel(userId, id)
+	if err != nil {
+		log.Error("Happened error when cancel offboarding. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, offboarding))
+}
+
+// Offboarding godoc
+// @Summary      Export offboarding summary
+// @Description  Download the signed PDF summary of a completed offboarding
+// @Tags         Offboarding
+// @Produce      application/pdf
+// @Param		id	path		int				true	"offboarding_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/offboardings/{id}/pdf [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *OffboardingHandler) ExportPDF(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	data, err := h.service.ExportPDF(userId, id)
+	if err != nil {
+		log.Error("Happened error when export offboarding summary. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=offboarding-%v.pdf", id))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+func (h *OffboardingHandler) parseId(c *gin.Context, key string) int64 {
+	id, err := strconv.ParseInt(c.Param(key), 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert id to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest)
+	}
+	return id
+}
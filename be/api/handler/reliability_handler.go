@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/reliability"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type ReliabilityHandler struct {
+	service *service.ReliabilityService
+}
+
+func NewReliabilityHandler(service *service.ReliabilityService) *ReliabilityHandler {
+	return &ReliabilityHandler{service: service}
+}
+
+// Reliability godoc
+// @Summary      Get reliability report
+// @Description  MTBF, MTTR and downtime per asset and per category from finished maintenance schedules and maintenance logs. Assets failing more often per year than the configured threshold are flagged as replacement candidates
+// @Tags         Reliability
+// @Accept       json
+// @Produce      json
+// @Param        request   query    dto.ReliabilityFilterRequest   false  "Filter"
+// @param Authorization header string true "Authorization"
+// @Router       /api/reliability/report [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ReliabilityHandler) GetReport(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.ReliabilityFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	res, err := h.service.GetReport(userId, request)
+	if err != nil {
+		log.Error("Happened error when get reliability report. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get reliability report")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// Reliability godoc
+// @Summary      Get asset reliability
+// @Description  MTBF, MTTR and downtime of an asset
+// @Tags         Reliability
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/assets/{id}/reliability [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ReliabilityHandler) GetAssetReliability(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	res, err := h.service.GetAssetReliability(userId, id)
+	if err != nil {
+		log.Error("Happened error when get asset reliability. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
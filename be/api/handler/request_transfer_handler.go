@@ -3,6 +3,7 @@ package handler
 import (
 	"BE_Manage_device/constant"
 	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
 	"BE_Manage_device/internal/domain/filter"
 	service "BE_Manage_device/internal/service/request_transfer"
 
@@ -25,7 +26,7 @@ func NewRequestTransferHandler(service *service.RequestTransferService) *Request
 
 // Request Transfer godoc
 // @Summary      Request Transfer
-// @Description  Request Transfer
+// @Description  Request a specific asset (assetId) or a quantity of a category (categoryId), optionally with a needed-by date
 // @Tags         RequestTransfer
 // @Accept       json
 // @Produce      json
@@ -44,7 +45,7 @@ func (h *RequestTransferHandler) Create(c *gin.Context) {
 		log.Error("Happened error when mapping request from FE. Error", err)
 		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
 	}
-	requestTransfer, err := h.service.Create(userId, request.CategoryId, request.Description)
+	requestTransfer, err := h.service.Create(userId, request)
 	if err != nil {
 		log.Error("Happened error when create request transfer. Error", err)
 		pkg.PanicExeption(constant.UnknownError, "Happened error when create request transfer")
@@ -55,11 +56,11 @@ func (h *RequestTransferHandler) Create(c *gin.Context) {
 
 // Request Transfer godoc
 // @Summary      Accept Request Transfer
-// @Description  Accept Request Transfer
+// @Description  Transfer one asset to the request; assetId may be omitted when the request targets a specific asset. Returns 202 with the approval request when an approval workflow is configured
 // @Tags         RequestTransfer
 // @Accept       json
 // @Produce      json
-// @Param        Asset   body    dto.ConfirmRequestTransferRequest   true  "Data"
+// @Param        Asset   body    dto.ConfirmRequestTransferRequest   false  "Data"
 // @Param		id	path		int				true	"request_transfer_id"
 // @param Authorization header string true "Authorization"
 // @Router       /api/request-transfer/confirm/{id} [PATCH]
@@ -71,9 +72,11 @@ func (h *RequestTransferHandler) Accept(c *gin.Context) {
 	defer pkg.PanicHandler(c)
 	userId := utils.GetUserIdFromContext(c)
 	var request dto.ConfirmRequestTransferRequest
-	if err := c.ShouldBindJSON(&request); err != nil {
-		log.Error("Happened error when mapping request from FE. Error", err)
-		pkg.PanicExeption(constant.InvalidRequest)
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			log.Error("Happened error when mapping request from FE. Error", err)
+			pkg.PanicExeption(constant.InvalidRequest)
+		}
 	}
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -81,21 +84,26 @@ func (h *RequestTransferHandler) Accept(c *gin.Context) {
 		log.Error("Happened error when convert id to int64. Error", err)
 		pkg.PanicExeption(constant.InvalidRequest)
 	}
-	requestTransfer, err := h.service.Accept(userId, id, request.AssetId)
+	requestTransfer, instance, err := h.service.Accept(userId, id, request.AssetId)
 	if err != nil {
 		log.Error("Happened error when accept request transfer. Error", err)
 		pkg.PanicExeption(constant.UnknownError, "Happened error when accept request transfer")
 	}
+	if instance != nil {
+		c.JSON(http.StatusAccepted, pkg.BuildReponseSuccess(http.StatusAccepted, constant.Success, utils.ConvertWorkflowInstanceToResponse(instance)))
+		return
+	}
 	requestTransferResponse := utils.ConvertRequestTransferToResponse(requestTransfer)
 	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, requestTransferResponse))
 }
 
 // Request Transfer godoc
 // @Summary      Deny Request Transfer
-// @Description  Deny Request Transfer
+// @Description  Deny a pending request transfer with an optional reason
 // @Tags         RequestTransfer
 // @Accept       json
 // @Produce      json
+// @Param        Reason   body    dto.ReasonRequestTransferRequest   false  "Data"
 // @Param		id	path		int				true	"request_transfer_id"
 // @param Authorization header string true "Authorization"
 // @Router       /api/request-transfer/deny/{id} [PATCH]
@@ -112,7 +120,14 @@ func (h *RequestTransferHandler) Deny(c *gin.Context) {
 		log.Error("Happened error when convert id to int64. Error", err)
 		pkg.PanicExeption(constant.InvalidRequest)
 	}
-	requestTransfer, err := h.service.Deny(userId, id)
+	var request dto.ReasonRequestTransferRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			log.Error("Happened error when mapping request from FE. Error", err)
+			pkg.PanicExeption(constant.InvalidRequest)
+		}
+	}
+	requestTransfer, err := h.service.Deny(userId, id, request.Reason)
 	if err != nil {
 		log.Error("Happened error when deny request transfer. Error", err)
 		pkg.PanicExeption(constant.UnknownError, "Happened error when deny request transfer")
@@ -123,7 +138,7 @@ func (h *RequestTransferHandler) Deny(c *gin.Context) {
 
 // Request transfer godoc
 // @Summary      Get request transfer
-// @Description  Get request transfer
+// @Description  Get request transfer with status history and transferred assets. Visible to the requester and transfer managers
 // @Tags         RequestTransfer
 // @Accept       json
 // @Produce      json
@@ -148,8 +163,7 @@ func (h *RequestTransferHandler) GetRequestTransferById(c *gin.Context) {
 		log.Error("Happened error when get request transfer. Error", err)
 		pkg.PanicExeption(constant.UnknownError, "Happened error when get request transfer")
 	}
-	requestTransferResponse := utils.ConvertRequestTransferToResponse(requestTransfer)
-	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, requestTransferResponse))
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, requestTransfer))
 }
 
 // Request Transfer godoc
@@ -180,3 +194,132 @@ func (h *RequestTransferHandler) FilterRequestTransfer(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, data))
 }
+
+// Request Transfer godoc
+// @Summary      Cancel Request Transfer
+// @Description  Requester cancels their own request transfer with an optional reason
+// @Tags         RequestTransfer
+// @Accept       json
+// @Produce      json
+// @Param        Reason   body    dto.ReasonRequestTransferRequest   false  "Data"
+// @Param		id	path		int				true	"request_transfer_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/request-transfer/cancel/{id} [PATCH]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *RequestTransferHandler) Cancel(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	var request dto.ReasonRequestTransferRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			log.Error("Happened error when mapping request from FE. Error", err)
+			pkg.PanicExeption(constant.InvalidRequest)
+		}
+	}
+	requestTransfer, err := h.service.Cancel(userId, id, request.Reason)
+	if err != nil {
+		log.Error("Happened error when cancel request transfer. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when cancel request transfer")
+	}
+	requestTransferResponse := utils.ConvertRequestTransferToResponse(requestTransfer)
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, requestTransferResponse))
+}
+
+// Request Transfer godoc
+// @Summary Get my request transfers
+// @Description Get request transfers created by current user
+// @Tags RequestTransfer
+// @Accept json
+// @Produce json
+// @Param        request_transfer   query    filter.RequestTransferFilter   false  "filter request transfer"
+// @param Authorization header string true "Authorization"
+// @Router /api/request-transfer/mine [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *RequestTransferHandler) GetMine(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	var filter filter.RequestTransferFilter
+	userId := utils.GetUserIdFromContext(c)
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	data, err := h.service.GetMine(userId, filter.Status)
+	if err != nil {
+		log.Error("Happened error when get request transfers. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get request transfers")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, data))
+}
+
+// Request Transfer godoc
+// @Summary      Comment on request transfer
+// @Description  Add a comment to a request transfer, the other party is notified
+// @Tags         RequestTransfer
+// @Accept       json
+// @Produce      json
+// @Param        Comment   body    dto.RequestTransferCommentRequest   true  "Data"
+// @Param		id	path		int				true	"request_transfer_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/request-transfer/{id}/comments [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *RequestTransferHandler) AddComment(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	var request dto.RequestTransferCommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	comment, err := h.service.AddComment(userId, id, request.Content)
+	if err != nil {
+		log.Error("Happened error when comment request transfer. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when comment request transfer")
+	}
+	res := utils.ConvertRequestTransferCommentsToResponses([]*entity.RequestTransferComment{comment})
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, res[0]))
+}
+
+// Request Transfer godoc
+// @Summary      Get comments of request transfer
+// @Description  Get comments of request transfer
+// @Tags         RequestTransfer
+// @Accept       json
+// @Produce      json
+// @Param		id	path		int				true	"request_transfer_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/request-transfer/{id}/comments [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *RequestTransferHandler) GetComments(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	comments, err := h.service.GetComments(userId, id)
+	if err != nil {
+		log.Error("Happened error when get comments of request transfer. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get comments of request transfer")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertRequestTransferCommentsToResponses(comments)))
+}
+
+func (h *RequestTransferHandler) parseId(c *gin.Context) int64 {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert id to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest)
+	}
+	return id
+}
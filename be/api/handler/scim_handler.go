@@ -0,0 +1,427 @@
+package handler
+
+import (
+	"BE_Manage_device/config"
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	service "BE_Manage_device/internal/service/scim"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type ScimHandler struct {
+	service *service.ScimService
+}
+
+func NewScimHandler(service *service.ScimService) *ScimHandler {
+	return &ScimHandler{service: service}
+}
+
+// Scim godoc
+// @Summary      Create SCIM token
+// @Description  Create a bearer token for the identity provider to call /scim/v2. The token is only returned once. Departments created through /Groups are placed at locationId
+// @Tags         Scim
+// @Accept       json
+// @Produce      json
+// @Param        token   body    dto.CreateScimTokenRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/company/scim/tokens [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ScimHandler) CreateToken(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.CreateScimTokenRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	token, err := h.service.CreateToken(userId, request, c.ClientIP())
+	if err != nil {
+		log.Error("Happened error when create SCIM token. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, token))
+}
+
+// Scim godoc
+// @Summary      Get SCIM tokens
+// @Description  Get SCIM tokens of the company, including revoked ones
+// @Tags         Scim
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/company/scim/tokens [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ScimHandler) GetTokens(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	tokens, err := h.service.GetTokens(userId)
+	if err != nil {
+		log.Error("Happened error when get SCIM tokens. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get SCIM tokens")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, tokens))
+}
+
+// Scim godoc
+// @Summary      Revoke SCIM token
+// @Description  Revoke a SCIM token, the identity provider can no longer use it
+// @Tags         Scim
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/company/scim/tokens/{id} [DELETE]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ScimHandler) RevokeToken(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	if err := h.service.RevokeToken(userId, id, c.ClientIP()); err != nil {
+		log.Error("Happened error when revoke SCIM token. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// Scim godoc
+// @Summary      SCIM service provider configuration
+// @Description  Features supported by the SCIM endpoints: PATCH and filtering, no bulk, sort or etag
+// @Tags         Scim
+// @Produce      json
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/ServiceProviderConfig [GET]
+func (h *ScimHandler) ServiceProviderConfig(c *gin.Context) {
+	writeScim(c, http.StatusOK, gin.H{
+		"schemas":        []string{dto.ScimProviderSchema},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": dto.ScimMaxPageSize},
+		"changePassword": gin.H{"supported": true},
+		"sort":           gin.H{"supported": false},
+		"etag":           gin.H{"supported": false},
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "SCIM token created in company settings",
+			"primary":     true,
+		}},
+	})
+}
+
+// Scim godoc
+// @Summary      List SCIM users
+// @Description  List users of the company. filter supports eq, ne, co, sw, ew, pr joined by and on userName, emails.value, externalId, name.givenName, name.familyName, active and id
+// @Tags         Scim
+// @Produce      json
+// @Param        filter       query   string  false  "filter"
+// @Param        startIndex   query   int     false  "startIndex (1-based)"
+// @Param        count        query   int     false  "count"
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/Users [GET]
+func (h *ScimHandler) ListUsers(c *gin.Context) {
+	var request dto.ScimListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		writeScim(c, http.StatusBadRequest, scimErrorBody(http.StatusBadRequest, "invalidValue", err.Error()))
+		return
+	}
+	res, err := h.service.ListUsers(scimToken(c), request)
+	if err != nil {
+		writeScimError(c, "list SCIM users", err)
+		return
+	}
+	writeScim(c, http.StatusOK, res)
+}
+
+// Scim godoc
+// @Summary      Get SCIM user
+// @Tags         Scim
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/Users/{id} [GET]
+func (h *ScimHandler) GetUser(c *gin.Context) {
+	res, err := h.service.GetUser(scimToken(c), c.Param("id"))
+	if err != nil {
+		writeScimError(c, "get SCIM user", err)
+		return
+	}
+	writeScim(c, http.StatusOK, res)
+}
+
+// Scim godoc
+// @Summary      Create SCIM user
+// @Description  Provision a user. userName is the login email, roles[].value a role slug and the enterprise department the name of an existing department
+// @Tags         Scim
+// @Accept       json
+// @Produce      json
+// @Param        user   body    dto.ScimUser   true  "Data"
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/Users [POST]
+func (h *ScimHandler) CreateUser(c *gin.Context) {
+	var request dto.ScimUser
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeScim(c, http.StatusBadRequest, scimErrorBody(http.StatusBadRequest, "invalidSyntax", err.Error()))
+		return
+	}
+	res, err := h.service.CreateUser(scimToken(c), request, c.ClientIP())
+	if err != nil {
+		writeScimError(c, "create SCIM user", err)
+		return
+	}
+	writeScim(c, http.StatusCreated, res)
+}
+
+// Scim godoc
+// @Summary      Replace SCIM user
+// @Description  Replace the attributes sent in the body. active false deprovisions the user and revokes their sessions
+// @Tags         Scim
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        user   body    dto.ScimUser   true  "Data"
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/Users/{id} [PUT]
+func (h *ScimHandler) ReplaceUser(c *gin.Context) {
+	var request dto.ScimUser
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeScim(c, http.StatusBadRequest, scimErrorBody(http.StatusBadRequest, "invalidSyntax", err.Error()))
+		return
+	}
+	res, deprovisioned, err := h.service.ReplaceUser(scimToken(c), c.Param("id"), request, c.ClientIP())
+	if err != nil {
+		writeScimError(c, "replace SCIM user", err)
+		return
+	}
+	if deprovisioned {
+		clearUserSessionCache(res.Id)
+	}
+	writeScim(c, http.StatusOK, res)
+}
+
+// Scim godoc
+// @Summary      Patch SCIM user
+// @Description  Apply add, replace and remove operations on active, userName, name, externalId, password, roles and the enterprise department. Setting active to false deprovisions the user and revokes their sessions
+// @Tags         Scim
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        patch   body    dto.ScimPatchRequest   true  "Data"
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/Users/{id} [PATCH]
+func (h *ScimHandler) PatchUser(c *gin.Context) {
+	var request dto.ScimPatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeScim(c, http.StatusBadRequest, scimErrorBody(http.StatusBadRequest, "invalidSyntax", err.Error()))
+		return
+	}
+	res, deprovisioned, err := h.service.PatchUser(scimToken(c), c.Param("id"), request, c.ClientIP())
+	if err != nil {
+		writeScimError(c, "patch SCIM user", err)
+		return
+	}
+	if deprovisioned {
+		clearUserSessionCache(res.Id)
+	}
+	writeScim(c, http.StatusOK, res)
+}
+
+// Scim godoc
+// @Summary      Delete SCIM user
+// @Description  Deprovision the user: the account is deactivated (kept for asset history) and its sessions are revoked
+// @Tags         Scim
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/Users/{id} [DELETE]
+func (h *ScimHandler) DeleteUser(c *gin.Context) {
+	userId, err := h.service.DeleteUser(scimToken(c), c.Param("id"), c.ClientIP())
+	if err != nil {
+		writeScimError(c, "delete SCIM user", err)
+		return
+	}
+	clearUserSessionCache(strconv.FormatInt(userId, 10))
+	c.Status(http.StatusNoContent)
+}
+
+// Scim godoc
+// @Summary      List SCIM groups
+// @Description  Groups are departments (id department-<id>) and roles (id role-<slug>). filter supports displayName and id
+// @Tags         Scim
+// @Produce      json
+// @Param        filter              query   string  false  "filter"
+// @Param        startIndex          query   int     false  "startIndex (1-based)"
+// @Param        count               query   int     false  "count"
+// @Param        excludedAttributes  query   string  false  "members"
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/Groups [GET]
+func (h *ScimHandler) ListGroups(c *gin.Context) {
+	var request dto.ScimListRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		writeScim(c, http.StatusBadRequest, scimErrorBody(http.StatusBadRequest, "invalidValue", err.Error()))
+		return
+	}
+	res, err := h.service.ListGroups(scimToken(c), request)
+	if err != nil {
+		writeScimError(c, "list SCIM groups", err)
+		return
+	}
+	writeScim(c, http.StatusOK, res)
+}
+
+// Scim godoc
+// @Summary      Get SCIM group
+// @Tags         Scim
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        excludedAttributes  query   string  false  "members"
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/Groups/{id} [GET]
+func (h *ScimHandler) GetGroup(c *gin.Context) {
+	res, err := h.service.GetGroup(scimToken(c), c.Param("id"), c.Query("excludedAttributes"))
+	if err != nil {
+		writeScimError(c, "get SCIM group", err)
+		return
+	}
+	writeScim(c, http.StatusOK, res)
+}
+
+// Scim godoc
+// @Summary      Create SCIM group
+// @Description  Create a department at the location of the SCIM token and move the members into it
+// @Tags         Scim
+// @Accept       json
+// @Produce      json
+// @Param        group   body    dto.ScimGroup   true  "Data"
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/Groups [POST]
+func (h *ScimHandler) CreateGroup(c *gin.Context) {
+	var request dto.ScimGroup
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeScim(c, http.StatusBadRequest, scimErrorBody(http.StatusBadRequest, "invalidSyntax", err.Error()))
+		return
+	}
+	res, err := h.service.CreateGroup(scimToken(c), request)
+	if err != nil {
+		writeScimError(c, "create SCIM group", err)
+		return
+	}
+	writeScim(c, http.StatusCreated, res)
+}
+
+// Scim godoc
+// @Summary      Replace SCIM group
+// @Description  Rename the department and replace its members. Role groups can't be renamed
+// @Tags         Scim
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        group   body    dto.ScimGroup   true  "Data"
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/Groups/{id} [PUT]
+func (h *ScimHandler) ReplaceGroup(c *gin.Context) {
+	var request dto.ScimGroup
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeScim(c, http.StatusBadRequest, scimErrorBody(http.StatusBadRequest, "invalidSyntax", err.Error()))
+		return
+	}
+	res, err := h.service.ReplaceGroup(scimToken(c), c.Param("id"), request)
+	if err != nil {
+		writeScimError(c, "replace SCIM group", err)
+		return
+	}
+	writeScim(c, http.StatusOK, res)
+}
+
+// Scim godoc
+// @Summary      Patch SCIM group
+// @Description  Rename and add, remove or replace members. Members added to a department leave their previous one, members removed from a role fall back to viewer
+// @Tags         Scim
+// @Accept       json
+// @Param		id	path		string				true	"id"
+// @Param        patch   body    dto.ScimPatchRequest   true  "Data"
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/Groups/{id} [PATCH]
+func (h *ScimHandler) PatchGroup(c *gin.Context) {
+	var request dto.ScimPatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeScim(c, http.StatusBadRequest, scimErrorBody(http.StatusBadRequest, "invalidSyntax", err.Error()))
+		return
+	}
+	if err := h.service.PatchGroup(scimToken(c), c.Param("id"), request); err != nil {
+		writeScimError(c, "patch SCIM group", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Scim godoc
+// @Summary      Delete SCIM group
+// @Description  Delete a department that no longer has users, assets or pending requests. Roles can't be deleted
+// @Tags         Scim
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Bearer SCIM token"
+// @Router       /scim/v2/Groups/{id} [DELETE]
+func (h *ScimHandler) DeleteGroup(c *gin.Context) {
+	if err := h.service.DeleteGroup(scimToken(c), c.Param("id")); err != nil {
+		writeScimError(c, "delete SCIM group", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func scimToken(c *gin.Context) *entity.ScimToken {
+	token, _ := c.MustGet("scimToken").(*entity.ScimToken)
+	return token
+}
+
+func writeScim(c *gin.Context, status int, body interface{}) {
+	c.Header("Content-Type", dto.ScimContentType)
+	c.JSON(status, body)
+}
+
+// writeScimError lỗi nghiệp vụ trả đúng mã SCIM, lỗi khác ghi log và trả 500
+func writeScimError(c *gin.Context, action string, err error) {
+	var scimErr *service.ScimError
+	if errors.As(err, &scimErr) {
+		writeScim(c, scimErr.Status, scimErrorBody(scimErr.Status, scimErr.ScimType, scimErr.Detail))
+		return
+	}
+	log.Error(fmt.Sprintf("Happened error when %s. Error", action), err)
+	writeScim(c, http.StatusInternalServerError, scimErrorBody(http.StatusInternalServerError, "", "Happened error when "+action))
+}
+
+func scimErrorBody(status int, scimType string, detail string) dto.ScimErrorResponse {
+	return dto.ScimErrorResponse{
+		Schemas:  []string{dto.ScimErrorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+// clearUserSessionCache xoá cache phiên giống khi đăng xuất để token cũ hết hiệu lực ngay
+func clearUserSessionCache(userId string) {
+	cacheKeyUserSessionStr := fmt.Sprintf("%s:%s", cacheKeyUserSession, userId)
+	config.Rdb.Del(config.Ctx, cacheKeyUserSessionStr)
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/service_accounts"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type ServiceAccountHandler struct {
+	service *service.ServiceAccountService
+}
+
+func NewServiceAccountHandler(service *service.ServiceAccountService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{service: service}
+}
+
+// ServiceAccount godoc
+// @Summary      Create service account
+// @Description  Create a company-scoped service account for an integration. It acts as a system user with the given role and department, and can't sign in with a password
+// @Tags         ServiceAccounts
+// @Accept       json
+// @Produce      json
+// @Param        serviceAccount   body    dto.CreateServiceAccountRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/service-accounts [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ServiceAccountHandler) Create(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	rejectApiKey(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	account, err := h.service.Create(userId, request, c.ClientIP())
+	if err != nil {
+		log.Error("Happened error when create service account. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, account))
+}
+
+// ServiceAccount godoc
+// @Summary      Get service accounts
+// @Description  Get service accounts of the company
+// @Tags         ServiceAccounts
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/service-accounts [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ServiceAccountHandler) GetAll(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	accounts, err := h.service.GetAll(userId)
+	if err != nil {
+		log.Error("Happened error when get service accounts. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get service accounts")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, accounts))
+}
+
+// ServiceAccount godoc
+// @Summary      Get service account
+// @Tags         ServiceAccounts
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/service-accounts/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ServiceAccountHandler) GetById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := pathId(c, "id")
+	account, err := h.service.GetById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get service account. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, account))
+}
+
+// ServiceAccount godoc
+// @Summary      Disable service account
+// @Description  Disable a service account: all its API keys are revoked and its system user is deactivated
+// @Tags         ServiceAccounts
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/service-accounts/{id} [DELETE]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ServiceAccountHandler) Disable(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	rejectApiKey(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := pathId(c, "id")
+	if err := h.service.Disable(userId, id, c.ClientIP()); err != nil {
+		log.Error("Happened error when disable service account. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// ServiceAccount godoc
+// @Summary      Create API key
+// @Description  Create an API key for the service account. Scopes are permission slugs the account role already grants. Send the key as X-Api-Key or Authorization: Bearer, it is only returned once. Keys are rejected on routes without a permission check
+// @Tags         ServiceAccounts
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        key   body    dto.CreateApiKeyRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/service-accounts/{id}/keys [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ServiceAccountHandler) CreateKey(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	rejectApiKey(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := pathId(c, "id")
+	var request dto.CreateApiKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	key, err := h.service.CreateKey(userId, id, request, c.ClientIP())
+	if err != nil {
+		log.Error("Happened error when create API key. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, key))
+}
+
+// ServiceAccount godoc
+// @Summary      Get API keys
+// @Description  Get API keys of the service account with their scopes, expiry and last use
+// @Tags         ServiceAccounts
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/service-accounts/{id}/keys [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ServiceAccountHandler) GetKeys(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := pathId(c, "id")
+	keys, err := h.service.GetKeys(userId, id)
+	if err != nil {
+		log.Error("Happened error when get API keys. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, keys))
+}
+
+// ServiceAccount godoc
+// @Summary      Revoke API key
+// @Description  Revoke an API key, it stops working immediately
+// @Tags         ServiceAccounts
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param		key_id	path		string				true	"key_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/service-accounts/{id}/keys/{key_id} [DELETE]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *ServiceAccountHandler) RevokeKey(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	rejectApiKey(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := pathId(c, "id")
+	keyId := pathId(c, "key_id")
+	if err := h.service.RevokeKey(userId, id, keyId, c.ClientIP()); err != nil {
+		log.Error("Happened error when revoke API key. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// rejectApiKey API key không được tự tạo key hay đổi service account, tránh leo thang quyền
+func rejectApiKey(c *gin.Context) {
+	if _, isApiKey := c.Get("apiKeyId"); isApiKey {
+		pkg.PanicExeption(constant.StatusForbidden, "API keys can't manage service accounts")
+	}
+}
+
+func pathId(c *gin.Context, name string) int64 {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	return id
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/sso"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type SsoHandler struct {
+	service *service.SsoService
+}
+
+func NewSsoHandler(service *service.SsoService) *SsoHandler {
+	return &SsoHandler{service: service}
+}
+
+// Sso godoc
+// @Summary      Get SSO configuration
+// @Description  Get OpenID Connect configuration of the company. The client secret is never returned
+// @Tags         Sso
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/company/sso [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *SsoHandler) GetConfig(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	config, err := h.service.GetConfig(userId)
+	if err != nil {
+		log.Error("Happened error when get SSO configuration. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get SSO configuration")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, config))
+}
+
+// Sso godoc
+// @Summary      Save SSO configuration
+// @Description  Create or update OpenID Connect configuration of the company: issuer, client, claim mapping to department/role, just-in-time provisioning and disabling password login
+// @Tags         Sso
+// @Accept       json
+// @Produce      json
+// @Param        sso   body    dto.SsoConfigRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/company/sso [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *SsoHandler) SaveConfig(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.SsoConfigRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	config, err := h.service.SaveConfig(userId, request, c.ClientIP())
+	if err != nil {
+		log.Error("Happened error when save SSO configuration. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, config))
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"BE_Manage_device/config"
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/tag"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type TagHandler struct {
+	service *service.TagService
+}
+
+func NewTagHandler(service *service.TagService) *TagHandler {
+	return &TagHandler{service: service}
+}
+
+// Tag godoc
+// @Summary      Create tag
+// @Description  Create tag for the company
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Param        tag   body    dto.CreateTagRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/tags [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TagHandler) Create(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.CreateTagRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	tag, err := h.service.Create(userId, request.TagName)
+	if err != nil {
+		log.Error("Happened error when create tag. Error", err.Error())
+		pkg.PanicExeption(constant.UnknownError, "Happened error when create tag. Error: "+err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, tag))
+}
+
+// Tag godoc
+// @Summary      Search tags
+// @Description  Autocomplete tags of the company by prefix
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Param        request   query    dto.SearchTagRequest   false  "keyword"
+// @param Authorization header string true "Authorization"
+// @Router       /api/tags [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TagHandler) Search(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.SearchTagRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	tags, err := h.service.Search(userId, request.Keyword, request.Limit)
+	if err != nil {
+		log.Error("Happened error when search tags. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when search tags")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, tags))
+}
+
+// Tag godoc
+// @Summary      Delete tag
+// @Description  Delete tag via id, the tag is removed from every asset
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/tags/{id} [DELETE]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TagHandler) Delete(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	if err := h.service.Delete(userId, id); err != nil {
+		log.Error("Happened error when delete tag. Error", err)
+		pkg.PanicExeption(constant.UnknownError, err.Error())
+	}
+	config.Rdb.Del(config.Ctx, "assets:all")
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// Tag godoc
+// @Summary      Set tags of asset
+// @Description  Replace all tags of an asset, unknown tags are created
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        request   body    dto.SetAssetTagsRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/assets/{id}/tags [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TagHandler) SetAssetTags(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	assetId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert assetId to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when convert assetId to int64")
+	}
+	var request dto.SetAssetTagsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	asset, err := h.service.SetAssetTags(userId, assetId, request.TagNames)
+	if err != nil {
+		log.Error("Happened error when set tags of asset. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	config.Rdb.Del(config.Ctx, "assets:all")
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertAssetToResponse(*asset)))
+}
+
+// Tag godoc
+// @Summary      Bulk add/remove tags
+// @Description  Add or remove tags on every asset matching the filter
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Param        request   body    dto.BulkTagRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/assets/tags/bulk [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TagHandler) BulkUpdate(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.BulkTagRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	affected, tagNames, err := h.service.BulkUpdate(userId, request.Action, request.TagNames, request.Filter)
+	if err != nil {
+		log.Error("Happened error when bulk update tags. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	config.Rdb.Del(config.Ctx, "assets:all")
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, dto.BulkTagResponse{
+		Action:        request.Action,
+		TagNames:      tagNames,
+		AffectedAsset: affected,
+	}))
+}
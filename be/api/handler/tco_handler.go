@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/tco"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type TcoHandler struct {
+	service *service.TcoService
+}
+
+func NewTcoHandler(service *service.TcoService) *TcoHandler {
+	return &TcoHandler{service: service}
+}
+
+// Tco godoc
+// @Summary      Get total cost of ownership of an asset
+// @Description  Purchase cost (bill amount when present) + maintenance costs - residual value at retirement
+// @Tags         TCO
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/assets/{id}/tco [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TcoHandler) GetAssetTco(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	assetId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert assetId to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when convert assetId to int64")
+	}
+	res, err := h.service.GetAssetTco(userId, assetId)
+	if err != nil {
+		log.Error("Happened error when get asset tco. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// Tco godoc
+// @Summary      Get total cost of ownership of assets
+// @Description  List TCO per asset, sortable by total, annual, maintenance, purchase or count
+// @Tags         TCO
+// @Accept       json
+// @Produce      json
+// @Param        request   query    dto.TcoFilterRequest   false  "filter"
+// @param Authorization header string true "Authorization"
+// @Router       /api/tco/assets [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TcoHandler) GetAssets(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	request := h.bindFilter(c)
+	res, err := h.service.GetAssets(userId, request)
+	if err != nil {
+		log.Error("Happened error when get assets tco. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get assets tco")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// Tco godoc
+// @Summary      Get total cost of ownership by category
+// @Description  Aggregate TCO per category, sort by average to find the most expensive models
+// @Tags         TCO
+// @Accept       json
+// @Produce      json
+// @Param        request   query    dto.TcoFilterRequest   false  "filter"
+// @param Authorization header string true "Authorization"
+// @Router       /api/tco/categories [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TcoHandler) GetByCategory(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	request := h.bindFilter(c)
+	res, err := h.service.GetByCategory(userId, request)
+	if err != nil {
+		log.Error("Happened error when get tco by category. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get tco by category")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// Tco godoc
+// @Summary      Get total cost of ownership by department
+// @Description  Aggregate TCO per department
+// @Tags         TCO
+// @Accept       json
+// @Produce      json
+// @Param        request   query    dto.TcoFilterRequest   false  "filter"
+// @param Authorization header string true "Authorization"
+// @Router       /api/tco/departments [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TcoHandler) GetByDepartment(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	request := h.bindFilter(c)
+	res, err := h.service.GetByDepartment(userId, request)
+	if err != nil {
+		log.Error("Happened error when get tco by department. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get tco by department")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+func (h *TcoHandler) bindFilter(c *gin.Context) dto.TcoFilterRequest {
+	var request dto.TcoFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	return request
+}
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/two_factor"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type TwoFactorHandler struct {
+	service *service.TwoFactorService
+}
+
+func NewTwoFactorHandler(service *service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{service: service}
+}
+
+// TwoFactor godoc
+// @Summary      Get two-factor status
+// @Description  Get two-factor authentication status of the current user
+// @Tags         TwoFactor
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/user/two-factor [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TwoFactorHandler) Status(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	status, err := h.service.Status(userId)
+	if err != nil {
+		log.Error("Happened error when get two-factor status. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get two-factor status")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, status))
+}
+
+// TwoFactor godoc
+// @Summary      Begin two-factor enrollment
+// @Description  Generate a new TOTP secret and its QR code. Two-factor authentication is enabled after confirming a code
+// @Tags         TwoFactor
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/user/two-factor/enroll [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TwoFactorHandler) BeginEnrollment(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	enrollment, err := h.service.BeginEnrollment(userId)
+	if err != nil {
+		log.Error("Happened error when begin two-factor enrollment. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, enrollment))
+}
+
+// TwoFactor godoc
+// @Summary      Confirm two-factor enrollment
+// @Description  Enable two-factor authentication with a code from the authenticator app. Returns one-time recovery codes
+// @Tags         TwoFactor
+// @Accept       json
+// @Produce      json
+// @Param        code   body    dto.TwoFactorCodeRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/user/two-factor/enroll/confirm [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TwoFactorHandler) ConfirmEnrollment(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	request := h.bindCode(c)
+	codes, err := h.service.ConfirmEnrollment(userId, request.Code, c.ClientIP())
+	if err != nil {
+		log.Error("Happened error when confirm two-factor enrollment. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, codes))
+}
+
+// TwoFactor godoc
+// @Summary      Disable two-factor authentication
+// @Description  Disable two-factor authentication of the current user, not allowed when the company requires it
+// @Tags         TwoFactor
+// @Accept       json
+// @Produce      json
+// @Param        code   body    dto.TwoFactorCodeRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/user/two-factor/disable [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	request := h.bindCode(c)
+	if err := h.service.Disable(userId, request.Code, c.ClientIP()); err != nil {
+		log.Error("Happened error when disable two-factor. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// TwoFactor godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replace all recovery codes of the current user
+// @Tags         TwoFactor
+// @Accept       json
+// @Produce      json
+// @Param        code   body    dto.TwoFactorCodeRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/user/two-factor/recovery-codes [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	request := h.bindCode(c)
+	codes, err := h.service.RegenerateRecoveryCodes(userId, request.Code, c.ClientIP())
+	if err != nil {
+		log.Error("Happened error when regenerate recovery codes. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, codes))
+}
+
+// TwoFactor godoc
+// @Summary      Reset two-factor of a user
+// @Description  Admin removes two-factor authentication of a user who lost their device. An audit entry is recorded
+// @Tags         TwoFactor
+// @Accept       json
+// @Produce      json
+// @Param        reset   body    dto.ResetTwoFactorRequest   true  "Data"
+// @Param		user_id	path		int				true	"user_id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/user/two-factor/reset/{user_id} [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TwoFactorHandler) Reset(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	targetId, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when convert user id to int64. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest)
+	}
+	var request dto.ResetTwoFactorRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	if err := h.service.Reset(userId, targetId, request.Reason, c.ClientIP()); err != nil {
+		log.Error("Happened error when reset two-factor. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// TwoFactor godoc
+// @Summary      Set two-factor enforcement
+// @Description  Require (or stop requiring) two-factor authentication for every user of the company
+// @Tags         TwoFactor
+// @Accept       json
+// @Produce      json
+// @Param        enforcement   body    dto.TwoFactorEnforcementRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/company/two-factor [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TwoFactorHandler) SetEnforcement(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.TwoFactorEnforcementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	if err := h.service.SetEnforcement(userId, *request.Required, c.ClientIP()); err != nil {
+		log.Error("Happened error when set two-factor enforcement. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when set two-factor enforcement")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// TwoFactor godoc
+// @Summary      Get audit logs
+// @Description  Get security audit logs of the company
+// @Tags         TwoFactor
+// @Accept       json
+// @Produce      json
+// @Param		action	query		string				false	"action"
+// @Param		userId	query		int				false	"actor or target user id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/audit-logs [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *TwoFactorHandler) GetAuditLogs(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.AuditLogFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	logs, err := h.service.GetAuditLogs(userId, request)
+	if err != nil {
+		log.Error("Happened error when get audit logs. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get audit logs")
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, logs))
+}
+
+func (h *TwoFactorHandler) bindCode(c *gin.Context) dto.TwoFactorCodeRequest {
+	var request dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	return request
+}
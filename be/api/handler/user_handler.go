@@ -7,6 +7,7 @@ import (
 	"BE_Manage_device/internal/domain/entity"
 	service "BE_Manage_device/internal/service/user"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"BE_Manage_device/pkg"
@@ -76,13 +77,26 @@ func (h *UserHandler) Login(c *gin.Context) {
 		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
 	}
 
-	userLogin, accessToken, refreshToken, err := h.service.Login(user.Email, user.Password)
+	userLogin, accessToken, refreshToken, challenge, err := h.service.Login(user.Email, user.Password)
+	if errors.Is(err, service.ErrPasswordLoginDisabled) {
+		log.Error("Happened error when login. Error", err)
+		pkg.PanicExeption(constant.Unauthorized, err.Error())
+	}
 	if err != nil {
 		log.Error("Happened error when login. Error", err)
 		pkg.PanicExeption(constant.Invalidemailorpassword)
 	}
 	dataResponese := map[string]interface{}{}
-	if userLogin.IsActive {
+	if challenge != nil {
+		// Cần bước xác thực hai lớp, token được cấp ở /auth/login/two-factor/verify
+		dataResponese = map[string]interface{}{
+			"two_factor_required": true,
+			"setup_required":      challenge.SetupRequired,
+			"challenge_token":     challenge.ChallengeToken,
+			"expires_at":          challenge.ExpiresAt,
+			"is_active":           userLogin.IsActive,
+		}
+	} else if userLogin.IsActive {
 		dataResponese = map[string]interface{}{
 			"access_token":  accessToken,
 			"refresh_token": refreshToken,
@@ -97,6 +111,121 @@ func (h *UserHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, dataResponese))
 }
 
+// User godoc
+// @Summary      Set up two-factor login
+// @Description  When the company requires two-factor authentication and the user hasn't enrolled, returns the secret and QR code for the login challenge
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        challenge   body    dto.TwoFactorChallengeRequest   true  "Data"
+// @Router       /api/auth/login/two-factor/setup [post]
+// @Success      200   {object}  dto.ApiResponseSuccessStruct
+// @Failure      500   {object}  dto.ApiResponseFail
+func (h *UserHandler) SetupTwoFactorLogin(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	var request dto.TwoFactorChallengeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	enrollment, err := h.service.SetupTwoFactorLogin(request.ChallengeToken)
+	if err != nil {
+		log.Error("Happened error when set up two-factor login. Error", err)
+		pkg.PanicExeption(constant.Unauthorized, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, enrollment))
+}
+
+// User godoc
+// @Summary      Verify two-factor login
+// @Description  Second login step: verify the TOTP or recovery code of the challenge and issue tokens. Recovery codes are returned once when the user has just enrolled
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        challenge   body    dto.TwoFactorVerifyRequest   true  "Data"
+// @Router       /api/auth/login/two-factor/verify [post]
+// @Success      200   {object}  dto.ApiResponseSuccessStruct
+// @Failure      500   {object}  dto.ApiResponseFail
+func (h *UserHandler) VerifyTwoFactorLogin(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	var request dto.TwoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	userLogin, accessToken, refreshToken, recoveryCodes, err := h.service.CompleteTwoFactorLogin(request.ChallengeToken, request.Code, c.ClientIP())
+	if err != nil {
+		log.Error("Happened error when verify two-factor login. Error", err)
+		pkg.PanicExeption(constant.Unauthorized, err.Error())
+	}
+	dataResponese := map[string]interface{}{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"is_active":     userLogin.IsActive,
+		"roleSlug":      userLogin.Role.Slug,
+	}
+	if len(recoveryCodes) > 0 {
+		dataResponese["recovery_codes"] = recoveryCodes
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, dataResponese))
+}
+
+// User godoc
+// @Summary      Start SSO login
+// @Description  Find the company of the email and return the authorization URL of its OpenID Connect provider (authorization code + PKCE)
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        sso   body    dto.SsoAuthorizeRequest   true  "Data"
+// @Router       /api/auth/sso/authorize [post]
+// @Success      200   {object}  dto.ApiResponseSuccessStruct
+// @Failure      500   {object}  dto.ApiResponseFail
+func (h *UserHandler) SsoAuthorize(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	var request dto.SsoAuthorizeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	authorize, err := h.service.BeginSsoLogin(request.Email)
+	if err != nil {
+		log.Error("Happened error when start SSO login. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, authorize))
+}
+
+// User godoc
+// @Summary      Complete SSO login
+// @Description  Exchange the authorization code returned by the identity provider to the redirect URI and issue tokens. Users are provisioned on first login when the company allows it
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        sso   body    dto.SsoCallbackRequest   true  "Data"
+// @Router       /api/auth/sso/callback [post]
+// @Success      200   {object}  dto.ApiResponseSuccessStruct
+// @Failure      500   {object}  dto.ApiResponseFail
+func (h *UserHandler) SsoCallback(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	var request dto.SsoCallbackRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	userLogin, accessToken, refreshToken, err := h.service.CompleteSsoLogin(request.State, request.Code, c.ClientIP())
+	if err != nil {
+		log.Error("Happened error when complete SSO login. Error", err)
+		pkg.PanicExeption(constant.Unauthorized, err.Error())
+	}
+	dataResponese := map[string]interface{}{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"is_active":     userLogin.IsActive,
+		"roleSlug":      userLogin.Role.Slug,
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, dataResponese))
+}
+
 func (h *UserHandler) Activate(c *gin.Context) {
 	defer pkg.PanicHandler(c)
 	token, exist := c.GetQuery("token")
@@ -0,0 +1,401 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	service "BE_Manage_device/internal/service/work_orders"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type WorkOrderHandler struct {
+	service *service.WorkOrderService
+}
+
+func NewWorkOrderHandler(service *service.WorkOrderService) *WorkOrderHandler {
+	return &WorkOrderHandler{service: service}
+}
+
+// Consumable godoc
+// @Summary      Create consumable
+// @Description  Create a consumable/spare part stock item of the company
+// @Tags         WorkOrders
+// @Accept       json
+// @Produce      json
+// @Param        consumable   body    dto.CreateConsumableRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/consumables [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkOrderHandler) CreateConsumable(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.CreateConsumableRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	consumable, err := h.service.CreateConsumable(userId, request)
+	if err != nil {
+		log.Error("Happened error when create consumable. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, utils.ConvertConsumableToResponse(consumable)))
+}
+
+// Consumable godoc
+// @Summary      Get all consumables
+// @Description  Get all consumables of the company with stock on hand
+// @Tags         WorkOrders
+// @Accept       json
+// @Produce      json
+// @param Authorization header string true "Authorization"
+// @Router       /api/consumables [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkOrderHandler) GetConsumables(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	consumables, err := h.service.GetConsumables(userId)
+	if err != nil {
+		log.Error("Happened error when get consumables. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get consumables")
+	}
+	res := []dto.ConsumableResponse{}
+	for _, item := range consumables {
+		res = append(res, utils.ConvertConsumableToResponse(item))
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// Consumable godoc
+// @Summary      Adjust consumable stock
+// @Description  Add (positive delta) or remove (negative delta) stock of a consumable
+// @Tags         WorkOrders
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        request   body    dto.AdjustConsumableRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/consumables/{id}/stock [PATCH]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkOrderHandler) AdjustConsumable(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	var request dto.AdjustConsumableRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	consumable, err := h.service.AdjustConsumable(userId, id, request.Delta)
+	if err != nil {
+		log.Error("Happened error when adjust consumable stock. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertConsumableToResponse(consumable)))
+}
+
+// WorkOrder godoc
+// @Summary      Create work order
+// @Description  Create a work order for a maintenance schedule, assigned to an internal technician or an external vendor
+// @Tags         WorkOrders
+// @Accept       json
+// @Produce      json
+// @Param        workOrder   body    dto.CreateWorkOrderRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/work-orders [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkOrderHandler) Create(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.CreateWorkOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	workOrder, err := h.service.Create(userId, request)
+	if err != nil {
+		log.Error("Happened error when create work order. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, utils.ConvertWorkOrderToResponse(workOrder)))
+}
+
+// WorkOrder godoc
+// @Summary      Filter work orders
+// @Description  Filter work orders by status, schedule, asset or technician
+// @Tags         WorkOrders
+// @Accept       json
+// @Produce      json
+// @Param        filter   query    dto.WorkOrderFilterRequest   false  "Filter"
+// @param Authorization header string true "Authorization"
+// @Router       /api/work-orders [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkOrderHandler) Filter(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.WorkOrderFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	workOrders, err := h.service.Filter(userId, request)
+	if err != nil {
+		log.Error("Happened error when filter work orders. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when filter work orders")
+	}
+	res := []dto.WorkOrderResponse{}
+	for _, workOrder := range workOrders {
+		res = append(res, utils.ConvertWorkOrderToResponse(workOrder))
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// WorkOrder godoc
+// @Summary      Get work order
+// @Description  Get work order by id with tasks, parts and photos
+// @Tags         WorkOrders
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/work-orders/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkOrderHandler) GetById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	workOrder, err := h.service.GetById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get work order. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	h.respond(c, workOrder)
+}
+
+// WorkOrder godoc
+// @Summary      Update work order
+// @Description  Update work order details, technician/vendor or status (open, in_progress, cancelled)
+// @Tags         WorkOrders
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        workOrder   body    dto.UpdateWorkOrderRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/work-orders/{id} [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkOrderHandler) Update(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	var request dto.UpdateWorkOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	workOrder, err := h.service.Update(userId, id, request)
+	if err != nil {
+		log.Error("Happened error when update work order. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	h.respond(c, workOrder)
+}
+
+// WorkOrder godoc
+// @Summary      Add work order task
+// @Description  Add a checklist task to the work order
+// @Tags         WorkOrders
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        task   body    dto.CreateWorkOrderTaskRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/work-orders/{id}/tasks [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkOrderHandler) AddTask(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	var request dto.CreateWorkOrderTaskRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	workOrder, err := h.service.AddTask(userId, id, request.Description)
+	if err != nil {
+		log.Error("Happened error when add work order task. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	h.respond(c, workOrder)
+}
+
+// WorkOrder godoc
+// @Summary      Update work order task
+// @Description  Mark a work order task done or not done
+// @Tags         WorkOrders
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param		taskId	path		string				true	"taskId"
+// @Param        task   body    dto.UpdateWorkOrderTaskRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/work-orders/{id}/tasks/{taskId} [PATCH]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkOrderHandler) UpdateTask(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	taskId := h.parseId(c, "taskId")
+	var request dto.UpdateWorkOrderTaskRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	workOrder, err := h.service.UpdateTask(userId, id, taskId, *request.IsDone)
+	if err != nil {
+		log.Error("Happened error when update work order task. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	h.respond(c, workOrder)
+}
+
+// WorkOrder godoc
+// @Summary      Add work order part
+// @Description  Record a consumable used by the work order, stock is decremented
+// @Tags         WorkOrders
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        part   body    dto.AddWorkOrderPartRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/work-orders/{id}/parts [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkOrderHandler) AddPart(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	var request dto.AddWorkOrderPartRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	workOrder, err := h.service.AddPart(userId, id, request.ConsumableId, request.Quantity)
+	if err != nil {
+		log.Error("Happened error when add work order part. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	h.respond(c, workOrder)
+}
+
+// WorkOrder godoc
+// @Summary      Remove work order part
+// @Description  Remove a consumable from the work order, stock is restored
+// @Tags         WorkOrders
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param		partId	path		string				true	"partId"
+// @param Authorization header string true "Authorization"
+// @Router       /api/work-orders/{id}/parts/{partId} [DELETE]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkOrderHandler) RemovePart(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	partId := h.parseId(c, "partId")
+	workOrder, err := h.service.RemovePart(userId, id, partId)
+	if err != nil {
+		log.Error("Happened error when remove work order part. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	h.respond(c, workOrder)
+}
+
+// WorkOrder godoc
+// @Summary      Complete work order
+// @Description  Complete the work order with notes, labor hours and photos. Cost rolls up to the maintenance schedule and the asset leaves "Under Maintenance" when no open work order remains
+// @Tags         WorkOrders
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        completionNotes formData string false "Completion notes"
+// @Param        laborHours formData number false "Labor hours"
+// @Param        photos formData file false "Photos"
+// @param Authorization header string true "Authorization"
+// @Router       /api/work-orders/{id}/complete [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkOrderHandler) Complete(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c, "id")
+	var request dto.CompleteWorkOrderRequest
+	if err := c.ShouldBind(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	var photos []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		photos = form.File["photos"]
+	}
+	workOrder, err := h.service.Complete(userId, id, request.CompletionNotes, request.LaborHours, photos)
+	if err != nil {
+		log.Error("Happened error when complete work order. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	h.respond(c, workOrder)
+}
+
+func (h *WorkOrderHandler) respond(c *gin.Context, workOrder *entity.WorkOrder) {
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertWorkOrderToResponse(workOrder)))
+}
+
+func (h *WorkOrderHandler) parseId(c *gin.Context, param string) int64 {
+	id, err := strconv.ParseInt(c.Param(param), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	return id
+}
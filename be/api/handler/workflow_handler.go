@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	service "BE_Manage_device/internal/service/workflows"
+	"BE_Manage_device/pkg"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type WorkflowHandler struct {
+	service *service.WorkflowService
+}
+
+func NewWorkflowHandler(service *service.WorkflowService) *WorkflowHandler {
+	return &WorkflowHandler{service: service}
+}
+
+// Workflow godoc
+// @Summary      Create workflow definition
+// @Description  Create the approval workflow of a business action (transfer_request, asset_retirement, maintenance_schedule). Steps with the same stepOrder run in parallel, minCost applies a step only from that cost
+// @Tags         Workflows
+// @Accept       json
+// @Produce      json
+// @Param        workflow   body    dto.WorkflowDefinitionRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/workflows [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkflowHandler) CreateDefinition(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.WorkflowDefinitionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	definition, err := h.service.CreateDefinition(userId, request)
+	if err != nil {
+		log.Error("Happened error when create workflow. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusCreated, pkg.BuildReponseSuccess(http.StatusCreated, constant.Success, utils.ConvertWorkflowDefinitionToResponse(definition)))
+}
+
+// Workflow godoc
+// @Summary      Get workflow definitions
+// @Description  Get approval workflows of the company
+// @Tags         Workflows
+// @Accept       json
+// @Produce      json
+// @Param        filter   query    dto.WorkflowDefinitionFilterRequest   false  "Filter"
+// @param Authorization header string true "Authorization"
+// @Router       /api/workflows [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkflowHandler) GetDefinitions(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.WorkflowDefinitionFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	definitions, err := h.service.GetDefinitions(userId, request.EntityType)
+	if err != nil {
+		log.Error("Happened error when get workflows. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get workflows")
+	}
+	res := []dto.WorkflowDefinitionResponse{}
+	for _, definition := range definitions {
+		res = append(res, utils.ConvertWorkflowDefinitionToResponse(definition))
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// Workflow godoc
+// @Summary      Get workflow definition
+// @Description  Get approval workflow by id
+// @Tags         Workflows
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/workflows/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkflowHandler) GetDefinitionById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	definition, err := h.service.GetDefinitionById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get workflow. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertWorkflowDefinitionToResponse(definition)))
+}
+
+// Workflow godoc
+// @Summary      Update workflow definition
+// @Description  Update approval workflow and replace its steps. Pending requests keep the steps they started with
+// @Tags         Workflows
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        workflow   body    dto.WorkflowDefinitionRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/workflows/{id} [PUT]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkflowHandler) UpdateDefinition(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	var request dto.WorkflowDefinitionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	definition, err := h.service.UpdateDefinition(userId, id, request)
+	if err != nil {
+		log.Error("Happened error when update workflow. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertWorkflowDefinitionToResponse(definition)))
+}
+
+// Workflow godoc
+// @Summary      Delete workflow definition
+// @Description  Delete approval workflow. Workflows already used by requests are deactivated instead
+// @Tags         Workflows
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/workflows/{id} [DELETE]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkflowHandler) DeleteDefinition(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	if err := h.service.DeleteDefinition(userId, id); err != nil {
+		log.Error("Happened error when delete workflow. Error", err)
+		pkg.PanicExeption(constant.UnknownError, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccessNoData(http.StatusOK, constant.Success))
+}
+
+// Workflow godoc
+// @Summary      Get approval requests
+// @Description  Get approval requests visible to the user, awaitingMe=true returns only requests waiting for the user's approval
+// @Tags         Workflows
+// @Accept       json
+// @Produce      json
+// @Param        filter   query    dto.WorkflowInstanceFilterRequest   false  "Filter"
+// @param Authorization header string true "Authorization"
+// @Router       /api/approvals [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkflowHandler) GetInstances(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	var request dto.WorkflowInstanceFilterRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		log.Error("Happened error when mapping query to filter. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping query to filter")
+	}
+	instances, err := h.service.GetInstances(userId, request)
+	if err != nil {
+		log.Error("Happened error when get approval requests. Error", err)
+		pkg.PanicExeption(constant.UnknownError, "Happened error when get approval requests")
+	}
+	res := []dto.WorkflowInstanceResponse{}
+	for _, instance := range instances {
+		res = append(res, utils.ConvertWorkflowInstanceToResponse(instance))
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, res))
+}
+
+// Workflow godoc
+// @Summary      Get approval request
+// @Description  Get approval request by id with its steps and audit trail
+// @Tags         Workflows
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @param Authorization header string true "Authorization"
+// @Router       /api/approvals/{id} [GET]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkflowHandler) GetInstanceById(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	instance, err := h.service.GetInstanceById(userId, id)
+	if err != nil {
+		log.Error("Happened error when get approval request. Error", err)
+		pkg.PanicExeption(constant.DataNotFound, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertWorkflowInstanceToResponse(instance)))
+}
+
+// Workflow godoc
+// @Summary      Approve request
+// @Description  Approve the current step assigned to the user. The action is executed when the last step is approved
+// @Tags         Workflows
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        approve   body    dto.ApproveWorkflowRequest   false  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/approvals/{id}/approve [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkflowHandler) Approve(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	var request dto.ApproveWorkflowRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			log.Error("Happened error when mapping request from FE. Error", err)
+			pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+		}
+	}
+	instance, err := h.service.Approve(userId, id, request.Comment)
+	if errors.Is(err, service.ErrOwnRequest) {
+		log.Error("Happened error when approve request. Error", err)
+		pkg.PanicExeption(constant.StatusForbidden, err.Error())
+	}
+	if err != nil {
+		log.Error("Happened error when approve request. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertWorkflowInstanceToResponse(instance)))
+}
+
+// Workflow godoc
+// @Summary      Reject request
+// @Description  Reject the request with a reason
+// @Tags         Workflows
+// @Accept       json
+// @Produce      json
+// @Param		id	path		string				true	"id"
+// @Param        reject   body    dto.RejectWorkflowRequest   true  "Data"
+// @param Authorization header string true "Authorization"
+// @Router       /api/approvals/{id}/reject [POST]
+// @securityDefinitions.apiKey token
+// @in header
+// @name Authorization
+// @Security JWT
+func (h *WorkflowHandler) Reject(c *gin.Context) {
+	defer pkg.PanicHandler(c)
+	userId := utils.GetUserIdFromContext(c)
+	id := h.parseId(c)
+	var request dto.RejectWorkflowRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Happened error when mapping request from FE. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when mapping request from FE.")
+	}
+	instance, err := h.service.Reject(userId, id, request.Reason)
+	if errors.Is(err, service.ErrOwnRequest) {
+		log.Error("Happened error when reject request. Error", err)
+		pkg.PanicExeption(constant.StatusForbidden, err.Error())
+	}
+	if err != nil {
+		log.Error("Happened error when reject request. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, err.Error())
+	}
+	c.JSON(http.StatusOK, pkg.BuildReponseSuccess(http.StatusOK, constant.Success, utils.ConvertWorkflowInstanceToResponse(instance)))
+}
+
+func (h *WorkflowHandler) parseId(c *gin.Context) int64 {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Happened error when get id via path. Error", err)
+		pkg.PanicExeption(constant.InvalidRequest, "Happened error when get id via path")
+	}
+	return id
+}
@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"BE_Manage_device/constant"
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
 	repository "BE_Manage_device/internal/repository/user_session"
 
 	"BE_Manage_device/pkg"
@@ -9,6 +11,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"reflect"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -23,11 +27,40 @@ func AuthMiddleware(secretKey string, session repository.UsersSessionRepository)
 	return func(c *gin.Context) {
 		defer pkg.PanicHandler(c)
 		authHeader := c.GetHeader("Authorization")
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		// Tích hợp gửi API key của service account qua X-Api-Key hoặc Bearer
+		apiKey := c.GetHeader("X-Api-Key")
+		if apiKey == "" && strings.HasPrefix(tokenString, entity.ApiKeyPrefix) {
+			apiKey = tokenString
+		}
+		if apiKey != "" {
+			key, err := session.FindActiveApiKey(utils.HashToken(apiKey))
+			if err != nil {
+				pkg.PanicExeption(constant.Unauthorized, "Invalid, expired or revoked API key")
+				c.Abort()
+				return
+			}
+			// Không ghi lần dùng ở mọi request
+			if key.LastUsedAt == nil || time.Since(*key.LastUsedAt) > time.Minute {
+				if err := session.TouchApiKey(key.Id, time.Now()); err != nil {
+					logrus.Error("Happened error when update API key last used. Error", err)
+				}
+			}
+			// Mặc định từ chối: API key chỉ gọi được route có RequirePermission, nơi scope của key được kiểm tra
+			if !hasPermissionCheck(c) {
+				pkg.PanicExeption(constant.StatusForbidden, "API keys can't call this route")
+				c.Abort()
+				return
+			}
+			c.Set("userID", key.ServiceAccount.UserId)
+			c.Set("apiKeyId", key.Id)
+			c.Next()
+			return
+		}
 		if authHeader == "" {
 			pkg.PanicExeption(constant.Unauthorized, "Unauthorized Access Token")
 			c.Abort()
 		}
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, http.ErrAbortHandler
@@ -76,6 +109,19 @@ func AuthMiddleware(secretKey string, session repository.UsersSessionRepository)
 	}
 }
 
+// Tên hàm của các closure do RequirePermission tạo ra, ví dụ ".../middleware.RequirePermission.func1"
+var requirePermissionName = runtime.FuncForPC(reflect.ValueOf(RequirePermission).Pointer()).Name() + "."
+
+// hasPermissionCheck route hiện tại có RequirePermission trong chuỗi handler
+func hasPermissionCheck(c *gin.Context) bool {
+	for _, name := range c.HandlerNames() {
+		if strings.HasPrefix(name, requirePermissionName) {
+			return true
+		}
+	}
+	return false
+}
+
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
@@ -115,7 +161,12 @@ func RequirePermission(permSlug []string, accessLevel []string, db *gorm.DB) gin
 			c.Abort()
 			return
 		}
-		ok, err := utils.UserHasPermission(db, userIdConvert, permSlug, accessLevel)
+		var ok bool
+		if apiKeyId, isApiKey := c.Get("apiKeyId"); isApiKey {
+			ok, err = utils.ApiKeyHasPermission(db, apiKeyId.(int64), userIdConvert, permSlug, accessLevel)
+		} else {
+			ok, err = utils.UserHasPermission(db, userIdConvert, permSlug, accessLevel)
+		}
 		if err != nil {
 			pkg.PanicExeption(constant.UnknownError, "Internal server error")
 			c.Abort()
@@ -130,6 +181,34 @@ func RequirePermission(permSlug []string, accessLevel []string, db *gorm.DB) gin
 	}
 }
 
+// ScimAuthMiddleware xác thực IdP bằng bearer token SCIM của công ty, lỗi trả về theo định dạng SCIM
+func ScimAuthMiddleware(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		tokenString, ok := strings.CutPrefix(authHeader, "Bearer ")
+		if !ok || tokenString == "" {
+			abortScim(c, "Missing bearer token")
+			return
+		}
+		token, err := utils.FindScimToken(db, strings.TrimSpace(tokenString))
+		if err != nil {
+			abortScim(c, "Invalid or revoked token")
+			return
+		}
+		c.Set("scimToken", token)
+		c.Next()
+	}
+}
+
+func abortScim(c *gin.Context, detail string) {
+	c.Header("Content-Type", dto.ScimContentType)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ScimErrorResponse{
+		Schemas: []string{dto.ScimErrorSchema},
+		Status:  strconv.Itoa(http.StatusUnauthorized),
+		Detail:  detail,
+	})
+}
+
 func TimeoutMiddleware(d time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"BE_Manage_device/internal/domain/mocks"
+	"BE_Manage_device/pkg/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testApiKey = entity.ApiKeyPrefix + "test"
+
+// scopeProbe ghi lại route có RequirePermission hay không rồi dừng, không cần DB
+func scopeProbe(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"userId": c.GetInt64("userID"), "hasPermissionCheck": hasPermissionCheck(c)})
+	c.Abort()
+}
+
+func newApiKeyRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	session := mocks.NewUsersSessionRepository(t)
+	lastUsed := time.Now()
+	session.On("FindActiveApiKey", utils.HashToken(testApiKey)).Return(&entity.ApiKey{
+		Id:             1,
+		LastUsedAt:     &lastUsed,
+		ServiceAccount: entity.ServiceAccount{UserId: 42},
+	}, nil).Maybe()
+	session.On("FindActiveApiKey", mock.Anything).Return(nil, assert.AnError).Maybe()
+
+	r := gin.New()
+	api := r.Group("/api")
+	api.Use(AuthMiddleware("secret", session))
+	api.GET("/unscoped", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	api.GET("/scoped", scopeProbe, RequirePermission([]string{"manage-assets"}, nil, nil))
+	return r
+}
+
+func TestAuthMiddlewareApiKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		header     string
+		value      string
+		wantStatus int
+	}{
+		{name: "route without permission check", path: "/api/unscoped", header: "X-Api-Key", value: testApiKey, wantStatus: http.StatusForbidden},
+		{name: "bearer key on route without permission check", path: "/api/unscoped", header: "Authorization", value: "Bearer " + testApiKey, wantStatus: http.StatusForbidden},
+		{name: "route with permission check", path: "/api/scoped", header: "X-Api-Key", value: testApiKey, wantStatus: http.StatusOK},
+		{name: "bearer key on route with permission check", path: "/api/scoped", header: "Authorization", value: "Bearer " + testApiKey, wantStatus: http.StatusOK},
+		{name: "unknown key", path: "/api/scoped", header: "X-Api-Key", value: entity.ApiKeyPrefix + "unknown", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newApiKeyRouter(t)
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set(tt.header, tt.value)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.JSONEq(t, `{"userId": 42, "hasPermissionCheck": true}`, w.Body.String())
+			}
+		})
+	}
+}
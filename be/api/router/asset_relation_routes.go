@@ -0,0 +1,19 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerAssetRelationRoutes(api *gin.RouterGroup, h *handler.AssetRelationHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/assets/:id/relations", middleware.RequirePermission([]string{"manage-assets"}, []string{"full", "limited"}, db), h.Create)
+	api.GET("/assets/:id/graph", h.GetGraph)
+	api.DELETE("/asset-relations/:id", middleware.RequirePermission([]string{"manage-assets"}, []string{"full", "limited"}, db), h.Delete)
+}
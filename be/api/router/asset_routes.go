@@ -23,5 +23,8 @@ func registerAssetsRoutes(api *gin.RouterGroup, h *handler.AssetsHandler, sessio
 	api.GET("/assets/filter-dashboard", middleware.RequirePermission([]string{"dashboards"}, []string{"full", "scoped"}, db), h.FilterAssetDashboard) // đã check
 	api.GET("/assets/request-transfer", h.GetAssetsByCateOfDepartment)
 	api.GET("/assets/maintenance-schedules", h.GetAllAssetNotHaveMaintenance)
+	api.POST("/assets/:id/clone", middleware.RequirePermission([]string{"manage-assets"}, []string{"full", "limited"}, db), h.Clone)
+	api.GET("/assets/:id/dependents", h.GetDependentAssets)
+	api.PATCH("/assets/:id/room", middleware.RequirePermission([]string{"manage-assets"}, []string{"full", "limited"}, db), h.MoveRoom)
 
 }
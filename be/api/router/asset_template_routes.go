@@ -0,0 +1,22 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerAssetTemplateRoutes(api *gin.RouterGroup, h *handler.AssetTemplateHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/asset-templates", middleware.RequirePermission([]string{"manage-assets"}, []string{"full", "limited"}, db), h.Create)
+	api.GET("/asset-templates", h.GetAll)
+	api.GET("/asset-templates/:id", h.GetById)
+	api.PUT("/asset-templates/:id", middleware.RequirePermission([]string{"manage-assets"}, []string{"full", "limited"}, db), h.Update)
+	api.DELETE("/asset-templates/:id", middleware.RequirePermission([]string{"manage-assets"}, []string{"full", "limited"}, db), h.Delete)
+	api.POST("/asset-templates/:id/assets", middleware.RequirePermission([]string{"manage-assets"}, []string{"full", "limited"}, db), h.CreateAssets)
+}
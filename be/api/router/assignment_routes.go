@@ -14,8 +14,11 @@ func registerAssignmentRoutes(api *gin.RouterGroup, h *handler.AssignmentHandler
 	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
 
 	api.POST("/assignments", middleware.RequirePermission([]string{"assign-assets"}, nil, db), h.Create)
+	api.PUT("/assignments/bulk", middleware.RequirePermission([]string{"assign-assets"}, []string{"full", "conditional"}, db), h.BulkUpdate)
 	api.PUT("/assignments/:id", middleware.RequirePermission([]string{"assign-assets"}, []string{"full", "conditional"}, db), h.Update)              // đã check
 	api.GET("/assignments/filter", middleware.RequirePermission([]string{"assign-assets"}, []string{"full", "conditional"}, db), h.FilterAssignment) // đã check
-	api.GET("/assignments/:id", middleware.RequirePermission([]string{"assign-assets"}, []string{"full", "conditional"}, db), h.GetAssignmentById)   // đã check
+	api.GET("/assignments/history/assets/:id", middleware.RequirePermission([]string{"assign-assets"}, []string{"full", "conditional"}, db), h.GetAssetHistories)
+	api.GET("/assignments/history/users/:id", middleware.RequirePermission([]string{"assign-assets"}, []string{"full", "conditional"}, db), h.GetUserHistories)
+	api.GET("/assignments/:id", middleware.RequirePermission([]string{"assign-assets"}, []string{"full", "conditional"}, db), h.GetAssignmentById) // đã check
 
 }
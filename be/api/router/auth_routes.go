@@ -9,6 +9,10 @@ import (
 func registerAuthRoutes(api *gin.RouterGroup, h *handler.UserHandler, sse *handler.SSEHandler) {
 	api.POST("/auth/register", h.Register)
 	api.POST("/auth/login", h.Login)
+	api.POST("/auth/login/two-factor/setup", h.SetupTwoFactorLogin)
+	api.POST("/auth/login/two-factor/verify", h.VerifyTwoFactorLogin)
+	api.POST("/auth/sso/authorize", h.SsoAuthorize)
+	api.POST("/auth/sso/callback", h.SsoCallback)
 	api.POST("/auth/refresh", h.Refresh)
 	api.GET("/activate", h.Activate)
 	api.POST("/user/forget-password", h.CheckPasswordReset)
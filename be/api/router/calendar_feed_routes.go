@@ -0,0 +1,23 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ứng dụng lịch không gửi được header Authorization nên feed xác thực bằng token trong url
+func registerCalendarFeedPublicRoutes(api *gin.RouterGroup, h *handler.CalendarFeedHandler) {
+	api.GET("/calendar-feeds/ics/:token", h.GetICS)
+}
+
+func registerCalendarFeedRoutes(api *gin.RouterGroup, h *handler.CalendarFeedHandler, session repository.UsersSessionRepository) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/calendar-feeds", h.Create)
+	api.GET("/calendar-feeds", h.GetAll)
+	api.DELETE("/calendar-feeds/:id", h.Revoke)
+}
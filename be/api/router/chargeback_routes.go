@@ -0,0 +1,20 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerChargebackRoutes(api *gin.RouterGroup, h *handler.ChargebackHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/chargebacks/compute", middleware.RequirePermission([]string{"depreciation"}, nil, db), h.Compute)
+	api.GET("/chargebacks", middleware.RequirePermission([]string{"dashboards"}, []string{"full", "scoped"}, db), h.GetByPeriod)
+	api.GET("/chargebacks/lines", middleware.RequirePermission([]string{"dashboards"}, []string{"full", "scoped"}, db), h.GetLines)
+	api.GET("/chargebacks/export", middleware.RequirePermission([]string{"dashboards"}, []string{"full", "scoped"}, db), h.Export)
+}
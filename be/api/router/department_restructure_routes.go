@@ -0,0 +1,21 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerDepartmentRestructureRoutes(api *gin.RouterGroup, h *handler.DepartmentRestructureHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/departments/restructures", middleware.RequirePermission([]string{"manage-taxonomy"}, nil, db), h.Create)
+	api.GET("/departments/restructures", middleware.RequirePermission([]string{"manage-taxonomy"}, nil, db), h.GetAll)
+	api.GET("/departments/restructures/:id", middleware.RequirePermission([]string{"manage-taxonomy"}, nil, db), h.GetById)
+	api.POST("/departments/restructures/:id/apply", middleware.RequirePermission([]string{"manage-taxonomy"}, nil, db), h.Apply)
+	api.POST("/departments/restructures/:id/undo", middleware.RequirePermission([]string{"manage-taxonomy"}, nil, db), h.Undo)
+}
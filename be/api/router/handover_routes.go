@@ -0,0 +1,20 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerHandoverRoutes(api *gin.RouterGroup, h *handler.HandoverHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.GET("/handovers/mine", h.GetMine)
+	api.GET("/handovers", middleware.RequirePermission([]string{"assign-assets"}, []string{"full", "conditional"}, db), h.GetAll)
+	api.GET("/handovers/:id", h.GetById)
+	api.POST("/handovers/:id/accept", h.Accept)
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerInspectionRoutes(api *gin.RouterGroup, h *handler.InspectionHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/inspection-templates", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.CreateTemplate)
+	api.GET("/inspection-templates", middleware.RequirePermission([]string{"maintenance-logs"}, []string{"full", "view"}, db), h.GetTemplates)
+	api.GET("/inspection-templates/:id", middleware.RequirePermission([]string{"maintenance-logs"}, []string{"full", "view"}, db), h.GetTemplateById)
+	api.PUT("/inspection-templates/:id", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.UpdateTemplate)
+	api.DELETE("/inspection-templates/:id", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.DeleteTemplate)
+
+	api.POST("/assets/:id/inspections", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.Create)
+	api.GET("/assets/:id/inspections", middleware.RequirePermission([]string{"maintenance-logs"}, []string{"full", "view"}, db), h.GetByAsset)
+	api.GET("/inspections/overdue", middleware.RequirePermission([]string{"maintenance-logs"}, []string{"full", "view"}, db), h.GetOverdue)
+	api.GET("/inspections/:id", middleware.RequirePermission([]string{"maintenance-logs"}, []string{"full", "view"}, db), h.GetById)
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerIssueTicketRoutes(api *gin.RouterGroup, h *handler.IssueTicketHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	// Người giữ tài sản/người quét QR: quyền xem ticket được kiểm tra trong service
+	api.POST("/assets/:id/issue-tickets", h.Create)
+	api.GET("/issue-tickets", h.Filter)
+	api.GET("/issue-tickets/:id", h.GetById)
+	api.POST("/issue-tickets/:id/comments", h.AddComment)
+
+	api.PATCH("/issue-tickets/:id/triage", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.Triage)
+	api.POST("/issue-tickets/:id/convert", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.Convert)
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerLdapRoutes(api *gin.RouterGroup, h *handler.LdapHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.GET("/company/ldap", middleware.RequirePermission([]string{"system-settings"}, nil, db), h.GetConfig)
+	api.PUT("/company/ldap", middleware.RequirePermission([]string{"system-settings"}, nil, db), h.SaveConfig)
+	api.POST("/company/ldap/sync", middleware.RequirePermission([]string{"system-settings"}, nil, db), h.Sync)
+	api.GET("/company/ldap/sync-reports", middleware.RequirePermission([]string{"system-settings"}, nil, db), h.GetReports)
+	api.GET("/company/ldap/sync-reports/:id", middleware.RequirePermission([]string{"system-settings"}, nil, db), h.GetReportById)
+}
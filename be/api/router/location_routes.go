@@ -13,7 +13,10 @@ import (
 func registerLocationsRoutes(api *gin.RouterGroup, h *handler.LocationHandler, session repository.UsersSessionRepository, db *gorm.DB) {
 	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
 
-	api.POST("/locations", middleware.RequirePermission([]string{"manage-taxonomy"}, nil, db), h.Create)       // đã check
-	api.GET("/locations", h.GetAll)                                                                            // đã check
+	api.POST("/locations", middleware.RequirePermission([]string{"manage-taxonomy"}, nil, db), h.Create) // đã check
+	api.GET("/locations", h.GetAll)                                                                      // đã check
+	api.GET("/locations/tree", h.GetTree)
+	api.GET("/locations/dashboard", middleware.RequirePermission([]string{"dashboards"}, []string{"full", "scoped"}, db), h.Dashboard)
+	api.PUT("/locations/:id", middleware.RequirePermission([]string{"manage-taxonomy"}, nil, db), h.Update)
 	api.DELETE("/locations/:id", middleware.RequirePermission([]string{"manage-taxonomy"}, nil, db), h.Delete) // đã check
 }
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerMaintenancePlanRoutes(api *gin.RouterGroup, h *handler.MaintenancePlanHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/maintenance-plans", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.Create)
+	api.GET("/maintenance-plans", middleware.RequirePermission([]string{"maintenance-logs"}, []string{"full", "view"}, db), h.GetAll)
+	api.GET("/maintenance-plans/:id", middleware.RequirePermission([]string{"maintenance-logs"}, []string{"full", "view"}, db), h.GetById)
+	api.PUT("/maintenance-plans/:id", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.Update)
+	api.DELETE("/maintenance-plans/:id", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.Delete)
+	api.POST("/maintenance-plans/:id/generate", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.Generate)
+}
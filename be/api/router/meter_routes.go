@@ -0,0 +1,27 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerMeterRoutes(api *gin.RouterGroup, h *handler.MeterHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/meters", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.CreateMeter)
+	api.GET("/meters", middleware.RequirePermission([]string{"maintenance-logs"}, []string{"full", "view"}, db), h.GetMeters)
+	api.GET("/meters/:id", middleware.RequirePermission([]string{"maintenance-logs"}, []string{"full", "view"}, db), h.GetMeterById)
+	api.PUT("/meters/:id", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.UpdateMeter)
+	api.POST("/meters/:id/thresholds", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.AddThreshold)
+	api.PUT("/meter-thresholds/:id", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.UpdateThreshold)
+	api.DELETE("/meter-thresholds/:id", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.DeleteThreshold)
+
+	// Người giữ tài sản cũng được ghi chỉ số: quyền được kiểm tra trong service
+	api.POST("/assets/:id/meter-readings", h.CreateReading)
+	api.GET("/assets/:id/meter-readings", h.GetReadings)
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerOffboardingRoutes(api *gin.RouterGroup, h *handler.OffboardingHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/offboardings", middleware.RequirePermission([]string{"user-management"}, nil, db), h.Start)
+	api.GET("/offboardings", middleware.RequirePermission([]string{"user-management"}, nil, db), h.GetAll)
+	api.GET("/offboardings/:id", middleware.RequirePermission([]string{"user-management"}, nil, db), h.GetById)
+	api.PATCH("/offboardings/:id/items/:itemId", middleware.RequirePermission([]string{"user-management"}, nil, db), h.UpdateItem)
+	api.POST("/offboardings/:id/complete", middleware.RequirePermission([]string{"user-management"}, nil, db), h.Complete)
+	api.POST("/offboardings/:id/cancel", middleware.RequirePermission([]string{"user-management"}, nil, db), h.Cancel)
+	api.GET("/offboardings/:id/pdf", middleware.RequirePermission([]string{"user-management"}, nil, db), h.ExportPDF)
+}
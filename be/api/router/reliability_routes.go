@@ -0,0 +1,18 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerReliabilityRoutes(api *gin.RouterGroup, h *handler.ReliabilityHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.GET("/reliability/report", middleware.RequirePermission([]string{"dashboards"}, []string{"full", "scoped"}, db), h.GetReport)
+	api.GET("/assets/:id/reliability", middleware.RequirePermission([]string{"dashboards"}, []string{"full", "scoped"}, db), h.GetAssetReliability)
+}
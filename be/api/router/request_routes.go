@@ -16,7 +16,11 @@ func registerRequestTransferRoutes(api *gin.RouterGroup, h *handler.RequestTrans
 	api.POST("/request-transfer", middleware.RequirePermission([]string{"transfer-assets"}, []string{"full", "can-request"}, db), h.Create) // đã check
 	api.PATCH("/request-transfer/confirm/:id", middleware.RequirePermission([]string{"transfer-assets"}, nil, db), h.Accept)                // đã check
 	api.PATCH("/request-transfer/deny/:id", middleware.RequirePermission([]string{"transfer-assets"}, nil, db), h.Deny)                     // đã check
-	api.GET("/request-transfer/:id", middleware.RequirePermission([]string{"transfer-assets"}, nil, db), h.GetRequestTransferById)          // đã check
-	api.GET("/request-transfer/filter", middleware.RequirePermission([]string{"transfer-assets"}, nil, db), h.FilterRequestTransfer)        // đã check
+	api.PATCH("/request-transfer/cancel/:id", middleware.RequirePermission([]string{"transfer-assets"}, []string{"full", "can-request"}, db), h.Cancel)
+	api.GET("/request-transfer/mine", middleware.RequirePermission([]string{"transfer-assets"}, []string{"full", "can-request"}, db), h.GetMine)
+	api.GET("/request-transfer/:id", middleware.RequirePermission([]string{"transfer-assets"}, []string{"full", "can-request"}, db), h.GetRequestTransferById) // Người tạo hoặc quyền full, check trong service
+	api.POST("/request-transfer/:id/comments", middleware.RequirePermission([]string{"transfer-assets"}, []string{"full", "can-request"}, db), h.AddComment)
+	api.GET("/request-transfer/:id/comments", middleware.RequirePermission([]string{"transfer-assets"}, []string{"full", "can-request"}, db), h.GetComments)
+	api.GET("/request-transfer/filter", middleware.RequirePermission([]string{"transfer-assets"}, nil, db), h.FilterRequestTransfer) // đã check
 
 }
@@ -11,13 +11,15 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(r *gin.Engine, userHandler *handler.UserHandler, LocationHandler *handler.LocationHandler, CategoriesHandler *handler.CategoriesHandler, DepartmentsHandler *handler.DepartmentsHandler, AssetsHandler *handler.AssetsHandler, RoleHandler *handler.RoleHandler, AssignmentHandler *handler.AssignmentHandler, AssetLogHandler *handler.AssetLogHandler, RequestTransferHandler *handler.RequestTransferHandler, MaintenanceSchedulesHandler *handler.MaintenanceSchedulesHandler, SSEHandler *handler.SSEHandler, NotificationHandler *handler.NotificationHandler, CronJobTestHandler *handler.CronJobTestHandler, CompanyHandler *handler.CompanyHandler, BillsHandler *handler.BillsHandler, MonthlySummaryHandler *handler.MonthlySummaryHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+func SetupRoutes(r *gin.Engine, userHandler *handler.UserHandler, LocationHandler *handler.LocationHandler, CategoriesHandler *handler.CategoriesHandler, DepartmentsHandler *handler.DepartmentsHandler, AssetsHandler *handler.AssetsHandler, RoleHandler *handler.RoleHandler, AssignmentHandler *handler.AssignmentHandler, AssetLogHandler *handler.AssetLogHandler, RequestTransferHandler *handler.RequestTransferHandler, MaintenanceSchedulesHandler *handler.MaintenanceSchedulesHandler, SSEHandler *handler.SSEHandler, NotificationHandler *handler.NotificationHandler, CronJobTestHandler *handler.CronJobTestHandler, CompanyHandler *handler.CompanyHandler, BillsHandler *handler.BillsHandler, MonthlySummaryHandler *handler.MonthlySummaryHandler, AssetTemplateHandler *handler.AssetTemplateHandler, TagHandler *handler.TagHandler, AssetRelationHandler *handler.AssetRelationHandler, ChargebackHandler *handler.ChargebackHandler, TcoHandler *handler.TcoHandler, MaintenancePlanHandler *handler.MaintenancePlanHandler, WorkOrderHandler *handler.WorkOrderHandler, CalendarFeedHandler *handler.CalendarFeedHandler, ReliabilityHandler *handler.ReliabilityHandler, IssueTicketHandler *handler.IssueTicketHandler, InspectionHandler *handler.InspectionHandler, MeterHandler *handler.MeterHandler, WorkflowHandler *handler.WorkflowHandler, OffboardingHandler *handler.OffboardingHandler, HandoverHandler *handler.HandoverHandler, DepartmentRestructureHandler *handler.DepartmentRestructureHandler, TwoFactorHandler *handler.TwoFactorHandler, SsoHandler *handler.SsoHandler, LdapHandler *handler.LdapHandler, ScimHandler *handler.ScimHandler, ServiceAccountHandler *handler.ServiceAccountHandler, session repository.UsersSessionRepository, db *gorm.DB) {
 	//users
 	r.Use(middleware.CORSMiddleware())
 	r.Use(middleware.TimeoutMiddleware(5 * time.Second))
 	api := r.Group("/api")
+	registerScimProvisioningRoutes(r.Group("/scim/v2"), ScimHandler, db)
 	registerCronJobTestRoutes(api, CronJobTestHandler)
 	registerAuthRoutes(api, userHandler, SSEHandler)
+	registerCalendarFeedPublicRoutes(api, CalendarFeedHandler)
 	registerUserRoutes(api, userHandler, session, db)
 	registerLocationsRoutes(api, LocationHandler, session, db)
 	registerCategoriesRoutes(api, CategoriesHandler, session, db)
@@ -33,4 +35,25 @@ func SetupRoutes(r *gin.Engine, userHandler *handler.UserHandler, LocationHandle
 	registerCompanyRoutes(api, CompanyHandler, session, db)
 	registerBillsRoutes(api, BillsHandler, session, db)
 	registerMonthlySummaryRoutes(api, MonthlySummaryHandler, session, db)
+	registerAssetTemplateRoutes(api, AssetTemplateHandler, session, db)
+	registerTagRoutes(api, TagHandler, session, db)
+	registerAssetRelationRoutes(api, AssetRelationHandler, session, db)
+	registerChargebackRoutes(api, ChargebackHandler, session, db)
+	registerTcoRoutes(api, TcoHandler, session, db)
+	registerMaintenancePlanRoutes(api, MaintenancePlanHandler, session, db)
+	registerWorkOrderRoutes(api, WorkOrderHandler, session, db)
+	registerCalendarFeedRoutes(api, CalendarFeedHandler, session)
+	registerReliabilityRoutes(api, ReliabilityHandler, session, db)
+	registerIssueTicketRoutes(api, IssueTicketHandler, session, db)
+	registerInspectionRoutes(api, InspectionHandler, session, db)
+	registerMeterRoutes(api, MeterHandler, session, db)
+	registerWorkflowRoutes(api, WorkflowHandler, session, db)
+	registerOffboardingRoutes(api, OffboardingHandler, session, db)
+	registerHandoverRoutes(api, HandoverHandler, session, db)
+	registerDepartmentRestructureRoutes(api, DepartmentRestructureHandler, session, db)
+	registerTwoFactorRoutes(api, TwoFactorHandler, session, db)
+	registerSsoRoutes(api, SsoHandler, session, db)
+	registerLdapRoutes(api, LdapHandler, session, db)
+	registerScimRoutes(api, ScimHandler, session, db)
+	registerServiceAccountRoutes(api, ServiceAccountHandler, session, db)
 }
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// IdP gọi /scim/v2 bằng token SCIM của công ty, không dùng JWT của người dùng
+func registerScimProvisioningRoutes(scim *gin.RouterGroup, h *handler.ScimHandler, db *gorm.DB) {
+	scim.Use(middleware.ScimAuthMiddleware(db))
+
+	scim.GET("/ServiceProviderConfig", h.ServiceProviderConfig)
+	scim.GET("/Users", h.ListUsers)
+	scim.POST("/Users", h.CreateUser)
+	scim.GET("/Users/:id", h.GetUser)
+	scim.PUT("/Users/:id", h.ReplaceUser)
+	scim.PATCH("/Users/:id", h.PatchUser)
+	scim.DELETE("/Users/:id", h.DeleteUser)
+	scim.GET("/Groups", h.ListGroups)
+	scim.POST("/Groups", h.CreateGroup)
+	scim.GET("/Groups/:id", h.GetGroup)
+	scim.PUT("/Groups/:id", h.ReplaceGroup)
+	scim.PATCH("/Groups/:id", h.PatchGroup)
+	scim.DELETE("/Groups/:id", h.DeleteGroup)
+}
+
+func registerScimRoutes(api *gin.RouterGroup, h *handler.ScimHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/company/scim/tokens", middleware.RequirePermission([]string{"system-settings"}, nil, db), h.CreateToken)
+	api.GET("/company/scim/tokens", middleware.RequirePermission([]string{"system-settings"}, nil, db), h.GetTokens)
+	api.DELETE("/company/scim/tokens/:id", middleware.RequirePermission([]string{"system-settings"}, nil, db), h.RevokeToken)
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerServiceAccountRoutes(api *gin.RouterGroup, h *handler.ServiceAccountHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/service-accounts", middleware.RequirePermission([]string{"integrations"}, nil, db), h.Create)
+	api.GET("/service-accounts", middleware.RequirePermission([]string{"integrations"}, nil, db), h.GetAll)
+	api.GET("/service-accounts/:id", middleware.RequirePermission([]string{"integrations"}, nil, db), h.GetById)
+	api.DELETE("/service-accounts/:id", middleware.RequirePermission([]string{"integrations"}, nil, db), h.Disable)
+	api.POST("/service-accounts/:id/keys", middleware.RequirePermission([]string{"integrations"}, nil, db), h.CreateKey)
+	api.GET("/service-accounts/:id/keys", middleware.RequirePermission([]string{"integrations"}, nil, db), h.GetKeys)
+	api.DELETE("/service-accounts/:id/keys/:key_id", middleware.RequirePermission([]string{"integrations"}, nil, db), h.RevokeKey)
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerSsoRoutes(api *gin.RouterGroup, h *handler.SsoHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.GET("/company/sso", middleware.RequirePermission([]string{"system-settings"}, nil, db), h.GetConfig)
+	api.PUT("/company/sso", middleware.RequirePermission([]string{"system-settings"}, nil, db), h.SaveConfig)
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerTagRoutes(api *gin.RouterGroup, h *handler.TagHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/tags", middleware.RequirePermission([]string{"manage-assets"}, []string{"full", "limited"}, db), h.Create)
+	api.GET("/tags", h.Search)
+	api.DELETE("/tags/:id", middleware.RequirePermission([]string{"manage-assets"}, nil, db), h.Delete)
+	api.PUT("/assets/:id/tags", middleware.RequirePermission([]string{"manage-assets"}, []string{"full", "limited"}, db), h.SetAssetTags)
+	api.POST("/assets/tags/bulk", middleware.RequirePermission([]string{"manage-assets"}, []string{"full", "limited"}, db), h.BulkUpdate)
+}
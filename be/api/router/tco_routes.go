@@ -0,0 +1,20 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerTcoRoutes(api *gin.RouterGroup, h *handler.TcoHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.GET("/assets/:id/tco", middleware.RequirePermission([]string{"dashboards"}, []string{"full", "scoped"}, db), h.GetAssetTco)
+	api.GET("/tco/assets", middleware.RequirePermission([]string{"dashboards"}, []string{"full", "scoped"}, db), h.GetAssets)
+	api.GET("/tco/categories", middleware.RequirePermission([]string{"dashboards"}, []string{"full", "scoped"}, db), h.GetByCategory)
+	api.GET("/tco/departments", middleware.RequirePermission([]string{"dashboards"}, []string{"full", "scoped"}, db), h.GetByDepartment)
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerTwoFactorRoutes(api *gin.RouterGroup, h *handler.TwoFactorHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.GET("/user/two-factor", h.Status)
+	api.POST("/user/two-factor/enroll", h.BeginEnrollment)
+	api.POST("/user/two-factor/enroll/confirm", h.ConfirmEnrollment)
+	api.POST("/user/two-factor/disable", h.Disable)
+	api.POST("/user/two-factor/recovery-codes", h.RegenerateRecoveryCodes)
+	api.POST("/user/two-factor/reset/:user_id", middleware.RequirePermission([]string{"user-management"}, nil, db), h.Reset)
+	api.PUT("/company/two-factor", middleware.RequirePermission([]string{"system-settings"}, nil, db), h.SetEnforcement)
+	api.GET("/audit-logs", middleware.RequirePermission([]string{"audit-logs"}, []string{"full", "partial"}, db), h.GetAuditLogs)
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerWorkOrderRoutes(api *gin.RouterGroup, h *handler.WorkOrderHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/consumables", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.CreateConsumable)
+	api.GET("/consumables", middleware.RequirePermission([]string{"maintenance-logs"}, []string{"full", "view"}, db), h.GetConsumables)
+	api.PATCH("/consumables/:id/stock", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.AdjustConsumable)
+
+	api.POST("/work-orders", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.Create)
+	api.GET("/work-orders", middleware.RequirePermission([]string{"maintenance-logs"}, []string{"full", "view"}, db), h.Filter)
+	api.GET("/work-orders/:id", middleware.RequirePermission([]string{"maintenance-logs"}, []string{"full", "view"}, db), h.GetById)
+	api.PUT("/work-orders/:id", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.Update)
+	api.POST("/work-orders/:id/tasks", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.AddTask)
+	api.PATCH("/work-orders/:id/tasks/:taskId", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.UpdateTask)
+	api.POST("/work-orders/:id/parts", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.AddPart)
+	api.DELETE("/work-orders/:id/parts/:partId", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.RemovePart)
+	api.POST("/work-orders/:id/complete", middleware.RequirePermission([]string{"maintenance-logs"}, nil, db), h.Complete)
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"BE_Manage_device/api/handler"
+	"BE_Manage_device/api/middleware"
+	"BE_Manage_device/config"
+	repository "BE_Manage_device/internal/repository/user_session"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func registerWorkflowRoutes(api *gin.RouterGroup, h *handler.WorkflowHandler, session repository.UsersSessionRepository, db *gorm.DB) {
+	api.Use(middleware.AuthMiddleware(config.AccessSecret, session))
+
+	api.POST("/workflows", middleware.RequirePermission([]string{"user-management"}, nil, db), h.CreateDefinition)
+	api.GET("/workflows", middleware.RequirePermission([]string{"user-management"}, nil, db), h.GetDefinitions)
+	api.GET("/workflows/:id", middleware.RequirePermission([]string{"user-management"}, nil, db), h.GetDefinitionById)
+	api.PUT("/workflows/:id", middleware.RequirePermission([]string{"user-management"}, nil, db), h.UpdateDefinition)
+	api.DELETE("/workflows/:id", middleware.RequirePermission([]string{"user-management"}, nil, db), h.DeleteDefinition)
+
+	// Người duyệt được xác định theo từng bước: quyền được kiểm tra trong service
+	api.GET("/approvals", h.GetInstances)
+	api.GET("/approvals/:id", h.GetInstanceById)
+	api.POST("/approvals/:id/approve", h.Approve)
+	api.POST("/approvals/:id/reject", h.Reject)
+}
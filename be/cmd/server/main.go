@@ -34,7 +34,7 @@ func main() {
 	//SSE
 	SSeHandler := handler.NewSSEHandler(services.Notification)
 	//Assets
-	assetsHandler := handler.NewAssetsHandler(services.Assets)
+	assetsHandler := handler.NewAssetsHandler(services.Assets, services.Reliability)
 	//Role
 	roleHandler := handler.NewRoleHandler(services.Role)
 	//Assignment
@@ -55,6 +55,48 @@ func main() {
 	billHandler := handler.NewBillHandler(services.Bill)
 	//MonthlySummaryHandler
 	monthlySummaryHandler := handler.NewMonthlySummry(services.MonthlySummary)
+	//AssetTemplateHandler
+	assetTemplateHandler := handler.NewAssetTemplateHandler(services.AssetTemplate)
+	//TagHandler
+	tagHandler := handler.NewTagHandler(services.Tag)
+	//AssetRelationHandler
+	assetRelationHandler := handler.NewAssetRelationHandler(services.AssetRelation)
+	//ChargebackHandler
+	chargebackHandler := handler.NewChargebackHandler(services.Chargeback)
+	//TcoHandler
+	tcoHandler := handler.NewTcoHandler(services.Tco)
+	//MaintenancePlanHandler
+	maintenancePlanHandler := handler.NewMaintenancePlanHandler(services.MaintenancePlan)
+	//WorkOrderHandler
+	workOrderHandler := handler.NewWorkOrderHandler(services.WorkOrder)
+	//CalendarFeedHandler
+	calendarFeedHandler := handler.NewCalendarFeedHandler(services.CalendarFeed)
+	//ReliabilityHandler
+	reliabilityHandler := handler.NewReliabilityHandler(services.Reliability)
+	//IssueTicketHandler
+	issueTicketHandler := handler.NewIssueTicketHandler(services.IssueTicket)
+	//InspectionHandler
+	inspectionHandler := handler.NewInspectionHandler(services.Inspection)
+	//MeterHandler
+	meterHandler := handler.NewMeterHandler(services.Meter)
+	//WorkflowHandler
+	workflowHandler := handler.NewWorkflowHandler(services.Workflow)
+	//OffboardingHandler
+	offboardingHandler := handler.NewOffboardingHandler(services.Offboarding)
+	//HandoverHandler
+	handoverHandler := handler.NewHandoverHandler(services.Handover)
+	//DepartmentRestructureHandler
+	departmentRestructureHandler := handler.NewDepartmentRestructureHandler(services.DepartmentRestructure)
+	//TwoFactorHandler
+	twoFactorHandler := handler.NewTwoFactorHandler(services.TwoFactor)
+	//SsoHandler
+	ssoHandler := handler.NewSsoHandler(services.Sso)
+	//LdapHandler
+	ldapHandler := handler.NewLdapHandler(services.Ldap)
+	//ScimHandler
+	scimHandler := handler.NewScimHandler(services.Scim)
+	//ServiceAccountHandler
+	serviceAccountHandler := handler.NewServiceAccountHandler(services.ServiceAccount)
 	docs.SwaggerInfo.Title = "API Tool device manage"
 	docs.SwaggerInfo.Description = "App Tool device manage"
 	docs.SwaggerInfo.Version = "1.0"
@@ -64,10 +106,10 @@ func main() {
 
 	r := gin.Default()
 	pprof.Register(r)
-	api.SetupRoutes(r, userHandler, locationHandler, categoriesHandler, departmentHandler, assetsHandler, roleHandler, assignmentHandler, assetLogHandler, requestTransferHandler, maintenanceHandler, SSeHandler, notificationsHandler, cronJobTestHandler, companyHandler, billHandler, monthlySummaryHandler, repos.UserSession, db)
+	api.SetupRoutes(r, userHandler, locationHandler, categoriesHandler, departmentHandler, assetsHandler, roleHandler, assignmentHandler, assetLogHandler, requestTransferHandler, maintenanceHandler, SSeHandler, notificationsHandler, cronJobTestHandler, companyHandler, billHandler, monthlySummaryHandler, assetTemplateHandler, tagHandler, assetRelationHandler, chargebackHandler, tcoHandler, maintenancePlanHandler, workOrderHandler, calendarFeedHandler, reliabilityHandler, issueTicketHandler, inspectionHandler, meterHandler, workflowHandler, offboardingHandler, handoverHandler, departmentRestructureHandler, twoFactorHandler, ssoHandler, ldapHandler, scimHandler, serviceAccountHandler, repos.UserSession, db)
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	cronjob.InitCronJobs(db, services.Email, repos.Assets, repos.User, services.Notification, repos.AssetsLog, repos.Bill, repos.MonthlySummary, repos.Company)
+	cronjob.InitCronJobs(db, services.Email, repos.Assets, repos.User, services.Notification, repos.AssetsLog, repos.Bill, repos.MonthlySummary, repos.Company, services.Chargeback, services.MaintenancePlan, services.RequestTransfer, services.Handover, services.Ldap)
 
 	if err := r.Run(config.Port); err != nil {
 		log.Fatal("failed to run server:", err)
@@ -2,6 +2,7 @@ package config
 
 import (
 	"BE_Manage_device/internal/domain/entity"
+	"fmt"
 	"log"
 	"time"
 
@@ -252,10 +253,18 @@ func ConnectToDB() *gorm.DB {
 	db.Exec(createEnumSQL)
 	sql := "CREATE SEQUENCE bill_number_seq START WITH 1 INCREMENT BY 1;"
 	db.Exec(sql)
-	err = db.AutoMigrate(&entity.Roles{}, &entity.Permission{}, &entity.RolePermission{}, &entity.Users{}, &entity.UsersSessions{}, &entity.UserRbac{}, &entity.Locations{}, &entity.Departments{}, &entity.Categories{}, &entity.Assets{}, &entity.AssetLog{}, &entity.Assignments{}, &entity.RequestTransfer{}, &entity.Notifications{}, &entity.MaintenanceSchedules{}, &entity.MaintenanceNotifications{}, &entity.Company{}, &entity.Bill{}, &entity.MonthlySummary{})
+	err = db.AutoMigrate(&entity.Roles{}, &entity.Permission{}, &entity.RolePermission{}, &entity.Users{}, &entity.UsersSessions{}, &entity.UserRbac{}, &entity.Locations{}, &entity.Departments{}, &entity.Categories{}, &entity.Assets{}, &entity.AssetLog{}, &entity.Assignments{}, &entity.RequestTransfer{}, &entity.Notifications{}, &entity.MaintenanceSchedules{}, &entity.MaintenanceNotifications{}, &entity.Company{}, &entity.Bill{}, &entity.MonthlySummary{}, &entity.AssetTemplate{}, &entity.Tags{}, &entity.AssetTag{}, &entity.AssetRelation{}, &entity.DepartmentChargeback{}, &entity.ChargebackLine{}, &entity.MaintenancePlan{}, &entity.Consumable{}, &entity.WorkOrder{}, &entity.WorkOrderTask{}, &entity.WorkOrderPart{}, &entity.WorkOrderPhoto{}, &entity.CalendarFeed{}, &entity.IssueTicket{}, &entity.IssueTicketPhoto{}, &entity.IssueTicketComment{}, &entity.InspectionTemplate{}, &entity.InspectionTemplateItem{}, &entity.Inspection{}, &entity.InspectionItemResult{}, &entity.MeterDefinition{}, &entity.MeterThreshold{}, &entity.MeterReading{}, &entity.WorkflowDefinition{}, &entity.WorkflowStep{}, &entity.WorkflowInstance{}, &entity.WorkflowInstanceStep{}, &entity.WorkflowAction{}, &entity.RequestTransferHistory{}, &entity.RequestTransferComment{}, &entity.RequestTransferAsset{}, &entity.AssignmentHistory{}, &entity.Offboarding{}, &entity.OffboardingItem{}, &entity.HandoverAcknowledgement{}, &entity.DepartmentRestructure{}, &entity.DepartmentRestructureItem{}, &entity.UserTwoFactor{}, &entity.UserRecoveryCode{}, &entity.TwoFactorChallenge{}, &entity.AuditLog{}, &entity.CompanySso{}, &entity.SsoLoginState{}, &entity.UserIdentity{}, &entity.CompanyLdap{}, &entity.LdapDepartment{}, &entity.LdapSyncReport{}, &entity.ScimToken{}, &entity.ServiceAccount{}, &entity.ApiKey{}, &entity.ApiKeyScope{})
 	if err != nil {
 		log.Fatal("Error migrate to database. Error:", err)
 	}
+	// Đổi trạng thái cũ của yêu cầu điều chuyển sang trạng thái mới
+	db.Exec("UPDATE request_transfers SET status = 'Fulfilled', fulfilled_quantity = 1 WHERE status = 'Confirm'")
+	db.Exec("UPDATE request_transfers SET status = 'Denied' WHERE status = 'Deny'")
+	// Tạo lịch sử giữ tài sản cho các phân công đã có trước khi có bảng lịch sử
+	db.Exec(`INSERT INTO assignment_histories (assignment_id, asset_id, user_id, department_id, assign_by, reason, started_at, company_id)
+		SELECT a.id, a.asset_id, a.user_id, a.department_id, a.assign_by, 'Current assignment', COALESCE(s.acquisition_date, s.purchase_date), s.company_id
+		FROM assignments a JOIN assets s ON s.id = a.asset_id
+		WHERE NOT EXISTS (SELECT 1 FROM assignment_histories h WHERE h.asset_id = a.asset_id)`)
 	for _, company := range company {
 		var existing entity.Company
 		db.Where("company_name = ?", existing.CompanyName).FirstOrCreate(&existing, company)
@@ -287,5 +296,34 @@ func ConnectToDB() *gorm.DB {
 		var existing entity.Users
 		db.Where("email = ?", user.Email).FirstOrCreate(&existing, user)
 	}
+	migrateLegacyLocations(db)
 	return db
 }
+
+// migrateLegacyLocations gán công ty cho các vị trí cũ (trước khi có cây vị trí).
+// Một vị trí được nhiều công ty dùng sẽ được tách thành một site riêng cho mỗi công ty.
+func migrateLegacyLocations(db *gorm.DB) {
+	var legacy []entity.Locations
+	db.Where("company_id = 0 OR company_id IS NULL").Find(&legacy)
+	for _, location := range legacy {
+		var companyIds []int64
+		db.Model(&entity.Departments{}).Where("location_id = ?", location.Id).Distinct().Pluck("company_id", &companyIds)
+		for i, companyId := range companyIds {
+			if i == 0 {
+				db.Model(&entity.Locations{}).Where("id = ?", location.Id).Updates(map[string]interface{}{
+					"company_id":    companyId,
+					"location_type": entity.LocationTypeSite,
+					"path":          fmt.Sprintf("/%d/", location.Id),
+				})
+				continue
+			}
+			site := entity.Locations{LocationName: location.LocationName, LocationType: entity.LocationTypeSite, CompanyId: companyId}
+			if err := db.Create(&site).Error; err != nil {
+				log.Println("Error split legacy location. Error:", err)
+				continue
+			}
+			db.Model(&entity.Locations{}).Where("id = ?", site.Id).Update("path", fmt.Sprintf("/%d/", site.Id))
+			db.Model(&entity.Departments{}).Where("location_id = ? AND company_id = ?", location.Id, companyId).Update("location_id", site.Id)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 	storage_go "github.com/supabase-community/storage-go"
@@ -21,6 +22,14 @@ var (
 	DB_DNS                       string
 	StorageClient                *storage_go.Client
 	BASE_URL_BACKEND_FOR_SWAGGER string
+	MaintenancePlanHorizonDays   int     // Số ngày sinh trước lịch bảo trì từ kế hoạch định kỳ
+	ReliabilityFailureThreshold  float64 // Số lần hỏng/năm vượt ngưỡng này thì đề xuất thay thế
+	TransferRequestSlaHours      int     // Yêu cầu điều chuyển chờ quá số giờ này thì nhắc người xử lý
+	DocumentSigningKey           string  // Khoá HMAC ký các biên bản PDF
+	HandoverReminderHours        int     // Bàn giao chưa được xác nhận sau số giờ này thì nhắc người nhận
+	RestructureUndoHours         int     // Thời hạn hoàn tác tái cơ cấu phòng ban (giờ)
+	TwoFactorIssuer              string  // Tên hiển thị trong ứng dụng xác thực
+	TwoFactorEncryptionKey       string  // Khoá mã hoá TOTP secret trong DB
 )
 
 func LoadEnv() {
@@ -39,5 +48,37 @@ func LoadEnv() {
 	BASE_URL_FRONTEND = os.Getenv("BASE_URL_FRONTEND")
 	BASE_URL_BACKEND = os.Getenv("BASE_URL_BACKEND")
 	DB_DNS = os.Getenv("DATABASE_URL")
+	MaintenancePlanHorizonDays = 30
+	if v, err := strconv.Atoi(os.Getenv("MAINTENANCE_PLAN_HORIZON_DAYS")); err == nil && v > 0 {
+		MaintenancePlanHorizonDays = v
+	}
+	ReliabilityFailureThreshold = 2
+	if v, err := strconv.ParseFloat(os.Getenv("RELIABILITY_FAILURE_RATE_THRESHOLD"), 64); err == nil && v > 0 {
+		ReliabilityFailureThreshold = v
+	}
+	TransferRequestSlaHours = 48
+	if v, err := strconv.Atoi(os.Getenv("TRANSFER_REQUEST_SLA_HOURS")); err == nil && v > 0 {
+		TransferRequestSlaHours = v
+	}
+	HandoverReminderHours = 24
+	if v, err := strconv.Atoi(os.Getenv("HANDOVER_REMINDER_HOURS")); err == nil && v > 0 {
+		HandoverReminderHours = v
+	}
+	RestructureUndoHours = 72
+	if v, err := strconv.Atoi(os.Getenv("RESTRUCTURE_UNDO_HOURS")); err == nil && v > 0 {
+		RestructureUndoHours = v
+	}
+	TwoFactorIssuer = os.Getenv("TWO_FACTOR_ISSUER")
+	if TwoFactorIssuer == "" {
+		TwoFactorIssuer = "Manage Device"
+	}
+	TwoFactorEncryptionKey = os.Getenv("TWO_FACTOR_ENCRYPTION_KEY")
+	if TwoFactorEncryptionKey == "" {
+		TwoFactorEncryptionKey = PasswordSecret
+	}
+	DocumentSigningKey = os.Getenv("DOCUMENT_SIGNING_KEY")
+	if DocumentSigningKey == "" {
+		DocumentSigningKey = PasswordSecret
+	}
 	StorageClient = storage_go.NewClient("https://mvfitrngobsxryjosznw.supabase.co/storage/v1", SupabaseKey, nil)
 }
@@ -0,0 +1,47 @@
+package dto
+
+type CreateAssetRelationRequest struct {
+	ToAssetId    int64  `json:"toAssetId" binding:"required"`
+	RelationType string `json:"relationType" binding:"required,oneof=depends-on connected-to installed-in"`
+}
+
+type AssetGraphRequest struct {
+	Depth int `form:"depth"`
+}
+
+type AssetRelationResponse struct {
+	Id           int64  `json:"id"`
+	FromAssetId  int64  `json:"fromAssetId"`
+	ToAssetId    int64  `json:"toAssetId"`
+	RelationType string `json:"relationType"`
+}
+
+type AssetGraphNode struct {
+	Id           int64  `json:"id"`
+	AssetName    string `json:"assetName"`
+	SerialNumber string `json:"serialNumber"`
+	Status       string `json:"status"`
+	CategoryName string `json:"categoryName"`
+	Depth        int    `json:"depth"`
+}
+
+type AssetGraphResponse struct {
+	RootId int64                   `json:"rootId"`
+	Depth  int                     `json:"depth"`
+	Nodes  []AssetGraphNode        `json:"nodes"`
+	Edges  []AssetRelationResponse `json:"edges"`
+}
+
+type DependentAssetResponse struct {
+	Id           int64  `json:"id"`
+	AssetName    string `json:"assetName"`
+	SerialNumber string `json:"serialNumber"`
+	Status       string `json:"status"`
+	RelationType string `json:"relationType"`
+}
+
+type RetireAssetResponse struct {
+	AssetResponse
+	Warning    string                   `json:"warning,omitempty"`
+	Dependents []DependentAssetResponse `json:"dependents"`
+}
@@ -14,6 +14,8 @@ type AssetResponse struct {
 	Category       CategoryResponse   `json:"category"`
 	QrURL          string             `json:"qrUrl"`
 	Department     DepartmentResponse `json:"department"`
+	Room           *LocationResponse  `json:"room,omitempty"`
+	Tags           []TagResponse      `json:"tags"`
 }
 
 type CategoryResponse struct {
@@ -30,6 +32,7 @@ type DepartmentResponse struct {
 type LocationResponse struct {
 	ID           int64  `json:"id"`
 	LocationName string `json:"locationAddress"`
+	LocationType string `json:"locationType,omitempty"`
 }
 
 type OwnerResponse struct {
@@ -44,6 +47,9 @@ type DashboardSummary struct {
 	Assigned         int `json:"assigned"`
 	UnderMaintenance int `json:"under_maintenance"`
 	Retired          int `json:"retired"`
+
+	Tags        []*TagCount         `json:"tags,omitempty"`
+	Reliability *ReliabilitySummary `json:"reliability,omitempty"`
 }
 
 type GetAssetsByCateOfDepartmentRequest struct {
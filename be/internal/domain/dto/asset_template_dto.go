@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+type AssetTemplateRequest struct {
+	TemplateName       string   `json:"templateName" binding:"required"`
+	AssetName          string   `json:"assetName" binding:"required"`
+	CategoryId         int64    `json:"categoryId" binding:"required"`
+	Cost               float64  `json:"cost"`
+	WarrantyMonths     int      `json:"warrantyMonths"`
+	AnnualDepreciation *float64 `json:"annualDepreciation"`
+	ResidualValue      *float64 `json:"residualValue"`
+	UsefulLife         *float64 `json:"usefulLife"`
+}
+
+type AssetTemplateResponse struct {
+	Id                 int64            `json:"id"`
+	TemplateName       string           `json:"templateName"`
+	AssetName          string           `json:"assetName"`
+	Cost               float64          `json:"cost"`
+	WarrantyMonths     int              `json:"warrantyMonths"`
+	AnnualDepreciation *float64         `json:"annualDepreciation"`
+	ResidualValue      *float64         `json:"residualValue"`
+	UsefulLife         *float64         `json:"usefulLife"`
+	Category           CategoryResponse `json:"category"`
+}
+
+type CreateAssetsFromTemplateRequest struct {
+	DepartmentId  int64     `json:"departmentId" binding:"required"`
+	PurchaseDate  time.Time `json:"purchaseDate" binding:"required"`
+	SerialNumbers []string  `json:"serialNumbers" binding:"required,min=1"`
+	RedirectUrl   string    `json:"redirectUrl" binding:"required"`
+}
+
+type CloneAssetRequest struct {
+	SerialNumbers []string `json:"serialNumbers" binding:"required,min=1"`
+	RedirectUrl   string   `json:"redirectUrl" binding:"required"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type AssignmentCreateRequest struct {
 	UserId       *int64 `json:"userId"`
 	AssetId      int64  `json:"assetId" binding:"required"`
@@ -9,6 +11,7 @@ type AssignmentCreateRequest struct {
 type AssignmentUpdateRequest struct {
 	UserId       *int64 `json:"userId"`
 	DepartmentId *int64 `json:"departmentId"`
+	Reason       string `json:"reason"`
 }
 
 type AssignmentResponse struct {
@@ -17,6 +20,8 @@ type AssignmentResponse struct {
 	UserAssign   UsersAssignmentResponse     `json:"userAssign"`
 	Asset        UserAssignmentAssetResponse `json:"asset"`
 	Department   DepartmentResponse          `json:"department"`
+	// Url biên bản bàn giao đã ký, nil khi người nhận chưa xác nhận
+	HandoverReceiptUrl *string `json:"handoverReceiptUrl"`
 }
 
 type UsersAssignmentResponse struct {
@@ -33,3 +38,49 @@ type UserAssignmentAssetResponse struct {
 	FileAttachment string `json:"fileAttachment"`
 	ImageUpload    string `json:"imageUpload"`
 }
+
+type AssignmentHistoryResponse struct {
+	Id           int64                       `json:"id"`
+	AssignmentId int64                       `json:"assignmentId"`
+	Asset        UserAssignmentAssetResponse `json:"asset"`
+	UserAssigned *UsersAssignmentResponse    `json:"userAssigned"`
+	UserAssign   UsersAssignmentResponse     `json:"userAssign"`
+	DepartmentId *int64                      `json:"departmentId"`
+	Department   string                      `json:"departmentName"`
+	Reason       string                      `json:"reason"`
+	StartedAt    time.Time                   `json:"startedAt"`
+	EndedAt      *time.Time                  `json:"endedAt"`
+}
+
+// BulkAssignmentRequest chọn tài sản theo AssetIds hoặc Filter, chuyển tất cả cho một người dùng hoặc phòng ban
+type BulkAssignmentRequest struct {
+	AssetIds     []int64               `json:"assetIds"`
+	Filter       *BulkAssignmentFilter `json:"filter"`
+	UserId       *int64                `json:"userId"`
+	DepartmentId *int64                `json:"departmentId"`
+	Reason       string                `json:"reason"`
+}
+
+type BulkAssignmentFilter struct {
+	CategoryId   *int64  `json:"categoryId"`
+	DepartmentId *int64  `json:"departmentId"`
+	UserId       *int64  `json:"userId"` // Người đang giữ tài sản
+	Status       *string `json:"status"`
+}
+
+type BulkAssignmentResponse struct {
+	Applied  bool                   `json:"applied"` // false khi có tài sản không hợp lệ, không thay đổi gì
+	Total    int                    `json:"total"`
+	Updated  int                    `json:"updated"`
+	Skipped  int                    `json:"skipped"`
+	Rejected int                    `json:"rejected"`
+	Results  []BulkAssignmentResult `json:"results"`
+}
+
+type BulkAssignmentResult struct {
+	AssetId      int64  `json:"assetId"`
+	AssetName    string `json:"assetName"`
+	AssignmentId *int64 `json:"assignmentId"`
+	Status       string `json:"status"` // Updated, Skipped, Rejected
+	Message      string `json:"message"`
+}
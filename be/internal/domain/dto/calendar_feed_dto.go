@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+type CreateCalendarFeedRequest struct {
+	Name         string `json:"name"`
+	DepartmentId *int64 `json:"departmentId"` // Bỏ trống: feed cá nhân
+}
+
+type CalendarFeedResponse struct {
+	Id             int64      `json:"id"`
+	Name           string     `json:"name"`
+	UserId         int64      `json:"userId"`
+	DepartmentId   *int64     `json:"departmentId"`
+	DepartmentName string     `json:"departmentName,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	LastAccessedAt *time.Time `json:"lastAccessedAt"`
+	RevokedAt      *time.Time `json:"revokedAt"`
+	Url            string     `json:"url,omitempty"` // Chỉ trả về một lần khi tạo
+}
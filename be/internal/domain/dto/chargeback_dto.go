@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+type ComputeChargebackRequest struct {
+	Month int64 `json:"month" binding:"required,min=1,max=12"`
+	Year  int64 `json:"year" binding:"required,min=2000"`
+}
+
+type ChargebackFilterRequest struct {
+	Month        int64  `form:"month" binding:"required,min=1,max=12"`
+	Year         int64  `form:"year" binding:"required,min=2000"`
+	DepartmentId *int64 `form:"departmentId"`
+	Format       string `form:"format" binding:"omitempty,oneof=csv xlsx"`
+}
+
+type DepartmentChargebackResponse struct {
+	DepartmentId       int64   `json:"departmentId"`
+	DepartmentName     string  `json:"departmentName"`
+	AssetCount         int64   `json:"assetCount"`
+	DepreciationAmount float64 `json:"depreciationAmount"`
+	MaintenanceAmount  float64 `json:"maintenanceAmount"`
+	TotalAmount        float64 `json:"totalAmount"`
+}
+
+type ChargebackResponse struct {
+	Month       int64                           `json:"month"`
+	Year        int64                           `json:"year"`
+	TotalAmount float64                         `json:"totalAmount"`
+	GeneratedAt *time.Time                      `json:"generatedAt"`
+	Departments []*DepartmentChargebackResponse `json:"departments"`
+}
+
+type ChargebackLineResponse struct {
+	DepartmentId       int64   `json:"departmentId"`
+	DepartmentName     string  `json:"departmentName"`
+	AssetId            int64   `json:"assetId"`
+	AssetName          string  `json:"assetName"`
+	SerialNumber       string  `json:"serialNumber"`
+	DaysHeld           float64 `json:"daysHeld"`
+	DepreciationAmount float64 `json:"depreciationAmount"`
+	MaintenanceAmount  float64 `json:"maintenanceAmount"`
+	TotalAmount        float64 `json:"totalAmount"`
+}
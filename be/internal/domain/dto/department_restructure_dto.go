@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// CreateDepartmentRestructureRequest merge: chuyển toàn bộ từ Source sang TargetDepartmentId.
+// split: chuyển UserIds, AssetIds sang phòng ban mới tên Name. rename: đổi tên Source thành Name
+type CreateDepartmentRestructureRequest struct {
+	Type               string  `json:"type" binding:"required,oneof=merge split rename"`
+	SourceDepartmentId int64   `json:"sourceDepartmentId" binding:"required"`
+	TargetDepartmentId *int64  `json:"targetDepartmentId"`
+	Name               string  `json:"name"`
+	LocationId         *int64  `json:"locationId"` // Vị trí của phòng ban tách ra, mặc định như phòng ban gốc
+	UserIds            []int64 `json:"userIds"`
+	AssetIds           []int64 `json:"assetIds"`
+}
+
+type DepartmentRestructureResponse struct {
+	Id                   int64                               `json:"id"`
+	Type                 string                              `json:"type"`
+	Status               string                              `json:"status"`
+	SourceDepartmentId   int64                               `json:"sourceDepartmentId"`
+	SourceDepartmentName string                              `json:"sourceDepartmentName"`
+	TargetDepartmentId   *int64                              `json:"targetDepartmentId"`
+	TargetDepartmentName string                              `json:"targetDepartmentName"`
+	Name                 string                              `json:"name"`
+	OldName              string                              `json:"oldName"`
+	CreatedBy            UserResponseInAssetLog              `json:"createdBy"`
+	CreatedAt            time.Time                           `json:"createdAt"`
+	AppliedAt            *time.Time                          `json:"appliedAt"`
+	UndoDeadline         *time.Time                          `json:"undoDeadline"`
+	UndoneAt             *time.Time                          `json:"undoneAt"`
+	Summary              map[string]int                      `json:"summary"` // Số bản ghi theo loại
+	Items                []DepartmentRestructureItemResponse `json:"items,omitempty"`
+}
+
+type DepartmentRestructureItemResponse struct {
+	EntityType       string `json:"entityType"`
+	EntityId         int64  `json:"entityId"`
+	Label            string `json:"label"`
+	FromDepartmentId int64  `json:"fromDepartmentId"`
+	ToDepartmentId   int64  `json:"toDepartmentId"`
+	Reverted         bool   `json:"reverted"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type AcceptHandoverRequest struct {
+	Signature      string `json:"signature" binding:"required"` // Ảnh chữ ký dạng data URL (png/jpeg)
+	ConditionGrade string `json:"conditionGrade" binding:"required,oneof=A B C D E"`
+	ConditionNote  string `json:"conditionNote"`
+}
+
+type HandoverFilterRequest struct {
+	Status  *string `form:"status"`
+	AssetId *int64  `form:"assetId"`
+}
+
+type HandoverResponse struct {
+	Id             int64                               `json:"id"`
+	AssignmentId   int64                               `json:"assignmentId"`
+	Asset          AssetResponseInMaintenanceSchedules `json:"asset"`
+	User           UserResponseInAssetLog              `json:"user"`
+	AssignedBy     UserResponseInAssetLog              `json:"assignedBy"`
+	Status         string                              `json:"status"`
+	CreatedAt      time.Time                           `json:"createdAt"`
+	AcceptedAt     *time.Time                          `json:"acceptedAt"`
+	IpAddress      string                              `json:"ipAddress"`
+	ConditionGrade string                              `json:"conditionGrade"`
+	ConditionNote  string                              `json:"conditionNote"`
+	SignatureUrl   string                              `json:"signatureUrl"`
+	ReceiptUrl     string                              `json:"receiptUrl"`
+	Signature      string                              `json:"signature"`
+}
@@ -0,0 +1,98 @@
+package dto
+
+import "time"
+
+type InspectionTemplateItemRequest struct {
+	Label       string `json:"label" binding:"required"`
+	Description string `json:"description"`
+}
+
+type InspectionTemplateRequest struct {
+	Name         string                          `json:"name" binding:"required"`
+	CategoryId   int64                           `json:"categoryId" binding:"required"`
+	IntervalDays int                             `json:"intervalDays" binding:"required,min=1"`
+	IsActive     *bool                           `json:"isActive"`
+	Items        []InspectionTemplateItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type InspectionTemplateFilterRequest struct {
+	CategoryId *int64 `form:"categoryId"`
+}
+
+type InspectionItemResultRequest struct {
+	TemplateItemId int64  `json:"templateItemId" binding:"required"`
+	Passed         *bool  `json:"passed" binding:"required"`
+	Note           string `json:"note"`
+}
+
+// Chữ ký gửi dạng data URL (data:image/png;base64,...)
+type CreateInspectionRequest struct {
+	TemplateId         int64                         `json:"templateId" binding:"required"`
+	ConditionGrade     string                        `json:"conditionGrade" binding:"required,oneof=A B C D E"`
+	Notes              string                        `json:"notes"`
+	Items              []InspectionItemResultRequest `json:"items" binding:"required,min=1,dive"`
+	InspectorSignature string                        `json:"inspectorSignature" binding:"required"`
+	WitnessName        *string                       `json:"witnessName"`
+	WitnessSignature   *string                       `json:"witnessSignature"`
+}
+
+type InspectionOverdueRequest struct {
+	CategoryId   *int64 `form:"categoryId"`
+	DepartmentId *int64 `form:"departmentId"`
+}
+
+type InspectionTemplateItemResponse struct {
+	Id          int64  `json:"id"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+	SortOrder   int    `json:"sortOrder"`
+}
+
+type InspectionTemplateResponse struct {
+	Id           int64                            `json:"id"`
+	Name         string                           `json:"name"`
+	CategoryId   int64                            `json:"categoryId"`
+	CategoryName string                           `json:"categoryName"`
+	IntervalDays int                              `json:"intervalDays"`
+	IsActive     bool                             `json:"isActive"`
+	Items        []InspectionTemplateItemResponse `json:"items"`
+}
+
+type InspectionItemResultResponse struct {
+	Id             int64  `json:"id"`
+	TemplateItemId int64  `json:"templateItemId"`
+	Label          string `json:"label"`
+	Passed         bool   `json:"passed"`
+	Note           string `json:"note"`
+}
+
+type InspectionResponse struct {
+	Id                 int64                          `json:"id"`
+	AssetId            int64                          `json:"assetId"`
+	AssetName          string                         `json:"assetName"`
+	TemplateId         int64                          `json:"templateId"`
+	TemplateName       string                         `json:"templateName"`
+	Inspector          UserResponseInAssetLog         `json:"inspector"`
+	InspectedAt        time.Time                      `json:"inspectedAt"`
+	Passed             bool                           `json:"passed"`
+	ConditionGrade     string                         `json:"conditionGrade"`
+	Notes              string                         `json:"notes"`
+	InspectorSignature string                         `json:"inspectorSignature"`
+	WitnessName        *string                        `json:"witnessName"`
+	WitnessSignature   *string                        `json:"witnessSignature"`
+	ScheduleId         *int64                         `json:"scheduleId"`
+	Items              []InspectionItemResultResponse `json:"items"`
+}
+
+type InspectionOverdueResponse struct {
+	AssetId         int64      `json:"assetId"`
+	AssetName       string     `json:"assetName"`
+	SerialNumber    string     `json:"serialNumber"`
+	DepartmentId    int64      `json:"departmentId"`
+	DepartmentName  string     `json:"departmentName"`
+	TemplateId      int64      `json:"templateId"`
+	TemplateName    string     `json:"templateName"`
+	LastInspectedAt *time.Time `json:"lastInspectedAt"`
+	DueDate         time.Time  `json:"dueDate"`
+	DaysOverdue     int        `json:"daysOverdue"`
+}
@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+// Gửi dạng multipart/form-data, ảnh gửi qua field "photos"
+type CreateIssueTicketRequest struct {
+	Title       string `form:"title" binding:"required"`
+	Description string `form:"description"`
+	Severity    string `form:"severity" binding:"omitempty,oneof=low medium high critical"`
+}
+
+type IssueTicketFilterRequest struct {
+	Status   *string `form:"status"`
+	Severity *string `form:"severity"`
+	AssetId  *int64  `form:"assetId"`
+}
+
+type TriageIssueTicketRequest struct {
+	Status       string  `json:"status" binding:"omitempty,oneof=triaged resolved rejected closed"`
+	Severity     string  `json:"severity" binding:"omitempty,oneof=low medium high critical"`
+	AssignedToId *int64  `json:"assignedToId"`
+	Note         *string `json:"note"` // Ghi thành bình luận
+}
+
+type CreateIssueTicketCommentRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+type ConvertIssueTicketRequest struct {
+	StartDate time.Time `json:"startDate" binding:"required"`
+	EndDate   time.Time `json:"endDate" binding:"required"`
+	Cost      float64   `json:"cost" binding:"omitempty,gte=0"`
+}
+
+type IssueTicketCommentResponse struct {
+	Id        int64                  `json:"id"`
+	User      UserResponseInAssetLog `json:"user"`
+	Content   string                 `json:"content"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+type IssueTicketResponse struct {
+	Id          int64                               `json:"id"`
+	Asset       AssetResponseInMaintenanceSchedules `json:"asset"`
+	ReportedBy  UserResponseInAssetLog              `json:"reportedBy"`
+	Source      string                              `json:"source"`
+	Title       string                              `json:"title"`
+	Description string                              `json:"description"`
+	Severity    string                              `json:"severity"`
+	Status      string                              `json:"status"`
+	AssignedTo  *UserResponseInAssetLog             `json:"assignedTo"`
+	ScheduleId  *int64                              `json:"scheduleId"`
+	CreatedAt   time.Time                           `json:"createdAt"`
+	UpdatedAt   time.Time                           `json:"updatedAt"`
+	ClosedAt    *time.Time                          `json:"closedAt"`
+	Photos      []string                            `json:"photos"`
+	Comments    []IssueTicketCommentResponse        `json:"comments"`
+}
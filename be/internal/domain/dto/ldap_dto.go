@@ -0,0 +1,69 @@
+package dto
+
+import "time"
+
+type LdapConfigRequest struct {
+	Url                string `json:"url" binding:"required"` // ldap://host:389 hoặc ldaps://host:636
+	StartTls           bool   `json:"startTls"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+	BindDn             string `json:"bindDn" binding:"required"`
+	BindPassword       string `json:"bindPassword"` // Bỏ trống để giữ mật khẩu cũ
+	BaseDn             string `json:"baseDn" binding:"required"`
+	UserFilter         string `json:"userFilter"`
+	OrgUnitFilter      string `json:"orgUnitFilter"` // Bỏ trống thì không đồng bộ phòng ban
+	// Tên thuộc tính, bỏ trống dùng mặc định của OpenLDAP
+	UniqueIdAttribute    string            `json:"uniqueIdAttribute"`
+	EmailAttribute       string            `json:"emailAttribute"`
+	FirstNameAttribute   string            `json:"firstNameAttribute"`
+	LastNameAttribute    string            `json:"lastNameAttribute"`
+	GroupAttribute       string            `json:"groupAttribute"`
+	OrgUnitNameAttribute string            `json:"orgUnitNameAttribute"`
+	RoleMapping          map[string]string `json:"roleMapping"` // DN nhóm -> slug vai trò
+	DefaultRoleSlug      string            `json:"defaultRoleSlug"`
+	LocationId           int64             `json:"locationId"`
+	SyncEnabled          bool              `json:"syncEnabled"`
+	SyncIntervalHours    int               `json:"syncIntervalHours"`
+	LoginEnabled         bool              `json:"loginEnabled"`
+}
+
+type LdapConfigResponse struct {
+	Url                  string            `json:"url"`
+	StartTls             bool              `json:"startTls"`
+	InsecureSkipVerify   bool              `json:"insecureSkipVerify"`
+	BindDn               string            `json:"bindDn"`
+	HasBindPassword      bool              `json:"hasBindPassword"`
+	BaseDn               string            `json:"baseDn"`
+	UserFilter           string            `json:"userFilter"`
+	OrgUnitFilter        string            `json:"orgUnitFilter"`
+	UniqueIdAttribute    string            `json:"uniqueIdAttribute"`
+	EmailAttribute       string            `json:"emailAttribute"`
+	FirstNameAttribute   string            `json:"firstNameAttribute"`
+	LastNameAttribute    string            `json:"lastNameAttribute"`
+	GroupAttribute       string            `json:"groupAttribute"`
+	OrgUnitNameAttribute string            `json:"orgUnitNameAttribute"`
+	RoleMapping          map[string]string `json:"roleMapping"`
+	DefaultRoleSlug      string            `json:"defaultRoleSlug"`
+	LocationId           int64             `json:"locationId"`
+	SyncEnabled          bool              `json:"syncEnabled"`
+	SyncIntervalHours    int               `json:"syncIntervalHours"`
+	LoginEnabled         bool              `json:"loginEnabled"`
+	LastSyncAt           *time.Time        `json:"lastSyncAt"`
+	UpdatedAt            time.Time         `json:"updatedAt"`
+}
+
+type LdapSyncReportResponse struct {
+	Id                 int64      `json:"id"`
+	Trigger            string     `json:"trigger"`
+	Status             string     `json:"status"`
+	StartedAt          time.Time  `json:"startedAt"`
+	FinishedAt         *time.Time `json:"finishedAt"`
+	DepartmentsCreated int        `json:"departmentsCreated"`
+	DepartmentsUpdated int        `json:"departmentsUpdated"`
+	UsersCreated       int        `json:"usersCreated"`
+	UsersUpdated       int        `json:"usersUpdated"`
+	UsersDeactivated   int        `json:"usersDeactivated"`
+	UsersReactivated   int        `json:"usersReactivated"`
+	UsersSkipped       int        `json:"usersSkipped"`
+	Errors             []string   `json:"errors"`
+	TriggeredById      *int64     `json:"triggeredById"`
+}
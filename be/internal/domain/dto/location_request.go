@@ -1,5 +1,38 @@
 package dto
 
 type CreateLocationRequest struct {
-	LocationName string `json:"locationName" binding:"required"`
+	LocationName string   `json:"locationName" binding:"required"`
+	LocationType string   `json:"locationType"` // site, building, floor, room (mặc định site)
+	ParentId     *int64   `json:"parentId"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+}
+
+type UpdateLocationRequest struct {
+	LocationName string   `json:"locationName" binding:"required"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+}
+
+type LocationTreeResponse struct {
+	Id           int64                   `json:"id"`
+	LocationName string                  `json:"locationAddress"`
+	LocationType string                  `json:"locationType"`
+	ParentId     *int64                  `json:"parentId"`
+	Latitude     *float64                `json:"latitude"`
+	Longitude    *float64                `json:"longitude"`
+	Children     []*LocationTreeResponse `json:"children"`
+}
+
+type LocationDashboardSummary struct {
+	Location LocationResponse `json:"location"`
+	DashboardSummary
+}
+
+type LocationDashboardRequest struct {
+	LocationType string `form:"locationType" binding:"required"`
+}
+
+type MoveAssetRoomRequest struct {
+	RoomId int64 `json:"roomId" binding:"required"`
 }
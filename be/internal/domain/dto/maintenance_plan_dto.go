@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type MaintenancePlanRequest struct {
+	PlanName   string `json:"planName" binding:"required"`
+	AssetId    *int64 `json:"assetId"`
+	CategoryId *int64 `json:"categoryId"`
+	// Dùng RRule (vd: "FREQ=MONTHLY;INTERVAL=3") hoặc IntervalValue + IntervalUnit (vd: 90 day)
+	RRule         string    `json:"rrule"`
+	IntervalValue int       `json:"intervalValue" binding:"omitempty,min=1"`
+	IntervalUnit  string    `json:"intervalUnit" binding:"omitempty,oneof=day week month year"`
+	StartDate     time.Time `json:"startDate" binding:"required"`
+	DurationDays  int       `json:"durationDays" binding:"omitempty,min=1"`
+	Cost          float64   `json:"cost" binding:"omitempty,gte=0"`
+	IsActive      *bool     `json:"isActive"`
+}
+
+type MaintenancePlanResponse struct {
+	Id              int64    `json:"id"`
+	PlanName        string   `json:"planName"`
+	AssetId         *int64   `json:"assetId"`
+	AssetName       string   `json:"assetName,omitempty"`
+	CategoryId      *int64   `json:"categoryId"`
+	CategoryName    string   `json:"categoryName,omitempty"`
+	RRule           string   `json:"rrule"`
+	StartDate       string   `json:"startDate"`
+	DurationDays    int      `json:"durationDays"`
+	Cost            float64  `json:"cost"`
+	IsActive        bool     `json:"isActive"`
+	NextOccurrences []string `json:"nextOccurrences"`
+}
+
+type GenerateMaintenancePlanResponse struct {
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+}
@@ -6,17 +6,20 @@ type CreateMaintenanceSchedulesRequest struct {
 	AssetId   int64     `json:"assetId" binding:"required"`
 	StartDate time.Time `json:"startDate" binding:"required"`
 	EndDate   time.Time `json:"endDate" binding:"required"`
+	Cost      float64   `json:"cost" binding:"omitempty,gte=0"`
 }
 
 type UpdateMaintenanceSchedulesRequest struct {
 	StartDate time.Time `json:"startDate" binding:"required"`
 	EndDate   time.Time `json:"endDate" binding:"required"`
+	Cost      float64   `json:"cost" binding:"omitempty,gte=0"`
 }
 
 type MaintenanceSchedulesResponse struct {
 	Id        int64                               `json:"id"`
 	StartDate string                              `json:"startDate"`
 	EndDate   string                              `json:"endDate"`
+	Cost      float64                             `json:"cost"`
 	Asset     AssetResponseInMaintenanceSchedules `json:"asset"`
 }
 
@@ -0,0 +1,73 @@
+package dto
+
+import "time"
+
+type MeterThresholdRequest struct {
+	Name         string  `json:"name" binding:"required"`
+	Interval     float64 `json:"interval" binding:"required,gt=0"`
+	DurationDays int     `json:"durationDays" binding:"omitempty,min=1"`
+	Cost         float64 `json:"cost" binding:"min=0"`
+	IsActive     *bool   `json:"isActive"`
+}
+
+type MeterDefinitionRequest struct {
+	Name       string                  `json:"name" binding:"required"`
+	Unit       string                  `json:"unit" binding:"required"`
+	CategoryId int64                   `json:"categoryId" binding:"required"`
+	Thresholds []MeterThresholdRequest `json:"thresholds" binding:"dive"`
+}
+
+type UpdateMeterDefinitionRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Unit     string `json:"unit" binding:"required"`
+	IsActive *bool  `json:"isActive"`
+}
+
+type MeterFilterRequest struct {
+	CategoryId *int64 `form:"categoryId"`
+}
+
+// ReadAt bỏ trống thì lấy thời điểm hiện tại
+type CreateMeterReadingRequest struct {
+	MeterId int64      `json:"meterId" binding:"required"`
+	Value   *float64   `json:"value" binding:"required,min=0"`
+	ReadAt  *time.Time `json:"readAt"`
+	Note    string     `json:"note"`
+}
+
+type MeterReadingFilterRequest struct {
+	MeterId *int64 `form:"meterId"`
+}
+
+type MeterThresholdResponse struct {
+	Id           int64   `json:"id"`
+	MeterId      int64   `json:"meterId"`
+	Name         string  `json:"name"`
+	Interval     float64 `json:"interval"`
+	DurationDays int     `json:"durationDays"`
+	Cost         float64 `json:"cost"`
+	IsActive     bool    `json:"isActive"`
+}
+
+type MeterDefinitionResponse struct {
+	Id           int64                    `json:"id"`
+	Name         string                   `json:"name"`
+	Unit         string                   `json:"unit"`
+	CategoryId   int64                    `json:"categoryId"`
+	CategoryName string                   `json:"categoryName"`
+	IsActive     bool                     `json:"isActive"`
+	Thresholds   []MeterThresholdResponse `json:"thresholds"`
+}
+
+type MeterReadingResponse struct {
+	Id         int64                  `json:"id"`
+	AssetId    int64                  `json:"assetId"`
+	MeterId    int64                  `json:"meterId"`
+	MeterName  string                 `json:"meterName"`
+	Unit       string                 `json:"unit"`
+	Value      float64                `json:"value"`
+	ReadAt     time.Time              `json:"readAt"`
+	RecordedBy UserResponseInAssetLog `json:"recordedBy"`
+	Note       string                 `json:"note"`
+	ScheduleId *int64                 `json:"scheduleId"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+import "time"
+
+type StartOffboardingRequest struct {
+	UserId int64 `json:"userId" binding:"required"`
+}
+
+type UpdateOffboardingItemRequest struct {
+	Status string `json:"status" binding:"required,oneof=Pending Returned Missing"`
+	Note   string `json:"note"`
+}
+
+// CompleteOffboardingRequest bàn giao toàn bộ tài sản cho một người (targetUserId) hoặc kho chung của phòng ban (targetDepartmentId)
+type CompleteOffboardingRequest struct {
+	TargetUserId       *int64 `json:"targetUserId"`
+	TargetDepartmentId *int64 `json:"targetDepartmentId"`
+	Note               string `json:"note"`
+}
+
+type OffboardingResponse struct {
+	Id                 int64                     `json:"id"`
+	User               UserResponseInAssetLog    `json:"user"`
+	Status             string                    `json:"status"`
+	StartedBy          UserResponseInAssetLog    `json:"startedBy"`
+	StartedAt          time.Time                 `json:"startedAt"`
+	CompletedBy        *UserResponseInAssetLog   `json:"completedBy"`
+	CompletedAt        *time.Time                `json:"completedAt"`
+	TargetUser         *UserResponseInAssetLog   `json:"targetUser"`
+	TargetDepartmentId *int64                    `json:"targetDepartmentId"`
+	Note               string                    `json:"note"`
+	Signature          string                    `json:"signature"`
+	Items              []OffboardingItemResponse `json:"items"`
+	PendingRequests    []RequestTransferResponse `json:"pendingRequests"`
+}
+
+type OffboardingItemResponse struct {
+	Id           int64                               `json:"id"`
+	Asset        AssetResponseInMaintenanceSchedules `json:"asset"`
+	AssignmentId int64                               `json:"assignmentId"`
+	Status       string                              `json:"status"`
+	Note         string                              `json:"note"`
+	CheckedById  *int64                              `json:"checkedById"`
+	CheckedAt    *time.Time                          `json:"checkedAt"`
+}
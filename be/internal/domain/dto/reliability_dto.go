@@ -0,0 +1,60 @@
+package dto
+
+type ReliabilityFilterRequest struct {
+	CategoryId     *int64 `form:"categoryId"`
+	DepartmentId   *int64 `form:"departmentId"`
+	CandidatesOnly bool   `form:"candidatesOnly"`
+	SortBy         string `form:"sortBy" binding:"omitempty,oneof=failureRate failures mtbf mttr downtime"`
+	Order          string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit          int    `form:"limit" binding:"omitempty,min=1"`
+}
+
+// Thời gian tính bằng giờ, MTBF/MTTR = nil khi chưa có dữ liệu hỏng hóc
+type AssetReliabilityResponse struct {
+	AssetId              int64    `json:"assetId"`
+	AssetName            string   `json:"assetName"`
+	SerialNumber         string   `json:"serialNumber"`
+	Status               string   `json:"status"`
+	CategoryId           int64    `json:"categoryId"`
+	CategoryName         string   `json:"categoryName"`
+	DepartmentId         int64    `json:"departmentId"`
+	DepartmentName       string   `json:"departmentName"`
+	ObservedHours        float64  `json:"observedHours"`
+	Failures             int      `json:"failures"`
+	PlannedMaintenances  int      `json:"plannedMaintenances"`
+	DowntimeHours        float64  `json:"downtimeHours"`
+	DowntimePercent      float64  `json:"downtimePercent"`
+	MtbfHours            *float64 `json:"mtbfHours"`
+	MttrHours            *float64 `json:"mttrHours"`
+	FailuresPerYear      float64  `json:"failuresPerYear"`
+	ReplacementCandidate bool     `json:"replacementCandidate"`
+}
+
+type CategoryReliabilityResponse struct {
+	CategoryId            int64    `json:"categoryId"`
+	CategoryName          string   `json:"categoryName"`
+	AssetCount            int      `json:"assetCount"`
+	Failures              int      `json:"failures"`
+	DowntimeHours         float64  `json:"downtimeHours"`
+	DowntimePercent       float64  `json:"downtimePercent"`
+	MtbfHours             *float64 `json:"mtbfHours"`
+	MttrHours             *float64 `json:"mttrHours"`
+	FailuresPerYear       float64  `json:"failuresPerYear"`
+	ReplacementCandidates int      `json:"replacementCandidates"`
+}
+
+type ReliabilityReportResponse struct {
+	FailureRateThreshold float64                        `json:"failureRateThreshold"` // Số lần hỏng/năm
+	Assets               []*AssetReliabilityResponse    `json:"assets"`
+	Categories           []*CategoryReliabilityResponse `json:"categories"`
+}
+
+// ReliabilitySummary phần độ tin cậy hiển thị trên dashboard
+type ReliabilitySummary struct {
+	FailureRateThreshold  float64                     `json:"failureRateThreshold"`
+	MtbfHours             *float64                    `json:"mtbfHours"`
+	MttrHours             *float64                    `json:"mttrHours"`
+	DowntimePercent       float64                     `json:"downtimePercent"`
+	ReplacementCandidates int                         `json:"replacementCandidates"`
+	TopCandidates         []*AssetReliabilityResponse `json:"topCandidates"`
+}
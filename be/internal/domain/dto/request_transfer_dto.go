@@ -1,16 +1,57 @@
 package dto
 
+import "time"
+
+// CreateRequestTransferRequest yêu cầu một tài sản cụ thể (assetId) hoặc một số lượng theo loại (categoryId)
 type CreateRequestTransferRequest struct {
-	CategoryId  int64  `json:"categoryId" binding:"required"`
-	Description string `json:"description" binding:"required"`
+	CategoryId  *int64     `json:"categoryId"`
+	AssetId     *int64     `json:"assetId"`
+	Quantity    int        `json:"quantity" binding:"omitempty,min=1"`
+	NeededBy    *time.Time `json:"neededBy"`
+	Description string     `json:"description" binding:"required"`
 }
 
 type RequestTransferResponse struct {
-	Id          int64                             `json:"id"`
-	Status      string                            `json:"status"`
-	User        UserResponseInRequestTransfer     `json:"user"`
-	Category    CategoryResponseInRequestTransfer `json:"category"`
-	Description string                            `json:"description"`
+	Id                int64                                `json:"id"`
+	Status            string                               `json:"status"`
+	User              UserResponseInRequestTransfer        `json:"user"`
+	Category          CategoryResponseInRequestTransfer    `json:"category"`
+	Asset             *AssetResponseInMaintenanceSchedules `json:"asset"`
+	Quantity          int                                  `json:"quantity"`
+	FulfilledQuantity int                                  `json:"fulfilledQuantity"`
+	NeededBy          *time.Time                           `json:"neededBy"`
+	Description       string                               `json:"description"`
+	CreatedAt         time.Time                            `json:"createdAt"`
+	UpdatedAt         time.Time                            `json:"updatedAt"`
+}
+
+type RequestTransferDetailResponse struct {
+	RequestTransferResponse
+	Histories         []RequestTransferHistoryResponse `json:"histories"`
+	TransferredAssets []RequestTransferAssetResponse   `json:"transferredAssets"`
+}
+
+type RequestTransferHistoryResponse struct {
+	Id         int64                   `json:"id"`
+	FromStatus string                  `json:"fromStatus"`
+	ToStatus   string                  `json:"toStatus"`
+	ByUser     *UserResponseInAssetLog `json:"byUser"`
+	Note       string                  `json:"note"`
+	CreatedAt  time.Time               `json:"createdAt"`
+}
+
+type RequestTransferAssetResponse struct {
+	Id            int64                               `json:"id"`
+	Asset         AssetResponseInMaintenanceSchedules `json:"asset"`
+	ByUserId      int64                               `json:"byUserId"`
+	TransferredAt time.Time                           `json:"transferredAt"`
+}
+
+type RequestTransferCommentResponse struct {
+	Id        int64                  `json:"id"`
+	User      UserResponseInAssetLog `json:"user"`
+	Content   string                 `json:"content"`
+	CreatedAt time.Time              `json:"createdAt"`
 }
 
 type UserResponseInRequestTransfer struct {
@@ -26,6 +67,15 @@ type CategoryResponseInRequestTransfer struct {
 	CategoryName string `json:"categoryName"`
 }
 
+// ConfirmRequestTransferRequest assetId có thể bỏ trống khi yêu cầu đã chỉ định tài sản
 type ConfirmRequestTransferRequest struct {
-	AssetId int64 `json:"assetId" binding:"required"`
+	AssetId *int64 `json:"assetId"`
+}
+
+type ReasonRequestTransferRequest struct {
+	Reason string `json:"reason"`
+}
+
+type RequestTransferCommentRequest struct {
+	Content string `json:"content" binding:"required"`
 }
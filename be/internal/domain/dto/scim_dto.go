@@ -0,0 +1,125 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ScimUserSchema         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	ScimGroupSchema        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ScimEnterpriseSchema   = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	ScimListSchema         = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	ScimPatchSchema        = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ScimErrorSchema        = "urn:ietf:params:scim:api:messages:2.0:Error"
+	ScimProviderSchema     = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	ScimResourceTypeUser   = "User"
+	ScimResourceTypeGroup  = "Group"
+	ScimContentType        = "application/scim+json"
+	ScimDefaultPageSize    = 100
+	ScimMaxPageSize        = 500
+	ScimGroupPrefixDept    = "department-"
+	ScimGroupPrefixRole    = "role-"
+	ScimEnterpriseDeptPath = ScimEnterpriseSchema + ":department"
+)
+
+type CreateScimTokenRequest struct {
+	Name       string `json:"name" binding:"required"`
+	LocationId *int64 `json:"locationId"` // Bỏ trống thì IdP không tạo được phòng ban mới
+}
+
+type ScimTokenResponse struct {
+	Id          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	LocationId  *int64     `json:"locationId"`
+	CreatedById int64      `json:"createdById"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	LastUsedAt  *time.Time `json:"lastUsedAt"`
+	RevokedAt   *time.Time `json:"revokedAt"`
+	Token       string     `json:"token,omitempty"` // Chỉ trả về một lần khi tạo
+	BaseUrl     string     `json:"baseUrl"`
+}
+
+type ScimMeta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+}
+
+type ScimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type ScimMultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type ScimEnterpriseUser struct {
+	Department string `json:"department,omitempty"`
+}
+
+// ScimUser tài nguyên User, userName là email đăng nhập
+type ScimUser struct {
+	Schemas     []string            `json:"schemas"`
+	Id          string              `json:"id,omitempty"`
+	ExternalId  string              `json:"externalId,omitempty"`
+	UserName    string              `json:"userName"`
+	Name        *ScimName           `json:"name,omitempty"`
+	DisplayName string              `json:"displayName,omitempty"`
+	Emails      []ScimMultiValue    `json:"emails,omitempty"`
+	Active      *bool               `json:"active,omitempty"`
+	Password    string              `json:"password,omitempty"` // Chỉ nhận khi tạo/cập nhật, không bao giờ trả về
+	Roles       []ScimMultiValue    `json:"roles,omitempty"`
+	Groups      []ScimMultiValue    `json:"groups,omitempty"`
+	Enterprise  *ScimEnterpriseUser `json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,omitempty"`
+	Meta        *ScimMeta           `json:"meta,omitempty"`
+}
+
+// ScimGroup tài nguyên Group, ánh xạ vào phòng ban (department-<id>) hoặc vai trò (role-<slug>)
+type ScimGroup struct {
+	Schemas     []string         `json:"schemas"`
+	Id          string           `json:"id,omitempty"`
+	ExternalId  string           `json:"externalId,omitempty"`
+	DisplayName string           `json:"displayName"`
+	Members     []ScimMultiValue `json:"members,omitempty"`
+	Meta        *ScimMeta        `json:"meta,omitempty"`
+}
+
+type ScimListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+type ScimListRequest struct {
+	Filter             string `form:"filter"`
+	StartIndex         int    `form:"startIndex"`
+	Count              *int   `form:"count"`
+	ExcludedAttributes string `form:"excludedAttributes"`
+}
+
+type ScimPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+type ScimPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []ScimPatchOperation `json:"Operations"`
+}
+
+type ScimErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
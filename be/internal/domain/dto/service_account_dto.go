@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+type CreateServiceAccountRequest struct {
+	Name         string `json:"name" binding:"required"`
+	Description  string `json:"description"`
+	RoleSlug     string `json:"roleSlug"`     // Bỏ trống: viewer
+	DepartmentId *int64 `json:"departmentId"` // Phòng ban của người dùng hệ thống
+}
+
+type ServiceAccountResponse struct {
+	Id           int64      `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	UserId       int64      `json:"userId"`
+	RoleSlug     string     `json:"roleSlug"`
+	DepartmentId *int64     `json:"departmentId"`
+	CreatedById  int64      `json:"createdById"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	DisabledAt   *time.Time `json:"disabledAt"`
+}
+
+type CreateApiKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"` // Slug trong bảng permissions
+	ExpiresAt *time.Time `json:"expiresAt"`                       // Bỏ trống: không hết hạn
+}
+
+type ApiKeyResponse struct {
+	Id               int64      `json:"id"`
+	ServiceAccountId int64      `json:"serviceAccountId"`
+	Name             string     `json:"name"`
+	Prefix           string     `json:"prefix"`
+	Scopes           []string   `json:"scopes"`
+	ExpiresAt        *time.Time `json:"expiresAt"`
+	LastUsedAt       *time.Time `json:"lastUsedAt"`
+	RevokedAt        *time.Time `json:"revokedAt"`
+	CreatedById      int64      `json:"createdById"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	Key              string     `json:"key,omitempty"` // Chỉ trả về một lần khi tạo
+}
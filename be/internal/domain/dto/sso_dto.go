@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+type SsoConfigRequest struct {
+	Issuer       string `json:"issuer" binding:"required,url"`
+	ClientId     string `json:"clientId" binding:"required"`
+	ClientSecret string `json:"clientSecret"` // Bỏ trống để giữ secret cũ
+	RedirectUri  string `json:"redirectUri" binding:"required,url"`
+	Scopes       string `json:"scopes"`
+	// Tên claim, bỏ trống dùng mặc định email, given_name, family_name
+	EmailClaim      string `json:"emailClaim"`
+	FirstNameClaim  string `json:"firstNameClaim"`
+	LastNameClaim   string `json:"lastNameClaim"`
+	DepartmentClaim string `json:"departmentClaim"`
+	RoleClaim       string `json:"roleClaim"`
+	// Giá trị claim -> id phòng ban / slug vai trò
+	DepartmentMapping    map[string]int64  `json:"departmentMapping"`
+	RoleMapping          map[string]string `json:"roleMapping"`
+	DefaultRoleSlug      string            `json:"defaultRoleSlug"`
+	Enabled              bool              `json:"enabled"`
+	AutoProvision        bool              `json:"autoProvision"`        // Tự tạo tài khoản khi đăng nhập lần đầu
+	DisablePasswordLogin bool              `json:"disablePasswordLogin"` // Chỉ cho đăng nhập bằng SSO, trừ admin
+}
+
+type SsoConfigResponse struct {
+	Issuer               string            `json:"issuer"`
+	ClientId             string            `json:"clientId"`
+	HasClientSecret      bool              `json:"hasClientSecret"`
+	RedirectUri          string            `json:"redirectUri"`
+	Scopes               string            `json:"scopes"`
+	EmailClaim           string            `json:"emailClaim"`
+	FirstNameClaim       string            `json:"firstNameClaim"`
+	LastNameClaim        string            `json:"lastNameClaim"`
+	DepartmentClaim      string            `json:"departmentClaim"`
+	RoleClaim            string            `json:"roleClaim"`
+	DepartmentMapping    map[string]int64  `json:"departmentMapping"`
+	RoleMapping          map[string]string `json:"roleMapping"`
+	DefaultRoleSlug      string            `json:"defaultRoleSlug"`
+	Enabled              bool              `json:"enabled"`
+	AutoProvision        bool              `json:"autoProvision"`
+	DisablePasswordLogin bool              `json:"disablePasswordLogin"`
+	UpdatedAt            time.Time         `json:"updatedAt"`
+}
+
+type SsoAuthorizeRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type SsoAuthorizeResponse struct {
+	AuthorizationUrl string    `json:"authorizationUrl"` // FE chuyển hướng người dùng tới IdP
+	ExpiresAt        time.Time `json:"expiresAt"`
+}
+
+type SsoCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
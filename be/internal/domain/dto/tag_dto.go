@@ -0,0 +1,39 @@
+package dto
+
+import "BE_Manage_device/internal/domain/filter"
+
+type CreateTagRequest struct {
+	TagName string `json:"tagName" binding:"required"`
+}
+
+type SearchTagRequest struct {
+	Keyword string `form:"keyword"`
+	Limit   int    `form:"limit"`
+}
+
+type SetAssetTagsRequest struct {
+	TagNames []string `json:"tagNames"`
+}
+
+type BulkTagRequest struct {
+	Action   string             `json:"action" binding:"required,oneof=add remove"`
+	TagNames []string           `json:"tagNames" binding:"required,min=1"`
+	Filter   filter.AssetFilter `json:"filter"`
+}
+
+type BulkTagResponse struct {
+	Action        string   `json:"action"`
+	TagNames      []string `json:"tagNames"`
+	AffectedAsset int      `json:"affectedAssets"`
+}
+
+type TagResponse struct {
+	Id      int64  `json:"id"`
+	TagName string `json:"tagName"`
+}
+
+type TagCount struct {
+	Id      int64  `json:"id"`
+	TagName string `json:"tagName"`
+	Total   int    `json:"total"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+type TcoFilterRequest struct {
+	CategoryId   *int64 `form:"categoryId"`
+	DepartmentId *int64 `form:"departmentId"`
+	SortBy       string `form:"sortBy" binding:"omitempty,oneof=total average annual maintenance purchase count"`
+	Order        string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1"`
+}
+
+type AssetTcoResponse struct {
+	AssetId          int64   `json:"assetId"`
+	AssetName        string  `json:"assetName"`
+	SerialNumber     string  `json:"serialNumber"`
+	Status           string  `json:"status"`
+	CategoryId       int64   `json:"categoryId"`
+	CategoryName     string  `json:"categoryName"`
+	DepartmentId     int64   `json:"departmentId"`
+	DepartmentName   string  `json:"departmentName"`
+	PurchaseCost     float64 `json:"purchaseCost"`
+	MaintenanceCost  float64 `json:"maintenanceCost"`
+	MaintenanceCount int64   `json:"maintenanceCount"`
+	ResidualValue    float64 `json:"residualValue"`
+	TotalCost        float64 `json:"totalCost"`
+	YearsOwned       float64 `json:"yearsOwned"`
+	AnnualCost       float64 `json:"annualCost"`
+}
+
+type TcoGroupResponse struct {
+	Id              int64   `json:"id"`
+	Name            string  `json:"name"`
+	AssetCount      int64   `json:"assetCount"`
+	PurchaseCost    float64 `json:"purchaseCost"`
+	MaintenanceCost float64 `json:"maintenanceCost"`
+	ResidualValue   float64 `json:"residualValue"`
+	TotalCost       float64 `json:"totalCost"`
+	AverageCost     float64 `json:"averageCost"`
+}
@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+type TwoFactorStatusResponse struct {
+	Enabled           bool       `json:"enabled"`
+	Enforced          bool       `json:"enforced"` // Công ty bắt buộc 2FA
+	EnabledAt         *time.Time `json:"enabledAt"`
+	RecoveryCodesLeft int64      `json:"recoveryCodesLeft"`
+}
+
+type TwoFactorEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthUrl string `json:"otpauthUrl"`
+	QrCode     string `json:"qrCode"` // Ảnh QR dạng data URL
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"` // Mã TOTP 6 số hoặc mã khôi phục
+}
+
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"` // Chỉ hiển thị một lần
+}
+
+type TwoFactorChallengeResponse struct {
+	ChallengeToken string    `json:"challengeToken"`
+	SetupRequired  bool      `json:"setupRequired"` // Phải đăng ký 2FA trước khi đăng nhập
+	ExpiresAt      time.Time `json:"expiresAt"`
+}
+
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+}
+
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type TwoFactorEnforcementRequest struct {
+	Required *bool `json:"required" binding:"required"`
+}
+
+type ResetTwoFactorRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type AuditLogFilterRequest struct {
+	Action *string `form:"action"`
+	UserId *int64  `form:"userId"`
+}
+
+type AuditLogResponse struct {
+	Id         int64                   `json:"id"`
+	Action     string                  `json:"action"`
+	Actor      *UserResponseInAssetLog `json:"actor"`
+	TargetUser *UserResponseInAssetLog `json:"targetUser"`
+	Detail     string                  `json:"detail"`
+	IpAddress  string                  `json:"ipAddress"`
+	CreatedAt  time.Time               `json:"createdAt"`
+}
@@ -0,0 +1,107 @@
+package dto
+
+import "time"
+
+type CreateConsumableRequest struct {
+	Name     string  `json:"name" binding:"required"`
+	Unit     string  `json:"unit"`
+	Quantity float64 `json:"quantity" binding:"omitempty,gte=0"`
+	UnitCost float64 `json:"unitCost" binding:"omitempty,gte=0"`
+}
+
+type AdjustConsumableRequest struct {
+	Delta float64 `json:"delta" binding:"required"`
+}
+
+type ConsumableResponse struct {
+	Id       int64   `json:"id"`
+	Name     string  `json:"name"`
+	Unit     string  `json:"unit"`
+	Quantity float64 `json:"quantity"`
+	UnitCost float64 `json:"unitCost"`
+}
+
+type CreateWorkOrderRequest struct {
+	ScheduleId       int64    `json:"scheduleId" binding:"required"`
+	Title            string   `json:"title" binding:"required"`
+	Description      string   `json:"description"`
+	TechnicianUserId *int64   `json:"technicianUserId"`
+	VendorName       *string  `json:"vendorName"`
+	LaborRate        float64  `json:"laborRate" binding:"omitempty,gte=0"`
+	Tasks            []string `json:"tasks"`
+}
+
+type UpdateWorkOrderRequest struct {
+	Title            string  `json:"title" binding:"required"`
+	Description      string  `json:"description"`
+	Status           string  `json:"status" binding:"omitempty,oneof=open in_progress cancelled"`
+	TechnicianUserId *int64  `json:"technicianUserId"`
+	VendorName       *string `json:"vendorName"`
+	LaborHours       float64 `json:"laborHours" binding:"omitempty,gte=0"`
+	LaborRate        float64 `json:"laborRate" binding:"omitempty,gte=0"`
+}
+
+type WorkOrderFilterRequest struct {
+	Status           *string `form:"status"`
+	ScheduleId       *int64  `form:"scheduleId"`
+	AssetId          *int64  `form:"assetId"`
+	TechnicianUserId *int64  `form:"technicianUserId"`
+}
+
+type CreateWorkOrderTaskRequest struct {
+	Description string `json:"description" binding:"required"`
+}
+
+type UpdateWorkOrderTaskRequest struct {
+	IsDone *bool `json:"isDone" binding:"required"`
+}
+
+type AddWorkOrderPartRequest struct {
+	ConsumableId int64   `json:"consumableId" binding:"required"`
+	Quantity     float64 `json:"quantity" binding:"required,gt=0"`
+}
+
+// Gửi dạng multipart/form-data, ảnh gửi qua field "photos"
+type CompleteWorkOrderRequest struct {
+	CompletionNotes string   `form:"completionNotes"`
+	LaborHours      *float64 `form:"laborHours" binding:"omitempty,gte=0"`
+}
+
+type WorkOrderTaskResponse struct {
+	Id          int64      `json:"id"`
+	Description string     `json:"description"`
+	IsDone      bool       `json:"isDone"`
+	DoneById    *int64     `json:"doneById"`
+	DoneAt      *time.Time `json:"doneAt"`
+}
+
+type WorkOrderPartResponse struct {
+	Id             int64   `json:"id"`
+	ConsumableId   int64   `json:"consumableId"`
+	ConsumableName string  `json:"consumableName"`
+	Unit           string  `json:"unit"`
+	Quantity       float64 `json:"quantity"`
+	UnitCost       float64 `json:"unitCost"`
+}
+
+type WorkOrderResponse struct {
+	Id                int64                               `json:"id"`
+	ScheduleId        int64                               `json:"scheduleId"`
+	ScheduleStartDate string                              `json:"scheduleStartDate"`
+	ScheduleEndDate   string                              `json:"scheduleEndDate"`
+	Asset             AssetResponseInMaintenanceSchedules `json:"asset"`
+	Title             string                              `json:"title"`
+	Description       string                              `json:"description"`
+	Status            string                              `json:"status"`
+	Technician        *UserResponseInAssetLog             `json:"technician"`
+	VendorName        *string                             `json:"vendorName"`
+	LaborHours        float64                             `json:"laborHours"`
+	LaborRate         float64                             `json:"laborRate"`
+	PartsCost         float64                             `json:"partsCost"`
+	TotalCost         float64                             `json:"totalCost"`
+	CompletionNotes   string                              `json:"completionNotes"`
+	CompletedAt       *time.Time                          `json:"completedAt"`
+	Tasks             []WorkOrderTaskResponse             `json:"tasks"`
+	Parts             []WorkOrderPartResponse             `json:"parts"`
+	Photos            []string                            `json:"photos"`
+}
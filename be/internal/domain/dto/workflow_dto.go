@@ -0,0 +1,99 @@
+package dto
+
+import "time"
+
+// Các bước cùng stepOrder chạy song song, khác stepOrder chạy tuần tự theo thứ tự tăng dần
+type WorkflowStepRequest struct {
+	StepOrder      int      `json:"stepOrder" binding:"required,min=1"`
+	Name           string   `json:"name" binding:"required"`
+	ApproverType   string   `json:"approverType" binding:"required,oneof=role department_head user"`
+	ApproverRole   *string  `json:"approverRole"`
+	ApproverUserId *int64   `json:"approverUserId"`
+	MinCost        *float64 `json:"minCost" binding:"omitempty,min=0"`
+}
+
+type WorkflowDefinitionRequest struct {
+	Name       string                `json:"name" binding:"required"`
+	EntityType string                `json:"entityType" binding:"required,oneof=transfer_request asset_retirement maintenance_schedule"`
+	IsActive   *bool                 `json:"isActive"`
+	Steps      []WorkflowStepRequest `json:"steps" binding:"required,min=1,dive"`
+}
+
+type WorkflowDefinitionFilterRequest struct {
+	EntityType *string `form:"entityType"`
+}
+
+type WorkflowInstanceFilterRequest struct {
+	Status     *string `form:"status"`
+	EntityType *string `form:"entityType"`
+	AwaitingMe bool    `form:"awaitingMe"` // Chỉ lấy yêu cầu đang chờ người dùng hiện tại duyệt
+}
+
+type ApproveWorkflowRequest struct {
+	Comment string `json:"comment"`
+}
+
+type RejectWorkflowRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type WorkflowStepResponse struct {
+	Id             int64    `json:"id"`
+	StepOrder      int      `json:"stepOrder"`
+	Name           string   `json:"name"`
+	ApproverType   string   `json:"approverType"`
+	ApproverRole   *string  `json:"approverRole"`
+	ApproverUserId *int64   `json:"approverUserId"`
+	MinCost        *float64 `json:"minCost"`
+}
+
+type WorkflowDefinitionResponse struct {
+	Id         int64                  `json:"id"`
+	Name       string                 `json:"name"`
+	EntityType string                 `json:"entityType"`
+	IsActive   bool                   `json:"isActive"`
+	CreatedAt  time.Time              `json:"createdAt"`
+	Steps      []WorkflowStepResponse `json:"steps"`
+}
+
+type WorkflowInstanceStepResponse struct {
+	Id             int64                   `json:"id"`
+	StepOrder      int                     `json:"stepOrder"`
+	Name           string                  `json:"name"`
+	ApproverType   string                  `json:"approverType"`
+	ApproverRole   *string                 `json:"approverRole"`
+	ApproverUserId *int64                  `json:"approverUserId"`
+	Status         string                  `json:"status"`
+	ActedBy        *UserResponseInAssetLog `json:"actedBy"`
+	ActedAt        *time.Time              `json:"actedAt"`
+	Comment        string                  `json:"comment"`
+}
+
+type WorkflowActionResponse struct {
+	Id             int64                  `json:"id"`
+	InstanceStepId *int64                 `json:"instanceStepId"`
+	Action         string                 `json:"action"`
+	ByUser         UserResponseInAssetLog `json:"byUser"`
+	Comment        string                 `json:"comment"`
+	CreatedAt      time.Time              `json:"createdAt"`
+}
+
+type WorkflowInstanceResponse struct {
+	Id               int64                          `json:"id"`
+	DefinitionId     int64                          `json:"definitionId"`
+	DefinitionName   string                         `json:"definitionName"`
+	EntityType       string                         `json:"entityType"`
+	EntityId         int64                          `json:"entityId"`
+	AssetId          int64                          `json:"assetId"`
+	AssetName        string                         `json:"assetName"`
+	DepartmentId     int64                          `json:"departmentId"`
+	Cost             float64                        `json:"cost"`
+	Status           string                         `json:"status"`
+	CurrentStepOrder int                            `json:"currentStepOrder"`
+	RequestedBy      UserResponseInAssetLog         `json:"requestedBy"`
+	RejectReason     *string                        `json:"rejectReason"`
+	CreatedAt        time.Time                      `json:"createdAt"`
+	FinishedAt       *time.Time                     `json:"finishedAt"`
+	Steps            []WorkflowInstanceStepResponse `json:"steps"`
+	Actions          []WorkflowActionResponse       `json:"actions"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+const (
+	RelationDependsOn   = "depends-on"
+	RelationConnectedTo = "connected-to"
+	RelationInstalledIn = "installed-in"
+)
+
+// AssetRelation cạnh có hướng: FromAsset <RelationType> ToAsset
+// (vd: màn hình connected-to máy trạm, license installed-in laptop, server installed-in rack)
+type AssetRelation struct {
+	Id           int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	FromAssetId  int64     `gorm:"uniqueIndex:idx_asset_relation" json:"fromAssetId"`
+	ToAssetId    int64     `gorm:"uniqueIndex:idx_asset_relation;index" json:"toAssetId"`
+	RelationType string    `gorm:"uniqueIndex:idx_asset_relation" json:"relationType"`
+	CreatedById  int64     `json:"createdById"`
+	CreatedAt    time.Time `json:"createdAt"`
+	CompanyId    int64     `json:"-"`
+
+	FromAsset Assets `gorm:"foreignKey:FromAssetId;references:Id" json:"-"`
+	ToAsset   Assets `gorm:"foreignKey:ToAssetId;references:Id" json:"-"`
+}
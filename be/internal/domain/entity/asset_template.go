@@ -0,0 +1,16 @@
+package entity
+
+type AssetTemplate struct {
+	Id                 int64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	TemplateName       string   `gorm:"uniqueIndex:idx_template_company" json:"templateName"`
+	AssetName          string   `json:"assetName"`
+	CategoryId         int64    `json:"categoryId"`
+	Cost               float64  `json:"cost"`
+	WarrantyMonths     int      `json:"warrantyMonths"`     //Số tháng bảo hành tính từ ngày mua
+	AnnualDepreciation *float64 `json:"annualDepreciation"` //Nguyên giá tài sản
+	ResidualValue      *float64 `json:"residualValue"`      //Giá trị thu hồi dự kiến
+	UsefulLife         *float64 `json:"usefulLife"`         //Thời gian sử dụng dự kiến
+	CompanyId          int64    `gorm:"uniqueIndex:idx_template_company" json:"-"`
+
+	Category Categories `gorm:"foreignKey:CategoryId;references:Id"`
+}
@@ -17,6 +17,7 @@ type Assets struct {
 	ImageUpload          *string    `json:"image"`
 	CategoryId           int64      `json:"categoryId"`
 	DepartmentId         int64      `json:"departmentId"`
+	RoomId               *int64     `json:"roomId"`
 	QrUrl                *string    `json:"qrUrl"`
 	RetiredOrDisposeTime *time.Time `json:"-"`
 	CompanyId            int64      `json:"-"`
@@ -29,4 +30,6 @@ type Assets struct {
 	Category   Categories  `gorm:"foreignKey:CategoryId;references:Id"`
 	Department Departments `gorm:"foreignKey:DepartmentId;references:Id"`
 	OnwerUser  *Users      `gorm:"foreignKey:Owner;references:Id"`
+	Room       *Locations  `gorm:"foreignKey:RoomId;references:Id"`
+	Tags       []Tags      `gorm:"many2many:asset_tags;joinForeignKey:AssetId;joinReferences:TagId"`
 }
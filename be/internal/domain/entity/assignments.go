@@ -1,15 +1,37 @@
 package entity
 
+import "time"
+
 type Assignments struct {
-	Id           int64  `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserId       *int64 `json:"userId"`
-	AssetId      int64  `gorm:"index:unique_AssetId,unique" json:"assetId"`
-	AssignBy     int64  `json:"assetBy"`
-	DepartmentId *int64 `json:"departmentID"`
-	CompanyId    int64
+	Id                 int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserId             *int64 `json:"userId"`
+	AssetId            int64  `gorm:"index:unique_AssetId,unique" json:"assetId"`
+	AssignBy           int64  `json:"assetBy"`
+	DepartmentId       *int64 `json:"departmentID"`
+	CompanyId          int64
+	HandoverReceiptUrl *string `json:"handoverReceiptUrl"` // Biên bản bàn giao đã ký của lần giao gần nhất
 
 	UserAssigned Users       `gorm:"foreignKey:UserId;references:Id"`
 	UserAssign   Users       `gorm:"foreignKey:AssignBy;references:Id"`
 	Asset        Assets      `gorm:"foreignKey:AssetId;references:Id"`
 	Department   Departments `gorm:"foreignKey:DepartmentId;references:Id"`
 }
+
+// AssignmentHistory lưu từng giai đoạn giữ tài sản, EndedAt nil là người đang giữ hiện tại
+type AssignmentHistory struct {
+	Id           int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AssignmentId int64      `gorm:"index" json:"assignmentId"`
+	AssetId      int64      `gorm:"index" json:"assetId"`
+	UserId       *int64     `gorm:"index" json:"userId"`
+	DepartmentId *int64     `json:"departmentId"`
+	AssignBy     int64      `json:"assignBy"`
+	Reason       string     `json:"reason"`
+	StartedAt    time.Time  `gorm:"index" json:"startedAt"`
+	EndedAt      *time.Time `json:"endedAt"`
+	CompanyId    int64      `json:"-"`
+
+	UserAssigned *Users       `gorm:"foreignKey:UserId;references:Id"`
+	UserAssign   Users        `gorm:"foreignKey:AssignBy;references:Id"`
+	Asset        Assets       `gorm:"foreignKey:AssetId;references:Id"`
+	Department   *Departments `gorm:"foreignKey:DepartmentId;references:Id"`
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+const (
+	AuditTwoFactorEnabled           = "2fa_enabled"
+	AuditTwoFactorDisabled          = "2fa_disabled"
+	AuditTwoFactorReset             = "2fa_reset"
+	AuditTwoFactorRecoveryUsed      = "2fa_recovery_code_used"
+	AuditTwoFactorRecoveryRenewed   = "2fa_recovery_codes_regenerated"
+	AuditTwoFactorEnforcementChange = "2fa_enforcement_changed"
+	AuditSsoConfigUpdated           = "sso_config_updated"
+	AuditSsoLogin                   = "sso_login"
+	AuditSsoUserProvisioned         = "sso_user_provisioned"
+	AuditLdapConfigUpdated          = "ldap_config_updated"
+	AuditScimTokenCreated           = "scim_token_created"
+	AuditScimTokenRevoked           = "scim_token_revoked"
+	AuditScimUserProvisioned        = "scim_user_provisioned"
+	AuditScimUserDeprovisioned      = "scim_user_deprovisioned"
+	AuditServiceAccountCreated      = "service_account_created"
+	AuditServiceAccountDisabled     = "service_account_disabled"
+	AuditApiKeyCreated              = "api_key_created"
+	AuditApiKeyRevoked              = "api_key_revoked"
+)
+
+// AuditLog nhật ký các thao tác bảo mật trên tài khoản
+type AuditLog struct {
+	Id           int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Action       string    `gorm:"index" json:"action"`
+	ActorId      *int64    `json:"actorId"` // nil khi do hệ thống thực hiện
+	TargetUserId *int64    `gorm:"index" json:"targetUserId"`
+	Detail       string    `json:"detail"`
+	IpAddress    string    `json:"ipAddress"`
+	CreatedAt    time.Time `json:"createdAt"`
+	CompanyId    int64     `gorm:"index" json:"-"`
+
+	Actor      *Users `gorm:"foreignKey:ActorId;references:Id"`
+	TargetUser *Users `gorm:"foreignKey:TargetUserId;references:Id"`
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// CalendarFeed link ICS có token để lịch (Google/Outlook...) đăng ký, thu hồi bằng RevokedAt
+type CalendarFeed struct {
+	Id             int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name           string     `json:"name"`
+	UserId         int64      `gorm:"index" json:"userId"`
+	DepartmentId   *int64     `json:"departmentId"`                  // nil: feed cá nhân
+	TokenHash      string     `gorm:"uniqueIndex;not null" json:"-"` // Chỉ lưu SHA-256 của token
+	CreatedAt      time.Time  `json:"createdAt"`
+	LastAccessedAt *time.Time `json:"lastAccessedAt"`
+	RevokedAt      *time.Time `json:"revokedAt"`
+	CompanyId      int64      `json:"-"`
+
+	User       Users        `gorm:"foreignKey:UserId;references:Id"`
+	Department *Departments `gorm:"foreignKey:DepartmentId;references:Id"`
+}
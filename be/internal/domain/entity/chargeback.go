@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// DepartmentChargeback tổng chi phí phân bổ cho một phòng ban trong một kỳ (tháng)
+type DepartmentChargeback struct {
+	Id                 int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Month              int64     `gorm:"uniqueIndex:idx_chargeback_period" json:"month"`
+	Year               int64     `gorm:"uniqueIndex:idx_chargeback_period" json:"year"`
+	DepartmentId       int64     `gorm:"uniqueIndex:idx_chargeback_period" json:"departmentId"`
+	CompanyId          int64     `gorm:"uniqueIndex:idx_chargeback_period" json:"-"`
+	AssetCount         int64     `json:"assetCount"`
+	DepreciationAmount float64   `json:"depreciationAmount"`
+	MaintenanceAmount  float64   `json:"maintenanceAmount"`
+	TotalAmount        float64   `json:"totalAmount"`
+	GeneratedAt        time.Time `json:"generatedAt"`
+
+	Department Departments `gorm:"foreignKey:DepartmentId;references:Id"`
+}
+
+// ChargebackLine chi tiết phân bổ theo từng tài sản trong kỳ
+type ChargebackLine struct {
+	Id                 int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Month              int64   `gorm:"index:idx_chargeback_line_period" json:"month"`
+	Year               int64   `gorm:"index:idx_chargeback_line_period" json:"year"`
+	CompanyId          int64   `gorm:"index:idx_chargeback_line_period" json:"-"`
+	DepartmentId       int64   `json:"departmentId"`
+	AssetId            int64   `json:"assetId"`
+	DaysHeld           float64 `json:"daysHeld"`
+	DepreciationAmount float64 `json:"depreciationAmount"`
+	MaintenanceAmount  float64 `json:"maintenanceAmount"`
+
+	Department Departments `gorm:"foreignKey:DepartmentId;references:Id"`
+	Asset      Assets      `gorm:"foreignKey:AssetId;references:Id"`
+}
@@ -4,4 +4,6 @@ type Company struct {
 	Id          int64  `gorm:"primaryKey;autoIncrement" json:"id"`
 	CompanyName string `gorm:"unique" json:"companyName"`
 	Email       string `gorm:"unique" json:"email"`
+	// Bắt buộc mọi người dùng bật xác thực hai lớp
+	RequireTwoFactor bool `gorm:"not null;default:false" json:"requireTwoFactor"`
 }
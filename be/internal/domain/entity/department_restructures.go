@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+const (
+	RestructureMerge  = "merge"
+	RestructureSplit  = "split"
+	RestructureRename = "rename"
+)
+
+const (
+	RestructureDraft   = "Draft" // Mới xem trước, chưa thay đổi dữ liệu
+	RestructureApplied = "Applied"
+	RestructureUndone  = "Undone"
+)
+
+// Loại dữ liệu được chuyển phòng ban khi tái cơ cấu
+const (
+	RestructureItemUser             = "user"
+	RestructureItemAsset            = "asset"
+	RestructureItemAssignment       = "assignment"
+	RestructureItemWorkflowInstance = "workflow_instance"
+	RestructureItemCalendarFeed     = "calendar_feed"
+	RestructureItemOffboarding      = "offboarding"
+)
+
+// DepartmentRestructure một lần gộp, tách hoặc đổi tên phòng ban. Được tạo ở trạng thái Draft để xem trước,
+// áp dụng trong một transaction và có thể hoàn tác trong thời hạn UndoDeadline
+type DepartmentRestructure struct {
+	Id                 int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Type               string     `json:"type"`
+	Status             string     `gorm:"index" json:"status"`
+	SourceDepartmentId int64      `json:"sourceDepartmentId"`
+	TargetDepartmentId *int64     `json:"targetDepartmentId"` // Phòng ban nhận khi gộp, phòng ban mới khi tách
+	Name               string     `json:"name"`               // Tên mới khi đổi tên hoặc tên phòng ban tách ra
+	OldName            string     `json:"oldName"`
+	LocationId         *int64     `json:"locationId"`
+	Payload            string     `gorm:"type:text" json:"-"` // Danh sách người dùng, tài sản được tách (JSON)
+	CreatedById        int64      `json:"createdById"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	AppliedById        *int64     `json:"appliedById"`
+	AppliedAt          *time.Time `json:"appliedAt"`
+	UndoDeadline       *time.Time `json:"undoDeadline"`
+	UndoneAt           *time.Time `json:"undoneAt"`
+	CompanyId          int64      `json:"-"`
+
+	SourceDepartment Departments                 `gorm:"foreignKey:SourceDepartmentId;references:Id"`
+	TargetDepartment *Departments                `gorm:"foreignKey:TargetDepartmentId;references:Id"`
+	CreatedBy        Users                       `gorm:"foreignKey:CreatedById;references:Id"`
+	Items            []DepartmentRestructureItem `gorm:"foreignKey:RestructureId;references:Id"`
+}
+
+// DepartmentRestructureItem một bản ghi đã được chuyển phòng ban, dùng để hoàn tác
+type DepartmentRestructureItem struct {
+	Id               int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	RestructureId    int64  `gorm:"index" json:"restructureId"`
+	EntityType       string `json:"entityType"`
+	EntityId         int64  `json:"entityId"`
+	Label            string `json:"label"`
+	FromDepartmentId int64  `json:"fromDepartmentId"`
+	ToDepartmentId   int64  `json:"toDepartmentId"`
+	Reverted         bool   `json:"reverted"`
+}
@@ -5,6 +5,7 @@ type Departments struct {
 	DepartmentName string `gorm:"uniqueIndex:uniq_dept_location_company" json:"departmentName"`
 	LocationId     int64  `gorm:"uniqueIndex:uniq_dept_location_company" json:"locationId"`
 	CompanyId      int64  `gorm:"uniqueIndex:uniq_dept_location_company" json:"-"`
+	MergedIntoId   *int64 `json:"mergedIntoId"` // Đã gộp vào phòng ban khác, ẩn khỏi danh sách
 
 	Location Locations `gorm:"foreignKey:LocationId;references:Id"`
 }
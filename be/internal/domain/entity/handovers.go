@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+const (
+	HandoverPending    = "Pending"
+	HandoverAccepted   = "Accepted"
+	HandoverSuperseded = "Superseded" // Tài sản đã được giao cho người khác trước khi xác nhận
+)
+
+// HandoverAcknowledgement xác nhận đã nhận tài sản của người được giao
+type HandoverAcknowledgement struct {
+	Id             int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AssignmentId   int64      `gorm:"index" json:"assignmentId"`
+	AssetId        int64      `gorm:"index" json:"assetId"`
+	UserId         int64      `gorm:"index" json:"userId"` // Người nhận
+	AssignedById   int64      `json:"assignedById"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	AcceptedAt     *time.Time `json:"acceptedAt"`
+	IpAddress      string     `json:"ipAddress"`
+	ConditionGrade string     `json:"conditionGrade"` // A-E như biên bản kiểm tra
+	ConditionNote  string     `json:"conditionNote"`
+	SignatureUrl   string     `json:"signatureUrl"` // Url ảnh chữ ký
+	ReceiptUrl     string     `json:"receiptUrl"`   // Url biên bản PDF
+	Signature      string     `json:"signature"`    // HMAC-SHA256 của biên bản
+	LastReminderAt *time.Time `json:"-"`
+	CompanyId      int64      `json:"-"`
+
+	Asset      Assets `gorm:"foreignKey:AssetId;references:Id"`
+	User       Users  `gorm:"foreignKey:UserId;references:Id"`
+	AssignedBy Users  `gorm:"foreignKey:AssignedById;references:Id"`
+}
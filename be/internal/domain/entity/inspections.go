@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+// InspectionTemplate checklist kiểm tra định kỳ theo danh mục tài sản
+type InspectionTemplate struct {
+	Id           int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name         string    `json:"name"`
+	CategoryId   int64     `gorm:"index" json:"categoryId"`
+	IntervalDays int       `json:"intervalDays"` // Chu kỳ kiểm tra
+	IsActive     bool      `gorm:"not null;default:true" json:"isActive"`
+	CreatedById  int64     `json:"createdById"`
+	CreatedAt    time.Time `json:"createdAt"`
+	CompanyId    int64     `json:"-"`
+
+	Category Categories               `gorm:"foreignKey:CategoryId;references:Id"`
+	Items    []InspectionTemplateItem `gorm:"foreignKey:TemplateId;references:Id"`
+}
+
+type InspectionTemplateItem struct {
+	Id          int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	TemplateId  int64  `gorm:"index" json:"templateId"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+	SortOrder   int    `json:"sortOrder"`
+}
+
+// Inspection một lần kiểm tra tài sản theo template
+type Inspection struct {
+	Id                 int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	AssetId            int64     `gorm:"index" json:"assetId"`
+	TemplateId         int64     `gorm:"index" json:"templateId"`
+	InspectorId        int64     `json:"inspectorId"`
+	InspectedAt        time.Time `json:"inspectedAt"`
+	Passed             bool      `json:"passed"`
+	ConditionGrade     string    `gorm:"type:varchar(1)" json:"conditionGrade"` // A (tốt nhất) - E
+	Notes              string    `json:"notes"`
+	InspectorSignature string    `json:"inspectorSignature"` // Url ảnh chữ ký
+	WitnessName        *string   `json:"witnessName"`
+	WitnessSignature   *string   `json:"witnessSignature"`
+	ScheduleId         *int64    `json:"scheduleId"` // Lịch bảo trì tạo tự động khi không đạt
+	CompanyId          int64     `json:"-"`
+
+	Asset     Assets                 `gorm:"foreignKey:AssetId;references:Id"`
+	Template  InspectionTemplate     `gorm:"foreignKey:TemplateId;references:Id"`
+	Inspector Users                  `gorm:"foreignKey:InspectorId;references:Id"`
+	Items     []InspectionItemResult `gorm:"foreignKey:InspectionId;references:Id"`
+}
+
+// InspectionItemResult kết quả từng mục, lưu lại label để không đổi khi template bị sửa
+type InspectionItemResult struct {
+	Id             int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	InspectionId   int64  `gorm:"index" json:"inspectionId"`
+	TemplateItemId int64  `json:"templateItemId"`
+	Label          string `json:"label"`
+	Passed         bool   `json:"passed"`
+	Note           string `json:"note"`
+}
@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+const (
+	IssueTicketOpen      = "open"
+	IssueTicketTriaged   = "triaged"
+	IssueTicketConverted = "converted" // Đã chuyển thành lịch bảo trì
+	IssueTicketResolved  = "resolved"
+	IssueTicketRejected  = "rejected"
+	IssueTicketClosed    = "closed"
+)
+
+// IssueTicket sự cố do người giữ tài sản hoặc người quét QR báo cáo
+type IssueTicket struct {
+	Id           int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AssetId      int64      `gorm:"index" json:"assetId"`
+	ReportedById int64      `gorm:"index" json:"reportedById"`
+	Source       string     `json:"source"` // holder | qr
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	Severity     string     `gorm:"default:medium" json:"severity"` // low | medium | high | critical
+	Status       string     `gorm:"default:open;index" json:"status"`
+	AssignedToId *int64     `json:"assignedToId"`
+	ScheduleId   *int64     `json:"scheduleId"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	ClosedAt     *time.Time `json:"closedAt"`
+	CompanyId    int64      `json:"-"`
+
+	Asset      Assets                `gorm:"foreignKey:AssetId;references:Id"`
+	ReportedBy Users                 `gorm:"foreignKey:ReportedById;references:Id"`
+	AssignedTo *Users                `gorm:"foreignKey:AssignedToId;references:Id"`
+	Schedule   *MaintenanceSchedules `gorm:"foreignKey:ScheduleId;references:Id"`
+	Photos     []IssueTicketPhoto    `gorm:"foreignKey:TicketId;references:Id"`
+	Comments   []IssueTicketComment  `gorm:"foreignKey:TicketId;references:Id"`
+}
+
+type IssueTicketPhoto struct {
+	Id        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TicketId  int64     `gorm:"index" json:"ticketId"`
+	Url       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type IssueTicketComment struct {
+	Id        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TicketId  int64     `gorm:"index" json:"ticketId"`
+	UserId    int64     `json:"userId"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	User Users `gorm:"foreignKey:UserId;references:Id"`
+}
@@ -0,0 +1,70 @@
+package entity
+
+import "time"
+
+const (
+	LdapSyncRunning = "Running"
+	LdapSyncSuccess = "Success"
+	LdapSyncFailed  = "Failed"
+
+	LdapSyncTriggerSchedule = "Schedule"
+	LdapSyncTriggerManual   = "Manual"
+)
+
+// CompanyLdap cấu hình đồng bộ người dùng, phòng ban từ LDAP/Active Directory của công ty
+type CompanyLdap struct {
+	Id                 int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	CompanyId          int64  `gorm:"uniqueIndex" json:"-"`
+	Url                string `json:"url"` // ldap://host:389 hoặc ldaps://host:636
+	StartTls           bool   `gorm:"not null;default:false" json:"startTls"`
+	InsecureSkipVerify bool   `gorm:"not null;default:false" json:"insecureSkipVerify"`
+	BindDn             string `json:"bindDn"`
+	BindPassword       string `json:"-"` // Đã mã hoá AES-GCM
+	BaseDn             string `json:"baseDn"`
+	UserFilter         string `json:"userFilter"`
+	OrgUnitFilter      string `json:"orgUnitFilter"`
+	// Tên thuộc tính LDAP
+	UniqueIdAttribute    string `json:"uniqueIdAttribute"` // entryUUID hoặc objectGUID
+	EmailAttribute       string `json:"emailAttribute"`
+	FirstNameAttribute   string `json:"firstNameAttribute"`
+	LastNameAttribute    string `json:"lastNameAttribute"`
+	GroupAttribute       string `json:"groupAttribute"`
+	OrgUnitNameAttribute string `json:"orgUnitNameAttribute"`
+	// DN nhóm -> slug vai trò (JSON), rỗng thì không đổi vai trò người dùng đã có
+	RoleMapping       string     `gorm:"type:text" json:"-"`
+	DefaultRoleSlug   string     `json:"defaultRoleSlug"`
+	LocationId        int64      `json:"locationId"` // Vị trí của phòng ban tạo từ org unit
+	SyncEnabled       bool       `gorm:"not null;default:false" json:"syncEnabled"`
+	SyncIntervalHours int        `json:"syncIntervalHours"`
+	LoginEnabled      bool       `gorm:"not null;default:false" json:"loginEnabled"` // Cho đăng nhập bằng mật khẩu LDAP
+	LastSyncAt        *time.Time `json:"lastSyncAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+// LdapDepartment liên kết org unit với phòng ban đã tạo
+type LdapDepartment struct {
+	Id           int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	CompanyId    int64  `gorm:"uniqueIndex:idx_ldap_department" json:"-"`
+	ExternalId   string `gorm:"uniqueIndex:idx_ldap_department" json:"externalId"`
+	Dn           string `json:"dn"`
+	DepartmentId int64  `json:"departmentId"`
+}
+
+// LdapSyncReport kết quả một lần đồng bộ
+type LdapSyncReport struct {
+	Id                 int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Trigger            string     `json:"trigger"`
+	Status             string     `json:"status"`
+	StartedAt          time.Time  `json:"startedAt"`
+	FinishedAt         *time.Time `json:"finishedAt"`
+	DepartmentsCreated int        `json:"departmentsCreated"`
+	DepartmentsUpdated int        `json:"departmentsUpdated"`
+	UsersCreated       int        `json:"usersCreated"`
+	UsersUpdated       int        `json:"usersUpdated"`
+	UsersDeactivated   int        `json:"usersDeactivated"`
+	UsersReactivated   int        `json:"usersReactivated"`
+	UsersSkipped       int        `json:"usersSkipped"`
+	Errors             string     `gorm:"type:text" json:"-"` // Danh sách lỗi từng mục (JSON)
+	TriggeredById      *int64     `json:"triggeredById"`
+	CompanyId          int64      `gorm:"index" json:"-"`
+}
@@ -1,6 +1,24 @@
 package entity
 
+const (
+	LocationTypeSite     = "site"
+	LocationTypeBuilding = "building"
+	LocationTypeFloor    = "floor"
+	LocationTypeRoom     = "room"
+)
+
+// LocationLevels thứ tự các cấp trong cây vị trí: site > building > floor > room
+var LocationLevels = []string{LocationTypeSite, LocationTypeBuilding, LocationTypeFloor, LocationTypeRoom}
+
 type Locations struct {
-	Id           int64  `gorm:"primaryKey;autoIncrement" json:"id"`
-	LocationName string `json:"locationAddress"`
+	Id           int64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	LocationName string   `json:"locationAddress"`
+	LocationType string   `gorm:"default:site" json:"locationType"`
+	ParentId     *int64   `gorm:"index" json:"parentId"`
+	Path         string   `gorm:"index" json:"-"` // dạng "/1/4/9/", dùng để lọc theo cây con
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	CompanyId    int64    `gorm:"index" json:"-"`
+
+	Parent *Locations `gorm:"foreignKey:ParentId;references:Id" json:"-"`
 }
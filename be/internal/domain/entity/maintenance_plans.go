@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// MaintenancePlan kế hoạch bảo trì định kỳ cho một tài sản hoặc cả một danh mục.
+// RRule theo tập con RFC 5545 (FREQ, INTERVAL, COUNT, UNTIL), mỗi lần lặp sinh một MaintenanceSchedules.
+type MaintenancePlan struct {
+	Id           int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	PlanName     string    `json:"planName"`
+	AssetId      *int64    `json:"assetId"`
+	CategoryId   *int64    `json:"categoryId"`
+	RRule        string    `gorm:"column:rrule" json:"rrule"`
+	StartDate    time.Time `json:"startDate"`
+	DurationDays int       `gorm:"default:1" json:"durationDays"`
+	Cost         float64   `gorm:"default:0" json:"cost"` // Chi phí dự kiến cho mỗi lần bảo trì
+	IsActive     bool      `gorm:"default:true" json:"isActive"`
+	CreatedById  int64     `json:"createdById"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	CompanyId    int64     `json:"-"`
+
+	Asset    *Assets     `gorm:"foreignKey:AssetId;references:Id"`
+	Category *Categories `gorm:"foreignKey:CategoryId;references:Id"`
+}
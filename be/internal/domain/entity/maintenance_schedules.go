@@ -7,6 +7,11 @@ type MaintenanceSchedules struct {
 	AssetId   int64
 	StartDate time.Time
 	EndDate   time.Time
+	Cost      float64   `gorm:"default:0" json:"cost"`                      // Chi phí bảo trì
+	PlanId    *int64    `gorm:"index" json:"planId"`                        // Sinh ra từ kế hoạch bảo trì định kỳ
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"` // Dùng làm SEQUENCE/LAST-MODIFIED của feed ICS
+
+	MeterReadingId *int64 `gorm:"index" json:"meterReadingId"` // Sinh ra khi chỉ số đồng hồ vượt ngưỡng
 
 	Asset Assets `gorm:"foreignKey:AssetId;references:Id"`
 }
@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// MeterDefinition đồng hồ đo mức sử dụng theo danh mục: số trang in, số km, giờ chạy máy...
+type MeterDefinition struct {
+	Id          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `json:"name"`
+	Unit        string    `json:"unit"`
+	CategoryId  int64     `gorm:"index" json:"categoryId"`
+	IsActive    bool      `gorm:"not null" json:"isActive"`
+	CreatedById int64     `json:"createdById"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CompanyId   int64     `json:"-"`
+
+	Category   Categories       `gorm:"foreignKey:CategoryId;references:Id"`
+	Thresholds []MeterThreshold `gorm:"foreignKey:MeterId;references:Id"`
+}
+
+// MeterThreshold ngưỡng bảo trì theo mức sử dụng, ví dụ mỗi 10,000 km
+type MeterThreshold struct {
+	Id           int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	MeterId      int64   `gorm:"index" json:"meterId"`
+	Name         string  `json:"name"`
+	Interval     float64 `json:"interval"` // Mỗi khi chỉ số vượt qua bội số của Interval thì tạo lịch bảo trì
+	DurationDays int     `gorm:"default:1" json:"durationDays"`
+	Cost         float64 `gorm:"default:0" json:"cost"` // Chi phí dự kiến cho mỗi lần bảo trì
+	IsActive     bool    `gorm:"not null" json:"isActive"`
+}
+
+// MeterReading chỉ số đọc của tài sản, giá trị luỹ kế không được giảm
+type MeterReading struct {
+	Id           int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	AssetId      int64     `gorm:"index" json:"assetId"`
+	MeterId      int64     `gorm:"index" json:"meterId"`
+	Value        float64   `json:"value"`
+	ReadAt       time.Time `json:"readAt"`
+	RecordedById int64     `json:"recordedById"`
+	Note         string    `json:"note"`
+	ScheduleId   *int64    `json:"scheduleId"` // Lịch bảo trì tạo tự động khi vượt ngưỡng
+	CompanyId    int64     `json:"-"`
+
+	Meter      MeterDefinition `gorm:"foreignKey:MeterId;references:Id"`
+	RecordedBy Users           `gorm:"foreignKey:RecordedById;references:Id"`
+}
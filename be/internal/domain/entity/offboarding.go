@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+const (
+	OffboardingInProgress = "In Progress"
+	OffboardingCompleted  = "Completed"
+	OffboardingCancelled  = "Cancelled"
+
+	OffboardingItemPending  = "Pending"
+	OffboardingItemReturned = "Returned"
+	OffboardingItemMissing  = "Missing"
+)
+
+// Offboarding quy trình nghỉ việc của một người dùng: checklist thu hồi tài sản, bàn giao và khoá tài khoản
+type Offboarding struct {
+	Id                 int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserId             int64      `gorm:"index" json:"userId"`
+	Status             string     `json:"status"`
+	StartedById        int64      `json:"startedById"`
+	StartedAt          time.Time  `json:"startedAt"`
+	CompletedById      *int64     `json:"completedById"`
+	CompletedAt        *time.Time `json:"completedAt"`
+	TargetUserId       *int64     `json:"targetUserId"`
+	TargetDepartmentId *int64     `json:"targetDepartmentId"`
+	Note               string     `json:"note"`
+	Signature          string     `json:"signature"` // HMAC-SHA256 của bản tóm tắt khi hoàn tất
+	CompanyId          int64      `json:"-"`
+
+	User             Users              `gorm:"foreignKey:UserId;references:Id"`
+	StartedBy        Users              `gorm:"foreignKey:StartedById;references:Id"`
+	CompletedBy      *Users             `gorm:"foreignKey:CompletedById;references:Id"`
+	TargetUser       *Users             `gorm:"foreignKey:TargetUserId;references:Id"`
+	TargetDepartment *Departments       `gorm:"foreignKey:TargetDepartmentId;references:Id"`
+	Items            []*OffboardingItem `gorm:"foreignKey:OffboardingId;references:Id"`
+}
+
+type OffboardingItem struct {
+	Id            int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	OffboardingId int64      `gorm:"index" json:"offboardingId"`
+	AssetId       int64      `json:"assetId"`
+	AssignmentId  int64      `json:"assignmentId"`
+	Status        string     `json:"status"`
+	Note          string     `json:"note"`
+	CheckedById   *int64     `json:"checkedById"`
+	CheckedAt     *time.Time `json:"checkedAt"`
+
+	Asset Assets `gorm:"foreignKey:AssetId;references:Id"`
+}
@@ -1,13 +1,66 @@
 package entity
 
+import "time"
+
+const (
+	RequestTransferPending    = "Pending"
+	RequestTransferInApproval = "In Approval" // Đang chạy quy trình duyệt
+	RequestTransferApproved   = "Approved"    // Đã duyệt, chưa giao đủ số lượng
+	RequestTransferDenied     = "Denied"
+	RequestTransferCancelled  = "Cancelled"
+	RequestTransferFulfilled  = "Fulfilled"
+)
+
 type RequestTransfer struct {
-	Id          int64  `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserId      int64  `json:"userId"`
-	CategoryId  int64  `json:"categoryId"`
-	Status      string `json:"status"`
-	Description string `json:"description"`
-	CompanyId   int64  `json:"-"`
+	Id                int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserId            int64      `json:"userId"`
+	CategoryId        int64      `json:"categoryId"`
+	AssetId           *int64     `json:"assetId"` // Yêu cầu một tài sản cụ thể
+	Quantity          int        `gorm:"not null;default:1" json:"quantity"`
+	FulfilledQuantity int        `gorm:"not null;default:0" json:"fulfilledQuantity"`
+	NeededBy          *time.Time `json:"neededBy"`
+	Status            string     `json:"status"`
+	Description       string     `json:"description"`
+	CreatedAt         time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt         time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	LastReminderAt    *time.Time `json:"-"` // Lần nhắc SLA gần nhất
+	CompanyId         int64      `json:"-"`
 
 	User     Users      `gorm:"foreignKey:UserId;references:Id"`
 	Category Categories `gorm:"foreignKey:CategoryId;references:Id"`
+	Asset    *Assets    `gorm:"foreignKey:AssetId;references:Id"`
+}
+
+// RequestTransferHistory lịch sử đổi trạng thái của yêu cầu, ByUserId nil là hệ thống
+type RequestTransferHistory struct {
+	Id         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	RequestId  int64     `gorm:"index" json:"requestId"`
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	ByUserId   *int64    `json:"byUserId"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"createdAt"`
+
+	ByUser *Users `gorm:"foreignKey:ByUserId;references:Id"`
+}
+
+type RequestTransferComment struct {
+	Id        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	RequestId int64     `gorm:"index" json:"requestId"`
+	UserId    int64     `json:"userId"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	User Users `gorm:"foreignKey:UserId;references:Id"`
+}
+
+// RequestTransferAsset tài sản đã giao cho yêu cầu
+type RequestTransferAsset struct {
+	Id            int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	RequestId     int64     `gorm:"index" json:"requestId"`
+	AssetId       int64     `json:"assetId"`
+	ByUserId      int64     `json:"byUserId"`
+	TransferredAt time.Time `json:"transferredAt"`
+
+	Asset Assets `gorm:"foreignKey:AssetId;references:Id"`
 }
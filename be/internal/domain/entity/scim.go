@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// ScimToken bearer token cho IdP (Okta, Entra ID...) gọi API SCIM của công ty
+type ScimToken struct {
+	Id          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string     `json:"name"`
+	TokenHash   string     `gorm:"uniqueIndex;not null" json:"-"` // Chỉ lưu SHA-256 của token
+	Prefix      string     `json:"prefix"`                        // Vài ký tự đầu để nhận biết token
+	LocationId  *int64     `json:"locationId"`                    // Vị trí của phòng ban tạo qua /Groups
+	CreatedById int64      `json:"createdById"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	LastUsedAt  *time.Time `json:"lastUsedAt"`
+	RevokedAt   *time.Time `json:"revokedAt"`
+	CompanyId   int64      `gorm:"index" json:"-"`
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// ApiKeyPrefix tiền tố để AuthMiddleware phân biệt API key với JWT
+const ApiKeyPrefix = "sk_"
+
+// ServiceAccount tài khoản máy cho tích hợp (ERP...), gắn với một người dùng hệ thống để dùng chung phân quyền theo vai trò
+type ServiceAccount struct {
+	Id          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	UserId      int64      `gorm:"uniqueIndex" json:"userId"`
+	CreatedById int64      `json:"createdById"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	DisabledAt  *time.Time `json:"disabledAt"`
+	CompanyId   int64      `gorm:"index" json:"-"`
+
+	User Users `gorm:"foreignKey:UserId;references:Id"`
+}
+
+// ApiKey khoá của service account, chỉ lưu SHA-256
+type ApiKey struct {
+	Id               int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ServiceAccountId int64      `gorm:"index" json:"serviceAccountId"`
+	Name             string     `json:"name"`
+	KeyHash          string     `gorm:"uniqueIndex;not null" json:"-"`
+	Prefix           string     `json:"prefix"`
+	ExpiresAt        *time.Time `json:"expiresAt"` // nil: không hết hạn
+	LastUsedAt       *time.Time `json:"lastUsedAt"`
+	RevokedAt        *time.Time `json:"revokedAt"`
+	CreatedById      int64      `json:"createdById"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	CompanyId        int64      `json:"-"`
+
+	ServiceAccount ServiceAccount `gorm:"foreignKey:ServiceAccountId;references:Id"`
+	Scopes         []ApiKeyScope  `gorm:"foreignKey:ApiKeyId;references:Id"`
+}
+
+// ApiKeyScope quyền (bảng permissions) mà key được dùng, mức truy cập lấy theo vai trò của service account
+type ApiKeyScope struct {
+	ApiKeyId     int64 `gorm:"primaryKey" json:"apiKeyId"`
+	PermissionId int64 `gorm:"primaryKey" json:"permissionId"`
+
+	Permission Permission `gorm:"foreignKey:PermissionId;references:Id"`
+}
@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+const (
+	IdentityProviderOidc = "oidc"
+	IdentityProviderLdap = "ldap"
+	IdentityProviderScim = "scim"
+)
+
+// CompanySso cấu hình đăng nhập OpenID Connect của công ty
+type CompanySso struct {
+	Id           int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	CompanyId    int64  `gorm:"uniqueIndex" json:"-"`
+	Issuer       string `json:"issuer"`
+	ClientId     string `json:"clientId"`
+	ClientSecret string `json:"-"` // Đã mã hoá AES-GCM
+	RedirectUri  string `json:"redirectUri"`
+	Scopes       string `json:"scopes"` // Cách nhau bởi dấu cách, luôn có openid
+	// Tên claim trong id_token
+	EmailClaim      string `json:"emailClaim"`
+	FirstNameClaim  string `json:"firstNameClaim"`
+	LastNameClaim   string `json:"lastNameClaim"`
+	DepartmentClaim string `json:"departmentClaim"`
+	RoleClaim       string `json:"roleClaim"`
+	// Ánh xạ giá trị claim sang phòng ban / vai trò (JSON)
+	DepartmentMapping    string    `gorm:"type:text" json:"-"`
+	RoleMapping          string    `gorm:"type:text" json:"-"`
+	DefaultRoleSlug      string    `json:"defaultRoleSlug"`
+	Enabled              bool      `gorm:"not null;default:false" json:"enabled"`
+	AutoProvision        bool      `gorm:"not null;default:false" json:"autoProvision"`
+	DisablePasswordLogin bool      `gorm:"not null;default:false" json:"disablePasswordLogin"`
+	UpdatedAt            time.Time `json:"updatedAt"`
+}
+
+// SsoLoginState phiên đăng nhập SSO đang chờ IdP trả về, state chỉ lưu SHA-256
+type SsoLoginState struct {
+	Id           int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CompanyId    int64      `gorm:"index" json:"companyId"`
+	StateHash    string     `gorm:"uniqueIndex" json:"-"`
+	CodeVerifier string     `json:"-"` // PKCE
+	Nonce        string     `json:"-"`
+	ExpiresAt    time.Time  `json:"expiresAt"`
+	UsedAt       *time.Time `json:"usedAt"`
+}
+
+// UserIdentity liên kết người dùng với tài khoản ở hệ thống định danh bên ngoài
+type UserIdentity struct {
+	Id          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserId      int64      `gorm:"index" json:"userId"`
+	Provider    string     `gorm:"uniqueIndex:idx_user_identity" json:"provider"`
+	Issuer      string     `gorm:"uniqueIndex:idx_user_identity" json:"issuer"`
+	Subject     string     `gorm:"uniqueIndex:idx_user_identity" json:"subject"`
+	CompanyId   int64      `gorm:"index" json:"-"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	LastLoginAt *time.Time `json:"lastLoginAt"`
+	Suspended   bool       `gorm:"not null;default:false" json:"suspended"` // Bị khoá do tài khoản không còn trong thư mục, mở lại khi xuất hiện lại
+}
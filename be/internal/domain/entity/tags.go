@@ -0,0 +1,12 @@
+package entity
+
+type Tags struct {
+	Id        int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	TagName   string `gorm:"uniqueIndex:idx_tag_company" json:"tagName"`
+	CompanyId int64  `gorm:"uniqueIndex:idx_tag_company" json:"-"`
+}
+
+type AssetTag struct {
+	AssetId int64 `gorm:"primaryKey" json:"assetId"`
+	TagId   int64 `gorm:"primaryKey;index" json:"tagId"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// UserTwoFactor cấu hình TOTP của người dùng, Enabled = false khi đang đăng ký chưa xác nhận mã
+type UserTwoFactor struct {
+	Id           int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserId       int64      `gorm:"uniqueIndex" json:"userId"`
+	Secret       string     `json:"-"` // Secret đã mã hoá AES-GCM
+	Enabled      bool       `gorm:"not null;default:false" json:"enabled"`
+	EnabledAt    *time.Time `json:"enabledAt"`
+	LastUsedStep int64      `json:"-"` // Chu kỳ TOTP dùng gần nhất, chống dùng lại mã
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// UserRecoveryCode mã khôi phục dùng một lần, chỉ lưu SHA-256
+type UserRecoveryCode struct {
+	Id       int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserId   int64      `gorm:"index" json:"userId"`
+	CodeHash string     `json:"-"`
+	UsedAt   *time.Time `json:"usedAt"`
+}
+
+// TwoFactorChallenge bước xác thực thứ hai sau khi đúng mật khẩu, token chỉ lưu SHA-256
+type TwoFactorChallenge struct {
+	Id        int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserId    int64      `gorm:"index" json:"userId"`
+	TokenHash string     `gorm:"uniqueIndex" json:"-"`
+	Setup     bool       `gorm:"not null;default:false" json:"setup"` // Công ty bắt buộc 2FA nhưng người dùng chưa đăng ký
+	Attempts  int        `json:"attempts"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt"`
+}
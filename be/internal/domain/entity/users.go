@@ -14,6 +14,7 @@ type Users struct {
 	CompanyId      int64       `json:"-"`
 	CanExport      bool        `gorm:"not null;default:false" json:"canExport"`
 	Avatar         string      `json:"Avatar"`
+	IsSystemUser   bool        `gorm:"not null;default:false" json:"isSystemUser"` // Người dùng của service account, không đăng nhập bằng mật khẩu
 	Role           Roles       `gorm:"foreignKey:RoleId;references:Id"`
 	Department     Departments `gorm:"DepartmentId:RoleId;references:Id"`
 }
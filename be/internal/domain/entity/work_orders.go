@@ -0,0 +1,75 @@
+package entity
+
+import "time"
+
+const (
+	WorkOrderOpen       = "open"
+	WorkOrderInProgress = "in_progress"
+	WorkOrderCompleted  = "completed"
+	WorkOrderCancelled  = "cancelled"
+)
+
+// WorkOrder phiếu công việc bảo trì gắn với một lần bảo trì (MaintenanceSchedules)
+type WorkOrder struct {
+	Id               int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScheduleId       int64      `gorm:"index" json:"scheduleId"`
+	AssetId          int64      `gorm:"index" json:"assetId"`
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	Status           string     `gorm:"default:open" json:"status"`
+	TechnicianUserId *int64     `json:"technicianUserId"` // Kỹ thuật viên nội bộ
+	VendorName       *string    `json:"vendorName"`       // Hoặc đơn vị bên ngoài
+	LaborHours       float64    `json:"laborHours"`
+	LaborRate        float64    `json:"laborRate"` // Đơn giá nhân công theo giờ
+	PartsCost        float64    `json:"partsCost"`
+	TotalCost        float64    `json:"totalCost"`
+	CompletionNotes  string     `json:"completionNotes"`
+	CompletedAt      *time.Time `json:"completedAt"`
+	CreatedById      int64      `json:"createdById"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	CompanyId        int64      `json:"-"`
+
+	Schedule   MaintenanceSchedules `gorm:"foreignKey:ScheduleId;references:Id"`
+	Asset      Assets               `gorm:"foreignKey:AssetId;references:Id"`
+	Technician *Users               `gorm:"foreignKey:TechnicianUserId;references:Id"`
+	Tasks      []WorkOrderTask      `gorm:"foreignKey:WorkOrderId;references:Id"`
+	Parts      []WorkOrderPart      `gorm:"foreignKey:WorkOrderId;references:Id"`
+	Photos     []WorkOrderPhoto     `gorm:"foreignKey:WorkOrderId;references:Id"`
+}
+
+type WorkOrderTask struct {
+	Id          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	WorkOrderId int64      `gorm:"index" json:"workOrderId"`
+	Description string     `json:"description"`
+	IsDone      bool       `json:"isDone"`
+	DoneById    *int64     `json:"doneById"`
+	DoneAt      *time.Time `json:"doneAt"`
+}
+
+// WorkOrderPart vật tư đã dùng, số lượng bị trừ khỏi kho Consumable
+type WorkOrderPart struct {
+	Id           int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	WorkOrderId  int64   `gorm:"index" json:"workOrderId"`
+	ConsumableId int64   `json:"consumableId"`
+	Quantity     float64 `json:"quantity"`
+	UnitCost     float64 `json:"unitCost"` // Đơn giá tại thời điểm sử dụng
+
+	Consumable Consumable `gorm:"foreignKey:ConsumableId;references:Id"`
+}
+
+type WorkOrderPhoto struct {
+	Id          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	WorkOrderId int64     `gorm:"index" json:"workOrderId"`
+	Url         string    `json:"url"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Consumable vật tư tiêu hao trong kho (linh kiện, mực in, ...)
+type Consumable struct {
+	Id        int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string  `gorm:"uniqueIndex:idx_consumable_company" json:"name"`
+	Unit      string  `json:"unit"`
+	Quantity  float64 `json:"quantity"`
+	UnitCost  float64 `json:"unitCost"`
+	CompanyId int64   `gorm:"uniqueIndex:idx_consumable_company" json:"-"`
+}
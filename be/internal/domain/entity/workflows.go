@@ -0,0 +1,104 @@
+package entity
+
+import "time"
+
+// Loại nghiệp vụ chạy qua quy trình duyệt
+const (
+	WorkflowEntityTransferRequest     = "transfer_request"
+	WorkflowEntityAssetRetirement     = "asset_retirement"
+	WorkflowEntityMaintenanceSchedule = "maintenance_schedule"
+)
+
+// Người duyệt của một bước
+const (
+	WorkflowApproverRole           = "role"
+	WorkflowApproverDepartmentHead = "department_head" // Trưởng phòng ban của tài sản
+	WorkflowApproverUser           = "user"
+)
+
+const (
+	WorkflowPending   = "pending"
+	WorkflowApproved  = "approved"
+	WorkflowRejected  = "rejected"
+	WorkflowCancelled = "cancelled"
+	WorkflowSkipped   = "skipped" // Bước song song không cần duyệt nữa vì quy trình đã kết thúc
+)
+
+// WorkflowDefinition quy trình duyệt của công ty cho một loại nghiệp vụ, mỗi loại chỉ có một quy trình đang hoạt động
+type WorkflowDefinition struct {
+	Id          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `json:"name"`
+	EntityType  string    `gorm:"index" json:"entityType"`
+	IsActive    bool      `gorm:"not null" json:"isActive"`
+	CreatedById int64     `json:"createdById"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CompanyId   int64     `json:"-"`
+
+	Steps []WorkflowStep `gorm:"foreignKey:DefinitionId;references:Id"`
+}
+
+// WorkflowStep các bước cùng StepOrder chạy song song (tất cả phải duyệt), khác StepOrder chạy tuần tự
+type WorkflowStep struct {
+	Id             int64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	DefinitionId   int64    `gorm:"index" json:"definitionId"`
+	StepOrder      int      `json:"stepOrder"`
+	Name           string   `json:"name"`
+	ApproverType   string   `json:"approverType"`
+	ApproverRole   *string  `json:"approverRole"` // Slug của role
+	ApproverUserId *int64   `json:"approverUserId"`
+	MinCost        *float64 `json:"minCost"` // Chỉ áp dụng khi giá trị nghiệp vụ >= MinCost
+}
+
+// WorkflowInstance một lần chạy quy trình, các bước được sao chép lại nên sửa quy trình không ảnh hưởng yêu cầu đang chờ
+type WorkflowInstance struct {
+	Id               int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	DefinitionId     int64      `gorm:"index" json:"definitionId"`
+	EntityType       string     `gorm:"index:idx_workflow_instance_entity" json:"entityType"`
+	EntityId         int64      `gorm:"index:idx_workflow_instance_entity" json:"entityId"`
+	AssetId          int64      `json:"assetId"`
+	DepartmentId     int64      `json:"departmentId"`
+	Cost             float64    `json:"cost"`
+	Payload          string     `gorm:"type:text" json:"-"` // Tham số JSON để thực hiện nghiệp vụ khi được duyệt
+	Status           string     `gorm:"index" json:"status"`
+	CurrentStepOrder int        `json:"currentStepOrder"`
+	RequestedById    int64      `json:"requestedById"`
+	RejectReason     *string    `json:"rejectReason"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	FinishedAt       *time.Time `json:"finishedAt"`
+	CompanyId        int64      `json:"-"`
+
+	Definition  WorkflowDefinition     `gorm:"foreignKey:DefinitionId;references:Id"`
+	Asset       Assets                 `gorm:"foreignKey:AssetId;references:Id"`
+	RequestedBy Users                  `gorm:"foreignKey:RequestedById;references:Id"`
+	Steps       []WorkflowInstanceStep `gorm:"foreignKey:InstanceId;references:Id"`
+	Actions     []WorkflowAction       `gorm:"foreignKey:InstanceId;references:Id"`
+}
+
+type WorkflowInstanceStep struct {
+	Id             int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	InstanceId     int64      `gorm:"index" json:"instanceId"`
+	StepOrder      int        `json:"stepOrder"`
+	Name           string     `json:"name"`
+	ApproverType   string     `json:"approverType"`
+	ApproverRole   *string    `json:"approverRole"`
+	ApproverUserId *int64     `json:"approverUserId"`
+	Status         string     `json:"status"`
+	ActedById      *int64     `json:"actedById"`
+	ActedAt        *time.Time `json:"actedAt"`
+	Comment        string     `json:"comment"`
+
+	ActedBy *Users `gorm:"foreignKey:ActedById;references:Id"`
+}
+
+// WorkflowAction nhật ký duyệt: gửi, duyệt, từ chối, huỷ
+type WorkflowAction struct {
+	Id             int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	InstanceId     int64     `gorm:"index" json:"instanceId"`
+	InstanceStepId *int64    `json:"instanceStepId"`
+	Action         string    `json:"action"` // submitted | approved | rejected | completed | cancelled
+	ByUserId       int64     `json:"byUserId"`
+	Comment        string    `json:"comment"`
+	CreatedAt      time.Time `json:"createdAt"`
+
+	ByUser Users `gorm:"foreignKey:ByUserId;references:Id"`
+}
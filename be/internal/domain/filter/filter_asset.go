@@ -3,6 +3,7 @@ package filter
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -15,6 +16,9 @@ type AssetFilter struct {
 	SerialNumber *string `form:"serialNumber" json:"serialNumber"`
 	Email        *string `form:"email" json:"email"`
 	DepartmentId *string `form:"departmentId" json:"departmentId"`
+	LocationId   *string `form:"locationId" json:"locationId"` // site, building, floor hoặc room
+	Tags         *string `form:"tags" json:"tags"`             // danh sách tag, cách nhau bởi dấu phẩy
+	TagMode      *string `form:"tagMode" json:"tagMode"`       // "any" (mặc định) hoặc "all"
 	CompanyId    int64
 }
 
@@ -22,7 +26,11 @@ type AssetFilterDashboard struct {
 	CategoryId   *string `form:"categoryId" json:"categoryId"`
 	DepartmentId *string `form:"departmentId" json:"departmentId"`
 	Status       *string `form:"status" json:"status"`
+	LocationId   *string `form:"locationId" json:"locationId"`
+	Tags         *string `form:"tags" json:"tags"`
+	TagMode      *string `form:"tagMode" json:"tagMode"`
 	Export       *string `form:"export" json:"export"` // "csv" hoặc "pdf" hoặc ""
+	CompanyId    int64
 }
 
 func (f *AssetFilter) ApplyFilter(db *gorm.DB, userId int64) *gorm.DB {
@@ -69,13 +77,21 @@ func (f *AssetFilter) ApplyFilter(db *gorm.DB, userId int64) *gorm.DB {
 		parsedID, _ := strconv.ParseInt(*f.DepartmentId, 10, 64)
 		db = db.Where("assets.department_id = ?", parsedID)
 	}
-	return db.Preload("Category").Preload("Department").Preload("OnwerUser").Preload("Department.Location")
+	if f.LocationId != nil {
+		parsedID, _ := strconv.ParseInt(*f.LocationId, 10, 64)
+		db = ApplyLocationFilter(db, parsedID)
+	}
+	if f.Tags != nil {
+		db = ApplyTagFilter(db, f.CompanyId, *f.Tags, f.TagMode)
+	}
+	return db.Preload("Category").Preload("Department").Preload("OnwerUser").Preload("Department.Location").Preload("Room").Preload("Tags")
 }
 
 func (f *AssetFilterDashboard) ApplyFilterDashBoard(db *gorm.DB, userId int64) *gorm.DB {
+	db = db.Where("assets.company_id = ?", f.CompanyId)
 	if f.CategoryId != nil {
 		parsedID, _ := strconv.ParseInt(*f.CategoryId, 10, 64)
-		db = db.Where("categories.id = ?", parsedID)
+		db = db.Where("assets.category_id = ?", parsedID)
 	}
 	if f.DepartmentId != nil {
 		parsedID, _ := strconv.ParseInt(*f.DepartmentId, 10, 64)
@@ -84,5 +100,47 @@ func (f *AssetFilterDashboard) ApplyFilterDashBoard(db *gorm.DB, userId int64) *
 	if f.Status != nil {
 		db = db.Where("status = ?", *f.Status)
 	}
-	return db.Preload("Category").Preload("Department").Preload("OnwerUser").Preload("Department.Location")
+	if f.LocationId != nil {
+		parsedID, _ := strconv.ParseInt(*f.LocationId, 10, 64)
+		db = ApplyLocationFilter(db, parsedID)
+	}
+	if f.Tags != nil {
+		db = ApplyTagFilter(db, f.CompanyId, *f.Tags, f.TagMode)
+	}
+	return db.Preload("Category").Preload("Department").Preload("OnwerUser").Preload("Department.Location").Preload("Room").Preload("Tags")
+}
+
+// ApplyLocationFilter giữ lại tài sản nằm trong cây con của locationId.
+// Tài sản có phòng thì xét theo phòng, nếu không thì xét theo vị trí của phòng ban.
+func ApplyLocationFilter(db *gorm.DB, locationId int64) *gorm.DB {
+	subtree := "SELECT child.id FROM locations child JOIN locations node ON child.path LIKE node.path || '%' WHERE node.id = ?"
+	return db.Where("(assets.room_id IN ("+subtree+") OR (assets.room_id IS NULL AND assets.department_id IN (SELECT departments.id FROM departments WHERE departments.location_id IN ("+subtree+"))))", locationId, locationId)
+}
+
+// ApplyTagFilter lọc tài sản theo tag: mode "all" yêu cầu có đủ tất cả tag, mặc định chỉ cần một tag
+func ApplyTagFilter(db *gorm.DB, companyId int64, tags string, mode *string) *gorm.DB {
+	tagNames := NormalizeTagNames(strings.Split(tags, ","))
+	if len(tagNames) == 0 {
+		return db
+	}
+	subQuery := "SELECT asset_tags.asset_id FROM asset_tags JOIN tags ON tags.id = asset_tags.tag_id WHERE tags.company_id = ? AND tags.tag_name IN ?"
+	if mode != nil && strings.ToLower(*mode) == "all" {
+		return db.Where("assets.id IN ("+subQuery+" GROUP BY asset_tags.asset_id HAVING COUNT(DISTINCT tags.id) = ?)", companyId, tagNames, len(tagNames))
+	}
+	return db.Where("assets.id IN ("+subQuery+")", companyId, tagNames)
+}
+
+// NormalizeTagNames chuẩn hoá tên tag (bỏ khoảng trắng, chữ thường) và loại trùng
+func NormalizeTagNames(tagNames []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, name := range tagNames {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
 }
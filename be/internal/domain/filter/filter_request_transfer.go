@@ -16,7 +16,7 @@ func (f *RequestTransferFilter) ApplyFilter(db *gorm.DB, userId int64) *gorm.DB
 		db.Joins("JOIN users ON users.id = request_transfers.user_id").Where("users.department_id != ?", *f.DepId)
 	}
 	if f.Status != nil && *f.Status != "" {
-		db = db.Where("status = ?", *f.Status)
+		db = db.Where("request_transfers.status = ?", *f.Status)
 	}
-	return db.Preload("User").Preload("User.Department").Preload("Category").Order("request_transfers.id ASC")
+	return db.Preload("User").Preload("User.Department").Preload("Category").Preload("Asset").Order("request_transfers.id ASC")
 }
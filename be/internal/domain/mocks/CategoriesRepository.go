@@ -91,6 +91,36 @@ func (_m *CategoriesRepository) GetAll() ([]*entity.Categories, error) {
 	return r0, r1
 }
 
+// GetCategoryById provides a mock function with given fields: id
+func (_m *CategoriesRepository) GetCategoryById(id int64) (*entity.Categories, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategoryById")
+	}
+
+	var r0 *entity.Categories
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (*entity.Categories, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *entity.Categories); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Categories)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewCategoriesRepository creates a new instance of CategoriesRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCategoriesRepository(t interface {
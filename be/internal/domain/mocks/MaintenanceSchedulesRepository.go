@@ -5,6 +5,8 @@ package mocks
 import (
 	entity "BE_Manage_device/internal/domain/entity"
 
+	gorm "gorm.io/gorm"
+
 	mock "github.com/stretchr/testify/mock"
 
 	time "time"
@@ -15,9 +17,9 @@ type MaintenanceSchedulesRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: _a0
-func (_m *MaintenanceSchedulesRepository) Create(_a0 *entity.MaintenanceSchedules) (*entity.MaintenanceSchedules, error) {
-	ret := _m.Called(_a0)
+// Create provides a mock function with given fields: maintenance, tx
+func (_m *MaintenanceSchedulesRepository) Create(maintenance *entity.MaintenanceSchedules, tx *gorm.DB) (*entity.MaintenanceSchedules, error) {
+	ret := _m.Called(maintenance, tx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
@@ -25,19 +27,19 @@ func (_m *MaintenanceSchedulesRepository) Create(_a0 *entity.MaintenanceSchedule
 
 	var r0 *entity.MaintenanceSchedules
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.MaintenanceSchedules) (*entity.MaintenanceSchedules, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(*entity.MaintenanceSchedules, *gorm.DB) (*entity.MaintenanceSchedules, error)); ok {
+		return rf(maintenance, tx)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.MaintenanceSchedules) *entity.MaintenanceSchedules); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(*entity.MaintenanceSchedules, *gorm.DB) *entity.MaintenanceSchedules); ok {
+		r0 = rf(maintenance, tx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.MaintenanceSchedules)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.MaintenanceSchedules) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(*entity.MaintenanceSchedules, *gorm.DB) error); ok {
+		r1 = rf(maintenance, tx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -123,6 +125,26 @@ func (_m *MaintenanceSchedulesRepository) GetAllMaintenanceSchedulesByAssetId(as
 	return r0, r1
 }
 
+// GetDB provides a mock function with no fields
+func (_m *MaintenanceSchedulesRepository) GetDB() *gorm.DB {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDB")
+	}
+
+	var r0 *gorm.DB
+	if rf, ok := ret.Get(0).(func() *gorm.DB); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gorm.DB)
+		}
+	}
+
+	return r0
+}
+
 // GetMaintenanceSchedulesById provides a mock function with given fields: id
 func (_m *MaintenanceSchedulesRepository) GetMaintenanceSchedulesById(id int64) (*entity.MaintenanceSchedules, error) {
 	ret := _m.Called(id)
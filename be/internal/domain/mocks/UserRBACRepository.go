@@ -33,6 +33,24 @@ func (_m *UserRBACRepository) Create(userRBAC *entity.UserRbac, tx *gorm.DB) err
 	return r0
 }
 
+// GrantCompanyAssets provides a mock function with given fields: userId, roleId, companyId, tx
+func (_m *UserRBACRepository) GrantCompanyAssets(userId int64, roleId int64, companyId int64, tx *gorm.DB) error {
+	ret := _m.Called(userId, roleId, companyId, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantCompanyAssets")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, int64, int64, *gorm.DB) error); ok {
+		r0 = rf(userId, roleId, companyId, tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserRBACRepository creates a new instance of UserRBACRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRBACRepository(t interface {
@@ -8,6 +8,8 @@ import (
 	gorm "gorm.io/gorm"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UsersSessionRepository is an autogenerated mock type for the UsersSessionRepository type
@@ -69,6 +71,36 @@ func (_m *UsersSessionRepository) Create(usersSessions *entity.UsersSessions, tx
 	return r0
 }
 
+// FindActiveApiKey provides a mock function with given fields: keyHash
+func (_m *UsersSessionRepository) FindActiveApiKey(keyHash string) (*entity.ApiKey, error) {
+	ret := _m.Called(keyHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindActiveApiKey")
+	}
+
+	var r0 *entity.ApiKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.ApiKey, error)); ok {
+		return rf(keyHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.ApiKey); ok {
+		r0 = rf(keyHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ApiKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByRefreshToken provides a mock function with given fields: refreshToken
 func (_m *UsersSessionRepository) FindByRefreshToken(refreshToken string) (*entity.UsersSessions, error) {
 	ret := _m.Called(refreshToken)
@@ -129,6 +161,24 @@ func (_m *UsersSessionRepository) FindByUserIdInSession(UserId int64) (*entity.U
 	return r0, r1
 }
 
+// TouchApiKey provides a mock function with given fields: id, at
+func (_m *UsersSessionRepository) TouchApiKey(id int64, at time.Time) error {
+	ret := _m.Called(id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchApiKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, time.Time) error); ok {
+		r0 = rf(id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateIsRevoked provides a mock function with given fields: user
 func (_m *UsersSessionRepository) UpdateIsRevoked(user *entity.UsersSessions) error {
 	ret := _m.Called(user)
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLAssetRelationRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLAssetRelationRepository(db *gorm.DB) AssetRelationRepository {
+	return &PostgreSQLAssetRelationRepository{db: db}
+}
+
+func (r *PostgreSQLAssetRelationRepository) Create(relation *entity.AssetRelation, tx *gorm.DB) (*entity.AssetRelation, error) {
+	result := tx.Create(relation)
+	return relation, result.Error
+}
+
+func (r *PostgreSQLAssetRelationRepository) GetById(id int64) (*entity.AssetRelation, error) {
+	relation := &entity.AssetRelation{}
+	result := r.db.Model(entity.AssetRelation{}).Where("id = ?", id).First(relation)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return relation, nil
+}
+
+func (r *PostgreSQLAssetRelationRepository) Delete(id int64, tx *gorm.DB) error {
+	result := tx.Model(entity.AssetRelation{}).Where("id = ?", id).Delete(entity.AssetRelation{})
+	return result.Error
+}
+
+func (r *PostgreSQLAssetRelationRepository) GetByAssetIds(assetIds []int64) ([]*entity.AssetRelation, error) {
+	relations := []*entity.AssetRelation{}
+	result := r.db.Model(entity.AssetRelation{}).Where("from_asset_id IN ? OR to_asset_id IN ?", assetIds, assetIds).Find(&relations)
+	return relations, result.Error
+}
+
+func (r *PostgreSQLAssetRelationRepository) GetByFromAsset(fromAssetId int64, relationType string) ([]*entity.AssetRelation, error) {
+	relations := []*entity.AssetRelation{}
+	result := r.db.Model(entity.AssetRelation{}).Where("from_asset_id = ? AND relation_type = ?", fromAssetId, relationType).Find(&relations)
+	return relations, result.Error
+}
+
+// GetDependents trả về các quan hệ mà tài sản khác phụ thuộc vào (depends-on) hoặc được lắp trong (installed-in) assetId
+func (r *PostgreSQLAssetRelationRepository) GetDependents(assetId int64) ([]*entity.AssetRelation, error) {
+	relations := []*entity.AssetRelation{}
+	result := r.db.Model(entity.AssetRelation{}).
+		Where("to_asset_id = ? AND relation_type IN ?", assetId, []string{entity.RelationDependsOn, entity.RelationInstalledIn}).
+		Preload("FromAsset").
+		Find(&relations)
+	return relations, result.Error
+}
+
+// IsReachable kiểm tra có đường đi từ fromAssetId tới toAssetId theo các cạnh cùng loại hay không
+func (r *PostgreSQLAssetRelationRepository) IsReachable(fromAssetId int64, toAssetId int64, relationType string) (bool, error) {
+	var total int64
+	sql := `
+	WITH RECURSIVE reach(asset_id) AS (
+		SELECT to_asset_id FROM asset_relations WHERE from_asset_id = ? AND relation_type = ?
+		UNION
+		SELECT asset_relations.to_asset_id FROM asset_relations
+		JOIN reach ON asset_relations.from_asset_id = reach.asset_id
+		WHERE asset_relations.relation_type = ?
+	)
+	SELECT COUNT(*) FROM reach WHERE asset_id = ?`
+	result := r.db.Raw(sql, fromAssetId, relationType, relationType, toAssetId).Scan(&total)
+	return total > 0, result.Error
+}
+
+func (r *PostgreSQLAssetRelationRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type AssetRelationRepository interface {
+	Create(relation *entity.AssetRelation, tx *gorm.DB) (*entity.AssetRelation, error)
+	GetById(id int64) (*entity.AssetRelation, error)
+	Delete(id int64, tx *gorm.DB) error
+	GetByAssetIds(assetIds []int64) ([]*entity.AssetRelation, error)
+	GetByFromAsset(fromAssetId int64, relationType string) ([]*entity.AssetRelation, error)
+	GetDependents(assetId int64) ([]*entity.AssetRelation, error)
+	IsReachable(fromAssetId int64, toAssetId int64, relationType string) (bool, error)
+	GetDB() *gorm.DB
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLAssetTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLAssetTemplateRepository(db *gorm.DB) AssetTemplateRepository {
+	return &PostgreSQLAssetTemplateRepository{db: db}
+}
+
+func (r *PostgreSQLAssetTemplateRepository) Create(template *entity.AssetTemplate) (*entity.AssetTemplate, error) {
+	result := r.db.Create(template)
+	return template, result.Error
+}
+
+func (r *PostgreSQLAssetTemplateRepository) GetAll(companyId int64) ([]*entity.AssetTemplate, error) {
+	templates := []*entity.AssetTemplate{}
+	result := r.db.Model(entity.AssetTemplate{}).Where("company_id = ?", companyId).Preload("Category").Find(&templates)
+	return templates, result.Error
+}
+
+func (r *PostgreSQLAssetTemplateRepository) GetById(id int64) (*entity.AssetTemplate, error) {
+	template := &entity.AssetTemplate{}
+	result := r.db.Model(entity.AssetTemplate{}).Where("id = ?", id).Preload("Category").First(template)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return template, nil
+}
+
+func (r *PostgreSQLAssetTemplateRepository) Update(template *entity.AssetTemplate) (*entity.AssetTemplate, error) {
+	result := r.db.Model(&entity.AssetTemplate{}).Where("id = ?", template.Id).Updates(map[string]interface{}{
+		"template_name":       template.TemplateName,
+		"asset_name":          template.AssetName,
+		"category_id":         template.CategoryId,
+		"cost":                template.Cost,
+		"warranty_months":     template.WarrantyMonths,
+		"annual_depreciation": template.AnnualDepreciation,
+		"residual_value":      template.ResidualValue,
+		"useful_life":         template.UsefulLife,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return r.GetById(template.Id)
+}
+
+func (r *PostgreSQLAssetTemplateRepository) Delete(id int64) error {
+	result := r.db.Model(entity.AssetTemplate{}).Where("id = ?", id).Delete(entity.AssetTemplate{})
+	return result.Error
+}
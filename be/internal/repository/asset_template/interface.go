@@ -0,0 +1,11 @@
+package repository
+
+import "BE_Manage_device/internal/domain/entity"
+
+type AssetTemplateRepository interface {
+	Create(*entity.AssetTemplate) (*entity.AssetTemplate, error)
+	GetAll(companyId int64) ([]*entity.AssetTemplate, error)
+	GetById(id int64) (*entity.AssetTemplate, error)
+	Update(*entity.AssetTemplate) (*entity.AssetTemplate, error)
+	Delete(id int64) error
+}
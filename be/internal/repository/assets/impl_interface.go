@@ -24,7 +24,7 @@ func (r *PostgreSQLAssetsRepository) Create(assets *entity.Assets, tx *gorm.DB)
 
 func (r *PostgreSQLAssetsRepository) GetAssetById(id int64) (*entity.Assets, error) {
 	asset := &entity.Assets{}
-	result := r.db.Model(&entity.Assets{}).Where("id = ?", id).Preload("Category").Preload("Department").Preload("OnwerUser").Preload("Department.Location").First(asset)
+	result := r.db.Model(&entity.Assets{}).Where("id = ?", id).Preload("Category").Preload("Department").Preload("OnwerUser").Preload("Department.Location").Preload("Room").Preload("Tags").First(asset)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, errors.New("can't find record this id")
@@ -55,7 +55,7 @@ func (r *PostgreSQLAssetsRepository) UpdateAssetLifeCycleStage(id int64, status
 
 func (r *PostgreSQLAssetsRepository) GetAllAsset(companyId int64) ([]*entity.Assets, error) {
 	assets := []*entity.Assets{}
-	result := r.db.Model(entity.Assets{}).Where("company_id = ?", companyId).Preload("Category").Preload("Department").Preload("OnwerUser").Preload("Department.Location").Find(&assets)
+	result := r.db.Model(entity.Assets{}).Where("company_id = ?", companyId).Preload("Category").Preload("Department").Preload("OnwerUser").Preload("Department.Location").Preload("Room").Preload("Tags").Find(&assets)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -148,6 +148,8 @@ func (r *PostgreSQLAssetsRepository) CheckAssetFinishMaintenance(id int64) (bool
 		Model(&entity.MaintenanceSchedules{}).
 		Joins("JOIN assets ON assets.id = maintenance_schedules.asset_id").
 		Where("assets.id = ?", id).
+		// Bỏ qua các lần bảo trì trong tương lai (vd: sinh từ kế hoạch định kỳ)
+		Where("maintenance_schedules.start_date <= ?", time.Now()).
 		Order("maintenance_schedules.start_date DESC").
 		First(&schedule).Error
 
@@ -268,3 +270,26 @@ func (r *PostgreSQLAssetsRepository) GetAllAssetOfDep(depId int64) ([]*entity.As
 	}
 	return assets, nil
 }
+
+func (r *PostgreSQLAssetsRepository) GetAssetsBySerialNumbers(companyId int64, serialNumbers []string) ([]*entity.Assets, error) {
+	assets := []*entity.Assets{}
+	result := r.db.Model(entity.Assets{}).Where("company_id = ? AND serial_number IN ?", companyId, serialNumbers).Find(&assets)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assets, nil
+}
+
+func (r *PostgreSQLAssetsRepository) UpdateRoom(id int64, roomId int64, tx *gorm.DB) error {
+	result := tx.Model(entity.Assets{}).Where("id = ?", id).Update("room_id", roomId)
+	return result.Error
+}
+
+func (r *PostgreSQLAssetsRepository) GetAssetsByIds(ids []int64) ([]*entity.Assets, error) {
+	assets := []*entity.Assets{}
+	result := r.db.Model(entity.Assets{}).Where("id IN ?", ids).Preload("Category").Find(&assets)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assets, nil
+}
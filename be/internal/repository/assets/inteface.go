@@ -30,4 +30,7 @@ type AssetsRepository interface {
 	DeleteOwnerAssetOfOwnerId(ownerId int64) error
 	GetAllAssetNotHaveMaintenance(companyId int64) ([]*entity.Assets, error)
 	GetAllAssetOfDep(depId int64) ([]*entity.Assets, error)
+	GetAssetsBySerialNumbers(companyId int64, serialNumbers []string) ([]*entity.Assets, error)
+	UpdateRoom(id int64, roomId int64, tx *gorm.DB) error
+	GetAssetsByIds(ids []int64) ([]*entity.Assets, error)
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"BE_Manage_device/internal/domain/entity"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -21,11 +22,21 @@ func (r *PostgreSQLAssignmentRepository) Create(assignment *entity.Assignments,
 	}
 	var assignmentCreated = &entity.Assignments{}
 	result = tx.Model(entity.Assignments{}).Where("id = ?", assignment.Id).Preload("UserAssigned").Preload("UserAssign").Preload("Asset").Preload("Department").First(&assignmentCreated)
-	return assignmentCreated, result.Error
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if err := r.startHistory(assignmentCreated, "Initial assignment", tx); err != nil {
+		return nil, err
+	}
+	return assignmentCreated, nil
 }
 
-func (r *PostgreSQLAssignmentRepository) Update(assignmentId int64, AssignBy, assetId int64, userId, departmentId *int64, tx *gorm.DB) (*entity.Assignments, error) {
+func (r *PostgreSQLAssignmentRepository) Update(assignmentId int64, AssignBy, assetId int64, userId, departmentId *int64, reason string, tx *gorm.DB) (*entity.Assignments, error) {
 	var assignment entity.Assignments
+	var before entity.Assignments
+	if err := tx.First(&before, assignmentId).Error; err != nil {
+		return nil, err
+	}
 
 	updates := map[string]interface{}{}
 	if userId != nil {
@@ -48,9 +59,41 @@ func (r *PostgreSQLAssignmentRepository) Update(assignmentId int64, AssignBy, as
 		return nil, err
 	}
 
+	// Đổi người giữ hoặc phòng ban thì đóng giai đoạn cũ và mở giai đoạn mới
+	if !sameInt64(before.UserId, assignment.UserId) || !sameInt64(before.DepartmentId, assignment.DepartmentId) {
+		if err := r.startHistory(&assignment, reason, tx); err != nil {
+			return nil, err
+		}
+	}
 	return &assignment, nil
 }
 
+func (r *PostgreSQLAssignmentRepository) startHistory(assignment *entity.Assignments, reason string, tx *gorm.DB) error {
+	now := time.Now()
+	err := tx.Model(entity.AssignmentHistory{}).Where("asset_id = ? AND ended_at IS NULL", assignment.AssetId).Update("ended_at", now).Error
+	if err != nil {
+		return err
+	}
+	history := entity.AssignmentHistory{
+		AssignmentId: assignment.Id,
+		AssetId:      assignment.AssetId,
+		UserId:       assignment.UserId,
+		DepartmentId: assignment.DepartmentId,
+		AssignBy:     assignment.AssignBy,
+		Reason:       reason,
+		StartedAt:    now,
+		CompanyId:    assignment.Asset.CompanyId,
+	}
+	return tx.Create(&history).Error
+}
+
+func sameInt64(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func (r *PostgreSQLAssignmentRepository) GetDB() *gorm.DB {
 	return r.db
 }
@@ -73,8 +116,57 @@ func (r *PostgreSQLAssignmentRepository) GetAssignmentByAssetId(assetId int64) (
 	return &assignment, nil
 }
 
+// GetForBulk lấy assignment của các tài sản trong công ty theo danh sách id hoặc theo bộ lọc
+func (r *PostgreSQLAssignmentRepository) GetForBulk(companyId int64, assetIds []int64, categoryId, departmentId, userId *int64, status *string) ([]*entity.Assignments, error) {
+	var assignments []*entity.Assignments
+	db := r.db.Model(entity.Assignments{}).Joins("JOIN assets ON assets.id = assignments.asset_id").Where("assets.company_id = ?", companyId)
+	if len(assetIds) > 0 {
+		db = db.Where("assignments.asset_id IN ?", assetIds)
+	}
+	if categoryId != nil {
+		db = db.Where("assets.category_id = ?", *categoryId)
+	}
+	if departmentId != nil {
+		db = db.Where("assets.department_id = ?", *departmentId)
+	}
+	if userId != nil {
+		db = db.Where("assignments.user_id = ?", *userId)
+	}
+	if status != nil {
+		db = db.Where("assets.status = ?", *status)
+	}
+	result := db.Preload("UserAssigned").Preload("UserAssign").Preload("Asset").Preload("Asset.Department").Preload("Asset.OnwerUser").Preload("Department").Order("assignments.asset_id").Find(&assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assignments, nil
+}
+
 func (r *PostgreSQLAssignmentRepository) GetAssignmentForViewer(userId int64) (*entity.Assignments, error) {
 	var assignment entity.Assignments
 	result := r.db.Model(entity.Assignments{}).Where("user_id = ?", userId).Preload("UserAssigned").Preload("UserAssign").Preload("Asset").Preload("Department").Preload("Department.Location").First(&assignment)
 	return &assignment, result.Error
 }
+
+// GetHistoriesOfAsset lấy các giai đoạn giữ tài sản giao với khoảng [from, to), from/to nil là không giới hạn
+func (r *PostgreSQLAssignmentRepository) GetHistoriesOfAsset(assetId int64, from, to *time.Time) ([]*entity.AssignmentHistory, error) {
+	return r.getHistories(r.db.Where("assignment_histories.asset_id = ?", assetId), from, to)
+}
+
+// GetHistoriesOfUser lấy các tài sản người dùng đã giữ trong khoảng [from, to)
+func (r *PostgreSQLAssignmentRepository) GetHistoriesOfUser(userId int64, from, to *time.Time) ([]*entity.AssignmentHistory, error) {
+	return r.getHistories(r.db.Where("assignment_histories.user_id = ?", userId), from, to)
+}
+
+func (r *PostgreSQLAssignmentRepository) getHistories(db *gorm.DB, from, to *time.Time) ([]*entity.AssignmentHistory, error) {
+	var histories []*entity.AssignmentHistory
+	if from != nil {
+		db = db.Where("(assignment_histories.ended_at IS NULL OR assignment_histories.ended_at > ?)", *from)
+	}
+	if to != nil {
+		db = db.Where("assignment_histories.started_at < ?", *to)
+	}
+	result := db.Model(entity.AssignmentHistory{}).Preload("UserAssigned").Preload("UserAssign").Preload("Asset").Preload("Department").
+		Order("assignment_histories.started_at ASC, assignment_histories.id ASC").Find(&histories)
+	return histories, result.Error
+}
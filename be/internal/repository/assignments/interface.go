@@ -2,15 +2,19 @@ package repository
 
 import (
 	"BE_Manage_device/internal/domain/entity"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type AssignmentRepository interface {
 	Create(assignment *entity.Assignments, tx *gorm.DB) (*entity.Assignments, error)
-	Update(assignmentId int64, AssignBy, assetId int64, userId, departmentId *int64, tx *gorm.DB) (*entity.Assignments, error)
+	Update(assignmentId int64, AssignBy, assetId int64, userId, departmentId *int64, reason string, tx *gorm.DB) (*entity.Assignments, error)
 	GetDB() *gorm.DB
 	GetAssignmentById(id int64) (*entity.Assignments, error)
 	GetAssignmentByAssetId(assetId int64) (*entity.Assignments, error)
 	GetAssignmentForViewer(userId int64) (*entity.Assignments, error)
+	GetForBulk(companyId int64, assetIds []int64, categoryId, departmentId, userId *int64, status *string) ([]*entity.Assignments, error)
+	GetHistoriesOfAsset(assetId int64, from, to *time.Time) ([]*entity.AssignmentHistory, error)
+	GetHistoriesOfUser(userId int64, from, to *time.Time) ([]*entity.AssignmentHistory, error)
 }
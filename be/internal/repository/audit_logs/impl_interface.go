@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLAuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &PostgreSQLAuditLogRepository{db: db}
+}
+
+func (r *PostgreSQLAuditLogRepository) Create(auditLog *entity.AuditLog, tx *gorm.DB) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Omit("Actor", "TargetUser").Create(auditLog).Error
+}
+
+// Filter userId lọc theo người thực hiện hoặc người bị tác động
+func (r *PostgreSQLAuditLogRepository) Filter(companyId int64, action *string, userId *int64) ([]*entity.AuditLog, error) {
+	var logs []*entity.AuditLog
+	db := r.db.Model(entity.AuditLog{}).Where("company_id = ?", companyId)
+	if action != nil {
+		db = db.Where("action = ?", *action)
+	}
+	if userId != nil {
+		db = db.Where("actor_id = ? OR target_user_id = ?", *userId, *userId)
+	}
+	result := db.Preload("Actor").Preload("TargetUser").Order("created_at DESC").Limit(500).Find(&logs)
+	return logs, result.Error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	Create(auditLog *entity.AuditLog, tx *gorm.DB) error
+	Filter(companyId int64, action *string, userId *int64) ([]*entity.AuditLog, error)
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLCalendarFeedRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLCalendarFeedRepository(db *gorm.DB) CalendarFeedRepository {
+	return &PostgreSQLCalendarFeedRepository{db: db}
+}
+
+func (r *PostgreSQLCalendarFeedRepository) Create(feed *entity.CalendarFeed) (*entity.CalendarFeed, error) {
+	result := r.db.Create(feed)
+	return feed, result.Error
+}
+
+func (r *PostgreSQLCalendarFeedRepository) GetById(id int64) (*entity.CalendarFeed, error) {
+	feed := &entity.CalendarFeed{}
+	result := r.db.Model(entity.CalendarFeed{}).Where("id = ?", id).Preload("Department").First(feed)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return feed, nil
+}
+
+func (r *PostgreSQLCalendarFeedRepository) GetByTokenHash(tokenHash string) (*entity.CalendarFeed, error) {
+	feed := &entity.CalendarFeed{}
+	result := r.db.Model(entity.CalendarFeed{}).Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+		Preload("User").Preload("User.Role").Preload("Department").First(feed)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return feed, nil
+}
+
+func (r *PostgreSQLCalendarFeedRepository) GetByUser(userId int64) ([]*entity.CalendarFeed, error) {
+	var feeds []*entity.CalendarFeed
+	result := r.db.Model(entity.CalendarFeed{}).Where("user_id = ?", userId).Preload("Department").Order("id DESC").Find(&feeds)
+	return feeds, result.Error
+}
+
+func (r *PostgreSQLCalendarFeedRepository) GetByCompany(companyId int64) ([]*entity.CalendarFeed, error) {
+	var feeds []*entity.CalendarFeed
+	result := r.db.Model(entity.CalendarFeed{}).Where("company_id = ?", companyId).Preload("Department").Order("id DESC").Find(&feeds)
+	return feeds, result.Error
+}
+
+func (r *PostgreSQLCalendarFeedRepository) Revoke(id int64, revokedAt time.Time) error {
+	return r.db.Model(entity.CalendarFeed{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", revokedAt).Error
+}
+
+func (r *PostgreSQLCalendarFeedRepository) UpdateLastAccessed(id int64, accessedAt time.Time) error {
+	return r.db.Model(entity.CalendarFeed{}).Where("id = ?", id).Update("last_accessed_at", accessedAt).Error
+}
+
+// GetFeedAssets lấy tài sản do ownerId sở hữu hoặc thuộc departmentId (điều kiện OR)
+func (r *PostgreSQLCalendarFeedRepository) GetFeedAssets(companyId int64, ownerId *int64, departmentId *int64) ([]*entity.Assets, error) {
+	var assets []*entity.Assets
+	if ownerId == nil && departmentId == nil {
+		return assets, nil
+	}
+	db := r.db.Model(entity.Assets{}).Where("company_id = ?", companyId)
+	switch {
+	case ownerId != nil && departmentId != nil:
+		db = db.Where("owner = ? OR department_id = ?", *ownerId, *departmentId)
+	case ownerId != nil:
+		db = db.Where("owner = ?", *ownerId)
+	default:
+		db = db.Where("department_id = ?", *departmentId)
+	}
+	result := db.Order("id").Find(&assets)
+	return assets, result.Error
+}
+
+func (r *PostgreSQLCalendarFeedRepository) GetSchedulesOfAssets(assetIds []int64, from time.Time, to time.Time) ([]*entity.MaintenanceSchedules, error) {
+	var schedules []*entity.MaintenanceSchedules
+	if len(assetIds) == 0 {
+		return schedules, nil
+	}
+	result := r.db.Model(entity.MaintenanceSchedules{}).
+		Where("asset_id IN ? AND end_date >= ? AND start_date < ?", assetIds, from, to).
+		Order("start_date").Find(&schedules)
+	return schedules, result.Error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+)
+
+type CalendarFeedRepository interface {
+	Create(feed *entity.CalendarFeed) (*entity.CalendarFeed, error)
+	GetById(id int64) (*entity.CalendarFeed, error)
+	GetByTokenHash(tokenHash string) (*entity.CalendarFeed, error)
+	GetByUser(userId int64) ([]*entity.CalendarFeed, error)
+	GetByCompany(companyId int64) ([]*entity.CalendarFeed, error)
+	Revoke(id int64, revokedAt time.Time) error
+	UpdateLastAccessed(id int64, accessedAt time.Time) error
+	GetFeedAssets(companyId int64, ownerId *int64, departmentId *int64) ([]*entity.Assets, error)
+	GetSchedulesOfAssets(assetIds []int64, from time.Time, to time.Time) ([]*entity.MaintenanceSchedules, error)
+}
@@ -25,6 +25,15 @@ func (r *PostgreSQLCategoriesRepository) GetAll(companyId int64) ([]*entity.Cate
 	return categories, result.Error
 }
 
+func (r *PostgreSQLCategoriesRepository) GetCategoryById(id int64) (*entity.Categories, error) {
+	category := &entity.Categories{}
+	result := r.db.Model(entity.Categories{}).Where("id = ?", id).First(category)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return category, nil
+}
+
 func (r *PostgreSQLCategoriesRepository) Delete(id int64) error {
 	result := r.db.Model(entity.Categories{}).Where("id = ?", id).Delete(entity.Categories{})
 	return result.Error
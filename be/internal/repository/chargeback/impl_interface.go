@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLChargebackRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLChargebackRepository(db *gorm.DB) ChargebackRepository {
+	return &PostgreSQLChargebackRepository{db: db}
+}
+
+func (r *PostgreSQLChargebackRepository) ReplacePeriod(companyId, month, year int64, summaries []*entity.DepartmentChargeback, lines []*entity.ChargebackLine, tx *gorm.DB) error {
+	if err := tx.Where("company_id = ? AND month = ? AND year = ?", companyId, month, year).Delete(&entity.ChargebackLine{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("company_id = ? AND month = ? AND year = ?", companyId, month, year).Delete(&entity.DepartmentChargeback{}).Error; err != nil {
+		return err
+	}
+	if len(summaries) > 0 {
+		if err := tx.Create(&summaries).Error; err != nil {
+			return err
+		}
+	}
+	if len(lines) > 0 {
+		if err := tx.CreateInBatches(&lines, 500).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PostgreSQLChargebackRepository) GetByPeriod(companyId, month, year int64, departmentId *int64) ([]*entity.DepartmentChargeback, error) {
+	var summaries []*entity.DepartmentChargeback
+	db := r.db.Model(entity.DepartmentChargeback{}).Where("company_id = ? AND month = ? AND year = ?", companyId, month, year)
+	if departmentId != nil {
+		db = db.Where("department_id = ?", *departmentId)
+	}
+	result := db.Preload("Department").Order("total_amount DESC").Find(&summaries)
+	return summaries, result.Error
+}
+
+func (r *PostgreSQLChargebackRepository) GetLinesByPeriod(companyId, month, year int64, departmentId *int64) ([]*entity.ChargebackLine, error) {
+	var lines []*entity.ChargebackLine
+	db := r.db.Model(entity.ChargebackLine{}).Where("company_id = ? AND month = ? AND year = ?", companyId, month, year)
+	if departmentId != nil {
+		db = db.Where("department_id = ?", *departmentId)
+	}
+	result := db.Preload("Department").Preload("Asset").Order("department_id, asset_id").Find(&lines)
+	return lines, result.Error
+}
+
+// Tài sản đã mua trước khi kết thúc kỳ và chưa thanh lý/ngừng sử dụng trước khi bắt đầu kỳ
+func (r *PostgreSQLChargebackRepository) GetAssetsHeldInPeriod(companyId int64, start, end time.Time) ([]*entity.Assets, error) {
+	var assets []*entity.Assets
+	result := r.db.Model(entity.Assets{}).
+		Where("company_id = ?", companyId).
+		Where("purchase_date < ?", end).
+		Where("retired_or_dispose_time IS NULL OR retired_or_dispose_time >= ?", start).
+		Find(&assets)
+	return assets, result.Error
+}
+
+// Các log làm thay đổi phòng ban của tài sản, mới nhất trước.
+// Log cũ không có from/to department thì nhận diện qua change summary.
+func (r *PostgreSQLChargebackRepository) GetDepartmentLogsSince(assetIds []int64, since time.Time) ([]*entity.AssetLog, error) {
+	var logs []*entity.AssetLog
+	if len(assetIds) == 0 {
+		return logs, nil
+	}
+	result := r.db.Model(entity.AssetLog{}).
+		Where("asset_id IN ?", assetIds).
+		Where("asset_logs.timestamp >= ?", since).
+		Where("from_department_id IS NOT NULL OR (action = ? AND change_summary LIKE ?)", "Transfer", "Transfer from department %").
+		Order("asset_logs.timestamp DESC, asset_logs.id DESC").
+		Find(&logs)
+	return logs, result.Error
+}
+
+func (r *PostgreSQLChargebackRepository) GetBillsByAssetIds(assetIds []int64) ([]*entity.Bill, error) {
+	var bills []*entity.Bill
+	if len(assetIds) == 0 {
+		return bills, nil
+	}
+	result := r.db.Model(entity.Bill{}).Where("asset_id IN ?", assetIds).Find(&bills)
+	return bills, result.Error
+}
+
+func (r *PostgreSQLChargebackRepository) GetMaintenanceInPeriod(assetIds []int64, start, end time.Time) ([]*entity.MaintenanceSchedules, error) {
+	var maintenances []*entity.MaintenanceSchedules
+	if len(assetIds) == 0 {
+		return maintenances, nil
+	}
+	result := r.db.Model(entity.MaintenanceSchedules{}).
+		Where("asset_id IN ?", assetIds).
+		Where("start_date >= ? AND start_date < ?", start, end).
+		Where("cost > 0").
+		Find(&maintenances)
+	return maintenances, result.Error
+}
+
+func (r *PostgreSQLChargebackRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ChargebackRepository interface {
+	ReplacePeriod(companyId, month, year int64, summaries []*entity.DepartmentChargeback, lines []*entity.ChargebackLine, tx *gorm.DB) error
+	GetByPeriod(companyId, month, year int64, departmentId *int64) ([]*entity.DepartmentChargeback, error)
+	GetLinesByPeriod(companyId, month, year int64, departmentId *int64) ([]*entity.ChargebackLine, error)
+	GetAssetsHeldInPeriod(companyId int64, start, end time.Time) ([]*entity.Assets, error)
+	GetDepartmentLogsSince(assetIds []int64, since time.Time) ([]*entity.AssetLog, error)
+	GetBillsByAssetIds(assetIds []int64) ([]*entity.Bill, error)
+	GetMaintenanceInPeriod(assetIds []int64, start, end time.Time) ([]*entity.MaintenanceSchedules, error)
+	GetDB() *gorm.DB
+}
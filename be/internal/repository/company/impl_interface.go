@@ -36,3 +36,7 @@ func (r *PostgreSQLCompanyRepository) GetAllCompany() ([]*entity.Company, error)
 	result := r.db.Model(entity.Company{}).Find(&company)
 	return company, result.Error
 }
+
+func (r *PostgreSQLCompanyRepository) UpdateRequireTwoFactor(id int64, required bool) error {
+	return r.db.Model(entity.Company{}).Where("id = ?", id).Update("require_two_factor", required).Error
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLConsumableRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLConsumableRepository(db *gorm.DB) ConsumableRepository {
+	return &PostgreSQLConsumableRepository{db: db}
+}
+
+func (r *PostgreSQLConsumableRepository) Create(consumable *entity.Consumable) (*entity.Consumable, error) {
+	result := r.db.Create(consumable)
+	return consumable, result.Error
+}
+
+func (r *PostgreSQLConsumableRepository) GetAll(companyId int64) ([]*entity.Consumable, error) {
+	var consumables []*entity.Consumable
+	result := r.db.Model(entity.Consumable{}).Where("company_id = ?", companyId).Order("name").Find(&consumables)
+	return consumables, result.Error
+}
+
+func (r *PostgreSQLConsumableRepository) GetById(id int64) (*entity.Consumable, error) {
+	consumable := &entity.Consumable{}
+	result := r.db.Model(entity.Consumable{}).Where("id = ?", id).First(consumable)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return consumable, nil
+}
+
+// Cộng/trừ tồn kho trong một câu lệnh để tránh tranh chấp, không cho tồn kho âm
+func (r *PostgreSQLConsumableRepository) AdjustQuantity(id int64, delta float64, tx *gorm.DB) error {
+	result := tx.Model(entity.Consumable{}).
+		Where("id = ? AND quantity + ? >= 0", id, delta).
+		Update("quantity", gorm.Expr("quantity + ?", delta))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("not enough quantity in stock")
+	}
+	return nil
+}
+
+func (r *PostgreSQLConsumableRepository) GetDB() *gorm.DB {
+	return r.db
+}
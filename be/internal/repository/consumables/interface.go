@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type ConsumableRepository interface {
+	Create(consumable *entity.Consumable) (*entity.Consumable, error)
+	GetAll(companyId int64) ([]*entity.Consumable, error)
+	GetById(id int64) (*entity.Consumable, error)
+	AdjustQuantity(id int64, delta float64, tx *gorm.DB) error
+	GetDB() *gorm.DB
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLDepartmentRestructureRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLDepartmentRestructureRepository(db *gorm.DB) DepartmentRestructureRepository {
+	return &PostgreSQLDepartmentRestructureRepository{db: db}
+}
+
+// Bảng và cột phòng ban của từng loại dữ liệu được chuyển
+var restructureColumns = map[string][2]string{
+	entity.RestructureItemUser:             {"users", "department_id"},
+	entity.RestructureItemAsset:            {"assets", "department_id"},
+	entity.RestructureItemAssignment:       {"assignments", "department_id"},
+	entity.RestructureItemWorkflowInstance: {"workflow_instances", "department_id"},
+	entity.RestructureItemCalendarFeed:     {"calendar_feeds", "department_id"},
+	entity.RestructureItemOffboarding:      {"offboardings", "target_department_id"},
+}
+
+func (r *PostgreSQLDepartmentRestructureRepository) Create(restructure *entity.DepartmentRestructure, tx *gorm.DB) (*entity.DepartmentRestructure, error) {
+	if err := tx.Omit("SourceDepartment", "TargetDepartment", "CreatedBy", "Items").Create(restructure).Error; err != nil {
+		return nil, err
+	}
+	return restructure, nil
+}
+
+func (r *PostgreSQLDepartmentRestructureRepository) GetById(id int64) (*entity.DepartmentRestructure, error) {
+	var restructure entity.DepartmentRestructure
+	result := r.db.Model(entity.DepartmentRestructure{}).Where("id = ?", id).
+		Preload("SourceDepartment").Preload("TargetDepartment").Preload("CreatedBy").
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("department_restructure_items.id ASC") }).
+		First(&restructure)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &restructure, nil
+}
+
+func (r *PostgreSQLDepartmentRestructureRepository) GetAll(companyId int64) ([]*entity.DepartmentRestructure, error) {
+	var restructures []*entity.DepartmentRestructure
+	result := r.db.Model(entity.DepartmentRestructure{}).Where("company_id = ?", companyId).
+		Preload("SourceDepartment").Preload("TargetDepartment").Preload("CreatedBy").
+		Order("created_at DESC").Find(&restructures)
+	return restructures, result.Error
+}
+
+func (r *PostgreSQLDepartmentRestructureRepository) Update(restructure *entity.DepartmentRestructure, tx *gorm.DB) error {
+	return tx.Model(entity.DepartmentRestructure{}).Where("id = ?", restructure.Id).Updates(map[string]interface{}{
+		"status":               restructure.Status,
+		"target_department_id": restructure.TargetDepartmentId,
+		"old_name":             restructure.OldName,
+		"applied_by_id":        restructure.AppliedById,
+		"applied_at":           restructure.AppliedAt,
+		"undo_deadline":        restructure.UndoDeadline,
+		"undone_at":            restructure.UndoneAt,
+	}).Error
+}
+
+func (r *PostgreSQLDepartmentRestructureRepository) CreateItems(items []*entity.DepartmentRestructureItem, tx *gorm.DB) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(items, 500).Error
+}
+
+func (r *PostgreSQLDepartmentRestructureRepository) MarkItemsReverted(ids []int64, tx *gorm.DB) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Model(entity.DepartmentRestructureItem{}).Where("id IN ?", ids).Update("reverted", true).Error
+}
+
+// GetMovableItems liệt kê dữ liệu đang thuộc phòng ban. subset = true thì chỉ lấy người dùng trong userIds,
+// tài sản trong assetIds cùng assignment và yêu cầu đang chờ duyệt của các tài sản đó
+func (r *PostgreSQLDepartmentRestructureRepository) GetMovableItems(departmentId int64, subset bool, userIds, assetIds []int64) ([]*entity.DepartmentRestructureItem, error) {
+	type row struct {
+		Id    int64
+		Label string
+	}
+	var items []*entity.DepartmentRestructureItem
+	collect := func(entityType string, db *gorm.DB) error {
+		var rows []row
+		if err := db.Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, rw := range rows {
+			items = append(items, &entity.DepartmentRestructureItem{EntityType: entityType, EntityId: rw.Id, Label: rw.Label, FromDepartmentId: departmentId})
+		}
+		return nil
+	}
+
+	users := r.db.Table("users").Select("id, first_name || ' ' || last_name || ' (' || email || ')' AS label").Where("department_id = ?", departmentId).Order("id")
+	assets := r.db.Table("assets").Select("id, asset_name AS label").Where("department_id = ?", departmentId).Order("id")
+	assignments := r.db.Table("assignments").Select("assignments.id, assets.asset_name AS label").
+		Joins("JOIN assets ON assets.id = assignments.asset_id").Where("assignments.department_id = ?", departmentId).Order("assignments.id")
+	workflows := r.db.Table("workflow_instances").Select("id, entity_type || ' #' || entity_id AS label").
+		Where("department_id = ? AND status = ?", departmentId, entity.WorkflowPending).Order("id")
+	if subset {
+		users = users.Where("id IN ?", append([]int64{0}, userIds...))
+		assets = assets.Where("id IN ?", append([]int64{0}, assetIds...))
+		assignments = assignments.Where("assignments.asset_id IN ?", append([]int64{0}, assetIds...))
+		workflows = workflows.Where("asset_id IN ?", append([]int64{0}, assetIds...))
+	}
+	if err := collect(entity.RestructureItemUser, users); err != nil {
+		return nil, err
+	}
+	if err := collect(entity.RestructureItemAsset, assets); err != nil {
+		return nil, err
+	}
+	if err := collect(entity.RestructureItemAssignment, assignments); err != nil {
+		return nil, err
+	}
+	if err := collect(entity.RestructureItemWorkflowInstance, workflows); err != nil {
+		return nil, err
+	}
+	if subset {
+		return items, nil
+	}
+	feeds := r.db.Table("calendar_feeds").Select("id, name AS label").Where("department_id = ? AND revoked_at IS NULL", departmentId).Order("id")
+	if err := collect(entity.RestructureItemCalendarFeed, feeds); err != nil {
+		return nil, err
+	}
+	offboardings := r.db.Table("offboardings").Select("offboardings.id, 'Offboarding of ' || users.email AS label").
+		Joins("JOIN users ON users.id = offboardings.user_id").
+		Where("offboardings.target_department_id = ? AND offboardings.status = ?", departmentId, entity.OffboardingInProgress).Order("offboardings.id")
+	if err := collect(entity.RestructureItemOffboarding, offboardings); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// MoveItems chuyển các bản ghi còn đang thuộc fromDepartmentId sang toDepartmentId, trả về id đã chuyển
+func (r *PostgreSQLDepartmentRestructureRepository) MoveItems(entityType string, ids []int64, fromDepartmentId, toDepartmentId int64, tx *gorm.DB) ([]int64, error) {
+	column := restructureColumns[entityType]
+	var moved []int64
+	if len(ids) == 0 {
+		return moved, nil
+	}
+	if err := tx.Table(column[0]).Where("id IN ? AND "+column[1]+" = ?", ids, fromDepartmentId).Pluck("id", &moved).Error; err != nil {
+		return nil, err
+	}
+	if len(moved) == 0 {
+		return moved, nil
+	}
+	if err := tx.Table(column[0]).Where("id IN ?", moved).Update(column[1], toDepartmentId).Error; err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+func (r *PostgreSQLDepartmentRestructureRepository) CreateDepartment(department *entity.Departments, tx *gorm.DB) error {
+	return tx.Omit("Location").Create(department).Error
+}
+
+func (r *PostgreSQLDepartmentRestructureRepository) RenameDepartment(id int64, name string, tx *gorm.DB) error {
+	return tx.Model(entity.Departments{}).Where("id = ?", id).Update("department_name", name).Error
+}
+
+func (r *PostgreSQLDepartmentRestructureRepository) SetMergedInto(id int64, mergedIntoId *int64, tx *gorm.DB) error {
+	return tx.Model(entity.Departments{}).Where("id = ?", id).Update("merged_into_id", mergedIntoId).Error
+}
+
+func (r *PostgreSQLDepartmentRestructureRepository) NameExists(companyId, locationId int64, name string, excludeId int64) (bool, error) {
+	var count int64
+	result := r.db.Model(entity.Departments{}).
+		Where("company_id = ? AND location_id = ? AND department_name = ? AND id <> ?", companyId, locationId, name, excludeId).Count(&count)
+	return count > 0, result.Error
+}
+
+func (r *PostgreSQLDepartmentRestructureRepository) GetDB() *gorm.DB {
+	return r.db
+}
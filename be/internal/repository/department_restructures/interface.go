@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type DepartmentRestructureRepository interface {
+	Create(restructure *entity.DepartmentRestructure, tx *gorm.DB) (*entity.DepartmentRestructure, error)
+	GetById(id int64) (*entity.DepartmentRestructure, error)
+	GetAll(companyId int64) ([]*entity.DepartmentRestructure, error)
+	Update(restructure *entity.DepartmentRestructure, tx *gorm.DB) error
+	CreateItems(items []*entity.DepartmentRestructureItem, tx *gorm.DB) error
+	MarkItemsReverted(ids []int64, tx *gorm.DB) error
+	GetMovableItems(departmentId int64, subset bool, userIds, assetIds []int64) ([]*entity.DepartmentRestructureItem, error)
+	MoveItems(entityType string, ids []int64, fromDepartmentId, toDepartmentId int64, tx *gorm.DB) ([]int64, error)
+	CreateDepartment(department *entity.Departments, tx *gorm.DB) error
+	RenameDepartment(id int64, name string, tx *gorm.DB) error
+	SetMergedInto(id int64, mergedIntoId *int64, tx *gorm.DB) error
+	NameExists(companyId, locationId int64, name string, excludeId int64) (bool, error)
+	GetDB() *gorm.DB
+}
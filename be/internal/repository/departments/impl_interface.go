@@ -21,7 +21,7 @@ func (r *PostgreSQLDepartmentsRepository) Create(department *entity.Departments)
 
 func (r *PostgreSQLDepartmentsRepository) GetAll(companyId int64) ([]*entity.Departments, error) {
 	departments := []*entity.Departments{}
-	result := r.db.Model(entity.Departments{}).Where("company_id = ?", companyId).Preload("Location").Find(&departments)
+	result := r.db.Model(entity.Departments{}).Where("company_id = ? AND merged_into_id IS NULL", companyId).Preload("Location").Find(&departments)
 	return departments, result.Error
 }
 
@@ -38,3 +38,14 @@ func (r *PostgreSQLDepartmentsRepository) GetDepartmentById(id int64) (*entity.D
 	}
 	return department, nil
 }
+
+// HasReferences phòng ban còn người dùng, tài sản, assignment hoặc yêu cầu đang chờ duyệt
+func (r *PostgreSQLDepartmentsRepository) HasReferences(id int64) (bool, error) {
+	var exists bool
+	err := r.db.Raw(`SELECT EXISTS (SELECT 1 FROM users WHERE department_id = ?)
+		OR EXISTS (SELECT 1 FROM assets WHERE department_id = ?)
+		OR EXISTS (SELECT 1 FROM assignments WHERE department_id = ?)
+		OR EXISTS (SELECT 1 FROM workflow_instances WHERE department_id = ? AND status = ?)`,
+		id, id, id, id, entity.WorkflowPending).Scan(&exists).Error
+	return exists, err
+}
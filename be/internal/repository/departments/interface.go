@@ -7,4 +7,5 @@ type DepartmentsRepository interface {
 	GetAll(companyId int64) ([]*entity.Departments, error)
 	Delete(id int64) error
 	GetDepartmentById(id int64) (*entity.Departments, error)
+	HasReferences(id int64) (bool, error)
 }
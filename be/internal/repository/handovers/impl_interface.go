@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLHandoverRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLHandoverRepository(db *gorm.DB) HandoverRepository {
+	return &PostgreSQLHandoverRepository{db: db}
+}
+
+func (r *PostgreSQLHandoverRepository) Create(handover *entity.HandoverAcknowledgement, tx *gorm.DB) (*entity.HandoverAcknowledgement, error) {
+	if err := tx.Omit("Asset", "User", "AssignedBy").Create(handover).Error; err != nil {
+		return nil, err
+	}
+	return handover, nil
+}
+
+// SupersedePending đóng các xác nhận chưa ký của tài sản khi tài sản được giao tiếp cho người khác
+func (r *PostgreSQLHandoverRepository) SupersedePending(assetId int64, tx *gorm.DB) error {
+	return tx.Model(entity.HandoverAcknowledgement{}).Where("asset_id = ? AND status = ?", assetId, entity.HandoverPending).Update("status", entity.HandoverSuperseded).Error
+}
+
+func (r *PostgreSQLHandoverRepository) GetById(id int64) (*entity.HandoverAcknowledgement, error) {
+	var handover entity.HandoverAcknowledgement
+	result := r.db.Model(entity.HandoverAcknowledgement{}).Where("id = ?", id).Preload("Asset").Preload("User").Preload("AssignedBy").First(&handover)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &handover, nil
+}
+
+func (r *PostgreSQLHandoverRepository) Filter(companyId int64, userId *int64, status *string, assetId *int64) ([]*entity.HandoverAcknowledgement, error) {
+	var handovers []*entity.HandoverAcknowledgement
+	db := r.db.Model(entity.HandoverAcknowledgement{}).Where("company_id = ?", companyId)
+	if userId != nil {
+		db = db.Where("user_id = ?", *userId)
+	}
+	if status != nil && *status != "" {
+		db = db.Where("status = ?", *status)
+	}
+	if assetId != nil {
+		db = db.Where("asset_id = ?", *assetId)
+	}
+	result := db.Preload("Asset").Preload("User").Preload("AssignedBy").Order("id DESC").Find(&handovers)
+	return handovers, result.Error
+}
+
+func (r *PostgreSQLHandoverRepository) Accept(handover *entity.HandoverAcknowledgement, tx *gorm.DB) error {
+	return tx.Model(&entity.HandoverAcknowledgement{}).Where("id = ?", handover.Id).Updates(map[string]interface{}{
+		"status":          handover.Status,
+		"accepted_at":     handover.AcceptedAt,
+		"ip_address":      handover.IpAddress,
+		"condition_grade": handover.ConditionGrade,
+		"condition_note":  handover.ConditionNote,
+		"signature_url":   handover.SignatureUrl,
+		"receipt_url":     handover.ReceiptUrl,
+		"signature":       handover.Signature,
+	}).Error
+}
+
+func (r *PostgreSQLHandoverRepository) UpdateAssignmentReceipt(assignmentId int64, receiptUrl *string, tx *gorm.DB) error {
+	return tx.Model(entity.Assignments{}).Where("id = ?", assignmentId).Update("handover_receipt_url", receiptUrl).Error
+}
+
+// GetPendingBefore lấy các xác nhận chưa ký mà lần tạo/nhắc gần nhất trước mốc before
+func (r *PostgreSQLHandoverRepository) GetPendingBefore(before time.Time) ([]*entity.HandoverAcknowledgement, error) {
+	var handovers []*entity.HandoverAcknowledgement
+	result := r.db.Model(entity.HandoverAcknowledgement{}).
+		Where("status = ? AND COALESCE(last_reminder_at, created_at) <= ?", entity.HandoverPending, before).
+		Preload("Asset").Preload("User").Find(&handovers)
+	return handovers, result.Error
+}
+
+func (r *PostgreSQLHandoverRepository) UpdateLastReminder(id int64, at time.Time) error {
+	return r.db.Model(entity.HandoverAcknowledgement{}).Where("id = ?", id).Update("last_reminder_at", at).Error
+}
+
+func (r *PostgreSQLHandoverRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type HandoverRepository interface {
+	Create(handover *entity.HandoverAcknowledgement, tx *gorm.DB) (*entity.HandoverAcknowledgement, error)
+	SupersedePending(assetId int64, tx *gorm.DB) error
+	GetById(id int64) (*entity.HandoverAcknowledgement, error)
+	Filter(companyId int64, userId *int64, status *string, assetId *int64) ([]*entity.HandoverAcknowledgement, error)
+	Accept(handover *entity.HandoverAcknowledgement, tx *gorm.DB) error
+	UpdateAssignmentReceipt(assignmentId int64, receiptUrl *string, tx *gorm.DB) error
+	GetPendingBefore(before time.Time) ([]*entity.HandoverAcknowledgement, error)
+	UpdateLastReminder(id int64, at time.Time) error
+	GetDB() *gorm.DB
+}
@@ -2,22 +2,45 @@ package repository
 
 import (
 	asset_log "BE_Manage_device/internal/repository/asset_log"
+	assetRelation "BE_Manage_device/internal/repository/asset_relation"
+	assetTemplate "BE_Manage_device/internal/repository/asset_template"
 	asset "BE_Manage_device/internal/repository/assets"
 	assignment "BE_Manage_device/internal/repository/assignments"
+	auditLog "BE_Manage_device/internal/repository/audit_logs"
 	bill "BE_Manage_device/internal/repository/bill"
+	calendarFeed "BE_Manage_device/internal/repository/calendar_feeds"
 	categories "BE_Manage_device/internal/repository/categories"
+	chargeback "BE_Manage_device/internal/repository/chargeback"
 	company "BE_Manage_device/internal/repository/company"
+	consumable "BE_Manage_device/internal/repository/consumables"
+	departmentRestructure "BE_Manage_device/internal/repository/department_restructures"
 	department "BE_Manage_device/internal/repository/departments"
+	handover "BE_Manage_device/internal/repository/handovers"
+	inspection "BE_Manage_device/internal/repository/inspections"
+	issueTicket "BE_Manage_device/internal/repository/issue_tickets"
+	ldap "BE_Manage_device/internal/repository/ldap"
 	location "BE_Manage_device/internal/repository/locations"
 	maintenanceNotification "BE_Manage_device/internal/repository/maintenance_notifications"
+	maintenancePlan "BE_Manage_device/internal/repository/maintenance_plans"
 	maintenanceSchedules "BE_Manage_device/internal/repository/maintenance_schedules"
+	meter "BE_Manage_device/internal/repository/meters"
 	monthlySummary "BE_Manage_device/internal/repository/monthly_summary"
 	notification "BE_Manage_device/internal/repository/noftifications"
+	offboarding "BE_Manage_device/internal/repository/offboarding"
+	reliability "BE_Manage_device/internal/repository/reliability"
 	request_transfer "BE_Manage_device/internal/repository/request_transfer"
 	role "BE_Manage_device/internal/repository/role"
+	scim "BE_Manage_device/internal/repository/scim"
+	serviceAccount "BE_Manage_device/internal/repository/service_accounts"
+	sso "BE_Manage_device/internal/repository/sso"
+	tag "BE_Manage_device/internal/repository/tags"
+	tco "BE_Manage_device/internal/repository/tco"
+	twoFactor "BE_Manage_device/internal/repository/two_factor"
 	user "BE_Manage_device/internal/repository/user"
 	userRBAC "BE_Manage_device/internal/repository/user_rbac"
 	userSession "BE_Manage_device/internal/repository/user_session"
+	workOrder "BE_Manage_device/internal/repository/work_orders"
+	workflow "BE_Manage_device/internal/repository/workflows"
 
 	"gorm.io/gorm"
 )
@@ -40,6 +63,29 @@ type Repository struct {
 	Company                 company.CompanyRepository
 	Bill                    bill.BillsRepository
 	MonthlySummary          monthlySummary.MonthlySummaryRepository
+	AssetTemplate           assetTemplate.AssetTemplateRepository
+	Tag                     tag.TagRepository
+	AssetRelation           assetRelation.AssetRelationRepository
+	Chargeback              chargeback.ChargebackRepository
+	Tco                     tco.TcoRepository
+	MaintenancePlan         maintenancePlan.MaintenancePlanRepository
+	Consumable              consumable.ConsumableRepository
+	WorkOrder               workOrder.WorkOrderRepository
+	CalendarFeed            calendarFeed.CalendarFeedRepository
+	Reliability             reliability.ReliabilityRepository
+	IssueTicket             issueTicket.IssueTicketRepository
+	Inspection              inspection.InspectionRepository
+	Meter                   meter.MeterRepository
+	Workflow                workflow.WorkflowRepository
+	Offboarding             offboarding.OffboardingRepository
+	Handover                handover.HandoverRepository
+	DepartmentRestructure   departmentRestructure.DepartmentRestructureRepository
+	TwoFactor               twoFactor.TwoFactorRepository
+	AuditLog                auditLog.AuditLogRepository
+	Sso                     sso.SsoRepository
+	Ldap                    ldap.LdapRepository
+	Scim                    scim.ScimRepository
+	ServiceAccount          serviceAccount.ServiceAccountRepository
 }
 
 func NewRepository(db *gorm.DB) *Repository {
@@ -61,5 +107,28 @@ func NewRepository(db *gorm.DB) *Repository {
 		Company:                 company.NewPostgreSQLCompanyRepository(db),
 		Bill:                    bill.NewPostgreSQLBillsRepository(db),
 		MonthlySummary:          monthlySummary.NewPostgreSQLMonthlySummary(db),
+		AssetTemplate:           assetTemplate.NewPostgreSQLAssetTemplateRepository(db),
+		Tag:                     tag.NewPostgreSQLTagRepository(db),
+		AssetRelation:           assetRelation.NewPostgreSQLAssetRelationRepository(db),
+		Chargeback:              chargeback.NewPostgreSQLChargebackRepository(db),
+		Tco:                     tco.NewPostgreSQLTcoRepository(db),
+		MaintenancePlan:         maintenancePlan.NewPostgreSQLMaintenancePlanRepository(db),
+		Consumable:              consumable.NewPostgreSQLConsumableRepository(db),
+		WorkOrder:               workOrder.NewPostgreSQLWorkOrderRepository(db),
+		CalendarFeed:            calendarFeed.NewPostgreSQLCalendarFeedRepository(db),
+		Reliability:             reliability.NewPostgreSQLReliabilityRepository(db),
+		IssueTicket:             issueTicket.NewPostgreSQLIssueTicketRepository(db),
+		Inspection:              inspection.NewPostgreSQLInspectionRepository(db),
+		Meter:                   meter.NewPostgreSQLMeterRepository(db),
+		Workflow:                workflow.NewPostgreSQLWorkflowRepository(db),
+		Offboarding:             offboarding.NewPostgreSQLOffboardingRepository(db),
+		Handover:                handover.NewPostgreSQLHandoverRepository(db),
+		DepartmentRestructure:   departmentRestructure.NewPostgreSQLDepartmentRestructureRepository(db),
+		TwoFactor:               twoFactor.NewPostgreSQLTwoFactorRepository(db),
+		AuditLog:                auditLog.NewPostgreSQLAuditLogRepository(db),
+		Sso:                     sso.NewPostgreSQLSsoRepository(db),
+		Ldap:                    ldap.NewPostgreSQLLdapRepository(db),
+		Scim:                    scim.NewPostgreSQLScimRepository(db),
+		ServiceAccount:          serviceAccount.NewPostgreSQLServiceAccountRepository(db),
 	}
 }
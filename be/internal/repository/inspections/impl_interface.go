@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLInspectionRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLInspectionRepository(db *gorm.DB) InspectionRepository {
+	return &PostgreSQLInspectionRepository{db: db}
+}
+
+func (r *PostgreSQLInspectionRepository) CreateTemplate(template *entity.InspectionTemplate, tx *gorm.DB) (*entity.InspectionTemplate, error) {
+	result := tx.Omit("Category").Create(template)
+	return template, result.Error
+}
+
+func (r *PostgreSQLInspectionRepository) GetTemplateById(id int64) (*entity.InspectionTemplate, error) {
+	template := &entity.InspectionTemplate{}
+	result := r.db.Model(entity.InspectionTemplate{}).Where("id = ?", id).Preload("Category").
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order, id") }).First(template)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return template, nil
+}
+
+func (r *PostgreSQLInspectionRepository) GetTemplates(companyId int64, categoryId *int64) ([]*entity.InspectionTemplate, error) {
+	var templates []*entity.InspectionTemplate
+	db := r.db.Model(entity.InspectionTemplate{}).Where("company_id = ?", companyId)
+	if categoryId != nil {
+		db = db.Where("category_id = ?", *categoryId)
+	}
+	result := db.Preload("Category").Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order, id") }).Order("id").Find(&templates)
+	return templates, result.Error
+}
+
+func (r *PostgreSQLInspectionRepository) UpdateTemplate(template *entity.InspectionTemplate, tx *gorm.DB) error {
+	return tx.Model(&entity.InspectionTemplate{Id: template.Id}).Select("Name", "CategoryId", "IntervalDays", "IsActive").Updates(template).Error
+}
+
+func (r *PostgreSQLInspectionRepository) ReplaceTemplateItems(templateId int64, items []entity.InspectionTemplateItem, tx *gorm.DB) error {
+	if err := tx.Where("template_id = ?", templateId).Delete(&entity.InspectionTemplateItem{}).Error; err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	for i := range items {
+		items[i].TemplateId = templateId
+	}
+	return tx.Create(&items).Error
+}
+
+// Các lần kiểm tra cũ vẫn giữ nhãn từng mục nên xoá template không làm mất lịch sử
+func (r *PostgreSQLInspectionRepository) DeleteTemplate(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", id).Delete(&entity.InspectionTemplateItem{}).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(entity.Inspection{}).Where("template_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		// Đã có lần kiểm tra tham chiếu thì chỉ ngừng kích hoạt
+		if count > 0 {
+			return tx.Model(entity.InspectionTemplate{}).Where("id = ?", id).Update("is_active", false).Error
+		}
+		return tx.Delete(&entity.InspectionTemplate{}, id).Error
+	})
+}
+
+func (r *PostgreSQLInspectionRepository) Create(inspection *entity.Inspection, tx *gorm.DB) (*entity.Inspection, error) {
+	result := tx.Omit("Asset", "Template", "Inspector").Create(inspection)
+	return inspection, result.Error
+}
+
+func (r *PostgreSQLInspectionRepository) GetById(id int64) (*entity.Inspection, error) {
+	inspection := &entity.Inspection{}
+	result := r.db.Model(entity.Inspection{}).Where("id = ?", id).
+		Preload("Asset").Preload("Template").Preload("Inspector").
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).First(inspection)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return inspection, nil
+}
+
+func (r *PostgreSQLInspectionRepository) GetByAsset(assetId int64) ([]*entity.Inspection, error) {
+	var inspections []*entity.Inspection
+	result := r.db.Model(entity.Inspection{}).Where("asset_id = ?", assetId).
+		Preload("Asset").Preload("Template").Preload("Inspector").
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Order("inspected_at DESC").Find(&inspections)
+	return inspections, result.Error
+}
+
+func (r *PostgreSQLInspectionRepository) GetLastInspections(templateIds []int64) ([]*LastInspectionRow, error) {
+	var rows []*LastInspectionRow
+	if len(templateIds) == 0 {
+		return rows, nil
+	}
+	result := r.db.Model(entity.Inspection{}).
+		Select("asset_id, template_id, MAX(inspected_at) AS last_inspected").
+		Where("template_id IN ?", templateIds).
+		Group("asset_id, template_id").Scan(&rows)
+	return rows, result.Error
+}
+
+// Tài sản còn hoạt động của các danh mục
+func (r *PostgreSQLInspectionRepository) GetAssetsOfCategories(companyId int64, categoryIds []int64, departmentId *int64) ([]*entity.Assets, error) {
+	var assets []*entity.Assets
+	if len(categoryIds) == 0 {
+		return assets, nil
+	}
+	db := r.db.Model(entity.Assets{}).
+		Where("company_id = ? AND category_id IN ? AND status NOT IN ?", companyId, categoryIds, []string{"Retired", "Disposed"})
+	if departmentId != nil {
+		db = db.Where("department_id = ?", *departmentId)
+	}
+	result := db.Preload("Department").Order("id").Find(&assets)
+	return assets, result.Error
+}
+
+func (r *PostgreSQLInspectionRepository) CreateSchedule(schedule *entity.MaintenanceSchedules, tx *gorm.DB) (*entity.MaintenanceSchedules, error) {
+	result := tx.Omit("Asset").Create(schedule)
+	return schedule, result.Error
+}
+
+func (r *PostgreSQLInspectionRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LastInspectionRow lần kiểm tra gần nhất của tài sản theo template
+type LastInspectionRow struct {
+	AssetId       int64
+	TemplateId    int64
+	LastInspected time.Time
+}
+
+type InspectionRepository interface {
+	CreateTemplate(template *entity.InspectionTemplate, tx *gorm.DB) (*entity.InspectionTemplate, error)
+	GetTemplateById(id int64) (*entity.InspectionTemplate, error)
+	GetTemplates(companyId int64, categoryId *int64) ([]*entity.InspectionTemplate, error)
+	UpdateTemplate(template *entity.InspectionTemplate, tx *gorm.DB) error
+	ReplaceTemplateItems(templateId int64, items []entity.InspectionTemplateItem, tx *gorm.DB) error
+	DeleteTemplate(id int64) error
+	Create(inspection *entity.Inspection, tx *gorm.DB) (*entity.Inspection, error)
+	GetById(id int64) (*entity.Inspection, error)
+	GetByAsset(assetId int64) ([]*entity.Inspection, error)
+	GetLastInspections(templateIds []int64) ([]*LastInspectionRow, error)
+	GetAssetsOfCategories(companyId int64, categoryIds []int64, departmentId *int64) ([]*entity.Assets, error)
+	CreateSchedule(schedule *entity.MaintenanceSchedules, tx *gorm.DB) (*entity.MaintenanceSchedules, error)
+	GetDB() *gorm.DB
+}
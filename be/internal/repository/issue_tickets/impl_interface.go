@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLIssueTicketRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLIssueTicketRepository(db *gorm.DB) IssueTicketRepository {
+	return &PostgreSQLIssueTicketRepository{db: db}
+}
+
+func (r *PostgreSQLIssueTicketRepository) Create(ticket *entity.IssueTicket, tx *gorm.DB) (*entity.IssueTicket, error) {
+	result := tx.Omit("Asset", "ReportedBy", "AssignedTo", "Schedule").Create(ticket)
+	return ticket, result.Error
+}
+
+func (r *PostgreSQLIssueTicketRepository) GetById(id int64) (*entity.IssueTicket, error) {
+	ticket := &entity.IssueTicket{}
+	result := r.db.Model(entity.IssueTicket{}).Where("id = ?", id).
+		Preload("Asset").Preload("ReportedBy").Preload("AssignedTo").Preload("Schedule").Preload("Photos").
+		Preload("Comments", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).Preload("Comments.User").
+		First(ticket)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return ticket, nil
+}
+
+// visibleToUserId: chỉ lấy ticket do người này báo cáo hoặc trên tài sản người này đang giữ
+func (r *PostgreSQLIssueTicketRepository) Filter(companyId int64, departmentId *int64, visibleToUserId *int64, status *string, severity *string, assetId *int64) ([]*entity.IssueTicket, error) {
+	var tickets []*entity.IssueTicket
+	db := r.db.Model(entity.IssueTicket{}).Where("issue_tickets.company_id = ?", companyId)
+	if departmentId != nil {
+		db = db.Where("asset_id IN (?)", r.db.Model(entity.Assets{}).Select("id").Where("department_id = ?", *departmentId))
+	}
+	if visibleToUserId != nil {
+		db = db.Where("reported_by_id = ? OR asset_id IN (?)", *visibleToUserId, r.db.Model(entity.Assets{}).Select("id").Where("owner = ?", *visibleToUserId))
+	}
+	if status != nil {
+		db = db.Where("status = ?", *status)
+	}
+	if severity != nil {
+		db = db.Where("severity = ?", *severity)
+	}
+	if assetId != nil {
+		db = db.Where("asset_id = ?", *assetId)
+	}
+	result := db.Preload("Asset").Preload("ReportedBy").Preload("AssignedTo").Preload("Photos").Order("id DESC").Find(&tickets)
+	return tickets, result.Error
+}
+
+func (r *PostgreSQLIssueTicketRepository) Update(ticket *entity.IssueTicket, tx *gorm.DB) error {
+	return tx.Model(&entity.IssueTicket{Id: ticket.Id}).Select("Severity", "Status", "AssignedToId", "ScheduleId", "UpdatedAt", "ClosedAt").Updates(ticket).Error
+}
+
+func (r *PostgreSQLIssueTicketRepository) CreatePhotos(photos []*entity.IssueTicketPhoto, tx *gorm.DB) error {
+	if len(photos) == 0 {
+		return nil
+	}
+	return tx.Create(&photos).Error
+}
+
+func (r *PostgreSQLIssueTicketRepository) CreateComment(comment *entity.IssueTicketComment) (*entity.IssueTicketComment, error) {
+	result := r.db.Omit("User").Create(comment)
+	return comment, result.Error
+}
+
+func (r *PostgreSQLIssueTicketRepository) CreateSchedule(schedule *entity.MaintenanceSchedules, tx *gorm.DB) (*entity.MaintenanceSchedules, error) {
+	result := tx.Omit("Asset").Create(schedule)
+	return schedule, result.Error
+}
+
+func (r *PostgreSQLIssueTicketRepository) CreateMaintenanceNotification(notification *entity.MaintenanceNotifications, tx *gorm.DB) error {
+	return tx.Omit("MaintenanceSchedule").Create(notification).Error
+}
+
+func (r *PostgreSQLIssueTicketRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type IssueTicketRepository interface {
+	Create(ticket *entity.IssueTicket, tx *gorm.DB) (*entity.IssueTicket, error)
+	GetById(id int64) (*entity.IssueTicket, error)
+	Filter(companyId int64, departmentId *int64, visibleToUserId *int64, status *string, severity *string, assetId *int64) ([]*entity.IssueTicket, error)
+	Update(ticket *entity.IssueTicket, tx *gorm.DB) error
+	CreatePhotos(photos []*entity.IssueTicketPhoto, tx *gorm.DB) error
+	CreateComment(comment *entity.IssueTicketComment) (*entity.IssueTicketComment, error)
+	CreateSchedule(schedule *entity.MaintenanceSchedules, tx *gorm.DB) (*entity.MaintenanceSchedules, error)
+	CreateMaintenanceNotification(notification *entity.MaintenanceNotifications, tx *gorm.DB) error
+	GetDB() *gorm.DB
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLLdapRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLLdapRepository(db *gorm.DB) LdapRepository {
+	return &PostgreSQLLdapRepository{db: db}
+}
+
+// GetByCompanyId trả về nil nếu công ty chưa cấu hình LDAP
+func (r *PostgreSQLLdapRepository) GetByCompanyId(companyId int64) (*entity.CompanyLdap, error) {
+	var ldap entity.CompanyLdap
+	result := r.db.Model(entity.CompanyLdap{}).Where("company_id = ?", companyId).First(&ldap)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &ldap, nil
+}
+
+func (r *PostgreSQLLdapRepository) GetAllSyncEnabled() ([]*entity.CompanyLdap, error) {
+	var ldaps []*entity.CompanyLdap
+	result := r.db.Model(entity.CompanyLdap{}).Where("sync_enabled = ?", true).Find(&ldaps)
+	return ldaps, result.Error
+}
+
+func (r *PostgreSQLLdapRepository) Save(ldap *entity.CompanyLdap) error {
+	return r.db.Save(ldap).Error
+}
+
+func (r *PostgreSQLLdapRepository) UpdateLastSyncAt(id int64, at time.Time) error {
+	return r.db.Model(entity.CompanyLdap{}).Where("id = ?", id).Update("last_sync_at", at).Error
+}
+
+func (r *PostgreSQLLdapRepository) GetDepartmentLinks(companyId int64) ([]*entity.LdapDepartment, error) {
+	var links []*entity.LdapDepartment
+	result := r.db.Model(entity.LdapDepartment{}).Where("company_id = ?", companyId).Find(&links)
+	return links, result.Error
+}
+
+func (r *PostgreSQLLdapRepository) SaveDepartmentLink(link *entity.LdapDepartment, tx *gorm.DB) error {
+	return tx.Save(link).Error
+}
+
+// FindDepartmentByName trả về nil nếu chưa có phòng ban cùng tên tại vị trí
+func (r *PostgreSQLLdapRepository) FindDepartmentByName(companyId, locationId int64, name string) (*entity.Departments, error) {
+	var department entity.Departments
+	result := r.db.Model(entity.Departments{}).Where("company_id = ? AND location_id = ? AND department_name = ?", companyId, locationId, name).First(&department)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &department, nil
+}
+
+func (r *PostgreSQLLdapRepository) CreateDepartment(department *entity.Departments, tx *gorm.DB) error {
+	return tx.Create(department).Error
+}
+
+func (r *PostgreSQLLdapRepository) RenameDepartment(id int64, name string, tx *gorm.DB) error {
+	return tx.Model(entity.Departments{}).Where("id = ?", id).Update("department_name", name).Error
+}
+
+func (r *PostgreSQLLdapRepository) GetIdentities(companyId int64) ([]*entity.UserIdentity, error) {
+	var identities []*entity.UserIdentity
+	result := r.db.Model(entity.UserIdentity{}).Where("provider = ? AND company_id = ?", entity.IdentityProviderLdap, companyId).Find(&identities)
+	return identities, result.Error
+}
+
+func (r *PostgreSQLLdapRepository) CreateUser(user *entity.Users, tx *gorm.DB) error {
+	return tx.Create(user).Error
+}
+
+func (r *PostgreSQLLdapRepository) UpdateUser(userId int64, updates map[string]interface{}, tx *gorm.DB) error {
+	return tx.Model(entity.Users{}).Where("id = ?", userId).Updates(updates).Error
+}
+
+func (r *PostgreSQLLdapRepository) CreateIdentity(identity *entity.UserIdentity, tx *gorm.DB) error {
+	return tx.Create(identity).Error
+}
+
+func (r *PostgreSQLLdapRepository) SetIdentitySuspended(id int64, suspended bool, tx *gorm.DB) error {
+	return tx.Model(entity.UserIdentity{}).Where("id = ?", id).Update("suspended", suspended).Error
+}
+
+func (r *PostgreSQLLdapRepository) RevokeSessions(userId int64, tx *gorm.DB) error {
+	return tx.Model(entity.UsersSessions{}).Where("user_id = ? AND is_revoked = ?", userId, false).Update("is_revoked", true).Error
+}
+
+func (r *PostgreSQLLdapRepository) CreateReport(report *entity.LdapSyncReport) error {
+	return r.db.Create(report).Error
+}
+
+func (r *PostgreSQLLdapRepository) UpdateReport(report *entity.LdapSyncReport) error {
+	return r.db.Save(report).Error
+}
+
+func (r *PostgreSQLLdapRepository) GetReports(companyId int64) ([]*entity.LdapSyncReport, error) {
+	var reports []*entity.LdapSyncReport
+	result := r.db.Model(entity.LdapSyncReport{}).Where("company_id = ?", companyId).Order("started_at DESC").Limit(100).Find(&reports)
+	return reports, result.Error
+}
+
+func (r *PostgreSQLLdapRepository) GetReportById(id int64) (*entity.LdapSyncReport, error) {
+	var report entity.LdapSyncReport
+	result := r.db.Model(entity.LdapSyncReport{}).Where("id = ?", id).First(&report)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &report, nil
+}
+
+func (r *PostgreSQLLdapRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LdapRepository interface {
+	GetByCompanyId(companyId int64) (*entity.CompanyLdap, error)
+	GetAllSyncEnabled() ([]*entity.CompanyLdap, error)
+	Save(ldap *entity.CompanyLdap) error
+	UpdateLastSyncAt(id int64, at time.Time) error
+	GetDepartmentLinks(companyId int64) ([]*entity.LdapDepartment, error)
+	SaveDepartmentLink(link *entity.LdapDepartment, tx *gorm.DB) error
+	FindDepartmentByName(companyId, locationId int64, name string) (*entity.Departments, error)
+	CreateDepartment(department *entity.Departments, tx *gorm.DB) error
+	RenameDepartment(id int64, name string, tx *gorm.DB) error
+	GetIdentities(companyId int64) ([]*entity.UserIdentity, error)
+	CreateUser(user *entity.Users, tx *gorm.DB) error
+	UpdateUser(userId int64, updates map[string]interface{}, tx *gorm.DB) error
+	CreateIdentity(identity *entity.UserIdentity, tx *gorm.DB) error
+	SetIdentitySuspended(id int64, suspended bool, tx *gorm.DB) error
+	RevokeSessions(userId int64, tx *gorm.DB) error
+	CreateReport(report *entity.LdapSyncReport) error
+	UpdateReport(report *entity.LdapSyncReport) error
+	GetReports(companyId int64) ([]*entity.LdapSyncReport, error)
+	GetReportById(id int64) (*entity.LdapSyncReport, error)
+	GetDB() *gorm.DB
+}
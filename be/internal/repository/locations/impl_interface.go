@@ -2,6 +2,8 @@ package repository
 
 import (
 	"BE_Manage_device/internal/domain/entity"
+	"errors"
+	"fmt"
 
 	"gorm.io/gorm"
 )
@@ -15,17 +17,93 @@ func NewPostgreSQLLocationRepository(db *gorm.DB) LocationRepository {
 }
 
 func (r *PostgreSQLLocationRepository) Create(location *entity.Locations) (*entity.Locations, error) {
-	result := r.db.Create(location)
-	return location, result.Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(location).Error; err != nil {
+			return err
+		}
+		parentPath := "/"
+		if location.ParentId != nil {
+			var parent entity.Locations
+			if err := tx.First(&parent, *location.ParentId).Error; err != nil {
+				return err
+			}
+			parentPath = parent.Path
+		}
+		location.Path = fmt.Sprintf("%v%d/", parentPath, location.Id)
+		return tx.Model(&entity.Locations{}).Where("id = ?", location.Id).Update("path", location.Path).Error
+	})
+	return location, err
 }
 
-func (r *PostgreSQLLocationRepository) GetAll() ([]*entity.Locations, error) {
+func (r *PostgreSQLLocationRepository) GetAll(companyId int64) ([]*entity.Locations, error) {
 	locations := []*entity.Locations{}
-	result := r.db.Model(entity.Locations{}).Find(&locations)
+	result := r.db.Model(entity.Locations{}).Where("company_id = ?", companyId).Order("path ASC").Find(&locations)
 	return locations, result.Error
 }
 
+func (r *PostgreSQLLocationRepository) GetById(id int64) (*entity.Locations, error) {
+	location := &entity.Locations{}
+	result := r.db.Model(entity.Locations{}).Where("id = ?", id).First(location)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return location, nil
+}
+
+func (r *PostgreSQLLocationRepository) Update(location *entity.Locations) (*entity.Locations, error) {
+	result := r.db.Model(&entity.Locations{}).Where("id = ?", location.Id).Updates(map[string]interface{}{
+		"location_name": location.LocationName,
+		"latitude":      location.Latitude,
+		"longitude":     location.Longitude,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return r.GetById(location.Id)
+}
+
 func (r *PostgreSQLLocationRepository) Delete(id int64) error {
 	result := r.db.Model(entity.Locations{}).Where("id = ?", id).Delete(entity.Locations{})
 	return result.Error
 }
+
+func (r *PostgreSQLLocationRepository) CountChildren(id int64) (int64, error) {
+	var total int64
+	result := r.db.Model(entity.Locations{}).Where("parent_id = ?", id).Count(&total)
+	return total, result.Error
+}
+
+func (r *PostgreSQLLocationRepository) CountReferences(id int64) (int64, error) {
+	var departments, assets int64
+	if err := r.db.Model(entity.Departments{}).Where("location_id = ?", id).Count(&departments).Error; err != nil {
+		return 0, err
+	}
+	if err := r.db.Model(entity.Assets{}).Where("room_id = ?", id).Count(&assets).Error; err != nil {
+		return 0, err
+	}
+	return departments + assets, nil
+}
+
+// CountAssetsByLevel đếm tài sản theo trạng thái cho từng vị trí thuộc cấp locationType.
+// Vị trí của tài sản là phòng (room_id) nếu có, ngược lại là vị trí của phòng ban.
+func (r *PostgreSQLLocationRepository) CountAssetsByLevel(companyId int64, locationType string, departmentId *int64) ([]*LocationStatusCount, error) {
+	counts := []*LocationStatusCount{}
+	db := r.db.Table("locations AS node").
+		Select("node.id AS location_id, assets.status AS status, COUNT(assets.id) AS total").
+		Joins("JOIN locations AS child ON child.path LIKE node.path || '%'").
+		Joins("JOIN departments ON departments.company_id = node.company_id").
+		Joins("JOIN assets ON assets.department_id = departments.id AND COALESCE(assets.room_id, departments.location_id) = child.id").
+		Where("node.company_id = ? AND node.location_type = ?", companyId, locationType)
+	if departmentId != nil {
+		db = db.Where("assets.department_id = ?", *departmentId)
+	}
+	result := db.Group("node.id, assets.status").Scan(&counts)
+	return counts, result.Error
+}
+
+func (r *PostgreSQLLocationRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -1,9 +1,26 @@
 package repository
 
-import "BE_Manage_device/internal/domain/entity"
+import (
+	"BE_Manage_device/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+// LocationStatusCount số tài sản theo trạng thái nằm trong cây con của một vị trí
+type LocationStatusCount struct {
+	LocationId int64
+	Status     string
+	Total      int
+}
 
 type LocationRepository interface {
 	Create(*entity.Locations) (*entity.Locations, error)
-	GetAll() ([]*entity.Locations, error)
+	GetAll(companyId int64) ([]*entity.Locations, error)
+	GetById(id int64) (*entity.Locations, error)
+	Update(*entity.Locations) (*entity.Locations, error)
 	Delete(id int64) error
+	CountChildren(id int64) (int64, error)
+	CountReferences(id int64) (int64, error)
+	CountAssetsByLevel(companyId int64, locationType string, departmentId *int64) ([]*LocationStatusCount, error)
+	GetDB() *gorm.DB
 }
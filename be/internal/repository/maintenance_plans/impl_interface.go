@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLMaintenancePlanRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLMaintenancePlanRepository(db *gorm.DB) MaintenancePlanRepository {
+	return &PostgreSQLMaintenancePlanRepository{db: db}
+}
+
+func (r *PostgreSQLMaintenancePlanRepository) Create(plan *entity.MaintenancePlan) (*entity.MaintenancePlan, error) {
+	result := r.db.Create(plan)
+	return plan, result.Error
+}
+
+func (r *PostgreSQLMaintenancePlanRepository) GetAll(companyId int64) ([]*entity.MaintenancePlan, error) {
+	var plans []*entity.MaintenancePlan
+	result := r.db.Model(entity.MaintenancePlan{}).Where("company_id = ?", companyId).Preload("Asset").Preload("Category").Order("id").Find(&plans)
+	return plans, result.Error
+}
+
+func (r *PostgreSQLMaintenancePlanRepository) GetActive() ([]*entity.MaintenancePlan, error) {
+	var plans []*entity.MaintenancePlan
+	result := r.db.Model(entity.MaintenancePlan{}).Where("is_active = ?", true).Find(&plans)
+	return plans, result.Error
+}
+
+func (r *PostgreSQLMaintenancePlanRepository) GetById(id int64) (*entity.MaintenancePlan, error) {
+	plan := &entity.MaintenancePlan{}
+	result := r.db.Model(entity.MaintenancePlan{}).Where("id = ?", id).Preload("Asset").Preload("Category").First(plan)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return plan, nil
+}
+
+func (r *PostgreSQLMaintenancePlanRepository) Update(plan *entity.MaintenancePlan, tx *gorm.DB) (*entity.MaintenancePlan, error) {
+	result := tx.Model(entity.MaintenancePlan{}).Where("id = ?", plan.Id).Updates(map[string]interface{}{
+		"plan_name":     plan.PlanName,
+		"asset_id":      plan.AssetId,
+		"category_id":   plan.CategoryId,
+		"rrule":         plan.RRule,
+		"start_date":    plan.StartDate,
+		"duration_days": plan.DurationDays,
+		"cost":          plan.Cost,
+		"is_active":     plan.IsActive,
+		"updated_at":    time.Now(),
+	})
+	return plan, result.Error
+}
+
+func (r *PostgreSQLMaintenancePlanRepository) Delete(id int64, tx *gorm.DB) error {
+	result := tx.Model(entity.MaintenancePlan{}).Where("id = ?", id).Delete(entity.MaintenancePlan{})
+	return result.Error
+}
+
+// Tài sản áp dụng kế hoạch: một tài sản cụ thể hoặc toàn bộ tài sản của danh mục, bỏ qua tài sản đã ngừng sử dụng/thanh lý
+func (r *PostgreSQLMaintenancePlanRepository) GetPlanAssets(plan *entity.MaintenancePlan) ([]*entity.Assets, error) {
+	var assets []*entity.Assets
+	db := r.db.Model(entity.Assets{}).Where("company_id = ?", plan.CompanyId).Where("status NOT IN ?", []string{"Retired", "Disposed"})
+	if plan.AssetId != nil {
+		db = db.Where("id = ?", *plan.AssetId)
+	} else {
+		db = db.Where("category_id = ?", plan.CategoryId)
+	}
+	result := db.Find(&assets)
+	return assets, result.Error
+}
+
+func (r *PostgreSQLMaintenancePlanRepository) GetSchedulesOfAssets(assetIds []int64, from time.Time) ([]*entity.MaintenanceSchedules, error) {
+	var schedules []*entity.MaintenanceSchedules
+	if len(assetIds) == 0 {
+		return schedules, nil
+	}
+	result := r.db.Model(entity.MaintenanceSchedules{}).Where("asset_id IN ?", assetIds).Where("end_date >= ?", from).Find(&schedules)
+	return schedules, result.Error
+}
+
+func (r *PostgreSQLMaintenancePlanRepository) CreateSchedules(schedules []*entity.MaintenanceSchedules) error {
+	if len(schedules) == 0 {
+		return nil
+	}
+	return r.db.Create(&schedules).Error
+}
+
+// Xóa các lần bảo trì chưa diễn ra của kế hoạch, giữ nguyên các lần đã qua
+func (r *PostgreSQLMaintenancePlanRepository) DeleteFutureSchedules(planId int64, from time.Time, tx *gorm.DB) (int64, error) {
+	future := tx.Model(entity.MaintenanceSchedules{}).Select("id").Where("plan_id = ? AND start_date >= ?", planId, from)
+	if err := tx.Where("schedule_id IN (?)", future).Delete(&entity.MaintenanceNotifications{}).Error; err != nil {
+		return 0, err
+	}
+	result := tx.Where("plan_id = ? AND start_date >= ?", planId, from).Delete(&entity.MaintenanceSchedules{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *PostgreSQLMaintenancePlanRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MaintenancePlanRepository interface {
+	Create(plan *entity.MaintenancePlan) (*entity.MaintenancePlan, error)
+	GetAll(companyId int64) ([]*entity.MaintenancePlan, error)
+	GetActive() ([]*entity.MaintenancePlan, error)
+	GetById(id int64) (*entity.MaintenancePlan, error)
+	Update(plan *entity.MaintenancePlan, tx *gorm.DB) (*entity.MaintenancePlan, error)
+	Delete(id int64, tx *gorm.DB) error
+	GetPlanAssets(plan *entity.MaintenancePlan) ([]*entity.Assets, error)
+	GetSchedulesOfAssets(assetIds []int64, from time.Time) ([]*entity.MaintenanceSchedules, error)
+	CreateSchedules(schedules []*entity.MaintenanceSchedules) error
+	DeleteFutureSchedules(planId int64, from time.Time, tx *gorm.DB) (int64, error)
+	GetDB() *gorm.DB
+}
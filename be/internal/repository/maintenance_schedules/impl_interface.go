@@ -15,8 +15,8 @@ func NewPostgreSQLMaintenanceSchedulesRepository(db *gorm.DB) MaintenanceSchedul
 	return &PostgreSQLMaintenanceSchedulesRepository{db: db}
 }
 
-func (r *PostgreSQLMaintenanceSchedulesRepository) Create(maintenance *entity.MaintenanceSchedules) (*entity.MaintenanceSchedules, error) {
-	result := r.db.Create(maintenance)
+func (r *PostgreSQLMaintenanceSchedulesRepository) Create(maintenance *entity.MaintenanceSchedules, tx *gorm.DB) (*entity.MaintenanceSchedules, error) {
+	result := tx.Create(maintenance)
 	return maintenance, result.Error
 }
 
@@ -31,9 +31,9 @@ func (r *PostgreSQLMaintenanceSchedulesRepository) GetAllMaintenanceSchedulesByA
 	return maintenances, result.Error
 }
 
-func (r *PostgreSQLMaintenanceSchedulesRepository) Update(id int64, startDate time.Time, endDate time.Time) (*entity.MaintenanceSchedules, error) {
+func (r *PostgreSQLMaintenanceSchedulesRepository) Update(id int64, startDate time.Time, endDate time.Time, cost float64) (*entity.MaintenanceSchedules, error) {
 	maintenance := entity.MaintenanceSchedules{}
-	result := r.db.Model(entity.MaintenanceSchedules{}).Where("id = ?", id).Update("start_date", startDate).Update("end_date", endDate)
+	result := r.db.Model(entity.MaintenanceSchedules{}).Where("id = ?", id).Updates(map[string]interface{}{"start_date": startDate, "end_date": endDate, "cost": cost})
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -82,3 +82,7 @@ func (r *PostgreSQLMaintenanceSchedulesRepository) GetDateMaintenanceSchedulesIn
 	}
 	return TimeRange, nil
 }
+
+func (r *PostgreSQLMaintenanceSchedulesRepository) GetDB() *gorm.DB {
+	return r.db
+}
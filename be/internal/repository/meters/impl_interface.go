@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLMeterRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLMeterRepository(db *gorm.DB) MeterRepository {
+	return &PostgreSQLMeterRepository{db: db}
+}
+
+func (r *PostgreSQLMeterRepository) CreateMeter(meter *entity.MeterDefinition, tx *gorm.DB) (*entity.MeterDefinition, error) {
+	result := tx.Omit("Category").Create(meter)
+	return meter, result.Error
+}
+
+func (r *PostgreSQLMeterRepository) GetMeterById(id int64) (*entity.MeterDefinition, error) {
+	meter := &entity.MeterDefinition{}
+	result := r.db.Model(entity.MeterDefinition{}).Where("id = ?", id).Preload("Category").
+		Preload("Thresholds", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).First(meter)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return meter, nil
+}
+
+func (r *PostgreSQLMeterRepository) GetMeters(companyId int64, categoryId *int64) ([]*entity.MeterDefinition, error) {
+	var meters []*entity.MeterDefinition
+	db := r.db.Model(entity.MeterDefinition{}).Where("company_id = ?", companyId)
+	if categoryId != nil {
+		db = db.Where("category_id = ?", *categoryId)
+	}
+	result := db.Preload("Category").Preload("Thresholds", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).Order("id").Find(&meters)
+	return meters, result.Error
+}
+
+func (r *PostgreSQLMeterRepository) UpdateMeter(meter *entity.MeterDefinition) error {
+	return r.db.Model(&entity.MeterDefinition{Id: meter.Id}).Select("Name", "Unit", "IsActive").Updates(meter).Error
+}
+
+func (r *PostgreSQLMeterRepository) CreateThreshold(threshold *entity.MeterThreshold) (*entity.MeterThreshold, error) {
+	result := r.db.Create(threshold)
+	return threshold, result.Error
+}
+
+func (r *PostgreSQLMeterRepository) GetThresholdById(id int64) (*entity.MeterThreshold, error) {
+	threshold := &entity.MeterThreshold{}
+	result := r.db.Model(entity.MeterThreshold{}).Where("id = ?", id).First(threshold)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return threshold, nil
+}
+
+func (r *PostgreSQLMeterRepository) UpdateThreshold(threshold *entity.MeterThreshold) error {
+	return r.db.Model(&entity.MeterThreshold{Id: threshold.Id}).Select("Name", "Interval", "DurationDays", "Cost", "IsActive").Updates(threshold).Error
+}
+
+func (r *PostgreSQLMeterRepository) DeleteThreshold(id int64) error {
+	return r.db.Delete(&entity.MeterThreshold{}, id).Error
+}
+
+// GetLastReading trả về nil nếu tài sản chưa có chỉ số nào của đồng hồ này
+func (r *PostgreSQLMeterRepository) GetLastReading(assetId int64, meterId int64) (*entity.MeterReading, error) {
+	var readings []*entity.MeterReading
+	result := r.db.Model(entity.MeterReading{}).Where("asset_id = ? AND meter_id = ?", assetId, meterId).
+		Order("read_at DESC, id DESC").Limit(1).Find(&readings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(readings) == 0 {
+		return nil, nil
+	}
+	return readings[0], nil
+}
+
+func (r *PostgreSQLMeterRepository) CreateReading(reading *entity.MeterReading, tx *gorm.DB) (*entity.MeterReading, error) {
+	result := tx.Omit("Meter", "RecordedBy").Create(reading)
+	return reading, result.Error
+}
+
+func (r *PostgreSQLMeterRepository) UpdateReadingSchedule(readingId int64, scheduleId int64, tx *gorm.DB) error {
+	return tx.Model(entity.MeterReading{}).Where("id = ?", readingId).Update("schedule_id", scheduleId).Error
+}
+
+func (r *PostgreSQLMeterRepository) GetReadingById(id int64) (*entity.MeterReading, error) {
+	reading := &entity.MeterReading{}
+	result := r.db.Model(entity.MeterReading{}).Where("id = ?", id).Preload("Meter").Preload("RecordedBy").First(reading)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return reading, nil
+}
+
+func (r *PostgreSQLMeterRepository) GetReadings(assetId int64, meterId *int64) ([]*entity.MeterReading, error) {
+	var readings []*entity.MeterReading
+	db := r.db.Model(entity.MeterReading{}).Where("asset_id = ?", assetId)
+	if meterId != nil {
+		db = db.Where("meter_id = ?", *meterId)
+	}
+	result := db.Preload("Meter").Preload("RecordedBy").Order("read_at DESC, id DESC").Find(&readings)
+	return readings, result.Error
+}
+
+func (r *PostgreSQLMeterRepository) CreateSchedule(schedule *entity.MaintenanceSchedules, tx *gorm.DB) (*entity.MaintenanceSchedules, error) {
+	result := tx.Omit("Asset").Create(schedule)
+	return schedule, result.Error
+}
+
+func (r *PostgreSQLMeterRepository) GetDB() *gorm.DB {
+	return r.db
+}
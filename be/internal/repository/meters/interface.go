@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type MeterRepository interface {
+	CreateMeter(meter *entity.MeterDefinition, tx *gorm.DB) (*entity.MeterDefinition, error)
+	GetMeterById(id int64) (*entity.MeterDefinition, error)
+	GetMeters(companyId int64, categoryId *int64) ([]*entity.MeterDefinition, error)
+	UpdateMeter(meter *entity.MeterDefinition) error
+	CreateThreshold(threshold *entity.MeterThreshold) (*entity.MeterThreshold, error)
+	GetThresholdById(id int64) (*entity.MeterThreshold, error)
+	UpdateThreshold(threshold *entity.MeterThreshold) error
+	DeleteThreshold(id int64) error
+	GetLastReading(assetId int64, meterId int64) (*entity.MeterReading, error)
+	CreateReading(reading *entity.MeterReading, tx *gorm.DB) (*entity.MeterReading, error)
+	UpdateReadingSchedule(readingId int64, scheduleId int64, tx *gorm.DB) error
+	GetReadingById(id int64) (*entity.MeterReading, error)
+	GetReadings(assetId int64, meterId *int64) ([]*entity.MeterReading, error)
+	CreateSchedule(schedule *entity.MaintenanceSchedules, tx *gorm.DB) (*entity.MaintenanceSchedules, error)
+	GetDB() *gorm.DB
+}
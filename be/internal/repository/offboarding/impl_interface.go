@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLOffboardingRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLOffboardingRepository(db *gorm.DB) OffboardingRepository {
+	return &PostgreSQLOffboardingRepository{db: db}
+}
+
+func (r *PostgreSQLOffboardingRepository) preload(db *gorm.DB) *gorm.DB {
+	return db.Preload("User").Preload("User.Department").Preload("StartedBy").Preload("CompletedBy").Preload("TargetUser").Preload("TargetDepartment").
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("offboarding_items.id ASC") }).Preload("Items.Asset")
+}
+
+func (r *PostgreSQLOffboardingRepository) Create(offboarding *entity.Offboarding, tx *gorm.DB) (*entity.Offboarding, error) {
+	if err := tx.Omit("Items").Create(offboarding).Error; err != nil {
+		return nil, err
+	}
+	return offboarding, nil
+}
+
+func (r *PostgreSQLOffboardingRepository) GetById(id int64) (*entity.Offboarding, error) {
+	var offboarding entity.Offboarding
+	result := r.preload(r.db.Model(entity.Offboarding{})).Where("id = ?", id).First(&offboarding)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &offboarding, nil
+}
+
+// GetOpenOfUser trả về nil nếu người dùng chưa có quy trình đang mở
+func (r *PostgreSQLOffboardingRepository) GetOpenOfUser(userId int64) (*entity.Offboarding, error) {
+	var offboarding entity.Offboarding
+	result := r.db.Model(entity.Offboarding{}).Where("user_id = ? AND status = ?", userId, entity.OffboardingInProgress).First(&offboarding)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &offboarding, nil
+}
+
+func (r *PostgreSQLOffboardingRepository) GetAll(companyId int64, status *string) ([]*entity.Offboarding, error) {
+	var offboardings []*entity.Offboarding
+	db := r.db.Model(entity.Offboarding{}).Where("company_id = ?", companyId)
+	if status != nil && *status != "" {
+		db = db.Where("status = ?", *status)
+	}
+	result := r.preload(db).Order("id DESC").Find(&offboardings)
+	return offboardings, result.Error
+}
+
+func (r *PostgreSQLOffboardingRepository) Update(offboarding *entity.Offboarding, tx *gorm.DB) error {
+	return tx.Model(&entity.Offboarding{}).Where("id = ?", offboarding.Id).Updates(map[string]interface{}{
+		"status":               offboarding.Status,
+		"completed_by_id":      offboarding.CompletedById,
+		"completed_at":         offboarding.CompletedAt,
+		"target_user_id":       offboarding.TargetUserId,
+		"target_department_id": offboarding.TargetDepartmentId,
+		"note":                 offboarding.Note,
+		"signature":            offboarding.Signature,
+	}).Error
+}
+
+func (r *PostgreSQLOffboardingRepository) CreateItems(items []*entity.OffboardingItem, tx *gorm.DB) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return tx.Omit("Asset").Create(&items).Error
+}
+
+func (r *PostgreSQLOffboardingRepository) GetItemById(id int64) (*entity.OffboardingItem, error) {
+	var item entity.OffboardingItem
+	result := r.db.Model(entity.OffboardingItem{}).Where("id = ?", id).Preload("Asset").First(&item)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+func (r *PostgreSQLOffboardingRepository) UpdateItem(item *entity.OffboardingItem) error {
+	return r.db.Model(&entity.OffboardingItem{}).Where("id = ?", item.Id).Updates(map[string]interface{}{
+		"status":        item.Status,
+		"note":          item.Note,
+		"checked_by_id": item.CheckedById,
+		"checked_at":    item.CheckedAt,
+	}).Error
+}
+
+func (r *PostgreSQLOffboardingRepository) GetHeldAssignments(userId int64) ([]*entity.Assignments, error) {
+	var assignments []*entity.Assignments
+	result := r.db.Model(entity.Assignments{}).Where("user_id = ?", userId).Preload("Asset").Preload("Asset.Department").Order("asset_id ASC").Find(&assignments)
+	return assignments, result.Error
+}
+
+// GetOpenRequestTransfers các yêu cầu điều chuyển của người dùng chưa kết thúc
+func (r *PostgreSQLOffboardingRepository) GetOpenRequestTransfers(userId int64) ([]entity.RequestTransfer, error) {
+	var requests []entity.RequestTransfer
+	result := r.db.Model(entity.RequestTransfer{}).
+		Where("user_id = ? AND status IN ?", userId, []string{entity.RequestTransferPending, entity.RequestTransferInApproval, entity.RequestTransferApproved}).
+		Preload("User").Preload("User.Department").Preload("Category").Preload("Asset").Order("id ASC").Find(&requests)
+	return requests, result.Error
+}
+
+func (r *PostgreSQLOffboardingRepository) CancelRequestTransfer(request *entity.RequestTransfer, byUserId int64, note string, tx *gorm.DB) error {
+	now := time.Now()
+	err := tx.Model(entity.RequestTransfer{}).Where("id = ?", request.Id).Updates(map[string]interface{}{"status": entity.RequestTransferCancelled, "updated_at": now}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(&entity.RequestTransferHistory{
+		RequestId:  request.Id,
+		FromStatus: request.Status,
+		ToStatus:   entity.RequestTransferCancelled,
+		ByUserId:   &byUserId,
+		Note:       note,
+		CreatedAt:  now,
+	}).Error
+}
+
+func (r *PostgreSQLOffboardingRepository) DeactivateUser(userId int64, tx *gorm.DB) error {
+	return tx.Model(entity.Users{}).Where("id = ?", userId).Update("is_active", false).Error
+}
+
+func (r *PostgreSQLOffboardingRepository) RevokeSessions(userId int64, tx *gorm.DB) error {
+	return tx.Model(entity.UsersSessions{}).Where("user_id = ? AND is_revoked = ?", userId, false).Update("is_revoked", true).Error
+}
+
+func (r *PostgreSQLOffboardingRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type OffboardingRepository interface {
+	Create(offboarding *entity.Offboarding, tx *gorm.DB) (*entity.Offboarding, error)
+	GetById(id int64) (*entity.Offboarding, error)
+	GetOpenOfUser(userId int64) (*entity.Offboarding, error)
+	GetAll(companyId int64, status *string) ([]*entity.Offboarding, error)
+	Update(offboarding *entity.Offboarding, tx *gorm.DB) error
+	CreateItems(items []*entity.OffboardingItem, tx *gorm.DB) error
+	GetItemById(id int64) (*entity.OffboardingItem, error)
+	UpdateItem(item *entity.OffboardingItem) error
+	GetHeldAssignments(userId int64) ([]*entity.Assignments, error)
+	GetOpenRequestTransfers(userId int64) ([]entity.RequestTransfer, error)
+	CancelRequestTransfer(request *entity.RequestTransfer, byUserId int64, note string, tx *gorm.DB) error
+	DeactivateUser(userId int64, tx *gorm.DB) error
+	RevokeSessions(userId int64, tx *gorm.DB) error
+	GetDB() *gorm.DB
+}
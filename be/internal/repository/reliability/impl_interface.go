@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLReliabilityRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLReliabilityRepository(db *gorm.DB) ReliabilityRepository {
+	return &PostgreSQLReliabilityRepository{db: db}
+}
+
+// assetIds = nil: lấy toàn bộ tài sản của công ty theo bộ lọc
+func (r *PostgreSQLReliabilityRepository) GetAssets(companyId int64, assetIds []int64, categoryId *int64, departmentId *int64) ([]*AssetReliabilityRow, error) {
+	var rows []*AssetReliabilityRow
+	db := r.db.Table("assets").
+		Select(`assets.id AS asset_id, assets.asset_name, assets.serial_number, assets.status,
+			assets.category_id, categories.category_name, assets.department_id, departments.department_name,
+			assets.purchase_date, assets.acquisition_date, assets.retired_or_dispose_time AS retired_at`).
+		Joins("LEFT JOIN categories ON categories.id = assets.category_id").
+		Joins("LEFT JOIN departments ON departments.id = assets.department_id").
+		Where("assets.company_id = ?", companyId)
+	if assetIds != nil {
+		if len(assetIds) == 0 {
+			return rows, nil
+		}
+		db = db.Where("assets.id IN ?", assetIds)
+	}
+	if categoryId != nil {
+		db = db.Where("assets.category_id = ?", *categoryId)
+	}
+	if departmentId != nil {
+		db = db.Where("assets.department_id = ?", *departmentId)
+	}
+	result := db.Order("assets.id").Scan(&rows)
+	return rows, result.Error
+}
+
+// Lịch bảo trì đã kết thúc trước thời điểm before
+func (r *PostgreSQLReliabilityRepository) GetFinishedSchedules(assetIds []int64, before time.Time) ([]*entity.MaintenanceSchedules, error) {
+	var schedules []*entity.MaintenanceSchedules
+	if len(assetIds) == 0 {
+		return schedules, nil
+	}
+	result := r.db.Model(entity.MaintenanceSchedules{}).
+		Where("asset_id IN ? AND end_date < ?", assetIds, before).
+		Order("asset_id, start_date").Find(&schedules)
+	return schedules, result.Error
+}
+
+func (r *PostgreSQLReliabilityRepository) GetMaintenanceLogs(assetIds []int64) ([]*entity.AssetLog, error) {
+	var logs []*entity.AssetLog
+	if len(assetIds) == 0 {
+		return logs, nil
+	}
+	result := r.db.Model(entity.AssetLog{}).
+		Where("asset_id IN ? AND action = ?", assetIds, "Maintenance").
+		Order("asset_id, timestamp").Find(&logs)
+	return logs, result.Error
+}
+
+func (r *PostgreSQLReliabilityRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AssetReliabilityRow thông tin tài sản dùng để tính độ tin cậy
+type AssetReliabilityRow struct {
+	AssetId         int64
+	AssetName       string
+	SerialNumber    string
+	Status          string
+	CategoryId      int64
+	CategoryName    string
+	DepartmentId    int64
+	DepartmentName  string
+	PurchaseDate    time.Time
+	AcquisitionDate *time.Time
+	RetiredAt       *time.Time
+}
+
+type ReliabilityRepository interface {
+	GetAssets(companyId int64, assetIds []int64, categoryId *int64, departmentId *int64) ([]*AssetReliabilityRow, error)
+	GetFinishedSchedules(assetIds []int64, before time.Time) ([]*entity.MaintenanceSchedules, error)
+	GetMaintenanceLogs(assetIds []int64) ([]*entity.AssetLog, error)
+	GetDB() *gorm.DB
+}
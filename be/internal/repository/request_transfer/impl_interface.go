@@ -2,6 +2,7 @@ package repository
 
 import (
 	"BE_Manage_device/internal/domain/entity"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,40 +15,24 @@ func NewPostgreSQLRequestTransferRepository(db *gorm.DB) RequestTransferReposito
 	return &PostgreSQLRequestTransferRepository{db: db}
 }
 
-func (r *PostgreSQLRequestTransferRepository) Create(requestTransfer *entity.RequestTransfer) (*entity.RequestTransfer, error) {
-	result := r.db.Model(entity.RequestTransfer{}).Preload("User").Preload("User.Department").Preload("Category").Create(requestTransfer)
+func (r *PostgreSQLRequestTransferRepository) Create(requestTransfer *entity.RequestTransfer, tx *gorm.DB) (*entity.RequestTransfer, error) {
+	result := tx.Model(entity.RequestTransfer{}).Create(requestTransfer)
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	request := entity.RequestTransfer{}
-	result = r.db.Model(entity.RequestTransfer{}).Where("id = ?", requestTransfer.Id).Preload("User").Preload("User.Department").Preload("Category").First(&request)
-	return &request, result.Error
+	return requestTransfer, nil
 }
 
-func (r *PostgreSQLRequestTransferRepository) UpdateStatusConfirm(id int64, tx *gorm.DB) (*entity.RequestTransfer, error) {
-	request := entity.RequestTransfer{}
-	result := tx.Model(entity.RequestTransfer{}).Where("id = ?", id).Update("status", "Confirm")
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	result = tx.Model(entity.RequestTransfer{}).Where("id = ?", id).Preload("User").Preload("User.Department").Preload("Category").First(&request)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return &request, nil
+func (r *PostgreSQLRequestTransferRepository) UpdateStatus(id int64, status string, tx *gorm.DB) error {
+	return tx.Model(entity.RequestTransfer{}).Where("id = ?", id).Updates(map[string]interface{}{"status": status, "updated_at": time.Now()}).Error
 }
 
-func (r PostgreSQLRequestTransferRepository) UpdateStatusDeny(id int64, tx *gorm.DB) (*entity.RequestTransfer, error) {
-	request := entity.RequestTransfer{}
-	result := r.db.Model(entity.RequestTransfer{}).Where("id = ?", id).Update("status", "Deny")
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	result = r.db.Model(entity.RequestTransfer{}).Where("id = ?", id).Preload("User").Preload("User.Department").Preload("Category").First(&request)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	return &request, nil
+func (r *PostgreSQLRequestTransferRepository) IncreaseFulfilledQuantity(id int64, tx *gorm.DB) error {
+	return tx.Model(entity.RequestTransfer{}).Where("id = ?", id).Update("fulfilled_quantity", gorm.Expr("fulfilled_quantity + 1")).Error
+}
+
+func (r *PostgreSQLRequestTransferRepository) UpdateLastReminder(id int64, at time.Time) error {
+	return r.db.Model(entity.RequestTransfer{}).Where("id = ?", id).Update("last_reminder_at", at).Error
 }
 
 func (r *PostgreSQLRequestTransferRepository) GetDB() *gorm.DB {
@@ -56,9 +41,73 @@ func (r *PostgreSQLRequestTransferRepository) GetDB() *gorm.DB {
 
 func (r *PostgreSQLRequestTransferRepository) GetRequestTransferById(id int64) (*entity.RequestTransfer, error) {
 	request := entity.RequestTransfer{}
-	result := r.db.Model(entity.RequestTransfer{}).Where("id = ?", id).Preload("User").Preload("User.Department").Preload("Category").First(&request)
+	result := r.db.Model(entity.RequestTransfer{}).Where("id = ?", id).Preload("User").Preload("User.Department").Preload("Category").Preload("Asset").First(&request)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return &request, nil
 }
+
+func (r *PostgreSQLRequestTransferRepository) GetByUserId(userId int64, status *string) ([]entity.RequestTransfer, error) {
+	var requests []entity.RequestTransfer
+	db := r.db.Model(entity.RequestTransfer{}).Where("user_id = ?", userId)
+	if status != nil && *status != "" {
+		db = db.Where("status = ?", *status)
+	}
+	result := db.Preload("User").Preload("User.Department").Preload("Category").Preload("Asset").Order("id DESC").Find(&requests)
+	return requests, result.Error
+}
+
+// GetPendingBefore lấy các yêu cầu đang chờ xử lý mà lần tạo/nhắc gần nhất trước mốc before
+func (r *PostgreSQLRequestTransferRepository) GetPendingBefore(before time.Time) ([]entity.RequestTransfer, error) {
+	var requests []entity.RequestTransfer
+	result := r.db.Model(entity.RequestTransfer{}).
+		Where("status = ? AND COALESCE(last_reminder_at, created_at) <= ?", entity.RequestTransferPending, before).
+		Preload("User").Preload("User.Department").Preload("Category").Preload("Asset").Find(&requests)
+	return requests, result.Error
+}
+
+func (r *PostgreSQLRequestTransferRepository) CreateHistory(history *entity.RequestTransferHistory, tx *gorm.DB) error {
+	return tx.Create(history).Error
+}
+
+func (r *PostgreSQLRequestTransferRepository) GetHistories(requestId int64) ([]*entity.RequestTransferHistory, error) {
+	var histories []*entity.RequestTransferHistory
+	result := r.db.Model(entity.RequestTransferHistory{}).Where("request_id = ?", requestId).Preload("ByUser").Order("created_at ASC, id ASC").Find(&histories)
+	return histories, result.Error
+}
+
+func (r *PostgreSQLRequestTransferRepository) CreateComment(comment *entity.RequestTransferComment) (*entity.RequestTransferComment, error) {
+	if err := r.db.Create(comment).Error; err != nil {
+		return nil, err
+	}
+	result := r.db.Model(entity.RequestTransferComment{}).Where("id = ?", comment.Id).Preload("User").First(comment)
+	return comment, result.Error
+}
+
+func (r *PostgreSQLRequestTransferRepository) GetComments(requestId int64) ([]*entity.RequestTransferComment, error) {
+	var comments []*entity.RequestTransferComment
+	result := r.db.Model(entity.RequestTransferComment{}).Where("request_id = ?", requestId).Preload("User").Order("created_at ASC, id ASC").Find(&comments)
+	return comments, result.Error
+}
+
+func (r *PostgreSQLRequestTransferRepository) CreateTransferredAsset(item *entity.RequestTransferAsset, tx *gorm.DB) error {
+	return tx.Create(item).Error
+}
+
+func (r *PostgreSQLRequestTransferRepository) GetTransferredAssets(requestId int64) ([]*entity.RequestTransferAsset, error) {
+	var items []*entity.RequestTransferAsset
+	result := r.db.Model(entity.RequestTransferAsset{}).Where("request_id = ?", requestId).Preload("Asset").Order("transferred_at ASC").Find(&items)
+	return items, result.Error
+}
+
+// GetTransferManagers lấy người dùng có quyền transfer-assets mức full trong công ty
+func (r *PostgreSQLRequestTransferRepository) GetTransferManagers(companyId int64) ([]*entity.Users, error) {
+	var users []*entity.Users
+	result := r.db.Model(entity.Users{}).
+		Joins("join role_permissions on role_permissions.role_id = users.role_id").
+		Joins("join permissions on permissions.id = role_permissions.permission_id").
+		Where("users.company_id = ? AND users.is_active = ? AND permissions.slug = ? AND role_permissions.access_level = ?", companyId, true, "transfer-assets", "full").
+		Find(&users)
+	return users, result.Error
+}
@@ -2,14 +2,25 @@ package repository
 
 import (
 	"BE_Manage_device/internal/domain/entity"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type RequestTransferRepository interface {
-	Create(*entity.RequestTransfer) (*entity.RequestTransfer, error)
-	UpdateStatusConfirm(id int64, tx *gorm.DB) (*entity.RequestTransfer, error)
-	UpdateStatusDeny(id int64, tx *gorm.DB) (*entity.RequestTransfer, error)
+	Create(requestTransfer *entity.RequestTransfer, tx *gorm.DB) (*entity.RequestTransfer, error)
+	UpdateStatus(id int64, status string, tx *gorm.DB) error
+	IncreaseFulfilledQuantity(id int64, tx *gorm.DB) error
+	UpdateLastReminder(id int64, at time.Time) error
 	GetDB() *gorm.DB
 	GetRequestTransferById(id int64) (*entity.RequestTransfer, error)
+	GetByUserId(userId int64, status *string) ([]entity.RequestTransfer, error)
+	GetPendingBefore(before time.Time) ([]entity.RequestTransfer, error)
+	CreateHistory(history *entity.RequestTransferHistory, tx *gorm.DB) error
+	GetHistories(requestId int64) ([]*entity.RequestTransferHistory, error)
+	CreateComment(comment *entity.RequestTransferComment) (*entity.RequestTransferComment, error)
+	GetComments(requestId int64) ([]*entity.RequestTransferComment, error)
+	CreateTransferredAsset(item *entity.RequestTransferAsset, tx *gorm.DB) error
+	GetTransferredAssets(requestId int64) ([]*entity.RequestTransferAsset, error)
+	GetTransferManagers(companyId int64) ([]*entity.Users, error)
 }
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLScimRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLScimRepository(db *gorm.DB) ScimRepository {
+	return &PostgreSQLScimRepository{db: db}
+}
+
+func (r *PostgreSQLScimRepository) CreateToken(token *entity.ScimToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *PostgreSQLScimRepository) GetTokens(companyId int64) ([]*entity.ScimToken, error) {
+	var tokens []*entity.ScimToken
+	result := r.db.Model(entity.ScimToken{}).Where("company_id = ?", companyId).Order("created_at DESC").Find(&tokens)
+	return tokens, result.Error
+}
+
+func (r *PostgreSQLScimRepository) GetTokenById(id int64) (*entity.ScimToken, error) {
+	var token entity.ScimToken
+	result := r.db.Model(entity.ScimToken{}).Where("id = ?", id).First(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+func (r *PostgreSQLScimRepository) RevokeToken(id int64, at time.Time) error {
+	return r.db.Model(entity.ScimToken{}).Where("id = ?", id).Update("revoked_at", at).Error
+}
+
+// FindUsers lọc người dùng của công ty, externalId nằm ở bảng user_identities
+func (r *PostgreSQLScimRepository) FindUsers(companyId int64, conditions []UserCondition, offset, limit int) ([]*entity.Users, int64, error) {
+	query := r.db.Model(entity.Users{}).
+		Joins("LEFT JOIN user_identities ON user_identities.user_id = users.id AND user_identities.provider = ? AND user_identities.company_id = ?", entity.IdentityProviderScim, companyId).
+		Where("users.company_id = ? AND users.is_system_user = ?", companyId, false)
+	for _, condition := range conditions {
+		switch condition.Operator {
+		case "eq":
+			if condition.Value == nil {
+				query = query.Where(fmt.Sprintf("%s IS NULL", condition.Column))
+			} else if value, ok := condition.Value.(string); ok {
+				// Thuộc tính chuỗi của SCIM không phân biệt hoa thường
+				query = query.Where(fmt.Sprintf("LOWER(%s) = ?", condition.Column), strings.ToLower(value))
+			} else {
+				query = query.Where(fmt.Sprintf("%s = ?", condition.Column), condition.Value)
+			}
+		case "ne":
+			if condition.Value == nil {
+				query = query.Where(fmt.Sprintf("%s IS NOT NULL", condition.Column))
+			} else if value, ok := condition.Value.(string); ok {
+				query = query.Where(fmt.Sprintf("LOWER(%s) <> ?", condition.Column), strings.ToLower(value))
+			} else {
+				query = query.Where(fmt.Sprintf("%s <> ?", condition.Column), condition.Value)
+			}
+		case "co":
+			query = query.Where(fmt.Sprintf("%s ILIKE ?", condition.Column), "%"+escapeLike(fmt.Sprint(condition.Value))+"%")
+		case "sw":
+			query = query.Where(fmt.Sprintf("%s ILIKE ?", condition.Column), escapeLike(fmt.Sprint(condition.Value))+"%")
+		case "ew":
+			query = query.Where(fmt.Sprintf("%s ILIKE ?", condition.Column), "%"+escapeLike(fmt.Sprint(condition.Value)))
+		case "pr":
+			query = query.Where(fmt.Sprintf("%s IS NOT NULL AND %s <> ''", condition.Column, condition.Column))
+		}
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []*entity.Users
+	result := query.Preload("Role").Preload("Department").Order("users.id").Offset(offset).Limit(limit).Find(&users)
+	return users, total, result.Error
+}
+
+func (r *PostgreSQLScimRepository) GetUserIdsByRole(companyId, roleId int64) ([]int64, error) {
+	var ids []int64
+	result := r.db.Model(entity.Users{}).Where("company_id = ? AND role_id = ? AND is_system_user = ?", companyId, roleId, false).Order("id").Pluck("id", &ids)
+	return ids, result.Error
+}
+
+func (r *PostgreSQLScimRepository) GetUserIdsByDepartment(departmentId int64) ([]int64, error) {
+	var ids []int64
+	result := r.db.Model(entity.Users{}).Where("department_id = ? AND is_system_user = ?", departmentId, false).Order("id").Pluck("id", &ids)
+	return ids, result.Error
+}
+
+func (r *PostgreSQLScimRepository) GetIdentities(companyId int64, userIds []int64) ([]*entity.UserIdentity, error) {
+	var identities []*entity.UserIdentity
+	result := r.db.Model(entity.UserIdentity{}).Where("provider = ? AND company_id = ? AND user_id IN ?", entity.IdentityProviderScim, companyId, userIds).Find(&identities)
+	return identities, result.Error
+}
+
+func (r *PostgreSQLScimRepository) CreateUser(user *entity.Users, tx *gorm.DB) error {
+	return tx.Create(user).Error
+}
+
+func (r *PostgreSQLScimRepository) UpdateUser(userId int64, updates map[string]interface{}, tx *gorm.DB) error {
+	return tx.Model(entity.Users{}).Where("id = ?", userId).Updates(updates).Error
+}
+
+func (r *PostgreSQLScimRepository) UpdateUsers(companyId int64, userIds []int64, updates map[string]interface{}, tx *gorm.DB) error {
+	return tx.Model(entity.Users{}).Where("company_id = ? AND id IN ?", companyId, userIds).Updates(updates).Error
+}
+
+func (r *PostgreSQLScimRepository) SaveIdentity(identity *entity.UserIdentity, tx *gorm.DB) error {
+	return tx.Save(identity).Error
+}
+
+func (r *PostgreSQLScimRepository) DeleteIdentity(companyId, userId int64, tx *gorm.DB) error {
+	return tx.Where("provider = ? AND company_id = ? AND user_id = ?", entity.IdentityProviderScim, companyId, userId).Delete(&entity.UserIdentity{}).Error
+}
+
+func (r *PostgreSQLScimRepository) FindDepartmentsByName(companyId int64, name string) ([]*entity.Departments, error) {
+	var departments []*entity.Departments
+	result := r.db.Model(entity.Departments{}).Where("company_id = ? AND merged_into_id IS NULL AND LOWER(department_name) = ?", companyId, strings.ToLower(name)).Order("id").Find(&departments)
+	return departments, result.Error
+}
+
+func (r *PostgreSQLScimRepository) CreateDepartment(department *entity.Departments, tx *gorm.DB) error {
+	return tx.Create(department).Error
+}
+
+func (r *PostgreSQLScimRepository) RenameDepartment(id int64, name string, tx *gorm.DB) error {
+	return tx.Model(entity.Departments{}).Where("id = ?", id).Update("department_name", name).Error
+}
+
+func (r *PostgreSQLScimRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserCondition điều kiện lọc người dùng đã đổi từ filter SCIM sang cột của bảng
+type UserCondition struct {
+	Column   string
+	Operator string
+	Value    interface{}
+}
+
+type ScimRepository interface {
+	CreateToken(token *entity.ScimToken) error
+	GetTokens(companyId int64) ([]*entity.ScimToken, error)
+	GetTokenById(id int64) (*entity.ScimToken, error)
+	RevokeToken(id int64, at time.Time) error
+	FindUsers(companyId int64, conditions []UserCondition, offset, limit int) ([]*entity.Users, int64, error)
+	GetUserIdsByRole(companyId, roleId int64) ([]int64, error)
+	GetUserIdsByDepartment(departmentId int64) ([]int64, error)
+	GetIdentities(companyId int64, userIds []int64) ([]*entity.UserIdentity, error)
+	CreateUser(user *entity.Users, tx *gorm.DB) error
+	UpdateUser(userId int64, updates map[string]interface{}, tx *gorm.DB) error
+	UpdateUsers(companyId int64, userIds []int64, updates map[string]interface{}, tx *gorm.DB) error
+	SaveIdentity(identity *entity.UserIdentity, tx *gorm.DB) error
+	DeleteIdentity(companyId, userId int64, tx *gorm.DB) error
+	FindDepartmentsByName(companyId int64, name string) ([]*entity.Departments, error)
+	CreateDepartment(department *entity.Departments, tx *gorm.DB) error
+	RenameDepartment(id int64, name string, tx *gorm.DB) error
+	GetDB() *gorm.DB
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLServiceAccountRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLServiceAccountRepository(db *gorm.DB) ServiceAccountRepository {
+	return &PostgreSQLServiceAccountRepository{db: db}
+}
+
+func (r *PostgreSQLServiceAccountRepository) CreateAccount(account *entity.ServiceAccount, tx *gorm.DB) error {
+	return tx.Create(account).Error
+}
+
+func (r *PostgreSQLServiceAccountRepository) GetAccounts(companyId int64) ([]*entity.ServiceAccount, error) {
+	var accounts []*entity.ServiceAccount
+	result := r.db.Model(entity.ServiceAccount{}).Where("company_id = ?", companyId).Preload("User.Role").Order("created_at DESC").Find(&accounts)
+	return accounts, result.Error
+}
+
+func (r *PostgreSQLServiceAccountRepository) GetAccountById(id int64) (*entity.ServiceAccount, error) {
+	var account entity.ServiceAccount
+	result := r.db.Model(entity.ServiceAccount{}).Where("id = ?", id).Preload("User.Role").First(&account)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &account, nil
+}
+
+func (r *PostgreSQLServiceAccountRepository) DisableAccount(id int64, at time.Time, tx *gorm.DB) error {
+	return tx.Model(entity.ServiceAccount{}).Where("id = ?", id).Update("disabled_at", at).Error
+}
+
+func (r *PostgreSQLServiceAccountRepository) CreateUser(user *entity.Users, tx *gorm.DB) error {
+	return tx.Create(user).Error
+}
+
+func (r *PostgreSQLServiceAccountRepository) DeactivateUser(userId int64, tx *gorm.DB) error {
+	return tx.Model(entity.Users{}).Where("id = ?", userId).Update("is_active", false).Error
+}
+
+// CreateKey tạo key cùng các scope
+func (r *PostgreSQLServiceAccountRepository) CreateKey(key *entity.ApiKey, tx *gorm.DB) error {
+	return tx.Create(key).Error
+}
+
+func (r *PostgreSQLServiceAccountRepository) GetKeys(serviceAccountId int64) ([]*entity.ApiKey, error) {
+	var keys []*entity.ApiKey
+	result := r.db.Model(entity.ApiKey{}).Where("service_account_id = ?", serviceAccountId).Preload("Scopes.Permission").Order("created_at DESC").Find(&keys)
+	return keys, result.Error
+}
+
+func (r *PostgreSQLServiceAccountRepository) GetKeyById(id int64) (*entity.ApiKey, error) {
+	var key entity.ApiKey
+	result := r.db.Model(entity.ApiKey{}).Where("id = ?", id).Preload("Scopes.Permission").First(&key)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &key, nil
+}
+
+func (r *PostgreSQLServiceAccountRepository) RevokeKey(id int64, at time.Time) error {
+	return r.db.Model(entity.ApiKey{}).Where("id = ?", id).Update("revoked_at", at).Error
+}
+
+func (r *PostgreSQLServiceAccountRepository) RevokeKeys(serviceAccountId int64, at time.Time, tx *gorm.DB) error {
+	return tx.Model(entity.ApiKey{}).Where("service_account_id = ? AND revoked_at IS NULL", serviceAccountId).Update("revoked_at", at).Error
+}
+
+func (r *PostgreSQLServiceAccountRepository) GetPermissionsBySlugs(slugs []string) ([]*entity.Permission, error) {
+	var permissions []*entity.Permission
+	result := r.db.Model(entity.Permission{}).Where("slug IN ?", slugs).Find(&permissions)
+	return permissions, result.Error
+}
+
+func (r *PostgreSQLServiceAccountRepository) GetRolePermissionIds(roleId int64) ([]int64, error) {
+	var ids []int64
+	result := r.db.Model(entity.RolePermission{}).Where("role_id = ?", roleId).Pluck("permission_id", &ids)
+	return ids, result.Error
+}
+
+func (r *PostgreSQLServiceAccountRepository) GetDB() *gorm.DB {
+	return r.db
+}
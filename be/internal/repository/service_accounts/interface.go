@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ServiceAccountRepository interface {
+	CreateAccount(account *entity.ServiceAccount, tx *gorm.DB) error
+	GetAccounts(companyId int64) ([]*entity.ServiceAccount, error)
+	GetAccountById(id int64) (*entity.ServiceAccount, error)
+	DisableAccount(id int64, at time.Time, tx *gorm.DB) error
+	CreateUser(user *entity.Users, tx *gorm.DB) error
+	DeactivateUser(userId int64, tx *gorm.DB) error
+	CreateKey(key *entity.ApiKey, tx *gorm.DB) error
+	GetKeys(serviceAccountId int64) ([]*entity.ApiKey, error)
+	GetKeyById(id int64) (*entity.ApiKey, error)
+	RevokeKey(id int64, at time.Time) error
+	RevokeKeys(serviceAccountId int64, at time.Time, tx *gorm.DB) error
+	GetPermissionsBySlugs(slugs []string) ([]*entity.Permission, error)
+	GetRolePermissionIds(roleId int64) ([]int64, error)
+	GetDB() *gorm.DB
+}
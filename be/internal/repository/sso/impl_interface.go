@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLSsoRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLSsoRepository(db *gorm.DB) SsoRepository {
+	return &PostgreSQLSsoRepository{db: db}
+}
+
+// GetByCompanyId trả về nil nếu công ty chưa cấu hình SSO
+func (r *PostgreSQLSsoRepository) GetByCompanyId(companyId int64) (*entity.CompanySso, error) {
+	var sso entity.CompanySso
+	result := r.db.Model(entity.CompanySso{}).Where("company_id = ?", companyId).First(&sso)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &sso, nil
+}
+
+func (r *PostgreSQLSsoRepository) Save(sso *entity.CompanySso) error {
+	return r.db.Save(sso).Error
+}
+
+func (r *PostgreSQLSsoRepository) CreateState(state *entity.SsoLoginState) error {
+	return r.db.Create(state).Error
+}
+
+func (r *PostgreSQLSsoRepository) GetStateByHash(stateHash string) (*entity.SsoLoginState, error) {
+	var state entity.SsoLoginState
+	result := r.db.Model(entity.SsoLoginState{}).Where("state_hash = ?", stateHash).First(&state)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &state, nil
+}
+
+// MarkStateUsed trả về false nếu state đã được dùng ở request khác
+func (r *PostgreSQLSsoRepository) MarkStateUsed(id int64, at time.Time) (bool, error) {
+	result := r.db.Model(entity.SsoLoginState{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetIdentity trả về nil nếu tài khoản bên ngoài chưa được liên kết
+func (r *PostgreSQLSsoRepository) GetIdentity(provider, issuer, subject string) (*entity.UserIdentity, error) {
+	var identity entity.UserIdentity
+	result := r.db.Model(entity.UserIdentity{}).Where("provider = ? AND issuer = ? AND subject = ?", provider, issuer, subject).First(&identity)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &identity, nil
+}
+
+func (r *PostgreSQLSsoRepository) CreateIdentity(identity *entity.UserIdentity, tx *gorm.DB) error {
+	return tx.Create(identity).Error
+}
+
+func (r *PostgreSQLSsoRepository) UpdateIdentityLogin(id int64, at time.Time) error {
+	return r.db.Model(entity.UserIdentity{}).Where("id = ?", id).Update("last_login_at", at).Error
+}
+
+func (r *PostgreSQLSsoRepository) GetDB() *gorm.DB {
+	return r.db
+}
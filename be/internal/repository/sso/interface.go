@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SsoRepository interface {
+	GetByCompanyId(companyId int64) (*entity.CompanySso, error)
+	Save(sso *entity.CompanySso) error
+	CreateState(state *entity.SsoLoginState) error
+	GetStateByHash(stateHash string) (*entity.SsoLoginState, error)
+	MarkStateUsed(id int64, at time.Time) (bool, error)
+	GetIdentity(provider, issuer, subject string) (*entity.UserIdentity, error)
+	CreateIdentity(identity *entity.UserIdentity, tx *gorm.DB) error
+	UpdateIdentityLogin(id int64, at time.Time) error
+	GetDB() *gorm.DB
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgreSQLTagRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLTagRepository(db *gorm.DB) TagRepository {
+	return &PostgreSQLTagRepository{db: db}
+}
+
+func (r *PostgreSQLTagRepository) Create(tag *entity.Tags, tx *gorm.DB) (*entity.Tags, error) {
+	result := tx.Create(tag)
+	return tag, result.Error
+}
+
+func (r *PostgreSQLTagRepository) GetById(id int64) (*entity.Tags, error) {
+	tag := &entity.Tags{}
+	result := r.db.Model(entity.Tags{}).Where("id = ?", id).First(tag)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return tag, nil
+}
+
+func (r *PostgreSQLTagRepository) GetByNames(companyId int64, tagNames []string) ([]*entity.Tags, error) {
+	tags := []*entity.Tags{}
+	result := r.db.Model(entity.Tags{}).Where("company_id = ? AND tag_name IN ?", companyId, tagNames).Find(&tags)
+	return tags, result.Error
+}
+
+func (r *PostgreSQLTagRepository) Search(companyId int64, keyword string, limit int) ([]*entity.Tags, error) {
+	tags := []*entity.Tags{}
+	result := r.db.Model(entity.Tags{}).
+		Where("company_id = ? AND tag_name LIKE ?", companyId, keyword+"%").
+		Order("tag_name ASC").
+		Limit(limit).
+		Find(&tags)
+	return tags, result.Error
+}
+
+func (r *PostgreSQLTagRepository) Delete(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", id).Delete(&entity.AssetTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(entity.Tags{}).Where("id = ?", id).Delete(entity.Tags{}).Error
+	})
+}
+
+func (r *PostgreSQLTagRepository) AddToAssets(assetIds []int64, tagIds []int64, tx *gorm.DB) error {
+	assetTags := []entity.AssetTag{}
+	for _, assetId := range assetIds {
+		for _, tagId := range tagIds {
+			assetTags = append(assetTags, entity.AssetTag{AssetId: assetId, TagId: tagId})
+		}
+	}
+	if len(assetTags) == 0 {
+		return nil
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&assetTags)
+	return result.Error
+}
+
+func (r *PostgreSQLTagRepository) RemoveFromAssets(assetIds []int64, tagIds []int64, tx *gorm.DB) error {
+	if len(assetIds) == 0 || len(tagIds) == 0 {
+		return nil
+	}
+	result := tx.Where("asset_id IN ? AND tag_id IN ?", assetIds, tagIds).Delete(&entity.AssetTag{})
+	return result.Error
+}
+
+func (r *PostgreSQLTagRepository) RemoveAllFromAsset(assetId int64, tx *gorm.DB) error {
+	result := tx.Where("asset_id = ?", assetId).Delete(&entity.AssetTag{})
+	return result.Error
+}
+
+func (r *PostgreSQLTagRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	Create(tag *entity.Tags, tx *gorm.DB) (*entity.Tags, error)
+	GetById(id int64) (*entity.Tags, error)
+	GetByNames(companyId int64, tagNames []string) ([]*entity.Tags, error)
+	Search(companyId int64, keyword string, limit int) ([]*entity.Tags, error)
+	Delete(id int64) error
+	AddToAssets(assetIds []int64, tagIds []int64, tx *gorm.DB) error
+	RemoveFromAssets(assetIds []int64, tagIds []int64, tx *gorm.DB) error
+	RemoveAllFromAsset(assetId int64, tx *gorm.DB) error
+	GetDB() *gorm.DB
+}
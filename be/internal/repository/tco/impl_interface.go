@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+type PostgreSQLTcoRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLTcoRepository(db *gorm.DB) TcoRepository {
+	return &PostgreSQLTcoRepository{db: db}
+}
+
+func (r *PostgreSQLTcoRepository) GetAssetCosts(companyId int64, assetId, categoryId, departmentId *int64) ([]*AssetCostRow, error) {
+	var rows []*AssetCostRow
+	maintenance := r.db.Table("maintenance_schedules").
+		Select("asset_id, SUM(cost) AS cost, COUNT(*) AS count").
+		Group("asset_id")
+	db := r.db.Table("assets").
+		Select(`assets.id AS asset_id, assets.asset_name, assets.serial_number, assets.status,
+			assets.category_id, categories.category_name, assets.department_id, departments.department_name,
+			assets.cost, bills.amount AS bill_amount,
+			COALESCE(m.cost, 0) AS maintenance_cost, COALESCE(m.count, 0) AS maintenance_count,
+			assets.residual_value, assets.purchase_date, assets.retired_or_dispose_time AS retired_at`).
+		Joins("LEFT JOIN categories ON categories.id = assets.category_id").
+		Joins("LEFT JOIN departments ON departments.id = assets.department_id").
+		Joins("LEFT JOIN bills ON bills.asset_id = assets.id").
+		Joins("LEFT JOIN (?) AS m ON m.asset_id = assets.id", maintenance).
+		Where("assets.company_id = ?", companyId)
+	if assetId != nil {
+		db = db.Where("assets.id = ?", *assetId)
+	}
+	if categoryId != nil {
+		db = db.Where("assets.category_id = ?", *categoryId)
+	}
+	if departmentId != nil {
+		db = db.Where("assets.department_id = ?", *departmentId)
+	}
+	result := db.Scan(&rows)
+	return rows, result.Error
+}
+
+func (r *PostgreSQLTcoRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AssetCostRow dữ liệu chi phí thô của một tài sản
+type AssetCostRow struct {
+	AssetId          int64
+	AssetName        string
+	SerialNumber     string
+	Status           string
+	CategoryId       int64
+	CategoryName     string
+	DepartmentId     int64
+	DepartmentName   string
+	Cost             float64
+	BillAmount       *float64
+	MaintenanceCost  float64
+	MaintenanceCount int64
+	ResidualValue    *float64
+	PurchaseDate     time.Time
+	RetiredAt        *time.Time
+}
+
+type TcoRepository interface {
+	GetAssetCosts(companyId int64, assetId, categoryId, departmentId *int64) ([]*AssetCostRow, error)
+	GetDB() *gorm.DB
+}
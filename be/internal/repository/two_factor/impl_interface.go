@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLTwoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &PostgreSQLTwoFactorRepository{db: db}
+}
+
+// GetByUserId trả về nil nếu người dùng chưa đăng ký 2FA
+func (r *PostgreSQLTwoFactorRepository) GetByUserId(userId int64) (*entity.UserTwoFactor, error) {
+	var twoFactor entity.UserTwoFactor
+	result := r.db.Model(entity.UserTwoFactor{}).Where("user_id = ?", userId).First(&twoFactor)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &twoFactor, nil
+}
+
+func (r *PostgreSQLTwoFactorRepository) Save(twoFactor *entity.UserTwoFactor, tx *gorm.DB) error {
+	return tx.Save(twoFactor).Error
+}
+
+// UpdateLastUsedStep chỉ tăng chu kỳ, trả về false nếu mã đã được dùng ở request khác
+func (r *PostgreSQLTwoFactorRepository) UpdateLastUsedStep(userId int64, step int64) (bool, error) {
+	result := r.db.Model(entity.UserTwoFactor{}).Where("user_id = ? AND last_used_step < ?", userId, step).Update("last_used_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgreSQLTwoFactorRepository) Delete(userId int64, tx *gorm.DB) error {
+	if err := tx.Where("user_id = ?", userId).Delete(&entity.UserTwoFactor{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", userId).Delete(&entity.UserRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	return tx.Model(entity.TwoFactorChallenge{}).Where("user_id = ? AND used_at IS NULL", userId).Update("used_at", time.Now()).Error
+}
+
+func (r *PostgreSQLTwoFactorRepository) ReplaceRecoveryCodes(userId int64, codeHashes []string, tx *gorm.DB) error {
+	if err := tx.Where("user_id = ?", userId).Delete(&entity.UserRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]entity.UserRecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, entity.UserRecoveryCode{UserId: userId, CodeHash: hash})
+	}
+	return tx.Create(&codes).Error
+}
+
+// UseRecoveryCode đánh dấu mã đã dùng, trả về false nếu mã sai hoặc đã dùng
+func (r *PostgreSQLTwoFactorRepository) UseRecoveryCode(userId int64, codeHash string, at time.Time) (bool, error) {
+	result := r.db.Model(entity.UserRecoveryCode{}).Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).Update("used_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgreSQLTwoFactorRepository) CountRecoveryCodes(userId int64) (int64, error) {
+	var count int64
+	result := r.db.Model(entity.UserRecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userId).Count(&count)
+	return count, result.Error
+}
+
+func (r *PostgreSQLTwoFactorRepository) CreateChallenge(challenge *entity.TwoFactorChallenge) error {
+	return r.db.Create(challenge).Error
+}
+
+func (r *PostgreSQLTwoFactorRepository) GetChallengeByHash(tokenHash string) (*entity.TwoFactorChallenge, error) {
+	var challenge entity.TwoFactorChallenge
+	result := r.db.Model(entity.TwoFactorChallenge{}).Where("token_hash = ?", tokenHash).First(&challenge)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &challenge, nil
+}
+
+func (r *PostgreSQLTwoFactorRepository) IncreaseChallengeAttempts(id int64) error {
+	return r.db.Model(entity.TwoFactorChallenge{}).Where("id = ?", id).Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+func (r *PostgreSQLTwoFactorRepository) MarkChallengeUsed(id int64, at time.Time) (bool, error) {
+	result := r.db.Model(entity.TwoFactorChallenge{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgreSQLTwoFactorRepository) GetDB() *gorm.DB {
+	return r.db
+}
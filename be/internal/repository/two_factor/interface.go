@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TwoFactorRepository interface {
+	GetByUserId(userId int64) (*entity.UserTwoFactor, error)
+	Save(twoFactor *entity.UserTwoFactor, tx *gorm.DB) error
+	UpdateLastUsedStep(userId int64, step int64) (bool, error)
+	Delete(userId int64, tx *gorm.DB) error
+	ReplaceRecoveryCodes(userId int64, codeHashes []string, tx *gorm.DB) error
+	UseRecoveryCode(userId int64, codeHash string, at time.Time) (bool, error)
+	CountRecoveryCodes(userId int64) (int64, error)
+	CreateChallenge(challenge *entity.TwoFactorChallenge) error
+	GetChallengeByHash(tokenHash string) (*entity.TwoFactorChallenge, error)
+	IncreaseChallengeAttempts(id int64) error
+	MarkChallengeUsed(id int64, at time.Time) (bool, error)
+	GetDB() *gorm.DB
+}
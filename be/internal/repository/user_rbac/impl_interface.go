@@ -20,3 +20,19 @@ func (r *PostgreSQLUserRBACRepository) Create(userRBAC *entity.UserRbac, tx *gor
 	}
 	return nil
 }
+
+// GrantCompanyAssets cấp quyền trên các tài sản của công ty cho người dùng mới, giống khi kích hoạt tài khoản
+func (r *PostgreSQLUserRBACRepository) GrantCompanyAssets(userId, roleId, companyId int64, tx *gorm.DB) error {
+	var assetIds []int64
+	if err := tx.Model(entity.Assets{}).Where("company_id = ?", companyId).Pluck("id", &assetIds).Error; err != nil {
+		return err
+	}
+	if len(assetIds) == 0 {
+		return nil
+	}
+	rbacs := make([]entity.UserRbac, 0, len(assetIds))
+	for _, assetId := range assetIds {
+		rbacs = append(rbacs, entity.UserRbac{AssetId: assetId, UserId: userId, RoleId: roleId})
+	}
+	return tx.CreateInBatches(&rbacs, 500).Error
+}
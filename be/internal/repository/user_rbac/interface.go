@@ -8,4 +8,5 @@ import (
 
 type UserRBACRepository interface {
 	Create(userRBAC *entity.UserRbac, tx *gorm.DB) error
+	GrantCompanyAssets(userId, roleId, companyId int64, tx *gorm.DB) error
 }
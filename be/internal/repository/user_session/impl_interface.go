@@ -3,6 +3,7 @@ package repository
 import (
 	"BE_Manage_device/internal/domain/entity"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -54,3 +55,24 @@ func (r *PostgreSQLUserSessionRepository) CheckTokenWasInVoked(accessToken strin
 	r.db.Model(&entity.UsersSessions{}).Where("access_token = ?", accessToken).First(userSessions)
 	return userSessions.IsRevoked
 }
+
+// FindActiveApiKey API key chưa thu hồi, chưa hết hạn và service account chưa bị vô hiệu hoá
+func (r *PostgreSQLUserSessionRepository) FindActiveApiKey(keyHash string) (*entity.ApiKey, error) {
+	var apiKey = &entity.ApiKey{}
+	result := r.db.Model(&entity.ApiKey{}).
+		Joins("JOIN service_accounts ON service_accounts.id = api_keys.service_account_id").
+		Joins("JOIN users ON users.id = service_accounts.user_id").
+		Where("api_keys.key_hash = ? AND api_keys.revoked_at IS NULL", keyHash).
+		Where("api_keys.expires_at IS NULL OR api_keys.expires_at > ?", time.Now()).
+		Where("service_accounts.disabled_at IS NULL AND users.is_active = ?", true).
+		Preload("ServiceAccount").
+		First(apiKey)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiKey, nil
+}
+
+func (r *PostgreSQLUserSessionRepository) TouchApiKey(id int64, at time.Time) error {
+	return r.db.Model(&entity.ApiKey{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
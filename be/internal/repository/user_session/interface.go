@@ -2,6 +2,7 @@ package repository
 
 import (
 	"BE_Manage_device/internal/domain/entity"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -13,4 +14,6 @@ type UsersSessionRepository interface {
 	CheckUserInSession(userId int64) bool
 	FindByUserIdInSession(UserId int64) (*entity.UsersSessions, error)
 	CheckTokenWasInVoked(accessToken string) bool
+	FindActiveApiKey(keyHash string) (*entity.ApiKey, error)
+	TouchApiKey(id int64, at time.Time) error
 }
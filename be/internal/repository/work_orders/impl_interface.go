@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostgreSQLWorkOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLWorkOrderRepository(db *gorm.DB) WorkOrderRepository {
+	return &PostgreSQLWorkOrderRepository{db: db}
+}
+
+func (r *PostgreSQLWorkOrderRepository) Create(workOrder *entity.WorkOrder, tx *gorm.DB) (*entity.WorkOrder, error) {
+	result := tx.Create(workOrder)
+	return workOrder, result.Error
+}
+
+func (r *PostgreSQLWorkOrderRepository) GetById(id int64) (*entity.WorkOrder, error) {
+	workOrder := &entity.WorkOrder{}
+	result := r.db.Model(entity.WorkOrder{}).Where("id = ?", id).
+		Preload("Schedule").Preload("Asset").Preload("Technician").
+		Preload("Tasks", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Parts").Preload("Parts.Consumable").Preload("Photos").
+		First(workOrder)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return workOrder, nil
+}
+
+func (r *PostgreSQLWorkOrderRepository) Filter(companyId int64, status *string, scheduleId, assetId, technicianUserId *int64) ([]*entity.WorkOrder, error) {
+	var workOrders []*entity.WorkOrder
+	db := r.db.Model(entity.WorkOrder{}).Where("company_id = ?", companyId)
+	if status != nil {
+		db = db.Where("status = ?", *status)
+	}
+	if scheduleId != nil {
+		db = db.Where("schedule_id = ?", *scheduleId)
+	}
+	if assetId != nil {
+		db = db.Where("asset_id = ?", *assetId)
+	}
+	if technicianUserId != nil {
+		db = db.Where("technician_user_id = ?", *technicianUserId)
+	}
+	result := db.Preload("Schedule").Preload("Asset").Preload("Technician").Order("id DESC").Find(&workOrders)
+	return workOrders, result.Error
+}
+
+func (r *PostgreSQLWorkOrderRepository) Update(workOrder *entity.WorkOrder, tx *gorm.DB) error {
+	result := tx.Model(entity.WorkOrder{}).Where("id = ?", workOrder.Id).Updates(map[string]interface{}{
+		"title":              workOrder.Title,
+		"description":        workOrder.Description,
+		"status":             workOrder.Status,
+		"technician_user_id": workOrder.TechnicianUserId,
+		"vendor_name":        workOrder.VendorName,
+		"labor_hours":        workOrder.LaborHours,
+		"labor_rate":         workOrder.LaborRate,
+		"parts_cost":         workOrder.PartsCost,
+		"total_cost":         workOrder.TotalCost,
+		"completion_notes":   workOrder.CompletionNotes,
+		"completed_at":       workOrder.CompletedAt,
+	})
+	return result.Error
+}
+
+func (r *PostgreSQLWorkOrderRepository) CreateTask(task *entity.WorkOrderTask) (*entity.WorkOrderTask, error) {
+	result := r.db.Create(task)
+	return task, result.Error
+}
+
+func (r *PostgreSQLWorkOrderRepository) GetTaskById(id int64) (*entity.WorkOrderTask, error) {
+	task := &entity.WorkOrderTask{}
+	result := r.db.Model(entity.WorkOrderTask{}).Where("id = ?", id).First(task)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return task, nil
+}
+
+func (r *PostgreSQLWorkOrderRepository) UpdateTask(task *entity.WorkOrderTask) error {
+	result := r.db.Model(entity.WorkOrderTask{}).Where("id = ?", task.Id).Updates(map[string]interface{}{
+		"is_done":    task.IsDone,
+		"done_by_id": task.DoneById,
+		"done_at":    task.DoneAt,
+	})
+	return result.Error
+}
+
+func (r *PostgreSQLWorkOrderRepository) CreatePart(part *entity.WorkOrderPart, tx *gorm.DB) (*entity.WorkOrderPart, error) {
+	result := tx.Create(part)
+	return part, result.Error
+}
+
+func (r *PostgreSQLWorkOrderRepository) GetPartById(id int64) (*entity.WorkOrderPart, error) {
+	part := &entity.WorkOrderPart{}
+	result := r.db.Model(entity.WorkOrderPart{}).Where("id = ?", id).First(part)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return part, nil
+}
+
+func (r *PostgreSQLWorkOrderRepository) DeletePart(id int64, tx *gorm.DB) error {
+	result := tx.Where("id = ?", id).Delete(&entity.WorkOrderPart{})
+	return result.Error
+}
+
+func (r *PostgreSQLWorkOrderRepository) CreatePhotos(photos []*entity.WorkOrderPhoto, tx *gorm.DB) error {
+	if len(photos) == 0 {
+		return nil
+	}
+	return tx.Create(&photos).Error
+}
+
+func (r *PostgreSQLWorkOrderRepository) SumCompletedCost(scheduleId int64, tx *gorm.DB) (float64, error) {
+	var total float64
+	result := tx.Model(entity.WorkOrder{}).
+		Where("schedule_id = ? AND status = ?", scheduleId, entity.WorkOrderCompleted).
+		Select("COALESCE(SUM(total_cost), 0)").Scan(&total)
+	return total, result.Error
+}
+
+// Số phiếu công việc chưa hoàn thành/hủy của tài sản
+func (r *PostgreSQLWorkOrderRepository) CountOpenByAsset(assetId int64, tx *gorm.DB) (int64, error) {
+	var count int64
+	result := tx.Model(entity.WorkOrder{}).
+		Where("asset_id = ? AND status IN ?", assetId, []string{entity.WorkOrderOpen, entity.WorkOrderInProgress}).
+		Count(&count)
+	return count, result.Error
+}
+
+func (r *PostgreSQLWorkOrderRepository) UpdateScheduleCompletion(scheduleId int64, cost float64, endDate *time.Time, tx *gorm.DB) error {
+	updates := map[string]interface{}{"cost": cost}
+	if endDate != nil {
+		updates["end_date"] = *endDate
+	}
+	return tx.Model(entity.MaintenanceSchedules{}).Where("id = ?", scheduleId).Updates(updates).Error
+}
+
+func (r *PostgreSQLWorkOrderRepository) GetDB() *gorm.DB {
+	return r.db
+}
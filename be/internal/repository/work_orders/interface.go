@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WorkOrderRepository interface {
+	Create(workOrder *entity.WorkOrder, tx *gorm.DB) (*entity.WorkOrder, error)
+	GetById(id int64) (*entity.WorkOrder, error)
+	Filter(companyId int64, status *string, scheduleId, assetId, technicianUserId *int64) ([]*entity.WorkOrder, error)
+	Update(workOrder *entity.WorkOrder, tx *gorm.DB) error
+	CreateTask(task *entity.WorkOrderTask) (*entity.WorkOrderTask, error)
+	GetTaskById(id int64) (*entity.WorkOrderTask, error)
+	UpdateTask(task *entity.WorkOrderTask) error
+	CreatePart(part *entity.WorkOrderPart, tx *gorm.DB) (*entity.WorkOrderPart, error)
+	GetPartById(id int64) (*entity.WorkOrderPart, error)
+	DeletePart(id int64, tx *gorm.DB) error
+	CreatePhotos(photos []*entity.WorkOrderPhoto, tx *gorm.DB) error
+	SumCompletedCost(scheduleId int64, tx *gorm.DB) (float64, error)
+	CountOpenByAsset(assetId int64, tx *gorm.DB) (int64, error)
+	UpdateScheduleCompletion(scheduleId int64, cost float64, endDate *time.Time, tx *gorm.DB) error
+	GetDB() *gorm.DB
+}
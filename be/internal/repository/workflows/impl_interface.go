@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgreSQLWorkflowRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgreSQLWorkflowRepository(db *gorm.DB) WorkflowRepository {
+	return &PostgreSQLWorkflowRepository{db: db}
+}
+
+func (r *PostgreSQLWorkflowRepository) CreateDefinition(definition *entity.WorkflowDefinition, tx *gorm.DB) (*entity.WorkflowDefinition, error) {
+	result := tx.Create(definition)
+	return definition, result.Error
+}
+
+func (r *PostgreSQLWorkflowRepository) GetDefinitionById(id int64) (*entity.WorkflowDefinition, error) {
+	definition := &entity.WorkflowDefinition{}
+	result := r.db.Model(entity.WorkflowDefinition{}).Where("id = ?", id).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("step_order, id") }).First(definition)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return definition, nil
+}
+
+func (r *PostgreSQLWorkflowRepository) GetDefinitions(companyId int64, entityType *string) ([]*entity.WorkflowDefinition, error) {
+	var definitions []*entity.WorkflowDefinition
+	db := r.db.Model(entity.WorkflowDefinition{}).Where("company_id = ?", companyId)
+	if entityType != nil {
+		db = db.Where("entity_type = ?", *entityType)
+	}
+	result := db.Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("step_order, id") }).Order("id").Find(&definitions)
+	return definitions, result.Error
+}
+
+// GetActiveDefinition trả về nil nếu công ty chưa cấu hình quy trình cho loại nghiệp vụ này
+func (r *PostgreSQLWorkflowRepository) GetActiveDefinition(companyId int64, entityType string) (*entity.WorkflowDefinition, error) {
+	var definitions []*entity.WorkflowDefinition
+	result := r.db.Model(entity.WorkflowDefinition{}).Where("company_id = ? AND entity_type = ? AND is_active = ?", companyId, entityType, true).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("step_order, id") }).Order("id DESC").Limit(1).Find(&definitions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(definitions) == 0 {
+		return nil, nil
+	}
+	return definitions[0], nil
+}
+
+func (r *PostgreSQLWorkflowRepository) UpdateDefinition(definition *entity.WorkflowDefinition, tx *gorm.DB) error {
+	return tx.Model(&entity.WorkflowDefinition{Id: definition.Id}).Select("Name", "EntityType", "IsActive").Updates(definition).Error
+}
+
+func (r *PostgreSQLWorkflowRepository) ReplaceSteps(definitionId int64, steps []entity.WorkflowStep, tx *gorm.DB) error {
+	if err := tx.Where("definition_id = ?", definitionId).Delete(&entity.WorkflowStep{}).Error; err != nil {
+		return err
+	}
+	if len(steps) == 0 {
+		return nil
+	}
+	for i := range steps {
+		steps[i].DefinitionId = definitionId
+	}
+	return tx.Create(&steps).Error
+}
+
+func (r *PostgreSQLWorkflowRepository) DeactivateOthers(companyId int64, entityType string, exceptId int64, tx *gorm.DB) error {
+	return tx.Model(entity.WorkflowDefinition{}).Where("company_id = ? AND entity_type = ? AND id <> ?", companyId, entityType, exceptId).
+		Update("is_active", false).Error
+}
+
+// Quy trình đã có yêu cầu chạy qua thì chỉ ngừng kích hoạt để giữ lịch sử
+func (r *PostgreSQLWorkflowRepository) DeleteDefinition(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(entity.WorkflowInstance{}).Where("definition_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return tx.Model(entity.WorkflowDefinition{}).Where("id = ?", id).Update("is_active", false).Error
+		}
+		if err := tx.Where("definition_id = ?", id).Delete(&entity.WorkflowStep{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.WorkflowDefinition{}, id).Error
+	})
+}
+
+func (r *PostgreSQLWorkflowRepository) CreateInstance(instance *entity.WorkflowInstance, tx *gorm.DB) (*entity.WorkflowInstance, error) {
+	result := tx.Omit("Definition", "Asset", "RequestedBy", "Actions").Create(instance)
+	return instance, result.Error
+}
+
+func (r *PostgreSQLWorkflowRepository) GetInstanceById(id int64) (*entity.WorkflowInstance, error) {
+	instance := &entity.WorkflowInstance{}
+	result := r.preloadInstance(r.db.Model(entity.WorkflowInstance{}).Where("id = ?", id)).First(instance)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	return instance, nil
+}
+
+// LockInstanceById giữ khoá dòng (SELECT ... FOR UPDATE) đến hết tx rồi đọc yêu cầu kèm các bước trong tx
+func (r *PostgreSQLWorkflowRepository) LockInstanceById(id int64, tx *gorm.DB) (*entity.WorkflowInstance, error) {
+	instance := &entity.WorkflowInstance{}
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(entity.WorkflowInstance{}).Where("id = ?", id).First(instance)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("can't find record this id")
+		}
+		return nil, result.Error
+	}
+	result = r.preloadInstance(tx.Model(entity.WorkflowInstance{}).Where("id = ?", id)).First(instance)
+	return instance, result.Error
+}
+
+func (r *PostgreSQLWorkflowRepository) GetPendingInstanceOfEntity(entityType string, entityId int64) (*entity.WorkflowInstance, error) {
+	var instances []*entity.WorkflowInstance
+	result := r.db.Model(entity.WorkflowInstance{}).Where("entity_type = ? AND entity_id = ? AND status = ?", entityType, entityId, entity.WorkflowPending).
+		Limit(1).Find(&instances)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(instances) == 0 {
+		return nil, nil
+	}
+	return r.GetInstanceById(instances[0].Id)
+}
+
+func (r *PostgreSQLWorkflowRepository) FilterInstances(companyId int64, status *string, entityType *string) ([]*entity.WorkflowInstance, error) {
+	var instances []*entity.WorkflowInstance
+	db := r.db.Model(entity.WorkflowInstance{}).Where("company_id = ?", companyId)
+	if status != nil {
+		db = db.Where("status = ?", *status)
+	}
+	if entityType != nil {
+		db = db.Where("entity_type = ?", *entityType)
+	}
+	result := r.preloadInstance(db).Order("created_at DESC, id DESC").Find(&instances)
+	return instances, result.Error
+}
+
+func (r *PostgreSQLWorkflowRepository) UpdateInstance(instance *entity.WorkflowInstance, tx *gorm.DB) error {
+	return tx.Model(&entity.WorkflowInstance{Id: instance.Id}).Select("Status", "CurrentStepOrder", "RejectReason", "FinishedAt").Updates(instance).Error
+}
+
+func (r *PostgreSQLWorkflowRepository) UpdateInstanceStep(step *entity.WorkflowInstanceStep, tx *gorm.DB) error {
+	return tx.Model(&entity.WorkflowInstanceStep{Id: step.Id}).Select("Status", "ActedById", "ActedAt", "Comment").Updates(step).Error
+}
+
+func (r *PostgreSQLWorkflowRepository) SkipPendingSteps(instanceId int64, tx *gorm.DB) error {
+	return tx.Model(entity.WorkflowInstanceStep{}).Where("instance_id = ? AND status = ?", instanceId, entity.WorkflowPending).
+		Update("status", entity.WorkflowSkipped).Error
+}
+
+func (r *PostgreSQLWorkflowRepository) CreateAction(action *entity.WorkflowAction, tx *gorm.DB) error {
+	return tx.Omit("ByUser").Create(action).Error
+}
+
+func (r *PostgreSQLWorkflowRepository) GetUsersByRole(companyId int64, roleSlug string) ([]*entity.Users, error) {
+	var users []*entity.Users
+	result := r.db.Model(entity.Users{}).Joins("join roles on roles.id = users.role_id").
+		Where("users.company_id = ? AND roles.slug = ? AND users.is_active = ?", companyId, roleSlug, true).Find(&users)
+	return users, result.Error
+}
+
+func (r *PostgreSQLWorkflowRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *PostgreSQLWorkflowRepository) preloadInstance(db *gorm.DB) *gorm.DB {
+	return db.Preload("Definition").Preload("Asset").Preload("RequestedBy").
+		Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("step_order, id") }).Preload("Steps.ActedBy").
+		Preload("Actions", func(db *gorm.DB) *gorm.DB { return db.Order("created_at, id") }).Preload("Actions.ByUser")
+}
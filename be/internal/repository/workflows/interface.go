@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+type WorkflowRepository interface {
+	CreateDefinition(definition *entity.WorkflowDefinition, tx *gorm.DB) (*entity.WorkflowDefinition, error)
+	GetDefinitionById(id int64) (*entity.WorkflowDefinition, error)
+	GetDefinitions(companyId int64, entityType *string) ([]*entity.WorkflowDefinition, error)
+	GetActiveDefinition(companyId int64, entityType string) (*entity.WorkflowDefinition, error)
+	UpdateDefinition(definition *entity.WorkflowDefinition, tx *gorm.DB) error
+	ReplaceSteps(definitionId int64, steps []entity.WorkflowStep, tx *gorm.DB) error
+	DeactivateOthers(companyId int64, entityType string, exceptId int64, tx *gorm.DB) error
+	DeleteDefinition(id int64) error
+	CreateInstance(instance *entity.WorkflowInstance, tx *gorm.DB) (*entity.WorkflowInstance, error)
+	GetInstanceById(id int64) (*entity.WorkflowInstance, error)
+	LockInstanceById(id int64, tx *gorm.DB) (*entity.WorkflowInstance, error)
+	GetPendingInstanceOfEntity(entityType string, entityId int64) (*entity.WorkflowInstance, error)
+	FilterInstances(companyId int64, status *string, entityType *string) ([]*entity.WorkflowInstance, error)
+	UpdateInstance(instance *entity.WorkflowInstance, tx *gorm.DB) error
+	UpdateInstanceStep(step *entity.WorkflowInstanceStep, tx *gorm.DB) error
+	SkipPendingSteps(instanceId int64, tx *gorm.DB) error
+	CreateAction(action *entity.WorkflowAction, tx *gorm.DB) error
+	GetUsersByRole(companyId int64, roleSlug string) ([]*entity.Users, error)
+	GetDB() *gorm.DB
+}
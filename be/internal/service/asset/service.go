@@ -5,14 +5,17 @@ import (
 	"BE_Manage_device/internal/domain/entity"
 	"BE_Manage_device/internal/domain/filter"
 	asset_log "BE_Manage_device/internal/repository/asset_log"
+	assetRelation "BE_Manage_device/internal/repository/asset_relation"
 	asset "BE_Manage_device/internal/repository/assets"
 	assignment "BE_Manage_device/internal/repository/assignments"
 	company "BE_Manage_device/internal/repository/company"
 	department "BE_Manage_device/internal/repository/departments"
+	location "BE_Manage_device/internal/repository/locations"
 	role "BE_Manage_device/internal/repository/role"
 	user "BE_Manage_device/internal/repository/user"
 	userRBAC "BE_Manage_device/internal/repository/user_rbac"
 	notificationS "BE_Manage_device/internal/service/notification"
+	workflowS "BE_Manage_device/internal/service/workflows"
 	"BE_Manage_device/pkg/utils"
 
 	"context"
@@ -24,6 +27,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type AssetsService struct {
@@ -36,10 +41,13 @@ type AssetsService struct {
 	departmentRepository department.DepartmentsRepository
 	NotificationService  *notificationS.NotificationService
 	companyRepo          company.CompanyRepository
+	locationRepository   location.LocationRepository
+	relationRepository   assetRelation.AssetRelationRepository
+	workflowService      *workflowS.WorkflowService
 }
 
-func NewAssetsService(repo asset.AssetsRepository, assertLogRepository asset_log.AssetsLogRepository, roleRepository role.RoleRepository, userRBACRepository userRBAC.UserRBACRepository, userRepository user.UserRepository, assignRepository assignment.AssignmentRepository, departmentRepository department.DepartmentsRepository, NotificationService *notificationS.NotificationService, companyRepo company.CompanyRepository) *AssetsService {
-	return &AssetsService{repo: repo, assertLogRepository: assertLogRepository, roleRepository: roleRepository, userRBACRepository: userRBACRepository, userRepository: userRepository, assignRepository: assignRepository, departmentRepository: departmentRepository, NotificationService: NotificationService, companyRepo: companyRepo}
+func NewAssetsService(repo asset.AssetsRepository, assertLogRepository asset_log.AssetsLogRepository, roleRepository role.RoleRepository, userRBACRepository userRBAC.UserRBACRepository, userRepository user.UserRepository, assignRepository assignment.AssignmentRepository, departmentRepository department.DepartmentsRepository, NotificationService *notificationS.NotificationService, companyRepo company.CompanyRepository, locationRepository location.LocationRepository, relationRepository assetRelation.AssetRelationRepository, workflowService *workflowS.WorkflowService) *AssetsService {
+	return &AssetsService{repo: repo, assertLogRepository: assertLogRepository, roleRepository: roleRepository, userRBACRepository: userRBACRepository, userRepository: userRepository, assignRepository: assignRepository, departmentRepository: departmentRepository, NotificationService: NotificationService, companyRepo: companyRepo, locationRepository: locationRepository, relationRepository: relationRepository, workflowService: workflowService}
 }
 
 func (service *AssetsService) Create(userId int64, assetName string, purchaseDate time.Time, warrantExpiry time.Time, serialNumber string, image *multipart.FileHeader, fileAttachment *multipart.FileHeader, categoryId int64, departmentId int64, url string, cost float64) (*entity.Assets, error) {
@@ -158,13 +166,14 @@ func (service *AssetsService) Create(userId int64, assetName string, purchaseDat
 	}
 	changeSummary := "Create asset"
 	assetLog := entity.AssetLog{
-		Action:        "Create",
-		Timestamp:     time.Now(),
-		ByUserId:      &userId,
-		AssignUserId:  &userAssetManager.Id,
-		ChangeSummary: changeSummary,
-		AssetId:       assetCreate.Id,
-		CompanyId:     company.Id,
+		Action:         "Create",
+		Timestamp:      time.Now(),
+		ByUserId:       &userId,
+		AssignUserId:   &userAssetManager.Id,
+		ChangeSummary:  changeSummary,
+		AssetId:        assetCreate.Id,
+		CompanyId:      company.Id,
+		ToDepartmentId: &assetCreate.DepartmentId,
 	}
 	_, err = service.assertLogRepository.Create(&assetLog, tx)
 	if err != nil {
@@ -456,19 +465,48 @@ func (service *AssetsService) DeleteAsset(userId int64, id int64) error {
 	return nil
 }
 
-func (service *AssetsService) UpdateAssetRetired(userId int64, id int64, ResidualValue float64) (*entity.Assets, error) {
-	var err error
-	userUpdate, err := service.userRepository.FindByUserId(userId)
-	if err != nil {
-		return nil, err
-	}
+// retirementPayload tham số thanh lý lưu lại khi gửi duyệt
+type retirementPayload struct {
+	ResidualValue float64 `json:"residualValue"`
+}
+
+// UpdateAssetRetired công ty có cấu hình quy trình duyệt thì chỉ gửi duyệt và trả về yêu cầu duyệt,
+// tài sản được thanh lý khi quy trình được duyệt xong
+func (service *AssetsService) UpdateAssetRetired(userId int64, id int64, ResidualValue float64) (*entity.Assets, *entity.WorkflowInstance, error) {
 	assetCheck, err := service.repo.GetAssetById(id)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if assetCheck.Status == "Disposed" {
-		return nil, errors.New("can't retired asset")
+		return nil, nil, errors.New("can't retired asset")
+	}
+	instance, err := service.workflowService.Start(userId, assetCheck.CompanyId, entity.WorkflowEntityAssetRetirement, assetCheck.Id, assetCheck.Id, assetCheck.DepartmentId, assetCheck.Cost, retirementPayload{ResidualValue: ResidualValue}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if instance != nil {
+		instance, err = service.workflowService.Submitted(instance)
+		return nil, instance, err
+	}
+	asset, err := service.retire(userId, id, ResidualValue)
+	return asset, nil, err
+}
+
+func (service *AssetsService) OnWorkflowApproved(instance *entity.WorkflowInstance, approverId int64, tx *gorm.DB) (func(), error) {
+	var payload retirementPayload
+	if err := workflowS.DecodePayload(instance, &payload); err != nil {
+		return nil, err
 	}
+	_, notify, err := service.retireInTx(approverId, instance.AssetId, payload.ResidualValue, tx)
+	return notify, err
+}
+
+func (service *AssetsService) OnWorkflowRejected(instance *entity.WorkflowInstance, approverId int64, tx *gorm.DB) (func(), error) {
+	return nil, nil
+}
+
+func (service *AssetsService) retire(userId int64, id int64, ResidualValue float64) (*entity.Assets, error) {
+	var err error
 	tx := service.repo.GetDB().Begin()
 	defer func() {
 		if r := recover(); r != nil {
@@ -478,10 +516,34 @@ func (service *AssetsService) UpdateAssetRetired(userId int64, id int64, Residua
 			tx.Rollback()
 		}
 	}()
-	asset, err := service.repo.UpdateAssetLifeCycleStage(id, "Retired", tx)
+	asset, notify, err := service.retireInTx(userId, id, ResidualValue, tx)
 	if err != nil {
 		return nil, err
 	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	notify()
+	return asset, nil
+}
+
+// retireInTx thanh lý tài sản trong tx của nghiệp vụ khác, trả về hàm gửi thông báo để gọi sau khi tx commit
+func (service *AssetsService) retireInTx(userId int64, id int64, ResidualValue float64, tx *gorm.DB) (*entity.Assets, func(), error) {
+	userUpdate, err := service.userRepository.FindByUserId(userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	assetCheck, err := service.repo.GetAssetById(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if assetCheck.Status == "Disposed" {
+		return nil, nil, errors.New("can't retired asset")
+	}
+	asset, err := service.repo.UpdateAssetLifeCycleStage(id, "Retired", tx)
+	if err != nil {
+		return nil, nil, err
+	}
 	now := time.Now()
 	asset.AcquisitionDate = &now
 	asset.ResidualValue = &ResidualValue
@@ -491,14 +553,14 @@ func (service *AssetsService) UpdateAssetRetired(userId int64, id int64, Residua
 	}
 	if yearsUsed <= 0 {
 		fmt.Println("Asset not in use for a full year.")
-		return nil, errors.New("a")
+		return nil, nil, errors.New("a")
 	}
 	// Tính khấu hao hàng năm
 	annualDepreciation := (asset.Cost - *asset.ResidualValue) / float64(yearsUsed)
 	asset.AnnualDepreciation = &annualDepreciation
 	asset, err = service.repo.UpdateAsset(asset, tx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	changeSummary := "Retired asset"
 	assetLog := entity.AssetLog{
@@ -511,32 +573,30 @@ func (service *AssetsService) UpdateAssetRetired(userId int64, id int64, Residua
 	}
 	_, err = service.assertLogRepository.Create(&assetLog, tx)
 	if err != nil {
-		return nil, err
-	}
-	if err = tx.Commit().Error; err != nil {
-		return nil, fmt.Errorf("commit failed: %w", err)
+		return nil, nil, err
 	}
-	userHeadDepart, _ := service.userRepository.GetUserHeadDepartment(asset.DepartmentId)
-	userManagerAsset, _ := service.userRepository.GetUserAssetManageOfDepartment(asset.DepartmentId)
-	usersToNotifications := []*entity.Users{}
-	usersToNotifications = append(usersToNotifications, asset.OnwerUser)
-	usersToNotifications = append(usersToNotifications, userHeadDepart)
-	usersToNotifications = append(usersToNotifications, userManagerAsset)
-	message := fmt.Sprintf("The asset '%v' (ID: %v) has just been updated by %v", asset.AssetName, asset.Id, userUpdate.Email)
-	userNotificationUnique := utils.ConvertUsersToNotificationsToMap(userId, usersToNotifications)
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				fmt.Println("SendNotificationToUsers panic:", r)
-			}
+	notify := func() {
+		userHeadDepart, _ := service.userRepository.GetUserHeadDepartment(asset.DepartmentId)
+		userManagerAsset, _ := service.userRepository.GetUserAssetManageOfDepartment(asset.DepartmentId)
+		usersToNotifications := []*entity.Users{}
+		usersToNotifications = append(usersToNotifications, asset.OnwerUser)
+		usersToNotifications = append(usersToNotifications, userHeadDepart)
+		usersToNotifications = append(usersToNotifications, userManagerAsset)
+		message := fmt.Sprintf("The asset '%v' (ID: %v) has just been updated by %v", asset.AssetName, asset.Id, userUpdate.Email)
+		userNotificationUnique := utils.ConvertUsersToNotificationsToMap(userId, usersToNotifications)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Println("SendNotificationToUsers panic:", r)
+				}
+			}()
+			service.NotificationService.SendNotificationToUsers(userNotificationUnique, message, *asset)
 		}()
-		service.NotificationService.SendNotificationToUsers(userNotificationUnique, message, *asset)
-	}()
-	return asset, nil
-
+	}
+	return asset, notify, nil
 }
 
-func (service *AssetsService) Filter(userId int64, assetName *string, status *string, categoryId *string, cost *string, serialNumber *string, email *string, departmentId *string) ([]dto.AssetResponse, error) {
+func (service *AssetsService) Filter(userId int64, assetName *string, status *string, categoryId *string, cost *string, serialNumber *string, email *string, departmentId *string, locationId *string, tags *string, tagMode *string) ([]dto.AssetResponse, error) {
 	var filter = filter.AssetFilter{
 		AssetName:    assetName,
 		CategoryId:   categoryId,
@@ -545,6 +605,9 @@ func (service *AssetsService) Filter(userId int64, assetName *string, status *st
 		Email:        email,
 		DepartmentId: departmentId,
 		Status:       status,
+		LocationId:   locationId,
+		Tags:         tags,
+		TagMode:      tagMode,
 	}
 	user, err := service.userRepository.FindByUserId(userId)
 	if err != nil {
@@ -595,6 +658,8 @@ func (service *AssetsService) Filter(userId int64, assetName *string, status *st
 					LocationName: asset.Department.Location.LocationName,
 				},
 			},
+			Room: utils.ConvertLocationToResponse(asset.Room),
+			Tags: utils.ConvertTagsToResponses(asset.Tags),
 		}
 		if asset.OnwerUser != nil {
 			assetResponse.Owner = dto.OwnerResponse{
@@ -609,7 +674,7 @@ func (service *AssetsService) Filter(userId int64, assetName *string, status *st
 	return assetsResponse, nil
 }
 
-func (service *AssetsService) ApplyFilterDashBoard(userId int64, status *string, categoryId *string, departmentId *string, export *string) (*dto.DashboardSummary, []*entity.Assets, error) {
+func (service *AssetsService) ApplyFilterDashBoard(userId int64, status *string, categoryId *string, departmentId *string, locationId *string, tags *string, tagMode *string, export *string) (*dto.DashboardSummary, []*entity.Assets, error) {
 	user, err := service.userRepository.FindByUserId(userId)
 	if err != nil {
 		return nil, nil, err
@@ -630,6 +695,10 @@ func (service *AssetsService) ApplyFilterDashBoard(userId int64, status *string,
 		CategoryId:   categoryId,
 		DepartmentId: departmentId,
 		Status:       status,
+		LocationId:   locationId,
+		Tags:         tags,
+		TagMode:      tagMode,
+		CompanyId:    user.CompanyId,
 	}
 	db := service.repo.GetDB()
 	dbFilter := filter.ApplyFilterDashBoard(db.Model(&entity.Assets{}), userId)
@@ -645,6 +714,8 @@ func (service *AssetsService) ApplyFilterDashBoard(userId int64, status *string,
 func CountDashboard(assets []*entity.Assets) dto.DashboardSummary {
 	var s dto.DashboardSummary
 	s.TotalAssets = len(assets)
+	tagCounts := map[int64]*dto.TagCount{}
+	s.Tags = []*dto.TagCount{}
 	for _, a := range assets {
 		switch a.Status {
 		case "In Use":
@@ -654,6 +725,15 @@ func CountDashboard(assets []*entity.Assets) dto.DashboardSummary {
 		case "Retired":
 			s.Retired++
 		}
+		for _, tag := range a.Tags {
+			count, ok := tagCounts[tag.Id]
+			if !ok {
+				count = &dto.TagCount{Id: tag.Id, TagName: tag.TagName}
+				tagCounts[tag.Id] = count
+				s.Tags = append(s.Tags, count)
+			}
+			count.Total++
+		}
 	}
 	return s
 }
@@ -709,3 +789,260 @@ func (service *AssetsService) GetAllAssetNotHaveMaintenance(userId int64) ([]*en
 	}
 	return assets, nil
 }
+
+func (service *AssetsService) Clone(userId int64, assetId int64, serialNumbers []string, url string) ([]*entity.Assets, error) {
+	user, err := service.userRepository.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	source, err := service.repo.GetAssetById(assetId)
+	if err != nil {
+		return nil, err
+	}
+	if source.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	if source.Status == "Retired" || source.Status == "Disposed" {
+		return nil, errors.New("can't clone a retired or disposed asset")
+	}
+	base := entity.Assets{
+		AssetName:          source.AssetName,
+		PurchaseDate:       source.PurchaseDate,
+		Cost:               source.Cost,
+		WarrantExpiry:      source.WarrantExpiry,
+		CategoryId:         source.CategoryId,
+		DepartmentId:       source.DepartmentId,
+		AnnualDepreciation: source.AnnualDepreciation,
+		ResidualValue:      source.ResidualValue,
+		UsefulLife:         source.UsefulLife,
+		ImageUpload:        source.ImageUpload,
+		FileAttachment:     source.FileAttachment,
+	}
+	changeSummary := fmt.Sprintf("Create asset by cloning asset ID %v", source.Id)
+	return service.CreateCopies(userId, base, serialNumbers, url, changeSummary)
+}
+
+// CreateCopies tạo nhiều tài sản giống nhau (mỗi serial một bản) trong cùng một transaction
+func (service *AssetsService) CreateCopies(userId int64, base entity.Assets, serialNumbers []string, url string, changeSummary string) ([]*entity.Assets, error) {
+	if len(serialNumbers) == 0 {
+		return nil, errors.New("serial numbers is required")
+	}
+	seen := map[string]bool{}
+	for i, serial := range serialNumbers {
+		serial = strings.TrimSpace(serial)
+		if serial == "" {
+			return nil, errors.New("serial number can't be empty")
+		}
+		if seen[serial] {
+			return nil, fmt.Errorf("serial number %v is duplicated", serial)
+		}
+		seen[serial] = true
+		serialNumbers[i] = serial
+	}
+	user, err := service.userRepository.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	company, err := service.companyRepo.GetCompanyBySuffixEmail(utils.GetSuffixEmail(user.Email))
+	if err != nil {
+		return nil, err
+	}
+	existed, err := service.repo.GetAssetsBySerialNumbers(company.Id, serialNumbers)
+	if err != nil {
+		return nil, err
+	}
+	if len(existed) > 0 {
+		return nil, fmt.Errorf("serial number %v already exists", existed[0].SerialNumber)
+	}
+	departmentCheck, err := service.departmentRepository.GetDepartmentById(base.DepartmentId)
+	if err != nil {
+		return nil, err
+	}
+	if departmentCheck.CompanyId != company.Id {
+		return nil, errors.New("department not found")
+	}
+	userAssetManager, err := service.userRepository.GetUserAssetManageOfDepartment(base.DepartmentId)
+	if err != nil {
+		return nil, err
+	}
+	// Mỗi bản sao có file riêng để việc cập nhật/xoá file của bản này không ảnh hưởng bản khác
+	images := make([]string, len(serialNumbers))
+	files := make([]string, len(serialNumbers))
+	copied := []string{}
+	uploader := utils.NewSupabaseUploader()
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			uploader.DeleteUrls(copied...)
+			panic(r)
+		} else if err != nil {
+			// Tạo lỗi thì xoá các file đã copy để không để lại file mồ côi
+			tx.Rollback()
+			uploader.DeleteUrls(copied...)
+		}
+	}()
+	for i := range serialNumbers {
+		images[i], err = copyStorageObject(uploader, base.ImageUpload, "images/")
+		if err != nil {
+			return nil, err
+		}
+		copied = append(copied, images[i])
+		files[i], err = copyStorageObject(uploader, base.FileAttachment, "files/")
+		if err != nil {
+			return nil, err
+		}
+		copied = append(copied, files[i])
+	}
+	assetsCreate := []*entity.Assets{}
+	for i, serial := range serialNumbers {
+		asset := base
+		asset.Id = 0
+		asset.Status = "New"
+		asset.SerialNumber = serial
+		asset.ImageUpload = &images[i]
+		asset.FileAttachment = &files[i]
+		asset.Owner = &userAssetManager.Id
+		asset.CompanyId = company.Id
+		asset.QrUrl = nil
+		asset.AcquisitionDate = nil
+		asset.RetiredOrDisposeTime = nil
+		var assetCreate *entity.Assets
+		assetCreate, err = service.repo.Create(&asset, tx)
+		if err != nil {
+			return nil, err
+		}
+		assetLog := entity.AssetLog{
+			Action:         "Create",
+			Timestamp:      time.Now(),
+			ByUserId:       &userId,
+			AssignUserId:   &userAssetManager.Id,
+			ChangeSummary:  changeSummary,
+			AssetId:        assetCreate.Id,
+			CompanyId:      company.Id,
+			ToDepartmentId: &assetCreate.DepartmentId,
+		}
+		_, err = service.assertLogRepository.Create(&assetLog, tx)
+		if err != nil {
+			return nil, err
+		}
+		departmentId := base.DepartmentId
+		assign := entity.Assignments{
+			AssetId:      assetCreate.Id,
+			UserId:       &userAssetManager.Id,
+			AssignBy:     userId,
+			DepartmentId: &departmentId,
+			CompanyId:    company.Id,
+		}
+		_, err = service.assignRepository.Create(&assign, tx)
+		if err != nil {
+			return nil, err
+		}
+		assetsCreate = append(assetsCreate, assetCreate)
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	for _, asset := range assetsCreate {
+		go service.SetRole(asset.Id)
+		go utils.GenQrAndUpdate(service.repo, asset.Id, url)
+	}
+	return assetsCreate, nil
+}
+
+func copyStorageObject(uploader *utils.SupabaseUploader, url *string, folder string) (string, error) {
+	if url == nil || *url == "" {
+		return "", nil
+	}
+	path, ok := utils.ExtractFilePath(*url)
+	if !ok {
+		return *url, nil
+	}
+	sourcePath := strings.TrimPrefix(path, uploader.Bucket+"/")
+	fileName := sourcePath[strings.LastIndex(sourcePath, "/")+1:]
+	if idx := strings.Index(fileName, "_"); idx != -1 {
+		fileName = fileName[idx+1:]
+	}
+	destinationPath := folder + fmt.Sprintf("%d_%s", time.Now().UnixNano(), fileName)
+	return uploader.Copy(sourcePath, destinationPath)
+}
+
+func (service *AssetsService) MoveRoom(userId int64, assetId int64, roomId int64) (*entity.Assets, error) {
+	var err error
+	user, err := service.userRepository.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := service.repo.GetAssetById(assetId)
+	if err != nil {
+		return nil, err
+	}
+	if asset.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find asset")
+	}
+	room, err := service.locationRepository.GetById(roomId)
+	if err != nil {
+		return nil, err
+	}
+	if room.CompanyId != user.CompanyId || room.LocationType != entity.LocationTypeRoom {
+		return nil, errors.New("location must be a room of your company")
+	}
+	if asset.RoomId != nil && *asset.RoomId == roomId {
+		return nil, errors.New("asset is already in this room")
+	}
+	changeSummary := fmt.Sprintf("Move asset to room '%v'", room.LocationName)
+	if asset.Room != nil {
+		changeSummary = fmt.Sprintf("Move asset from room '%v' to room '%v'", asset.Room.LocationName, room.LocationName)
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	err = service.repo.UpdateRoom(assetId, roomId, tx)
+	if err != nil {
+		return nil, err
+	}
+	assetLog := entity.AssetLog{
+		Action:        "Move",
+		Timestamp:     time.Now(),
+		ByUserId:      &userId,
+		ChangeSummary: changeSummary,
+		AssetId:       assetId,
+		CompanyId:     user.CompanyId,
+	}
+	_, err = service.assertLogRepository.Create(&assetLog, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return service.repo.GetAssetById(assetId)
+}
+
+// GetDependentAssets trả về các tài sản đang phụ thuộc hoặc được lắp trong assetId (bỏ qua tài sản đã ngừng sử dụng)
+func (service *AssetsService) GetDependentAssets(assetId int64) ([]dto.DependentAssetResponse, error) {
+	relations, err := service.relationRepository.GetDependents(assetId)
+	if err != nil {
+		return nil, err
+	}
+	dependents := []dto.DependentAssetResponse{}
+	for _, relation := range relations {
+		if relation.FromAsset.Status == "Retired" || relation.FromAsset.Status == "Disposed" {
+			continue
+		}
+		dependents = append(dependents, dto.DependentAssetResponse{
+			Id:           relation.FromAsset.Id,
+			AssetName:    relation.FromAsset.AssetName,
+			SerialNumber: relation.FromAsset.SerialNumber,
+			Status:       relation.FromAsset.Status,
+			RelationType: relation.RelationType,
+		})
+	}
+	return dependents, nil
+}
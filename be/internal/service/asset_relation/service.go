@@ -0,0 +1,244 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	asset_log "BE_Manage_device/internal/repository/asset_log"
+	assetRelation "BE_Manage_device/internal/repository/asset_relation"
+	asset "BE_Manage_device/internal/repository/assets"
+	user "BE_Manage_device/internal/repository/user"
+	"BE_Manage_device/pkg/utils"
+
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultGraphDepth = 1
+	maxGraphDepth     = 5
+)
+
+type AssetRelationService struct {
+	repo         assetRelation.AssetRelationRepository
+	assetRepo    asset.AssetsRepository
+	userRepo     user.UserRepository
+	assetLogRepo asset_log.AssetsLogRepository
+}
+
+func NewAssetRelationService(repo assetRelation.AssetRelationRepository, assetRepo asset.AssetsRepository, userRepo user.UserRepository, assetLogRepo asset_log.AssetsLogRepository) *AssetRelationService {
+	return &AssetRelationService{repo: repo, assetRepo: assetRepo, userRepo: userRepo, assetLogRepo: assetLogRepo}
+}
+
+func (service *AssetRelationService) Create(userId int64, fromAssetId int64, toAssetId int64, relationType string) (*entity.AssetRelation, error) {
+	var err error
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if fromAssetId == toAssetId {
+		return nil, errors.New("an asset can't be related to itself")
+	}
+	fromAsset, err := service.assetRepo.GetAssetById(fromAssetId)
+	if err != nil {
+		return nil, err
+	}
+	toAsset, err := service.assetRepo.GetAssetById(toAssetId)
+	if err != nil {
+		return nil, err
+	}
+	if fromAsset.CompanyId != user.CompanyId || toAsset.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find asset")
+	}
+	if toAsset.Status == "Disposed" || fromAsset.Status == "Disposed" {
+		return nil, errors.New("can't link a disposed asset")
+	}
+	switch relationType {
+	case entity.RelationDependsOn, entity.RelationInstalledIn:
+		// Hai loại quan hệ này không được tạo vòng (A phụ thuộc B, B phụ thuộc A)
+		reachable, err := service.repo.IsReachable(toAssetId, fromAssetId, relationType)
+		if err != nil {
+			return nil, err
+		}
+		if reachable {
+			return nil, fmt.Errorf("relation '%v' would create a cycle", relationType)
+		}
+		if relationType == entity.RelationInstalledIn {
+			installed, err := service.repo.GetByFromAsset(fromAssetId, entity.RelationInstalledIn)
+			if err != nil {
+				return nil, err
+			}
+			if len(installed) > 0 {
+				return nil, fmt.Errorf("asset is already installed in asset ID %v", installed[0].ToAssetId)
+			}
+		}
+	case entity.RelationConnectedTo:
+	default:
+		return nil, errors.New("relation type must be one of depends-on, connected-to, installed-in")
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	relation := &entity.AssetRelation{
+		FromAssetId:  fromAssetId,
+		ToAssetId:    toAssetId,
+		RelationType: relationType,
+		CreatedById:  userId,
+		CreatedAt:    time.Now(),
+		CompanyId:    user.CompanyId,
+	}
+	relation, err = service.repo.Create(relation, tx)
+	if err != nil {
+		if strings.Contains(err.Error(), "idx_asset_relation") {
+			return nil, errors.New("this relation already exists")
+		}
+		return nil, err
+	}
+	_, err = service.assetLogRepo.Create(&entity.AssetLog{
+		Action:        "Update",
+		Timestamp:     time.Now(),
+		ByUserId:      &userId,
+		ChangeSummary: fmt.Sprintf("Add relation '%v' to asset '%v' (ID: %v)", relationType, toAsset.AssetName, toAsset.Id),
+		AssetId:       fromAssetId,
+		CompanyId:     user.CompanyId,
+	}, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return relation, nil
+}
+
+func (service *AssetRelationService) Delete(userId int64, id int64) error {
+	var err error
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return err
+	}
+	relation, err := service.repo.GetById(id)
+	if err != nil {
+		return err
+	}
+	if relation.CompanyId != user.CompanyId {
+		return errors.New("can't find record this id")
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	err = service.repo.Delete(id, tx)
+	if err != nil {
+		return err
+	}
+	_, err = service.assetLogRepo.Create(&entity.AssetLog{
+		Action:        "Update",
+		Timestamp:     time.Now(),
+		ByUserId:      &userId,
+		ChangeSummary: fmt.Sprintf("Remove relation '%v' to asset ID %v", relation.RelationType, relation.ToAssetId),
+		AssetId:       relation.FromAssetId,
+		CompanyId:     user.CompanyId,
+	}, tx)
+	if err != nil {
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+	return nil
+}
+
+// GetGraph trả về các tài sản liên quan tới assetId trong phạm vi depth bước (theo cả hai chiều)
+func (service *AssetRelationService) GetGraph(userId int64, assetId int64, depth int) (*dto.AssetGraphResponse, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	root, err := service.assetRepo.GetAssetById(assetId)
+	if err != nil {
+		return nil, err
+	}
+	if root.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find asset")
+	}
+	if depth <= 0 {
+		depth = defaultGraphDepth
+	}
+	if depth > maxGraphDepth {
+		depth = maxGraphDepth
+	}
+	depths := map[int64]int{assetId: 0}
+	edges := map[int64]*entity.AssetRelation{}
+	frontier := []int64{assetId}
+	for level := 1; level <= depth && len(frontier) > 0; level++ {
+		relations, err := service.repo.GetByAssetIds(frontier)
+		if err != nil {
+			return nil, err
+		}
+		next := []int64{}
+		for _, relation := range relations {
+			edges[relation.Id] = relation
+			for _, id := range []int64{relation.FromAssetId, relation.ToAssetId} {
+				if _, ok := depths[id]; !ok {
+					depths[id] = level
+					next = append(next, id)
+				}
+			}
+		}
+		frontier = next
+	}
+	ids := make([]int64, 0, len(depths))
+	for id := range depths {
+		ids = append(ids, id)
+	}
+	assets, err := service.assetRepo.GetAssetsByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	graph := &dto.AssetGraphResponse{
+		RootId: assetId,
+		Depth:  depth,
+		Nodes:  []dto.AssetGraphNode{},
+		Edges:  []dto.AssetRelationResponse{},
+	}
+	for _, a := range assets {
+		graph.Nodes = append(graph.Nodes, dto.AssetGraphNode{
+			Id:           a.Id,
+			AssetName:    a.AssetName,
+			SerialNumber: a.SerialNumber,
+			Status:       a.Status,
+			CategoryName: a.Category.CategoryName,
+			Depth:        depths[a.Id],
+		})
+	}
+	relationIds := make([]int64, 0, len(edges))
+	for id := range edges {
+		relationIds = append(relationIds, id)
+	}
+	sort.Slice(relationIds, func(i, j int) bool { return relationIds[i] < relationIds[j] })
+	for _, id := range relationIds {
+		relation := edges[id]
+		// Chỉ trả về cạnh mà cả hai đầu đều nằm trong đồ thị
+		_, fromOk := depths[relation.FromAssetId]
+		_, toOk := depths[relation.ToAssetId]
+		if !fromOk || !toOk {
+			continue
+		}
+		graph.Edges = append(graph.Edges, utils.ConvertAssetRelationToResponse(relation))
+	}
+	return graph, nil
+}
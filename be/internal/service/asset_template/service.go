@@ -0,0 +1,164 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	assetTemplate "BE_Manage_device/internal/repository/asset_template"
+	categories "BE_Manage_device/internal/repository/categories"
+	company "BE_Manage_device/internal/repository/company"
+	user "BE_Manage_device/internal/repository/user"
+	assetS "BE_Manage_device/internal/service/asset"
+	"BE_Manage_device/pkg/utils"
+
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type AssetTemplateService struct {
+	repo          assetTemplate.AssetTemplateRepository
+	userRepo      user.UserRepository
+	companyRepo   company.CompanyRepository
+	categoryRepo  categories.CategoriesRepository
+	assetsService *assetS.AssetsService
+}
+
+func NewAssetTemplateService(repo assetTemplate.AssetTemplateRepository, userRepo user.UserRepository, companyRepo company.CompanyRepository, categoryRepo categories.CategoriesRepository, assetsService *assetS.AssetsService) *AssetTemplateService {
+	return &AssetTemplateService{repo: repo, userRepo: userRepo, companyRepo: companyRepo, categoryRepo: categoryRepo, assetsService: assetsService}
+}
+
+func (service *AssetTemplateService) Create(userId int64, request dto.AssetTemplateRequest) (*entity.AssetTemplate, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	company, err := service.companyRepo.GetCompanyBySuffixEmail(utils.GetSuffixEmail(user.Email))
+	if err != nil {
+		return nil, err
+	}
+	if request.WarrantyMonths < 0 {
+		return nil, errors.New("warranty months can't be negative")
+	}
+	if err := service.checkCategory(company.Id, request.CategoryId); err != nil {
+		return nil, err
+	}
+	template := &entity.AssetTemplate{
+		TemplateName:       request.TemplateName,
+		AssetName:          request.AssetName,
+		CategoryId:         request.CategoryId,
+		Cost:               request.Cost,
+		WarrantyMonths:     request.WarrantyMonths,
+		AnnualDepreciation: request.AnnualDepreciation,
+		ResidualValue:      request.ResidualValue,
+		UsefulLife:         request.UsefulLife,
+		CompanyId:          company.Id,
+	}
+	templateCreate, err := service.repo.Create(template)
+	if err != nil {
+		if strings.Contains(err.Error(), "idx_template_company") {
+			return nil, fmt.Errorf("This template already exists for this company")
+		}
+		return nil, err
+	}
+	return service.repo.GetById(templateCreate.Id)
+}
+
+func (service *AssetTemplateService) GetAll(userId int64) ([]*entity.AssetTemplate, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	return service.repo.GetAll(user.CompanyId)
+}
+
+func (service *AssetTemplateService) GetById(userId int64, id int64) (*entity.AssetTemplate, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	template, err := service.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if template.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	return template, nil
+}
+
+func (service *AssetTemplateService) Update(userId int64, id int64, request dto.AssetTemplateRequest) (*entity.AssetTemplate, error) {
+	template, err := service.GetById(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.WarrantyMonths < 0 {
+		return nil, errors.New("warranty months can't be negative")
+	}
+	if err := service.checkCategory(template.CompanyId, request.CategoryId); err != nil {
+		return nil, err
+	}
+	template.TemplateName = request.TemplateName
+	template.AssetName = request.AssetName
+	template.CategoryId = request.CategoryId
+	template.Cost = request.Cost
+	template.WarrantyMonths = request.WarrantyMonths
+	template.AnnualDepreciation = request.AnnualDepreciation
+	template.ResidualValue = request.ResidualValue
+	template.UsefulLife = request.UsefulLife
+	templateUpdate, err := service.repo.Update(template)
+	if err != nil {
+		if strings.Contains(err.Error(), "idx_template_company") {
+			return nil, fmt.Errorf("This template already exists for this company")
+		}
+		return nil, err
+	}
+	return templateUpdate, nil
+}
+
+func (service *AssetTemplateService) Delete(userId int64, id int64) error {
+	if _, err := service.GetById(userId, id); err != nil {
+		return err
+	}
+	return service.repo.Delete(id)
+}
+
+func (service *AssetTemplateService) CreateAssets(userId int64, templateId int64, departmentId int64, purchaseDate time.Time, serialNumbers []string, url string) ([]*entity.Assets, error) {
+	template, err := service.GetById(userId, templateId)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.checkCategory(template.CompanyId, template.CategoryId); err != nil {
+		return nil, err
+	}
+	// Phòng ban thuộc công ty được kiểm tra trong CreateCopies, ở đây chỉ kiểm tra quyền quản lý
+	if err := service.assetsService.CheckPermissionForManager(userId, departmentId); err != nil {
+		return nil, err
+	}
+	base := entity.Assets{
+		AssetName:          template.AssetName,
+		PurchaseDate:       purchaseDate,
+		Cost:               template.Cost,
+		WarrantExpiry:      purchaseDate.AddDate(0, template.WarrantyMonths, 0),
+		CategoryId:         template.CategoryId,
+		DepartmentId:       departmentId,
+		AnnualDepreciation: template.AnnualDepreciation,
+		ResidualValue:      template.ResidualValue,
+		UsefulLife:         template.UsefulLife,
+	}
+	changeSummary := fmt.Sprintf("Create asset from template '%v'", template.TemplateName)
+	return service.assetsService.CreateCopies(userId, base, serialNumbers, url, changeSummary)
+}
+
+// checkCategory danh mục phải thuộc công ty của mẫu
+func (service *AssetTemplateService) checkCategory(companyId int64, categoryId int64) error {
+	category, err := service.categoryRepo.GetCategoryById(categoryId)
+	if err != nil || category.CompanyId != companyId {
+		return errors.New("category not found")
+	}
+	return nil
+}
+
+func (service *AssetTemplateService) GetAssetById(userId int64, assetId int64) (*entity.Assets, error) {
+	return service.assetsService.GetAssetById(userId, assetId)
+}
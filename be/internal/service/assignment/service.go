@@ -10,11 +10,16 @@ import (
 	asset "BE_Manage_device/internal/repository/assets"
 	assignment "BE_Manage_device/internal/repository/assignments"
 	department "BE_Manage_device/internal/repository/departments"
+	handover "BE_Manage_device/internal/repository/handovers"
 	user "BE_Manage_device/internal/repository/user"
 	notificationS "BE_Manage_device/internal/service/notification"
 
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type AssignmentService struct {
@@ -23,11 +28,12 @@ type AssignmentService struct {
 	assetRepo           asset.AssetsRepository
 	departmentRepo      department.DepartmentsRepository
 	userRepo            user.UserRepository
+	handoverRepo        handover.HandoverRepository
 	NotificationService *notificationS.NotificationService
 }
 
-func NewAssignmentService(repo assignment.AssignmentRepository, assetLogRepo asset_log.AssetsLogRepository, assetRepo asset.AssetsRepository, departmentRepo department.DepartmentsRepository, userRepo user.UserRepository, NotificationService *notificationS.NotificationService) *AssignmentService {
-	return &AssignmentService{Repo: repo, assetLogRepo: assetLogRepo, assetRepo: assetRepo, departmentRepo: departmentRepo, userRepo: userRepo, NotificationService: NotificationService}
+func NewAssignmentService(repo assignment.AssignmentRepository, assetLogRepo asset_log.AssetsLogRepository, assetRepo asset.AssetsRepository, departmentRepo department.DepartmentsRepository, userRepo user.UserRepository, handoverRepo handover.HandoverRepository, NotificationService *notificationS.NotificationService) *AssignmentService {
+	return &AssignmentService{Repo: repo, assetLogRepo: assetLogRepo, assetRepo: assetRepo, departmentRepo: departmentRepo, userRepo: userRepo, handoverRepo: handoverRepo, NotificationService: NotificationService}
 }
 
 func (service *AssignmentService) Create(userIdAssign, departmentId *int64, userId, assetId int64) (*entity.Assignments, error) {
@@ -51,25 +57,52 @@ func (service *AssignmentService) Create(userIdAssign, departmentId *int64, user
 	if err != nil {
 		return nil, err
 	}
+	handover, err := service.requestHandover(assignmentCreated, nil, userId, tx)
+	if err != nil {
+		return nil, err
+	}
 	tx.Commit()
+	service.notifyHandover(handover)
 	return assignmentCreated, err
 }
 
-func (service *AssignmentService) Update(userId, assignmentId int64, userIdAssign, departmentId *int64) (*entity.Assignments, error) {
+func (service *AssignmentService) Update(userId, assignmentId int64, userIdAssign, departmentId *int64, reason string) (*entity.Assignments, error) {
 	var err error
-	assignment, err := service.Repo.GetAssignmentById(assignmentId)
+	tx := service.Repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	assignmentUpdated, notify, err := service.UpdateInTx(userId, assignmentId, userIdAssign, departmentId, reason, tx)
 	if err != nil {
 		return nil, err
 	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	notify()
+	return assignmentUpdated, nil
+}
+
+// UpdateInTx điều chuyển trong tx của nghiệp vụ khác, trả về hàm gửi thông báo để gọi sau khi tx commit
+func (service *AssignmentService) UpdateInTx(userId, assignmentId int64, userIdAssign, departmentId *int64, reason string, tx *gorm.DB) (*entity.Assignments, func(), error) {
+	assignment, err := service.Repo.GetAssignmentById(assignmentId)
+	if err != nil {
+		return nil, nil, err
+	}
 	byUser, err := service.userRepo.FindByUserId(userId)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	var assignUser *entity.Users
 	if byUser.Role.Slug == "viewer" {
 		userManager, err := service.userRepo.FindManager(byUser.Id)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		assignUser = userManager
 		userIdAssign = &userManager.Id
@@ -77,7 +110,7 @@ func (service *AssignmentService) Update(userId, assignmentId int64, userIdAssig
 		if userIdAssign != nil {
 			assignUser, err = service.userRepo.FindByUserId(*userIdAssign)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		} else {
 			assignUser = nil
@@ -85,10 +118,162 @@ func (service *AssignmentService) Update(userId, assignmentId int64, userIdAssig
 	}
 	asset, err := service.assetRepo.GetAssetById(assignment.AssetId)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if asset.Status == "Under Maintenance" {
-		return nil, fmt.Errorf("The asset is under maintenance.")
+		return nil, nil, fmt.Errorf("The asset is under maintenance.")
+	}
+	assignmentUpdated, handover, err := service.apply(tx, byUser, assignUser, assignment, asset, userIdAssign, departmentId, reason)
+	if err != nil {
+		return nil, nil, err
+	}
+	notify := func() {
+		var userHeadDepart *entity.Users
+		var userManagerAsset *entity.Users
+		if departmentId != nil {
+			userHeadDepart, _ = service.userRepo.GetUserHeadDepartment(*departmentId)
+			userManagerAsset, _ = service.userRepo.GetUserAssetManageOfDepartment(*departmentId)
+		} else {
+			userHeadDepart, _ = service.userRepo.GetUserHeadDepartment(asset.DepartmentId)
+			userManagerAsset, _ = service.userRepo.GetUserAssetManageOfDepartment(asset.DepartmentId)
+		}
+		usersToNotifications := []*entity.Users{asset.OnwerUser, userHeadDepart, userManagerAsset}
+		message := fmt.Sprintf("The asset '%v' (ID: %v) has just been updated by %v", asset.AssetName, asset.Id, byUser.Email)
+		userNotificationUnique := utils.ConvertUsersToNotificationsToMap(userId, usersToNotifications)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Println("SendNotificationToUsers panic:", r)
+				}
+			}()
+			service.NotificationService.SendNotificationToUsers(userNotificationUnique, message, *asset)
+		}()
+		service.notifyHandover(handover)
+	}
+	return assignmentUpdated, notify, nil
+}
+
+const (
+	bulkUpdated  = "Updated"
+	bulkSkipped  = "Skipped"
+	bulkRejected = "Rejected"
+	maxBulkSize  = 1000
+)
+
+// BulkUpdate chuyển nhiều tài sản cho một người dùng hoặc phòng ban trong một transaction.
+// Kiểm tra toàn bộ trước, chỉ cần một tài sản không hợp lệ thì không thay đổi gì
+func (service *AssignmentService) BulkUpdate(userId int64, request dto.BulkAssignmentRequest) (*dto.BulkAssignmentResponse, error) {
+	var err error
+	if (len(request.AssetIds) == 0) == (request.Filter == nil) {
+		return nil, errors.New("request must contain exactly one of assetIds or filter")
+	}
+	if (request.UserId == nil) == (request.DepartmentId == nil) {
+		return nil, errors.New("request must contain exactly one of userId or departmentId")
+	}
+	byUser, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if byUser.Role.Slug == "viewer" {
+		return nil, errors.New("viewer can't reassign assets in bulk")
+	}
+	var assignUser *entity.Users
+	if request.UserId != nil {
+		assignUser, err = service.userRepo.FindByUserId(*request.UserId)
+		if err != nil {
+			return nil, err
+		}
+		if assignUser.CompanyId != byUser.CompanyId || !assignUser.IsActive {
+			return nil, errors.New("target user not found")
+		}
+		if assignUser.DepartmentId == nil {
+			return nil, errors.New("target user doesn't belong to any department")
+		}
+	} else {
+		department, err := service.departmentRepo.GetDepartmentById(*request.DepartmentId)
+		if err != nil {
+			return nil, err
+		}
+		if department.CompanyId != byUser.CompanyId {
+			return nil, errors.New("target department not found")
+		}
+	}
+
+	var assignments []*entity.Assignments
+	if request.Filter != nil {
+		f := request.Filter
+		assignments, err = service.Repo.GetForBulk(byUser.CompanyId, nil, f.CategoryId, f.DepartmentId, f.UserId, f.Status)
+	} else {
+		assignments, err = service.Repo.GetForBulk(byUser.CompanyId, request.AssetIds, nil, nil, nil, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(assignments) > maxBulkSize {
+		return nil, fmt.Errorf("can't reassign more than %v assets at once", maxBulkSize)
+	}
+
+	// Kiểm tra từng tài sản
+	res := &dto.BulkAssignmentResponse{Results: []dto.BulkAssignmentResult{}}
+	found := map[int64]bool{}
+	var toApply []*entity.Assignments
+	for _, a := range assignments {
+		found[a.AssetId] = true
+		result := dto.BulkAssignmentResult{AssetId: a.AssetId, AssetName: a.Asset.AssetName, AssignmentId: &a.Id, Status: bulkUpdated}
+		switch {
+		case a.Asset.Status == "Under Maintenance" || a.Asset.Status == "Retired" || a.Asset.Status == "Disposed":
+			result.Status = bulkRejected
+			result.Message = fmt.Sprintf("The asset is %v.", strings.ToLower(a.Asset.Status))
+		case request.UserId != nil && a.UserId != nil && *a.UserId == *request.UserId:
+			result.Status = bulkSkipped
+			result.Message = "The asset is already assigned to this user."
+		case request.DepartmentId != nil && a.Asset.DepartmentId == *request.DepartmentId && a.DepartmentId != nil && *a.DepartmentId == *request.DepartmentId:
+			result.Status = bulkSkipped
+			result.Message = "The asset already belongs to this department."
+		default:
+			toApply = append(toApply, a)
+		}
+		res.Results = append(res.Results, result)
+	}
+	seen := map[int64]bool{}
+	for _, assetId := range request.AssetIds {
+		if found[assetId] || seen[assetId] {
+			continue
+		}
+		seen[assetId] = true
+		res.Results = append(res.Results, dto.BulkAssignmentResult{AssetId: assetId, Status: bulkRejected, Message: "Asset not found or not assigned."})
+	}
+	for _, r := range res.Results {
+		switch r.Status {
+		case bulkUpdated:
+			res.Updated++
+		case bulkSkipped:
+			res.Skipped++
+		case bulkRejected:
+			res.Rejected++
+		}
+	}
+	res.Total = len(res.Results)
+	if res.Rejected > 0 {
+		// Không áp dụng gì, các tài sản hợp lệ cũng chưa được cập nhật
+		res.Updated = 0
+		for i := range res.Results {
+			if res.Results[i].Status == bulkUpdated {
+				res.Results[i].Status = bulkSkipped
+				res.Results[i].Message = "Not applied because other assets were rejected."
+				res.Skipped++
+			}
+		}
+		return res, nil
+	}
+	if len(toApply) == 0 {
+		res.Applied = true
+		return res, nil
+	}
+
+	reason := request.Reason
+	if reason == "" {
+		reason = "Bulk reassignment"
 	}
 	tx := service.Repo.GetDB().Begin()
 	defer func() {
@@ -99,14 +284,123 @@ func (service *AssignmentService) Update(userId, assignmentId int64, userIdAssig
 			tx.Rollback()
 		}
 	}()
+	var handovers []*entity.HandoverAcknowledgement
+	for _, a := range toApply {
+		var handover *entity.HandoverAcknowledgement
+		if _, handover, err = service.apply(tx, byUser, assignUser, a, &a.Asset, request.UserId, request.DepartmentId, reason); err != nil {
+			return nil, fmt.Errorf("asset %v: %w", a.AssetId, err)
+		}
+		if handover != nil {
+			handovers = append(handovers, handover)
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	res.Applied = true
+	service.notifyBulk(byUser, assignUser, request.DepartmentId, toApply, handovers)
+	return res, nil
+}
+
+// notifyBulk gửi một thông báo tổng hợp cho mỗi người liên quan thay vì một thông báo cho mỗi tài sản
+func (service *AssignmentService) notifyBulk(byUser, assignUser *entity.Users, departmentId *int64, assignments []*entity.Assignments, handovers []*entity.HandoverAcknowledgement) {
+	recipients := map[int64]*entity.Users{}
+	assetsOf := map[int64][]string{}
+	add := func(u *entity.Users, assetName string) {
+		if u == nil || u.Id == byUser.Id {
+			return
+		}
+		recipients[u.Id] = u
+		assetsOf[u.Id] = append(assetsOf[u.Id], assetName)
+	}
+	managers := map[int64][]*entity.Users{}
+	departmentManagers := func(id int64) []*entity.Users {
+		if users, ok := managers[id]; ok {
+			return users
+		}
+		head, _ := service.userRepo.GetUserHeadDepartment(id)
+		manager, _ := service.userRepo.GetUserAssetManageOfDepartment(id)
+		managers[id] = []*entity.Users{head, manager}
+		return managers[id]
+	}
+	for _, a := range assignments {
+		// Người giữ cũ và quản lý phòng ban mới
+		add(a.Asset.OnwerUser, a.Asset.AssetName)
+		target := a.Asset.DepartmentId
+		if departmentId != nil {
+			target = *departmentId
+		} else if assignUser.DepartmentId != nil {
+			target = *assignUser.DepartmentId
+		}
+		for _, u := range departmentManagers(target) {
+			add(u, a.Asset.AssetName)
+		}
+	}
+	handoverOf := map[int64]int{}
+	for _, h := range handovers {
+		handoverOf[h.UserId]++
+	}
+	messages := map[int64]string{}
+	for id, names := range assetsOf {
+		names = uniqueStrings(names)
+		messages[id] = fmt.Sprintf("%v assets have just been reassigned by %v: %v", len(names), byUser.Email, summarizeNames(names))
+	}
+	if assignUser != nil && assignUser.Id != byUser.Id {
+		recipients[assignUser.Id] = assignUser
+		message := fmt.Sprintf("%v assets have just been assigned to you by %v", len(assignments), byUser.Email)
+		if handoverOf[assignUser.Id] > 0 {
+			message += ", please acknowledge receipt"
+		}
+		messages[assignUser.Id] = message
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Println("SendNotificationToUsers panic:", r)
+			}
+		}()
+		for id, u := range recipients {
+			service.NotificationService.SendNotificationWithoutAsset([]*entity.Users{u}, messages[id])
+		}
+	}()
+}
+
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// summarizeNames liệt kê tối đa 5 tên, phần còn lại gộp thành "and N more"
+func summarizeNames(names []string) string {
+	if len(names) <= 5 {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%v and %v more", strings.Join(names[:5], ", "), len(names)-5)
+}
+
+// apply chuyển một assignment sang người dùng hoặc phòng ban mới trong tx: cập nhật assignment, chủ sở hữu,
+// phòng ban, nhật ký tài sản và tạo yêu cầu xác nhận bàn giao
+func (service *AssignmentService) apply(tx *gorm.DB, byUser, assignUser *entity.Users, assignment *entity.Assignments, asset *entity.Assets, userIdAssign, departmentId *int64, reason string) (*entity.Assignments, *entity.HandoverAcknowledgement, error) {
 	var assignmentUpdated *entity.Assignments
+	var err error
 	if departmentId != nil {
-		assignmentUpdated, err = service.Repo.Update(assignmentId, userId, assignment.AssetId, userIdAssign, departmentId, tx)
+		assignmentUpdated, err = service.Repo.Update(assignment.Id, byUser.Id, assignment.AssetId, userIdAssign, departmentId, reason, tx)
 	} else {
-		assignmentUpdated, err = service.Repo.Update(assignmentId, userId, assignment.AssetId, userIdAssign, assignUser.DepartmentId, tx)
+		assignmentUpdated, err = service.Repo.Update(assignment.Id, byUser.Id, assignment.AssetId, userIdAssign, assignUser.DepartmentId, reason, tx)
 	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	handover, err := service.requestHandover(assignmentUpdated, assignment.UserId, byUser.Id, tx)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Chuyển phòng ban
@@ -116,24 +410,26 @@ func (service *AssignmentService) Update(userId, assignmentId int64, userIdAssig
 			Action:    "Transfer",
 			AssetId:   asset.Id,
 			ByUserId:  &byUser.Id,
-			CompanyId: assignUser.CompanyId,
+			CompanyId: byUser.CompanyId,
 		}
 		department, err := service.departmentRepo.GetDepartmentById(*departmentId)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+		assetLog.FromDepartmentId = &asset.DepartmentId
+		assetLog.ToDepartmentId = departmentId
 		assetLog.ChangeSummary = fmt.Sprintf("Transfer from department %v to department %v by user %v\n",
 			asset.Department.DepartmentName, department.DepartmentName, byUser.Email)
 		if _, err := service.assetRepo.UpdateAssetDepartment(assignment.AssetId, *departmentId, tx); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if _, err := service.assetLogRepo.Create(&assetLog, tx); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	// Chuyển người dùng
-	if userIdAssign != nil && (*userIdAssign != asset.OnwerUser.Id) {
+	if userIdAssign != nil && (asset.OnwerUser == nil || *userIdAssign != asset.OnwerUser.Id) {
 		assetLog := entity.AssetLog{
 			Timestamp: time.Now(),
 			Action:    "Transfer",
@@ -145,53 +441,77 @@ func (service *AssignmentService) Update(userId, assignmentId int64, userIdAssig
 		assetLog.ChangeSummary += fmt.Sprintf("Transfer from user: %v to user: %v\n",
 			byUser.Email, assignUser.Email)
 		if _, err := service.assetRepo.UpdateAssetOwner(assignment.AssetId, *userIdAssign, tx); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if err := service.assetRepo.UpdateOwner(assignment.AssetId, assignUser.Id, tx); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if *assignUser.DepartmentId != asset.DepartmentId {
 			_, err := service.assetRepo.UpdateAssetDepartment(asset.Id, *assignUser.DepartmentId, tx)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
+			}
+			if departmentId == nil {
+				assetLog.FromDepartmentId = &asset.DepartmentId
+				assetLog.ToDepartmentId = assignUser.DepartmentId
 			}
 		}
 		if _, err := service.assetLogRepo.Create(&assetLog, tx); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	if _, err := service.assetRepo.UpdateAssetLifeCycleStage(assignment.AssetId, "In Use", tx); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	err = service.assetRepo.UpdateAcquisitionDate(assignment.AssetId, time.Now(), tx)
-	if err != nil {
+	if err := service.assetRepo.UpdateAcquisitionDate(assignment.AssetId, time.Now(), tx); err != nil {
+		return nil, nil, err
+	}
+	return assignmentUpdated, handover, nil
+}
+
+// requestHandover tạo xác nhận bàn giao khi tài sản được giao cho người mới, người nhận phải ký xác nhận trên app.
+// Trả về nil nếu người giữ không đổi hoặc người giao tự nhận
+func (service *AssignmentService) requestHandover(assignment *entity.Assignments, previousUserId *int64, byUserId int64, tx *gorm.DB) (*entity.HandoverAcknowledgement, error) {
+	if assignment.UserId == nil || (previousUserId != nil && *previousUserId == *assignment.UserId) {
+		return nil, nil
+	}
+	if err := service.handoverRepo.SupersedePending(assignment.AssetId, tx); err != nil {
 		return nil, err
 	}
-	if err := tx.Commit().Error; err != nil {
+	if err := service.handoverRepo.UpdateAssignmentReceipt(assignment.Id, nil, tx); err != nil {
 		return nil, err
 	}
-	var userHeadDepart *entity.Users
-	var userManagerAsset *entity.Users
-	if departmentId != nil {
-		userHeadDepart, _ = service.userRepo.GetUserHeadDepartment(*departmentId)
-		userManagerAsset, _ = service.userRepo.GetUserAssetManageOfDepartment(*departmentId)
-	} else {
-		userHeadDepart, _ = service.userRepo.GetUserHeadDepartment(asset.DepartmentId)
-		userManagerAsset, _ = service.userRepo.GetUserAssetManageOfDepartment(asset.DepartmentId)
+	if *assignment.UserId == byUserId {
+		return nil, nil
 	}
-	usersToNotifications := []*entity.Users{asset.OnwerUser, userHeadDepart, userManagerAsset}
-	message := fmt.Sprintf("The asset '%v' (ID: %v) has just been updated by %v", asset.AssetName, asset.Id, byUser.Email)
-	userNotificationUnique := utils.ConvertUsersToNotificationsToMap(userId, usersToNotifications)
+	handover := &entity.HandoverAcknowledgement{
+		AssignmentId: assignment.Id,
+		AssetId:      assignment.AssetId,
+		UserId:       *assignment.UserId,
+		AssignedById: byUserId,
+		Status:       entity.HandoverPending,
+		CreatedAt:    time.Now(),
+		CompanyId:    assignment.Asset.CompanyId,
+	}
+	handover.Asset = assignment.Asset
+	handover.User = assignment.UserAssigned
+	return service.handoverRepo.Create(handover, tx)
+}
+
+func (service *AssignmentService) notifyHandover(handover *entity.HandoverAcknowledgement) {
+	if handover == nil {
+		return
+	}
+	message := fmt.Sprintf("Please acknowledge receipt of asset '%v' (ID: %v)", handover.Asset.AssetName, handover.AssetId)
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
 				fmt.Println("SendNotificationToUsers panic:", r)
 			}
 		}()
-		service.NotificationService.SendNotificationToUsers(userNotificationUnique, message, *asset)
+		service.NotificationService.SendNotificationToUsers([]*entity.Users{&handover.User}, message, handover.Asset)
 	}()
-	return assignmentUpdated, nil
 }
 
 func (service *AssignmentService) Filter(userId int64, emailAssigned *string, emailAssign *string, assetName *string) ([]dto.AssignmentResponse, error) {
@@ -237,3 +557,43 @@ func (service *AssignmentService) GetAssignmentById(userId int64, id int64) (*dt
 	assignResponse := utils.ConvertAssignmentToResponse(assignment)
 	return &assignResponse, nil
 }
+
+// GetAssetHistories ai đã giữ tài sản trong khoảng [from, to)
+func (service *AssignmentService) GetAssetHistories(userId int64, assetId int64, from, to *time.Time) ([]dto.AssignmentHistoryResponse, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := service.assetRepo.GetAssetById(assetId)
+	if err != nil {
+		return nil, err
+	}
+	if asset.CompanyId != user.CompanyId {
+		return nil, errors.New("asset not found")
+	}
+	histories, err := service.Repo.GetHistoriesOfAsset(assetId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ConvertAssignmentHistoriesToResponses(histories), nil
+}
+
+// GetUserHistories người dùng đã giữ những tài sản nào trong khoảng [from, to)
+func (service *AssignmentService) GetUserHistories(userId int64, holderId int64, from, to *time.Time) ([]dto.AssignmentHistoryResponse, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	holder, err := service.userRepo.FindByUserId(holderId)
+	if err != nil {
+		return nil, err
+	}
+	if holder.CompanyId != user.CompanyId {
+		return nil, errors.New("user not found")
+	}
+	histories, err := service.Repo.GetHistoriesOfUser(holderId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ConvertAssignmentHistoriesToResponses(histories), nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"BE_Manage_device/config"
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	calendarFeed "BE_Manage_device/internal/repository/calendar_feeds"
+	department "BE_Manage_device/internal/repository/departments"
+	user "BE_Manage_device/internal/repository/user"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Khoảng thời gian đưa vào feed
+const (
+	calendarFeedPastDays   = 30
+	calendarFeedFutureDays = 365
+)
+
+type CalendarFeedService struct {
+	repo           calendarFeed.CalendarFeedRepository
+	departmentRepo department.DepartmentsRepository
+	userRepo       user.UserRepository
+}
+
+func NewCalendarFeedService(repo calendarFeed.CalendarFeedRepository, departmentRepo department.DepartmentsRepository, userRepo user.UserRepository) *CalendarFeedService {
+	return &CalendarFeedService{repo: repo, departmentRepo: departmentRepo, userRepo: userRepo}
+}
+
+// Create tạo feed mới, token chỉ trả về một lần trong url
+func (service *CalendarFeedService) Create(userId int64, request dto.CreateCalendarFeedRequest) (*dto.CalendarFeedResponse, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	name := request.Name
+	if request.DepartmentId != nil {
+		dep, err := service.departmentRepo.GetDepartmentById(*request.DepartmentId)
+		if err != nil || dep.CompanyId != user.CompanyId {
+			return nil, errors.New("department not found")
+		}
+		if user.Role.Slug != "admin" && (user.DepartmentId == nil || *user.DepartmentId != dep.Id) {
+			return nil, errors.New("you can only subscribe to your own department")
+		}
+		if name == "" {
+			name = dep.DepartmentName
+		}
+	} else if name == "" {
+		name = fmt.Sprintf("%v %v", user.FirstName, user.LastName)
+	}
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	feed, err := service.repo.Create(&entity.CalendarFeed{
+		Name:         name,
+		UserId:       userId,
+		DepartmentId: request.DepartmentId,
+		TokenHash:    utils.HashToken(token),
+		CreatedAt:    time.Now(),
+		CompanyId:    user.CompanyId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	feed, err = service.repo.GetById(feed.Id)
+	if err != nil {
+		return nil, err
+	}
+	res := utils.ConvertCalendarFeedToResponse(feed)
+	res.Url = config.BASE_URL_BACKEND + "api/calendar-feeds/ics/" + token + ".ics"
+	return &res, nil
+}
+
+// Admin xem được toàn bộ feed của công ty, người khác chỉ xem feed của mình
+func (service *CalendarFeedService) GetAll(userId int64) ([]*entity.CalendarFeed, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role.Slug == "admin" {
+		return service.repo.GetByCompany(user.CompanyId)
+	}
+	return service.repo.GetByUser(userId)
+}
+
+func (service *CalendarFeedService) Revoke(userId int64, id int64) error {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return err
+	}
+	feed, err := service.repo.GetById(id)
+	if err != nil {
+		return err
+	}
+	if feed.CompanyId != user.CompanyId || (feed.UserId != userId && user.Role.Slug != "admin") {
+		return errors.New("can't find record this id")
+	}
+	if feed.RevokedAt != nil {
+		return errors.New("calendar feed is already revoked")
+	}
+	return service.repo.Revoke(id, time.Now())
+}
+
+// Render sinh nội dung ICS của feed ứng với token, token sai/đã thu hồi hoặc chủ feed bị khoá đều trả lỗi.
+// Lịch bảo trì bị xoá (kể cả lịch tương lai của kế hoạch bị tắt/xoá) bị xoá hẳn khỏi DB nên không có
+// sự kiện STATUS:CANCELLED, feed là bản thay thế toàn bộ nên sự kiện đó tự biến mất khi lịch làm mới
+func (service *CalendarFeedService) Render(token string) ([]byte, error) {
+	feed, err := service.repo.GetByTokenHash(utils.HashToken(token))
+	if err != nil {
+		return nil, errors.New("calendar feed not found")
+	}
+	if !feed.User.IsActive || feed.User.CompanyId != feed.CompanyId {
+		return nil, errors.New("calendar feed not found")
+	}
+	var ownerId, departmentId *int64
+	if feed.DepartmentId != nil {
+		departmentId = feed.DepartmentId
+	} else {
+		// Feed cá nhân: tài sản mình sở hữu + tài sản của phòng ban nếu là người quản lý tài sản/trưởng phòng
+		ownerId = &feed.UserId
+		if feed.User.DepartmentId != nil && (feed.User.IsAssetManager || feed.User.Role.Slug == "departmentHead") {
+			departmentId = feed.User.DepartmentId
+		}
+	}
+	assets, err := service.repo.GetFeedAssets(feed.CompanyId, ownerId, departmentId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	from := now.AddDate(0, 0, -calendarFeedPastDays)
+	to := now.AddDate(0, 0, calendarFeedFutureDays)
+	assetById := map[int64]*entity.Assets{}
+	assetIds := make([]int64, 0, len(assets))
+	for _, a := range assets {
+		assetById[a.Id] = a
+		assetIds = append(assetIds, a.Id)
+	}
+	schedules, err := service.repo.GetSchedulesOfAssets(assetIds, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, _ := time.LoadLocation("Asia/Bangkok") // GMT+7
+	events := []utils.ICSEvent{}
+	for _, s := range schedules {
+		a := assetById[s.AssetId]
+		start := s.StartDate.In(loc)
+		end := s.EndDate.In(loc)
+		event := utils.ICSEvent{
+			UID:          fmt.Sprintf("maintenance-schedule-%d@be-manage-device", s.Id),
+			Summary:      fmt.Sprintf("Maintenance: %v (%v)", a.AssetName, a.SerialNumber),
+			Description:  fmt.Sprintf("Maintenance schedule #%d for asset #%d", s.Id, a.Id),
+			Start:        time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc),
+			End:          time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, loc),
+			AllDay:       true,
+			Cancelled:    isAssetOutOfService(a),
+			LastModified: s.UpdatedAt,
+		}
+		if !s.UpdatedAt.IsZero() {
+			// Thời điểm cập nhật luôn tăng nên dùng làm SEQUENCE
+			event.Sequence = s.UpdatedAt.Unix()
+		}
+		events = append(events, event)
+	}
+	for _, a := range assets {
+		if a.WarrantExpiry.IsZero() || a.WarrantExpiry.Before(from) || !a.WarrantExpiry.Before(to) {
+			continue
+		}
+		expiry := a.WarrantExpiry.In(loc)
+		events = append(events, utils.ICSEvent{
+			UID:         fmt.Sprintf("warranty-expiry-%d@be-manage-device", a.Id),
+			Summary:     fmt.Sprintf("Warranty expires: %v (%v)", a.AssetName, a.SerialNumber),
+			Description: fmt.Sprintf("Warranty of asset #%d expires", a.Id),
+			Start:       time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 0, 0, 0, 0, loc),
+			End:         time.Date(expiry.Year(), expiry.Month(), expiry.Day()+1, 0, 0, 0, 0, loc),
+			AllDay:      true,
+			Cancelled:   isAssetOutOfService(a),
+		})
+	}
+	if err := service.repo.UpdateLastAccessed(feed.Id, now); err != nil {
+		logrus.Infof("Happen error when update last accessed of calendar feed %v: %v", feed.Id, err)
+	}
+	return utils.GenerateICS(feed.Name, events), nil
+}
+
+// Tài sản đã thanh lý/nghỉ hưu: sự kiện được giữ lại với STATUS:CANCELLED để lịch xoá đi
+func isAssetOutOfService(a *entity.Assets) bool {
+	return a.Status == "Retired" || a.Status == "Disposed"
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	chargeback "BE_Manage_device/internal/repository/chargeback"
+	company "BE_Manage_device/internal/repository/company"
+	department "BE_Manage_device/internal/repository/departments"
+	user "BE_Manage_device/internal/repository/user"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var chargebackLocation = time.FixedZone("Asia/Ho_Chi_Minh", 7*3600)
+
+// Log chuyển phòng ban cũ chỉ lưu tên phòng ban trong change summary
+var legacyTransferPattern = regexp.MustCompile(`^Transfer from department (.*) to department (.*) by user `)
+
+type ChargebackService struct {
+	repo           chargeback.ChargebackRepository
+	departmentRepo department.DepartmentsRepository
+	userRepo       user.UserRepository
+	companyRepo    company.CompanyRepository
+}
+
+func NewChargebackService(repo chargeback.ChargebackRepository, departmentRepo department.DepartmentsRepository, userRepo user.UserRepository, companyRepo company.CompanyRepository) *ChargebackService {
+	return &ChargebackService{repo: repo, departmentRepo: departmentRepo, userRepo: userRepo, companyRepo: companyRepo}
+}
+
+// holding khoảng thời gian [start, end) tài sản thuộc về một phòng ban
+type holding struct {
+	departmentId int64
+	start        time.Time
+	end          time.Time
+}
+
+func periodOf(month, year int64) (time.Time, time.Time) {
+	start := time.Date(int(year), time.Month(month), 1, 0, 0, 0, 0, chargebackLocation)
+	return start, start.AddDate(0, 1, 0)
+}
+
+func (service *ChargebackService) Compute(userId int64, month, year int64) (*dto.ChargebackResponse, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.ComputeForCompany(user.CompanyId, month, year); err != nil {
+		return nil, err
+	}
+	return service.GetByPeriod(userId, month, year, nil)
+}
+
+// Chạy cho tất cả công ty, dùng trong cron job đầu tháng
+func (service *ChargebackService) ComputeAllCompanies(month, year int64) {
+	companies, err := service.companyRepo.GetAllCompany()
+	if err != nil {
+		logrus.Infof("Happen error when compute chargeback at: %v", time.Now())
+		return
+	}
+	for _, c := range companies {
+		if err := service.ComputeForCompany(c.Id, month, year); err != nil {
+			logrus.Infof("Happen error when compute chargeback for company: %v. Error: %v", c.CompanyName, err)
+		}
+	}
+}
+
+// ComputeForCompany tính lại và ghi đè kết quả phân bổ chi phí của kỳ month/year.
+// Khấu hao được chia theo số ngày phòng ban giữ tài sản (dựa trên log chuyển phòng ban),
+// chi phí bảo trì tính cho phòng ban đang giữ tài sản tại ngày bắt đầu bảo trì.
+func (service *ChargebackService) ComputeForCompany(companyId int64, month, year int64) error {
+	if month < 1 || month > 12 {
+		return errors.New("month must be between 1 and 12")
+	}
+	start, end := periodOf(month, year)
+	if !start.Before(time.Now()) {
+		return errors.New("can't compute chargeback for a period that has not started")
+	}
+	assets, err := service.repo.GetAssetsHeldInPeriod(companyId, start, end)
+	if err != nil {
+		return err
+	}
+	assetIds := make([]int64, 0, len(assets))
+	for _, a := range assets {
+		assetIds = append(assetIds, a.Id)
+	}
+	logs, err := service.repo.GetDepartmentLogsSince(assetIds, start)
+	if err != nil {
+		return err
+	}
+	logsByAsset := map[int64][]*entity.AssetLog{}
+	for _, l := range logs {
+		logsByAsset[l.AssetId] = append(logsByAsset[l.AssetId], l)
+	}
+	departments, err := service.departmentRepo.GetAll(companyId)
+	if err != nil {
+		return err
+	}
+	departmentByName := map[string]int64{}
+	for _, d := range departments {
+		departmentByName[d.DepartmentName] = d.Id
+	}
+	bills, err := service.repo.GetBillsByAssetIds(assetIds)
+	if err != nil {
+		return err
+	}
+	billAmount := map[int64]float64{}
+	for _, b := range bills {
+		billAmount[b.AssetId] = b.Amount
+	}
+	maintenances, err := service.repo.GetMaintenanceInPeriod(assetIds, start, end)
+	if err != nil {
+		return err
+	}
+	maintenanceByAsset := map[int64][]*entity.MaintenanceSchedules{}
+	for _, m := range maintenances {
+		maintenanceByAsset[m.AssetId] = append(maintenanceByAsset[m.AssetId], m)
+	}
+
+	type lineKey struct{ departmentId, assetId int64 }
+	lines := map[lineKey]*entity.ChargebackLine{}
+	getLine := func(departmentId, assetId int64) *entity.ChargebackLine {
+		key := lineKey{departmentId, assetId}
+		if line, ok := lines[key]; ok {
+			return line
+		}
+		line := &entity.ChargebackLine{Month: month, Year: year, CompanyId: companyId, DepartmentId: departmentId, AssetId: assetId}
+		lines[key] = line
+		return line
+	}
+	for _, asset := range assets {
+		holdings := departmentHoldings(asset, logsByAsset[asset.Id], departmentByName, start, end)
+		// Khoảng thời gian tài sản còn được sử dụng trong kỳ
+		heldFrom := maxTime(start, asset.PurchaseDate)
+		heldTo := end
+		if asset.RetiredOrDisposeTime != nil && asset.RetiredOrDisposeTime.Before(heldTo) {
+			heldTo = *asset.RetiredOrDisposeTime
+		}
+		depreciationTo := heldTo
+		if asset.UsefulLife != nil && *asset.UsefulLife > 0 {
+			lifeEnd := asset.PurchaseDate.Add(time.Duration(*asset.UsefulLife * 365 * 24 * float64(time.Hour)))
+			if lifeEnd.Before(depreciationTo) {
+				depreciationTo = lifeEnd
+			}
+		}
+		var bill *float64
+		if amount, ok := billAmount[asset.Id]; ok {
+			bill = &amount
+		}
+		rate := dailyDepreciation(asset, bill)
+		for _, h := range holdings {
+			from, to := maxTime(h.start, heldFrom), minTime(h.end, heldTo)
+			if !to.After(from) {
+				continue
+			}
+			line := getLine(h.departmentId, asset.Id)
+			line.DaysHeld += to.Sub(from).Hours() / 24
+			depTo := minTime(to, depreciationTo)
+			if depTo.After(from) {
+				line.DepreciationAmount += depTo.Sub(from).Hours() / 24 * rate
+			}
+		}
+		for _, m := range maintenanceByAsset[asset.Id] {
+			line := getLine(holderAt(holdings, m.StartDate, asset.DepartmentId), asset.Id)
+			line.MaintenanceAmount += m.Cost
+		}
+	}
+
+	summaryByDepartment := map[int64]*entity.DepartmentChargeback{}
+	lineList := make([]*entity.ChargebackLine, 0, len(lines))
+	now := time.Now()
+	for _, line := range lines {
+		line.DaysHeld = utils.RoundAmount(line.DaysHeld)
+		line.DepreciationAmount = utils.RoundAmount(line.DepreciationAmount)
+		line.MaintenanceAmount = utils.RoundAmount(line.MaintenanceAmount)
+		lineList = append(lineList, line)
+		summary, ok := summaryByDepartment[line.DepartmentId]
+		if !ok {
+			summary = &entity.DepartmentChargeback{Month: month, Year: year, CompanyId: companyId, DepartmentId: line.DepartmentId, GeneratedAt: now}
+			summaryByDepartment[line.DepartmentId] = summary
+		}
+		summary.AssetCount++
+		summary.DepreciationAmount += line.DepreciationAmount
+		summary.MaintenanceAmount += line.MaintenanceAmount
+	}
+	summaries := make([]*entity.DepartmentChargeback, 0, len(summaryByDepartment))
+	for _, summary := range summaryByDepartment {
+		summary.DepreciationAmount = utils.RoundAmount(summary.DepreciationAmount)
+		summary.MaintenanceAmount = utils.RoundAmount(summary.MaintenanceAmount)
+		summary.TotalAmount = utils.RoundAmount(summary.DepreciationAmount + summary.MaintenanceAmount)
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].DepartmentId < summaries[j].DepartmentId })
+	sort.Slice(lineList, func(i, j int) bool {
+		if lineList[i].DepartmentId != lineList[j].DepartmentId {
+			return lineList[i].DepartmentId < lineList[j].DepartmentId
+		}
+		return lineList[i].AssetId < lineList[j].AssetId
+	})
+
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if err = service.repo.ReplacePeriod(companyId, month, year, summaries, lineList, tx); err != nil {
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+	return nil
+}
+
+func (service *ChargebackService) GetByPeriod(userId int64, month, year int64, departmentId *int64) (*dto.ChargebackResponse, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	res := &dto.ChargebackResponse{Month: month, Year: year, Departments: []*dto.DepartmentChargebackResponse{}}
+	departmentId, ok := scopeDepartment(user, departmentId)
+	if !ok {
+		return res, nil
+	}
+	summaries, err := service.repo.GetByPeriod(user.CompanyId, month, year, departmentId)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range summaries {
+		res.TotalAmount += s.TotalAmount
+		if res.GeneratedAt == nil {
+			generatedAt := s.GeneratedAt
+			res.GeneratedAt = &generatedAt
+		}
+		res.Departments = append(res.Departments, utils.ConvertDepartmentChargebackToResponse(s))
+	}
+	res.TotalAmount = utils.RoundAmount(res.TotalAmount)
+	return res, nil
+}
+
+func (service *ChargebackService) GetLines(userId int64, month, year int64, departmentId *int64) ([]*dto.ChargebackLineResponse, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	res := []*dto.ChargebackLineResponse{}
+	departmentId, ok := scopeDepartment(user, departmentId)
+	if !ok {
+		return res, nil
+	}
+	lines, err := service.repo.GetLinesByPeriod(user.CompanyId, month, year, departmentId)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lines {
+		res = append(res, utils.ConvertChargebackLineToResponse(l))
+	}
+	return res, nil
+}
+
+// Viewer chỉ được xuất báo cáo khi được cấp quyền can-export
+func (service *ChargebackService) GetLinesForExport(userId int64, month, year int64, departmentId *int64) ([]*dto.ChargebackLineResponse, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role.Slug == "viewer" && !user.CanExport {
+		return nil, errors.New("you don't have permission to export")
+	}
+	return service.GetLines(userId, month, year, departmentId)
+}
+
+// Viewer chỉ được xem chi phí của phòng ban mình
+func scopeDepartment(user *entity.Users, departmentId *int64) (*int64, bool) {
+	if user.Role.Slug != "viewer" {
+		return departmentId, true
+	}
+	if user.DepartmentId == nil || (departmentId != nil && *departmentId != *user.DepartmentId) {
+		return nil, false
+	}
+	return user.DepartmentId, true
+}
+
+// departmentHoldings dựng lại lịch sử phòng ban trong kỳ bằng cách đi ngược
+// từ phòng ban hiện tại qua các log chuyển phòng ban (logs sắp xếp mới nhất trước).
+func departmentHoldings(asset *entity.Assets, logs []*entity.AssetLog, departmentByName map[string]int64, start, end time.Time) []holding {
+	current := asset.DepartmentId
+	cursor := maxTime(end, time.Now())
+	holdings := []holding{}
+	for _, l := range logs {
+		from, to := l.FromDepartmentId, l.ToDepartmentId
+		if from == nil {
+			match := legacyTransferPattern.FindStringSubmatch(l.ChangeSummary)
+			if match == nil {
+				continue
+			}
+			fromId, okFrom := departmentByName[match[1]]
+			toId, okTo := departmentByName[match[2]]
+			if !okFrom || !okTo {
+				continue
+			}
+			from, to = &fromId, &toId
+		}
+		if to != nil {
+			current = *to
+		}
+		holdings = append(holdings, holding{departmentId: current, start: l.Timestamp, end: cursor})
+		current = *from
+		cursor = l.Timestamp
+	}
+	holdings = append(holdings, holding{departmentId: current, start: start, end: cursor})
+
+	clipped := []holding{}
+	for i := len(holdings) - 1; i >= 0; i-- {
+		h := holdings[i]
+		h.start, h.end = maxTime(h.start, start), minTime(h.end, end)
+		if h.end.After(h.start) {
+			clipped = append(clipped, h)
+		}
+	}
+	return clipped
+}
+
+func holderAt(holdings []holding, t time.Time, fallback int64) int64 {
+	for _, h := range holdings {
+		if !t.Before(h.start) && t.Before(h.end) {
+			return h.departmentId
+		}
+	}
+	return fallback
+}
+
+// Khấu hao theo ngày: (nguyên giá - giá trị thu hồi) / số ngày sử dụng dự kiến,
+// nguyên giá lấy theo hóa đơn nếu có
+func dailyDepreciation(asset *entity.Assets, billAmount *float64) float64 {
+	base := asset.Cost
+	if billAmount != nil {
+		base = *billAmount
+	}
+	residual := 0.0
+	if asset.ResidualValue != nil {
+		residual = *asset.ResidualValue
+	}
+	if asset.UsefulLife != nil && *asset.UsefulLife > 0 {
+		return math.Max(base-residual, 0) / (*asset.UsefulLife * 365)
+	}
+	if asset.AnnualDepreciation != nil && *asset.AnnualDepreciation > 0 {
+		return *asset.AnnualDepreciation / 365
+	}
+	return 0
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,461 @@
+package service
+
+import (
+	"BE_Manage_device/config"
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	asset_log "BE_Manage_device/internal/repository/asset_log"
+	restructure "BE_Manage_device/internal/repository/department_restructures"
+	department "BE_Manage_device/internal/repository/departments"
+	location "BE_Manage_device/internal/repository/locations"
+	user "BE_Manage_device/internal/repository/user"
+	"BE_Manage_device/pkg/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DepartmentRestructureService struct {
+	repo           restructure.DepartmentRestructureRepository
+	departmentRepo department.DepartmentsRepository
+	locationRepo   location.LocationRepository
+	userRepo       user.UserRepository
+	assetLogRepo   asset_log.AssetsLogRepository
+}
+
+func NewDepartmentRestructureService(repo restructure.DepartmentRestructureRepository, departmentRepo department.DepartmentsRepository, locationRepo location.LocationRepository, userRepo user.UserRepository, assetLogRepo asset_log.AssetsLogRepository) *DepartmentRestructureService {
+	return &DepartmentRestructureService{repo: repo, departmentRepo: departmentRepo, locationRepo: locationRepo, userRepo: userRepo, assetLogRepo: assetLogRepo}
+}
+
+// Danh sách người dùng, tài sản được tách, lưu trong Payload
+type splitPayload struct {
+	UserIds  []int64 `json:"userIds"`
+	AssetIds []int64 `json:"assetIds"`
+}
+
+// Create tạo bản nháp tái cơ cấu và trả về danh sách dữ liệu sẽ được chuyển, chưa thay đổi gì
+func (service *DepartmentRestructureService) Create(userId int64, request dto.CreateDepartmentRestructureRequest) (*dto.DepartmentRestructureResponse, error) {
+	admin, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	source, err := service.getDepartment(admin.CompanyId, request.SourceDepartmentId)
+	if err != nil {
+		return nil, err
+	}
+	restructure := &entity.DepartmentRestructure{
+		Type:               request.Type,
+		Status:             entity.RestructureDraft,
+		SourceDepartmentId: source.Id,
+		Name:               request.Name,
+		CreatedById:        admin.Id,
+		CreatedAt:          time.Now(),
+		CompanyId:          admin.CompanyId,
+	}
+	switch request.Type {
+	case entity.RestructureMerge:
+		if request.TargetDepartmentId == nil {
+			return nil, errors.New("targetDepartmentId is required to merge")
+		}
+		if *request.TargetDepartmentId == source.Id {
+			return nil, errors.New("can't merge a department into itself")
+		}
+		if _, err := service.getDepartment(admin.CompanyId, *request.TargetDepartmentId); err != nil {
+			return nil, err
+		}
+		restructure.TargetDepartmentId = request.TargetDepartmentId
+		restructure.Name = ""
+	case entity.RestructureSplit:
+		if request.Name == "" {
+			return nil, errors.New("name of the new department is required")
+		}
+		if len(request.UserIds) == 0 && len(request.AssetIds) == 0 {
+			return nil, errors.New("choose users or assets to move into the new department")
+		}
+		locationId := source.LocationId
+		if request.LocationId != nil {
+			location, err := service.locationRepo.GetById(*request.LocationId)
+			if err != nil {
+				return nil, err
+			}
+			if location.CompanyId != admin.CompanyId {
+				return nil, errors.New("can't find location")
+			}
+			locationId = location.Id
+		}
+		restructure.LocationId = &locationId
+		payload, err := json.Marshal(splitPayload{UserIds: request.UserIds, AssetIds: request.AssetIds})
+		if err != nil {
+			return nil, err
+		}
+		restructure.Payload = string(payload)
+	case entity.RestructureRename:
+		if request.Name == "" || request.Name == source.DepartmentName {
+			return nil, errors.New("new name is required and must differ from the current name")
+		}
+		restructure.OldName = source.DepartmentName
+	}
+	if err := service.checkName(restructure, source); err != nil {
+		return nil, err
+	}
+	if _, err := service.repo.Create(restructure, service.repo.GetDB()); err != nil {
+		return nil, err
+	}
+	return service.GetById(userId, restructure.Id)
+}
+
+func (service *DepartmentRestructureService) GetAll(userId int64) ([]dto.DepartmentRestructureResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	restructures, err := service.repo.GetAll(users.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.DepartmentRestructureResponse, 0, len(restructures))
+	for _, r := range restructures {
+		res = append(res, utils.ConvertDepartmentRestructureToResponse(r, nil, false))
+	}
+	return res, nil
+}
+
+// GetById bản nháp trả về dữ liệu dự kiến chuyển tính tại thời điểm xem, đã áp dụng thì trả về dữ liệu đã chuyển
+func (service *DepartmentRestructureService) GetById(userId int64, id int64) (*dto.DepartmentRestructureResponse, error) {
+	restructure, err := service.get(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*entity.DepartmentRestructureItem, 0, len(restructure.Items))
+	if restructure.Status == entity.RestructureDraft {
+		items, err = service.plan(restructure)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for i := range restructure.Items {
+			items = append(items, &restructure.Items[i])
+		}
+	}
+	res := utils.ConvertDepartmentRestructureToResponse(restructure, items, true)
+	return &res, nil
+}
+
+// Apply thực hiện tái cơ cấu trong một transaction, ghi AssetLog cho từng tài sản được chuyển
+func (service *DepartmentRestructureService) Apply(userId int64, id int64) (*dto.DepartmentRestructureResponse, error) {
+	var err error
+	restructure, err := service.get(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if restructure.Status != entity.RestructureDraft {
+		return nil, fmt.Errorf("restructure is already %v", restructure.Status)
+	}
+	admin, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	source, err := service.getDepartment(admin.CompanyId, restructure.SourceDepartmentId)
+	if err != nil {
+		return nil, err
+	}
+	targetName := ""
+	if restructure.Type == entity.RestructureMerge {
+		target, err := service.getDepartment(admin.CompanyId, *restructure.TargetDepartmentId)
+		if err != nil {
+			return nil, err
+		}
+		targetName = target.DepartmentName
+	}
+	if err = service.checkName(restructure, source); err != nil {
+		return nil, err
+	}
+	items, err := service.plan(restructure)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	switch restructure.Type {
+	case entity.RestructureSplit:
+		created := &entity.Departments{DepartmentName: restructure.Name, LocationId: *restructure.LocationId, CompanyId: restructure.CompanyId}
+		if err = service.repo.CreateDepartment(created, tx); err != nil {
+			return nil, err
+		}
+		restructure.TargetDepartmentId = &created.Id
+		targetName = created.DepartmentName
+	case entity.RestructureRename:
+		restructure.OldName = source.DepartmentName
+		if err = service.repo.RenameDepartment(source.Id, restructure.Name, tx); err != nil {
+			return nil, err
+		}
+	}
+	var moved []*entity.DepartmentRestructureItem
+	if restructure.TargetDepartmentId != nil {
+		for _, item := range items {
+			item.ToDepartmentId = *restructure.TargetDepartmentId
+		}
+		summary := fmt.Sprintf("Department restructure #%v (%v): transfer from department %v to department %v by user %v",
+			restructure.Id, restructure.Type, source.DepartmentName, targetName, admin.Email)
+		moved, err = service.move(tx, admin, items, false, summary)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range moved {
+			item.RestructureId = restructure.Id
+		}
+		if err = service.repo.CreateItems(moved, tx); err != nil {
+			return nil, err
+		}
+	}
+	if restructure.Type == entity.RestructureMerge {
+		if err = service.repo.SetMergedInto(source.Id, restructure.TargetDepartmentId, tx); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	deadline := now.Add(time.Duration(config.RestructureUndoHours) * time.Hour)
+	restructure.Status = entity.RestructureApplied
+	restructure.AppliedById = &admin.Id
+	restructure.AppliedAt = &now
+	restructure.UndoDeadline = &deadline
+	if err = service.repo.Update(restructure, tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return service.GetById(userId, id)
+}
+
+// Undo hoàn tác trong thời hạn: chuyển lại các bản ghi chưa bị thay đổi sau khi áp dụng
+func (service *DepartmentRestructureService) Undo(userId int64, id int64) (*dto.DepartmentRestructureResponse, error) {
+	var err error
+	restructure, err := service.get(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if restructure.Status != entity.RestructureApplied {
+		return nil, errors.New("only applied restructures can be undone")
+	}
+	if restructure.UndoDeadline == nil || time.Now().After(*restructure.UndoDeadline) {
+		return nil, errors.New("undo window has expired")
+	}
+	admin, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if restructure.Type == entity.RestructureRename {
+		exists, err := service.repo.NameExists(restructure.CompanyId, restructure.SourceDepartment.LocationId, restructure.OldName, restructure.SourceDepartmentId)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("department %v already exists at this location", restructure.OldName)
+		}
+	}
+	var items []*entity.DepartmentRestructureItem
+	for i := range restructure.Items {
+		if !restructure.Items[i].Reverted {
+			items = append(items, &restructure.Items[i])
+		}
+	}
+
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	targetName := ""
+	if restructure.TargetDepartment != nil {
+		targetName = restructure.TargetDepartment.DepartmentName
+	}
+	summary := fmt.Sprintf("Undo department restructure #%v (%v): transfer from department %v to department %v by user %v",
+		restructure.Id, restructure.Type, targetName, restructure.SourceDepartment.DepartmentName, admin.Email)
+	reverted, err := service.move(tx, admin, items, true, summary)
+	if err != nil {
+		return nil, err
+	}
+	revertedIds := make([]int64, 0, len(reverted))
+	for _, item := range reverted {
+		revertedIds = append(revertedIds, item.Id)
+	}
+	if err = service.repo.MarkItemsReverted(revertedIds, tx); err != nil {
+		return nil, err
+	}
+	switch restructure.Type {
+	case entity.RestructureMerge:
+		if err = service.repo.SetMergedInto(restructure.SourceDepartmentId, nil, tx); err != nil {
+			return nil, err
+		}
+	case entity.RestructureSplit:
+		// Chỉ ẩn phòng ban tách ra khi mọi dữ liệu đã được chuyển về
+		if len(reverted) == len(items) {
+			if err = service.repo.SetMergedInto(*restructure.TargetDepartmentId, &restructure.SourceDepartmentId, tx); err != nil {
+				return nil, err
+			}
+		}
+	case entity.RestructureRename:
+		if err = service.repo.RenameDepartment(restructure.SourceDepartmentId, restructure.OldName, tx); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	restructure.Status = entity.RestructureUndone
+	restructure.UndoneAt = &now
+	if err = service.repo.Update(restructure, tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return service.GetById(userId, id)
+}
+
+func (service *DepartmentRestructureService) GetCompanyId(userId int64) (int64, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return 0, err
+	}
+	return users.CompanyId, nil
+}
+
+// move chuyển các bản ghi theo từng loại, reverse = true thì chuyển từ To về From. Trả về các bản ghi đã chuyển
+func (service *DepartmentRestructureService) move(tx *gorm.DB, admin *entity.Users, items []*entity.DepartmentRestructureItem, reverse bool, summary string) ([]*entity.DepartmentRestructureItem, error) {
+	groups := map[string][]*entity.DepartmentRestructureItem{}
+	var order []string
+	for _, item := range items {
+		if _, ok := groups[item.EntityType]; !ok {
+			order = append(order, item.EntityType)
+		}
+		groups[item.EntityType] = append(groups[item.EntityType], item)
+	}
+	var moved []*entity.DepartmentRestructureItem
+	for _, entityType := range order {
+		group := groups[entityType]
+		from, to := group[0].FromDepartmentId, group[0].ToDepartmentId
+		if reverse {
+			from, to = to, from
+		}
+		ids := make([]int64, 0, len(group))
+		for _, item := range group {
+			ids = append(ids, item.EntityId)
+		}
+		movedIds, err := service.repo.MoveItems(entityType, ids, from, to, tx)
+		if err != nil {
+			return nil, err
+		}
+		isMoved := map[int64]bool{}
+		for _, id := range movedIds {
+			isMoved[id] = true
+		}
+		for _, item := range group {
+			if !isMoved[item.EntityId] {
+				continue
+			}
+			moved = append(moved, item)
+			if entityType != entity.RestructureItemAsset {
+				continue
+			}
+			assetLog := entity.AssetLog{
+				Timestamp:        time.Now(),
+				Action:           "Transfer",
+				AssetId:          item.EntityId,
+				ByUserId:         &admin.Id,
+				CompanyId:        admin.CompanyId,
+				FromDepartmentId: &from,
+				ToDepartmentId:   &to,
+				ChangeSummary:    summary,
+			}
+			if _, err := service.assetLogRepo.Create(&assetLog, tx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return moved, nil
+}
+
+// plan liệt kê dữ liệu sẽ được chuyển theo dữ liệu hiện tại
+func (service *DepartmentRestructureService) plan(restructure *entity.DepartmentRestructure) ([]*entity.DepartmentRestructureItem, error) {
+	switch restructure.Type {
+	case entity.RestructureMerge:
+		items, err := service.repo.GetMovableItems(restructure.SourceDepartmentId, false, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			item.ToDepartmentId = *restructure.TargetDepartmentId
+		}
+		return items, nil
+	case entity.RestructureSplit:
+		var payload splitPayload
+		if err := json.Unmarshal([]byte(restructure.Payload), &payload); err != nil {
+			return nil, err
+		}
+		return service.repo.GetMovableItems(restructure.SourceDepartmentId, true, payload.UserIds, payload.AssetIds)
+	}
+	return []*entity.DepartmentRestructureItem{}, nil
+}
+
+// checkName tên phòng ban mới không được trùng trong cùng vị trí
+func (service *DepartmentRestructureService) checkName(restructure *entity.DepartmentRestructure, source *entity.Departments) error {
+	var exists bool
+	var err error
+	switch restructure.Type {
+	case entity.RestructureSplit:
+		exists, err = service.repo.NameExists(restructure.CompanyId, *restructure.LocationId, restructure.Name, 0)
+	case entity.RestructureRename:
+		exists, err = service.repo.NameExists(restructure.CompanyId, source.LocationId, restructure.Name, source.Id)
+	}
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("department %v already exists at this location", restructure.Name)
+	}
+	return nil
+}
+
+func (service *DepartmentRestructureService) getDepartment(companyId int64, id int64) (*entity.Departments, error) {
+	department, err := service.departmentRepo.GetDepartmentById(id)
+	if err != nil {
+		return nil, err
+	}
+	if department.CompanyId != companyId {
+		return nil, errors.New("can't find department")
+	}
+	if department.MergedIntoId != nil {
+		return nil, fmt.Errorf("department %v has been merged into another department", department.DepartmentName)
+	}
+	return department, nil
+}
+
+func (service *DepartmentRestructureService) get(userId int64, id int64) (*entity.DepartmentRestructure, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	restructure, err := service.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if restructure.CompanyId != users.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	return restructure, nil
+}
@@ -4,18 +4,21 @@ import (
 	"BE_Manage_device/internal/domain/entity"
 	company "BE_Manage_device/internal/repository/company"
 	department "BE_Manage_device/internal/repository/departments"
+	location "BE_Manage_device/internal/repository/locations"
 	user "BE_Manage_device/internal/repository/user"
 	"BE_Manage_device/pkg/utils"
+	"errors"
 )
 
 type DepartmentsService struct {
-	repo        department.DepartmentsRepository
-	userRepo    user.UserRepository
-	companyRepo company.CompanyRepository
+	repo         department.DepartmentsRepository
+	userRepo     user.UserRepository
+	companyRepo  company.CompanyRepository
+	locationRepo location.LocationRepository
 }
 
-func NewDepartmentsService(repo department.DepartmentsRepository, userRepo user.UserRepository, companyRepo company.CompanyRepository) *DepartmentsService {
-	return &DepartmentsService{repo: repo, userRepo: userRepo, companyRepo: companyRepo}
+func NewDepartmentsService(repo department.DepartmentsRepository, userRepo user.UserRepository, companyRepo company.CompanyRepository, locationRepo location.LocationRepository) *DepartmentsService {
+	return &DepartmentsService{repo: repo, userRepo: userRepo, companyRepo: companyRepo, locationRepo: locationRepo}
 }
 
 func (service *DepartmentsService) Create(userId int64, departmentsName string, locationId int64) (*entity.Departments, error) {
@@ -27,6 +30,13 @@ func (service *DepartmentsService) Create(userId int64, departmentsName string,
 	if err != nil {
 		return nil, err
 	}
+	location, err := service.locationRepo.GetById(locationId)
+	if err != nil {
+		return nil, err
+	}
+	if location.CompanyId != company.Id {
+		return nil, errors.New("can't find location")
+	}
 	var departments = &entity.Departments{
 		DepartmentName: departmentsName,
 		LocationId:     locationId,
@@ -52,7 +62,15 @@ func (service *DepartmentsService) GetAll(userId int64) ([]*entity.Departments,
 }
 
 func (service *DepartmentsService) Delete(id int64) error {
-	err := service.repo.Delete(id)
+	// Còn dữ liệu thì phải gộp sang phòng ban khác trước khi xoá
+	inUse, err := service.repo.HasReferences(id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("department still has users, assets or pending requests, merge it into another department first")
+	}
+	err = service.repo.Delete(id)
 	return err
 }
 
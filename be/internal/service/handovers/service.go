@@ -0,0 +1,212 @@
+package service
+
+import (
+	"BE_Manage_device/config"
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	handover "BE_Manage_device/internal/repository/handovers"
+	user "BE_Manage_device/internal/repository/user"
+	notificationS "BE_Manage_device/internal/service/notification"
+	"BE_Manage_device/pkg/utils"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type HandoverService struct {
+	repo                handover.HandoverRepository
+	userRepo            user.UserRepository
+	NotificationService *notificationS.NotificationService
+}
+
+func NewHandoverService(repo handover.HandoverRepository, userRepo user.UserRepository, NotificationService *notificationS.NotificationService) *HandoverService {
+	return &HandoverService{repo: repo, userRepo: userRepo, NotificationService: NotificationService}
+}
+
+// GetMine danh sách bàn giao mà người dùng là người nhận
+func (service *HandoverService) GetMine(userId int64, status *string) ([]dto.HandoverResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	handovers, err := service.repo.Filter(users.CompanyId, &userId, status, nil)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ConvertHandoversToResponses(handovers), nil
+}
+
+// Filter danh sách bàn giao trong công ty cho người quản lý
+func (service *HandoverService) Filter(userId int64, request dto.HandoverFilterRequest) ([]dto.HandoverResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	handovers, err := service.repo.Filter(users.CompanyId, nil, request.Status, request.AssetId)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ConvertHandoversToResponses(handovers), nil
+}
+
+// GetById người nhận hoặc người có quyền giao tài sản mới xem được
+func (service *HandoverService) GetById(userId int64, id int64) (*dto.HandoverResponse, error) {
+	handover, err := service.get(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if handover.UserId != userId {
+		ok, err := utils.UserHasPermission(service.repo.GetDB(), userId, []string{"assign-assets"}, []string{"full", "conditional"})
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("can't find record this id")
+		}
+	}
+	res := utils.ConvertHandoverToResponse(handover)
+	return &res, nil
+}
+
+// Accept người nhận ký xác nhận đã nhận tài sản. Lưu ảnh chữ ký, sinh biên bản PDF có ký HMAC
+// và gắn url biên bản vào assignment
+func (service *HandoverService) Accept(userId int64, id int64, ip string, request dto.AcceptHandoverRequest) (*dto.HandoverResponse, error) {
+	var err error
+	handover, err := service.get(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if handover.UserId != userId {
+		return nil, errors.New("only the recipient can accept this handover")
+	}
+	if handover.Status != entity.HandoverPending {
+		return nil, fmt.Errorf("handover is already %v", handover.Status)
+	}
+	image, contentType, err := utils.DecodeImageDataUrl(request.Signature)
+	if err != nil {
+		return nil, err
+	}
+	imageType := ""
+	switch contentType {
+	case "image/png":
+		imageType = "PNG"
+	case "image/jpeg", "image/jpg":
+		imageType = "JPG"
+	default:
+		return nil, errors.New("signature must be a png or jpeg image")
+	}
+	now := time.Now()
+	handover.SignatureUrl, err = utils.UploadImageDataUrl(fmt.Sprintf("handovers/%v/%v_signature", handover.Id, now.UnixNano()), request.Signature)
+	if err != nil {
+		return nil, err
+	}
+	handover.Status = entity.HandoverAccepted
+	handover.AcceptedAt = &now
+	handover.IpAddress = ip
+	handover.ConditionGrade = request.ConditionGrade
+	handover.ConditionNote = request.ConditionNote
+
+	imageHash := sha256.Sum256(image)
+	document := buildReceipt(handover, hex.EncodeToString(imageHash[:]))
+	handover.Signature = utils.SignDocument(document.Content())
+	document.SignatureImage = image
+	document.SignatureImageType = imageType
+	receipt, err := utils.GenerateSignedPDF(document, handover.Signature)
+	if err != nil {
+		return nil, err
+	}
+	handover.ReceiptUrl, err = utils.NewSupabaseUploader().UploadReader(fmt.Sprintf("handovers/%v/%v_receipt.pdf", handover.Id, now.UnixNano()), bytes.NewReader(receipt), "application/pdf")
+	if err != nil {
+		return nil, err
+	}
+
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if err = service.repo.Accept(handover, tx); err != nil {
+		return nil, err
+	}
+	if err = service.repo.UpdateAssignmentReceipt(handover.AssignmentId, &handover.ReceiptUrl, tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+
+	message := fmt.Sprintf("%v %v acknowledged receipt of asset %v", handover.User.FirstName, handover.User.LastName, handover.Asset.AssetName)
+	usersToNotifications := utils.ConvertUsersToNotificationsToMap(userId, []*entity.Users{&handover.AssignedBy})
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Println("SendNotificationToUsers panic:", r)
+			}
+		}()
+		service.NotificationService.SendNotificationToUsers(usersToNotifications, message, handover.Asset)
+	}()
+	res := utils.ConvertHandoverToResponse(handover)
+	return &res, nil
+}
+
+// SendReminders nhắc người nhận các bàn giao chưa xác nhận quá HandoverReminderHours, mỗi kỳ nhắc một lần
+func (service *HandoverService) SendReminders() {
+	before := time.Now().Add(-time.Duration(config.HandoverReminderHours) * time.Hour)
+	handovers, err := service.repo.GetPendingBefore(before)
+	if err != nil {
+		log.Error("Happened error when get pending handovers. Error = ", err)
+		return
+	}
+	for _, h := range handovers {
+		message := fmt.Sprintf("Please acknowledge receipt of asset %v assigned to you on %v", h.Asset.AssetName, utils.FormatDocumentTime(h.CreatedAt))
+		usersToNotifications := utils.ConvertUsersToNotificationsToMap(h.AssignedById, []*entity.Users{&h.User})
+		service.NotificationService.SendNotificationToUsers(usersToNotifications, message, h.Asset)
+		if err := service.repo.UpdateLastReminder(h.Id, time.Now()); err != nil {
+			log.Error("Happened error when update handover reminder. Error = ", err)
+		}
+	}
+}
+
+func (service *HandoverService) get(userId int64, id int64) (*entity.HandoverAcknowledgement, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	handover, err := service.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if handover.CompanyId != users.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	return handover, nil
+}
+
+// buildReceipt nội dung biên bản bàn giao. Ảnh chữ ký được ký qua mã băm để nội dung dựng lại được từ DB
+func buildReceipt(handover *entity.HandoverAcknowledgement, signatureImageHash string) utils.SignedDocument {
+	return utils.SignedDocument{
+		Title: "Asset handover receipt",
+		Fields: [][2]string{
+			{"Handover", fmt.Sprintf("#%v", handover.Id)},
+			{"Asset", fmt.Sprintf("%v (#%v)", handover.Asset.AssetName, handover.AssetId)},
+			{"Serial number", handover.Asset.SerialNumber},
+			{"Recipient", fmt.Sprintf("%v %v (%v)", handover.User.FirstName, handover.User.LastName, handover.User.Email)},
+			{"Assigned by", fmt.Sprintf("%v %v", handover.AssignedBy.FirstName, handover.AssignedBy.LastName)},
+			{"Assigned at", utils.FormatDocumentTime(handover.CreatedAt)},
+			{"Accepted at", utils.FormatDocumentTime(*handover.AcceptedAt)},
+			{"IP address", handover.IpAddress},
+			{"Condition grade", handover.ConditionGrade},
+			{"Condition note", handover.ConditionNote},
+			{"Signature image SHA-256", signatureImageHash},
+		},
+	}
+}
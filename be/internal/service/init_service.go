@@ -1,45 +1,92 @@
 package service
 
 import (
+	"BE_Manage_device/internal/domain/entity"
 	"BE_Manage_device/internal/repository"
 	assetS "BE_Manage_device/internal/service/asset"
 	assetLogS "BE_Manage_device/internal/service/asset_log"
+	assetRelationS "BE_Manage_device/internal/service/asset_relation"
+	assetTemplateS "BE_Manage_device/internal/service/asset_template"
 	assignmentS "BE_Manage_device/internal/service/assignment"
 	bill "BE_Manage_device/internal/service/bill"
+	calendarFeedS "BE_Manage_device/internal/service/calendar_feeds"
 	categoriesS "BE_Manage_device/internal/service/categories"
+	chargebackS "BE_Manage_device/internal/service/chargeback"
 	company "BE_Manage_device/internal/service/company"
+	departmentRestructureS "BE_Manage_device/internal/service/department_restructures"
 	departmentS "BE_Manage_device/internal/service/departments"
 	emailS "BE_Manage_device/internal/service/email"
+	handoverS "BE_Manage_device/internal/service/handovers"
+	inspectionS "BE_Manage_device/internal/service/inspections"
+	issueTicketS "BE_Manage_device/internal/service/issue_tickets"
+	ldapS "BE_Manage_device/internal/service/ldap"
 	locationS "BE_Manage_device/internal/service/location"
+	maintenancePlanS "BE_Manage_device/internal/service/maintenance_plans"
 	maintenanceSchedulesS "BE_Manage_device/internal/service/maintenance_schedules"
+	meterS "BE_Manage_device/internal/service/meters"
 	MonthlySummary "BE_Manage_device/internal/service/monthly_summary"
 	notificationS "BE_Manage_device/internal/service/notification"
+	offboardingS "BE_Manage_device/internal/service/offboarding"
+	reliabilityS "BE_Manage_device/internal/service/reliability"
 	requestTransferS "BE_Manage_device/internal/service/request_transfer"
 	roleS "BE_Manage_device/internal/service/role"
+	scimS "BE_Manage_device/internal/service/scim"
+	serviceAccountS "BE_Manage_device/internal/service/service_accounts"
+	ssoS "BE_Manage_device/internal/service/sso"
+	tagS "BE_Manage_device/internal/service/tag"
+	tcoS "BE_Manage_device/internal/service/tco"
+	twoFactorS "BE_Manage_device/internal/service/two_factor"
 	userS "BE_Manage_device/internal/service/user"
+	workOrderS "BE_Manage_device/internal/service/work_orders"
+	workflowS "BE_Manage_device/internal/service/workflows"
 )
 
 type Services struct {
-	User                 *userS.UserService
-	Location             *locationS.LocationService
-	Categories           *categoriesS.CategoriesService
-	Department           *departmentS.DepartmentsService
-	Assets               *assetS.AssetsService
-	Role                 *roleS.RoleService
-	Assignment           *assignmentS.AssignmentService
-	AssetLog             *assetLogS.AssetLogService
-	RequestTransfer      *requestTransferS.RequestTransferService
-	MaintenanceSchedules *maintenanceSchedulesS.MaintenanceSchedulesService
-	Notification         *notificationS.NotificationService
-	Email                *emailS.EmailService
-	Company              *company.CompanyService
-	Bill                 *bill.BillsService
-	MonthlySummary       *MonthlySummary.MonthlySummaryService
+	User                  *userS.UserService
+	Location              *locationS.LocationService
+	Categories            *categoriesS.CategoriesService
+	Department            *departmentS.DepartmentsService
+	Assets                *assetS.AssetsService
+	Role                  *roleS.RoleService
+	Assignment            *assignmentS.AssignmentService
+	AssetLog              *assetLogS.AssetLogService
+	RequestTransfer       *requestTransferS.RequestTransferService
+	MaintenanceSchedules  *maintenanceSchedulesS.MaintenanceSchedulesService
+	Notification          *notificationS.NotificationService
+	Email                 *emailS.EmailService
+	Company               *company.CompanyService
+	Bill                  *bill.BillsService
+	MonthlySummary        *MonthlySummary.MonthlySummaryService
+	AssetTemplate         *assetTemplateS.AssetTemplateService
+	Tag                   *tagS.TagService
+	AssetRelation         *assetRelationS.AssetRelationService
+	Chargeback            *chargebackS.ChargebackService
+	Tco                   *tcoS.TcoService
+	MaintenancePlan       *maintenancePlanS.MaintenancePlanService
+	WorkOrder             *workOrderS.WorkOrderService
+	CalendarFeed          *calendarFeedS.CalendarFeedService
+	Reliability           *reliabilityS.ReliabilityService
+	IssueTicket           *issueTicketS.IssueTicketService
+	Inspection            *inspectionS.InspectionService
+	Meter                 *meterS.MeterService
+	Workflow              *workflowS.WorkflowService
+	Offboarding           *offboardingS.OffboardingService
+	Handover              *handoverS.HandoverService
+	DepartmentRestructure *departmentRestructureS.DepartmentRestructureService
+	TwoFactor             *twoFactorS.TwoFactorService
+	Sso                   *ssoS.SsoService
+	Ldap                  *ldapS.LdapService
+	Scim                  *scimS.ScimService
+	ServiceAccount        *serviceAccountS.ServiceAccountService
 }
 
 func NewServices(repos *repository.Repository, emailPass string) *Services {
 	emailService := emailS.NewEmailService(emailPass)
 	notificationService := notificationS.NewNotificationService(repos.Notification)
+	workflowService := workflowS.NewWorkflowService(repos.Workflow, repos.User, notificationService)
+	twoFactorService := twoFactorS.NewTwoFactorService(repos.TwoFactor, repos.User, repos.Company, repos.AuditLog)
+	ldapService := ldapS.NewLdapService(repos.Ldap, repos.User, repos.Location, repos.Role, repos.AuditLog, repos.UserRBAC)
+	ssoService := ssoS.NewSsoService(repos.Sso, repos.User, repos.Company, repos.Department, repos.Role, repos.AuditLog)
 
 	assignmentService := assignmentS.NewAssignmentService(
 		repos.Assignment,
@@ -47,24 +94,55 @@ func NewServices(repos *repository.Repository, emailPass string) *Services {
 		repos.Assets,
 		repos.Department,
 		repos.User,
+		repos.Handover,
 		notificationService,
 	)
 
+	assetsService := assetS.NewAssetsService(repos.Assets, repos.AssetsLog, repos.Role, repos.UserRBAC, repos.User, repos.Assignment, repos.Department, notificationService, repos.Company, repos.Location, repos.AssetRelation, workflowService)
+	requestTransferService := requestTransferS.NewRequestTransferService(repos.RequestTransfer, assignmentService, repos.User, repos.Assets, repos.Categories, notificationService, workflowService)
+	maintenanceSchedulesService := maintenanceSchedulesS.NewMaintenanceSchedulesService(repos.MaintenanceSchedules, repos.Assets, repos.User, notificationService, workflowService)
+
+	// Nghiệp vụ được thực hiện khi quy trình duyệt kết thúc
+	workflowService.RegisterHandler(entity.WorkflowEntityTransferRequest, requestTransferService)
+	workflowService.RegisterHandler(entity.WorkflowEntityAssetRetirement, assetsService)
+	workflowService.RegisterHandler(entity.WorkflowEntityMaintenanceSchedule, maintenanceSchedulesService)
+
 	return &Services{
-		User:                 userS.NewUserService(repos.User, emailService, repos.UserSession, repos.Role, repos.Assets, repos.UserRBAC, repos.Company),
-		Location:             locationS.NewLocationService(repos.Location),
-		Categories:           categoriesS.NewCategoriesService(repos.Categories, repos.User, repos.Company),
-		Department:           departmentS.NewDepartmentsService(repos.Department, repos.User, repos.Company),
-		Assets:               assetS.NewAssetsService(repos.Assets, repos.AssetsLog, repos.Role, repos.UserRBAC, repos.User, repos.Assignment, repos.Department, notificationService, repos.Company),
-		Role:                 roleS.NewRoleService(repos.Role),
-		Assignment:           assignmentService,
-		AssetLog:             assetLogS.NewAssetLogService(repos.AssetsLog, repos.User, repos.Role, repos.Assets),
-		RequestTransfer:      requestTransferS.NewRequestTransferService(repos.RequestTransfer, assignmentService, repos.User, repos.Assets),
-		MaintenanceSchedules: maintenanceSchedulesS.NewMaintenanceSchedulesService(repos.MaintenanceSchedules, repos.Assets, repos.User, notificationService),
-		Notification:         notificationService,
-		Email:                emailService,
-		Company:              company.NewCompanyService(repos.Company),
-		Bill:                 bill.NewBillService(repos.Bill, repos.Assets, repos.User),
-		MonthlySummary:       MonthlySummary.NewMonthlySummaryService(repos.MonthlySummary, repos.Bill, repos.User),
+		User:                  userS.NewUserService(repos.User, emailService, repos.UserSession, repos.Role, repos.Assets, repos.UserRBAC, repos.Company, twoFactorService, ssoService, ldapService),
+		Location:              locationS.NewLocationService(repos.Location, repos.User, repos.Company),
+		Categories:            categoriesS.NewCategoriesService(repos.Categories, repos.User, repos.Company),
+		Department:            departmentS.NewDepartmentsService(repos.Department, repos.User, repos.Company, repos.Location),
+		Assets:                assetsService,
+		Role:                  roleS.NewRoleService(repos.Role),
+		Assignment:            assignmentService,
+		AssetLog:              assetLogS.NewAssetLogService(repos.AssetsLog, repos.User, repos.Role, repos.Assets),
+		RequestTransfer:       requestTransferService,
+		MaintenanceSchedules:  maintenanceSchedulesService,
+		Notification:          notificationService,
+		Email:                 emailService,
+		Company:               company.NewCompanyService(repos.Company),
+		Bill:                  bill.NewBillService(repos.Bill, repos.Assets, repos.User),
+		MonthlySummary:        MonthlySummary.NewMonthlySummaryService(repos.MonthlySummary, repos.Bill, repos.User),
+		AssetTemplate:         assetTemplateS.NewAssetTemplateService(repos.AssetTemplate, repos.User, repos.Company, repos.Categories, assetsService),
+		Tag:                   tagS.NewTagService(repos.Tag, repos.Assets, repos.User, repos.AssetsLog, assetsService),
+		AssetRelation:         assetRelationS.NewAssetRelationService(repos.AssetRelation, repos.Assets, repos.User, repos.AssetsLog),
+		Chargeback:            chargebackS.NewChargebackService(repos.Chargeback, repos.Department, repos.User, repos.Company),
+		Tco:                   tcoS.NewTcoService(repos.Tco, repos.User),
+		MaintenancePlan:       maintenancePlanS.NewMaintenancePlanService(repos.MaintenancePlan, repos.Assets, repos.Categories, repos.User),
+		WorkOrder:             workOrderS.NewWorkOrderService(repos.WorkOrder, repos.Consumable, repos.MaintenanceSchedules, repos.Assets, repos.User, repos.AssetsLog, notificationService),
+		CalendarFeed:          calendarFeedS.NewCalendarFeedService(repos.CalendarFeed, repos.Department, repos.User),
+		Reliability:           reliabilityS.NewReliabilityService(repos.Reliability, repos.User),
+		IssueTicket:           issueTicketS.NewIssueTicketService(repos.IssueTicket, repos.Assets, repos.MaintenanceSchedules, repos.User, repos.AssetsLog, notificationService),
+		Inspection:            inspectionS.NewInspectionService(repos.Inspection, repos.Assets, repos.Categories, repos.MaintenanceSchedules, repos.User, repos.AssetsLog, notificationService),
+		Meter:                 meterS.NewMeterService(repos.Meter, repos.Assets, repos.Categories, repos.MaintenanceSchedules, repos.User, repos.AssetsLog, notificationService),
+		Workflow:              workflowService,
+		Offboarding:           offboardingS.NewOffboardingService(repos.Offboarding, repos.Assignment, repos.Assets, repos.AssetsLog, repos.User, repos.Department, workflowService, notificationService),
+		Handover:              handoverS.NewHandoverService(repos.Handover, repos.User, notificationService),
+		DepartmentRestructure: departmentRestructureS.NewDepartmentRestructureService(repos.DepartmentRestructure, repos.Department, repos.Location, repos.User, repos.AssetsLog),
+		TwoFactor:             twoFactorService,
+		Sso:                   ssoService,
+		Ldap:                  ldapService,
+		Scim:                  scimS.NewScimService(repos.Scim, repos.User, repos.UserSession, repos.Department, repos.Location, repos.Role, repos.AuditLog, repos.UserRBAC),
+		ServiceAccount:        serviceAccountS.NewServiceAccountService(repos.ServiceAccount, repos.User, repos.Role, repos.Department, repos.AuditLog, repos.UserRBAC),
 	}
 }
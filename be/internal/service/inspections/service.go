@@ -0,0 +1,411 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	asset_log "BE_Manage_device/internal/repository/asset_log"
+	asset "BE_Manage_device/internal/repository/assets"
+	categories "BE_Manage_device/internal/repository/categories"
+	inspection "BE_Manage_device/internal/repository/inspections"
+	maintenanceSchedules "BE_Manage_device/internal/repository/maintenance_schedules"
+	user "BE_Manage_device/internal/repository/user"
+	notificationS "BE_Manage_device/internal/service/notification"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type InspectionService struct {
+	repo                inspection.InspectionRepository
+	assetRepo           asset.AssetsRepository
+	categoryRepo        categories.CategoriesRepository
+	scheduleRepo        maintenanceSchedules.MaintenanceSchedulesRepository
+	userRepo            user.UserRepository
+	assetLogRepo        asset_log.AssetsLogRepository
+	NotificationService *notificationS.NotificationService
+}
+
+func NewInspectionService(repo inspection.InspectionRepository, assetRepo asset.AssetsRepository, categoryRepo categories.CategoriesRepository, scheduleRepo maintenanceSchedules.MaintenanceSchedulesRepository, userRepo user.UserRepository, assetLogRepo asset_log.AssetsLogRepository, NotificationService *notificationS.NotificationService) *InspectionService {
+	return &InspectionService{repo: repo, assetRepo: assetRepo, categoryRepo: categoryRepo, scheduleRepo: scheduleRepo, userRepo: userRepo, assetLogRepo: assetLogRepo, NotificationService: NotificationService}
+}
+
+func (service *InspectionService) CreateTemplate(userId int64, request dto.InspectionTemplateRequest) (*entity.InspectionTemplate, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.checkCategory(user.CompanyId, request.CategoryId); err != nil {
+		return nil, err
+	}
+	template := &entity.InspectionTemplate{
+		Name:         request.Name,
+		CategoryId:   request.CategoryId,
+		IntervalDays: request.IntervalDays,
+		IsActive:     request.IsActive == nil || *request.IsActive,
+		CreatedById:  userId,
+		CreatedAt:    time.Now(),
+		CompanyId:    user.CompanyId,
+		Items:        toTemplateItems(request.Items),
+	}
+	if _, err := service.repo.CreateTemplate(template, service.repo.GetDB()); err != nil {
+		return nil, err
+	}
+	return service.repo.GetTemplateById(template.Id)
+}
+
+func (service *InspectionService) GetTemplates(userId int64, categoryId *int64) ([]*entity.InspectionTemplate, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	return service.repo.GetTemplates(user.CompanyId, categoryId)
+}
+
+func (service *InspectionService) GetTemplateById(userId int64, id int64) (*entity.InspectionTemplate, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	template, err := service.repo.GetTemplateById(id)
+	if err != nil {
+		return nil, err
+	}
+	if template.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	return template, nil
+}
+
+// UpdateTemplate thay toàn bộ danh sách mục, kết quả các lần kiểm tra cũ không bị ảnh hưởng
+func (service *InspectionService) UpdateTemplate(userId int64, id int64, request dto.InspectionTemplateRequest) (*entity.InspectionTemplate, error) {
+	template, err := service.GetTemplateById(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.checkCategory(template.CompanyId, request.CategoryId); err != nil {
+		return nil, err
+	}
+	template.Name = request.Name
+	template.CategoryId = request.CategoryId
+	template.IntervalDays = request.IntervalDays
+	if request.IsActive != nil {
+		template.IsActive = *request.IsActive
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if err = service.repo.UpdateTemplate(template, tx); err != nil {
+		return nil, err
+	}
+	if err = service.repo.ReplaceTemplateItems(template.Id, toTemplateItems(request.Items), tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return service.repo.GetTemplateById(template.Id)
+}
+
+func (service *InspectionService) DeleteTemplate(userId int64, id int64) error {
+	template, err := service.GetTemplateById(userId, id)
+	if err != nil {
+		return err
+	}
+	return service.repo.DeleteTemplate(template.Id)
+}
+
+// Create ghi nhận một lần kiểm tra. Có mục không đạt thì tự tạo lịch bảo trì từ ngày mai và báo cho người quản lý tài sản
+func (service *InspectionService) Create(userId int64, assetId int64, request dto.CreateInspectionRequest) (*entity.Inspection, error) {
+	inspector, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := service.assetRepo.GetAssetById(assetId)
+	if err != nil {
+		return nil, err
+	}
+	if asset.CompanyId != inspector.CompanyId {
+		return nil, errors.New("asset not found")
+	}
+	if asset.Status == "Disposed" || asset.Status == "Retired" {
+		return nil, errors.New("can't inspect asset because status")
+	}
+	template, err := service.repo.GetTemplateById(request.TemplateId)
+	if err != nil {
+		return nil, err
+	}
+	if template.CompanyId != asset.CompanyId || !template.IsActive {
+		return nil, errors.New("inspection template not found")
+	}
+	if template.CategoryId != asset.CategoryId {
+		return nil, errors.New("inspection template does not apply to this asset category")
+	}
+	results := map[int64]dto.InspectionItemResultRequest{}
+	for _, item := range request.Items {
+		results[item.TemplateItemId] = item
+	}
+	passed := true
+	items := []entity.InspectionItemResult{}
+	failedLabels := []string{}
+	for _, item := range template.Items {
+		result, ok := results[item.Id]
+		if !ok {
+			return nil, fmt.Errorf("missing result for item %q", item.Label)
+		}
+		delete(results, item.Id)
+		items = append(items, entity.InspectionItemResult{TemplateItemId: item.Id, Label: item.Label, Passed: *result.Passed, Note: result.Note})
+		if !*result.Passed {
+			passed = false
+			failedLabels = append(failedLabels, item.Label)
+		}
+	}
+	if len(results) > 0 {
+		return nil, errors.New("result contains items not in the template")
+	}
+
+	now := time.Now()
+	inspectorSignature, err := utils.UploadImageDataUrl(fmt.Sprintf("inspections/%d/%d_inspector", asset.Id, now.UnixNano()), request.InspectorSignature)
+	if err != nil {
+		return nil, err
+	}
+	var witnessSignature *string
+	if request.WitnessSignature != nil && *request.WitnessSignature != "" {
+		url, err := utils.UploadImageDataUrl(fmt.Sprintf("inspections/%d/%d_witness", asset.Id, now.UnixNano()), *request.WitnessSignature)
+		if err != nil {
+			return nil, err
+		}
+		witnessSignature = &url
+	}
+	inspection := &entity.Inspection{
+		AssetId:            asset.Id,
+		TemplateId:         template.Id,
+		InspectorId:        userId,
+		InspectedAt:        now,
+		Passed:             passed,
+		ConditionGrade:     request.ConditionGrade,
+		Notes:              request.Notes,
+		InspectorSignature: inspectorSignature,
+		WitnessName:        request.WitnessName,
+		WitnessSignature:   witnessSignature,
+		CompanyId:          asset.CompanyId,
+		Items:              items,
+	}
+
+	// Tài sản đang bảo trì hoặc đã có lịch trùng thì không tạo thêm
+	var schedule *entity.MaintenanceSchedules
+	if !passed && asset.Status != "Under Maintenance" {
+		start := startOfTomorrow()
+		overlap := false
+		timeRange, err := service.scheduleRepo.GetDateMaintenanceSchedulesInFuture(asset.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range timeRange {
+			if !(start.Before(r.Start) || start.After(r.End)) {
+				overlap = true
+			}
+		}
+		if !overlap {
+			schedule = &entity.MaintenanceSchedules{AssetId: asset.Id, StartDate: start, EndDate: start}
+		}
+	}
+
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if schedule != nil {
+		if _, err = service.repo.CreateSchedule(schedule, tx); err != nil {
+			return nil, err
+		}
+		inspection.ScheduleId = &schedule.Id
+	}
+	if _, err = service.repo.Create(inspection, tx); err != nil {
+		return nil, err
+	}
+	summary := fmt.Sprintf("Inspection #%d (%v) by %v: grade %v, passed", inspection.Id, template.Name, inspector.Email, inspection.ConditionGrade)
+	if !passed {
+		summary = fmt.Sprintf("Inspection #%d (%v) by %v: grade %v, failed items: %v", inspection.Id, template.Name, inspector.Email, inspection.ConditionGrade, strings.Join(failedLabels, ", "))
+		if schedule != nil {
+			summary += fmt.Sprintf(", maintenance schedule #%d created", schedule.Id)
+		}
+	}
+	assetLog := entity.AssetLog{
+		Action:        "Inspection",
+		Timestamp:     now,
+		ByUserId:      &userId,
+		AssetId:       asset.Id,
+		ChangeSummary: summary,
+		CompanyId:     asset.CompanyId,
+	}
+	if _, err = service.assetLogRepo.Create(&assetLog, tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	if !passed {
+		userManagerAsset, _ := service.userRepo.GetUserAssetManageOfDepartment(asset.DepartmentId)
+		userHeadDepart, _ := service.userRepo.GetUserHeadDepartment(asset.DepartmentId)
+		usersToNotifications := utils.ConvertUsersToNotificationsToMap(userId, []*entity.Users{userManagerAsset, userHeadDepart})
+		message := fmt.Sprintf("The asset (ID: %v) failed inspection: %v", asset.Id, strings.Join(failedLabels, ", "))
+		if schedule != nil {
+			message += fmt.Sprintf(". Maintenance schedule (ID: %v) has been created", schedule.Id)
+		}
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Println("SendNotificationToUsers panic:", r)
+				}
+			}()
+			service.NotificationService.SendNotificationToUsers(usersToNotifications, message, *asset)
+		}()
+	}
+	return service.repo.GetById(inspection.Id)
+}
+
+func (service *InspectionService) GetById(userId int64, id int64) (*entity.Inspection, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	inspection, err := service.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if inspection.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	return inspection, nil
+}
+
+func (service *InspectionService) GetByAsset(userId int64, assetId int64) ([]*entity.Inspection, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := service.assetRepo.GetAssetById(assetId)
+	if err != nil {
+		return nil, err
+	}
+	if asset.CompanyId != user.CompanyId {
+		return nil, errors.New("asset not found")
+	}
+	return service.repo.GetByAsset(assetId)
+}
+
+// GetOverdue tài sản đã quá hạn kiểm tra theo từng template đang hoạt động.
+// Chưa kiểm tra lần nào thì hạn tính từ ngày đưa vào sử dụng (hoặc ngày mua)
+func (service *InspectionService) GetOverdue(userId int64, request dto.InspectionOverdueRequest) ([]*dto.InspectionOverdueResponse, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	departmentId, ok := utils.ViewerDepartmentScope(user, request.DepartmentId)
+	if !ok {
+		return []*dto.InspectionOverdueResponse{}, nil
+	}
+	templates, err := service.repo.GetTemplates(user.CompanyId, request.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+	templatesByCategory := map[int64][]*entity.InspectionTemplate{}
+	templateIds := []int64{}
+	categoryIds := []int64{}
+	for _, t := range templates {
+		if !t.IsActive {
+			continue
+		}
+		if _, ok := templatesByCategory[t.CategoryId]; !ok {
+			categoryIds = append(categoryIds, t.CategoryId)
+		}
+		templatesByCategory[t.CategoryId] = append(templatesByCategory[t.CategoryId], t)
+		templateIds = append(templateIds, t.Id)
+	}
+	assets, err := service.repo.GetAssetsOfCategories(user.CompanyId, categoryIds, departmentId)
+	if err != nil {
+		return nil, err
+	}
+	lastRows, err := service.repo.GetLastInspections(templateIds)
+	if err != nil {
+		return nil, err
+	}
+	last := map[[2]int64]time.Time{}
+	for _, r := range lastRows {
+		last[[2]int64{r.AssetId, r.TemplateId}] = r.LastInspected
+	}
+	now := time.Now()
+	res := []*dto.InspectionOverdueResponse{}
+	for _, a := range assets {
+		for _, t := range templatesByCategory[a.CategoryId] {
+			var lastInspectedAt *time.Time
+			base := a.PurchaseDate
+			if a.AcquisitionDate != nil {
+				base = *a.AcquisitionDate
+			}
+			if l, ok := last[[2]int64{a.Id, t.Id}]; ok {
+				lastInspectedAt = &l
+				base = l
+			}
+			due := base.AddDate(0, 0, t.IntervalDays)
+			if !due.Before(now) {
+				continue
+			}
+			res = append(res, &dto.InspectionOverdueResponse{
+				AssetId:         a.Id,
+				AssetName:       a.AssetName,
+				SerialNumber:    a.SerialNumber,
+				DepartmentId:    a.DepartmentId,
+				DepartmentName:  a.Department.DepartmentName,
+				TemplateId:      t.Id,
+				TemplateName:    t.Name,
+				LastInspectedAt: lastInspectedAt,
+				DueDate:         due,
+				DaysOverdue:     int(now.Sub(due).Hours() / 24),
+			})
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].DaysOverdue > res[j].DaysOverdue })
+	return res, nil
+}
+
+func (service *InspectionService) checkCategory(companyId int64, categoryId int64) error {
+	categories, err := service.categoryRepo.GetAll(companyId)
+	if err != nil {
+		return err
+	}
+	for _, c := range categories {
+		if c.Id == categoryId {
+			return nil
+		}
+	}
+	return errors.New("category not found")
+}
+
+func toTemplateItems(items []dto.InspectionTemplateItemRequest) []entity.InspectionTemplateItem {
+	res := make([]entity.InspectionTemplateItem, 0, len(items))
+	for i, item := range items {
+		res = append(res, entity.InspectionTemplateItem{Label: item.Label, Description: item.Description, SortOrder: i + 1})
+	}
+	return res
+}
+
+func startOfTomorrow() time.Time {
+	loc, _ := time.LoadLocation("Asia/Bangkok") // GMT+7
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,381 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	asset_log "BE_Manage_device/internal/repository/asset_log"
+	asset "BE_Manage_device/internal/repository/assets"
+	issueTicket "BE_Manage_device/internal/repository/issue_tickets"
+	maintenanceSchedules "BE_Manage_device/internal/repository/maintenance_schedules"
+	user "BE_Manage_device/internal/repository/user"
+	notificationS "BE_Manage_device/internal/service/notification"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IssueTicketService struct {
+	repo                issueTicket.IssueTicketRepository
+	assetRepo           asset.AssetsRepository
+	scheduleRepo        maintenanceSchedules.MaintenanceSchedulesRepository
+	userRepo            user.UserRepository
+	assetLogRepo        asset_log.AssetsLogRepository
+	NotificationService *notificationS.NotificationService
+}
+
+func NewIssueTicketService(repo issueTicket.IssueTicketRepository, assetRepo asset.AssetsRepository, scheduleRepo maintenanceSchedules.MaintenanceSchedulesRepository, userRepo user.UserRepository, assetLogRepo asset_log.AssetsLogRepository, NotificationService *notificationS.NotificationService) *IssueTicketService {
+	return &IssueTicketService{repo: repo, assetRepo: assetRepo, scheduleRepo: scheduleRepo, userRepo: userRepo, assetLogRepo: assetLogRepo, NotificationService: NotificationService}
+}
+
+// Create mở ticket trên tài sản. Người giữ tài sản (source = holder) hoặc bất kỳ ai trong công ty quét QR (source = qr)
+func (service *IssueTicketService) Create(userId int64, assetId int64, request dto.CreateIssueTicketRequest, photos []*multipart.FileHeader) (*entity.IssueTicket, error) {
+	reporter, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := service.assetRepo.GetAssetById(assetId)
+	if err != nil {
+		return nil, err
+	}
+	if asset.CompanyId != reporter.CompanyId {
+		return nil, errors.New("asset not found")
+	}
+	if asset.Status == "Disposed" || asset.Status == "Retired" {
+		return nil, errors.New("can't report issue because status")
+	}
+	source := "qr"
+	if asset.Owner != nil && *asset.Owner == userId {
+		source = "holder"
+	}
+	severity := request.Severity
+	if severity == "" {
+		severity = "medium"
+	}
+	uploader := utils.NewSupabaseUploader()
+	ticketPhotos := []*entity.IssueTicketPhoto{}
+	for _, photo := range photos {
+		file, err := photo.Open()
+		if err != nil {
+			return nil, err
+		}
+		path := fmt.Sprintf("issue-tickets/%d/%d_%s", asset.Id, time.Now().UnixNano(), photo.Filename)
+		url, err := uploader.Upload(path, file, photo.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, err
+		}
+		ticketPhotos = append(ticketPhotos, &entity.IssueTicketPhoto{Url: url, CreatedAt: time.Now()})
+	}
+	now := time.Now()
+	ticket := &entity.IssueTicket{
+		AssetId:      asset.Id,
+		ReportedById: userId,
+		Source:       source,
+		Title:        request.Title,
+		Description:  request.Description,
+		Severity:     severity,
+		Status:       entity.IssueTicketOpen,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		CompanyId:    asset.CompanyId,
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if _, err = service.repo.Create(ticket, tx); err != nil {
+		return nil, err
+	}
+	for _, p := range ticketPhotos {
+		p.TicketId = ticket.Id
+	}
+	if err = service.repo.CreatePhotos(ticketPhotos, tx); err != nil {
+		return nil, err
+	}
+	summary := fmt.Sprintf("Issue #%d (%v) reported by %v: %v", ticket.Id, severity, reporter.Email, ticket.Title)
+	if err = service.createLog(tx, asset, userId, "Issue Reported", summary); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	userManagerAsset, _ := service.userRepo.GetUserAssetManageOfDepartment(asset.DepartmentId)
+	userHeadDepart, _ := service.userRepo.GetUserHeadDepartment(asset.DepartmentId)
+	service.notify(userId, []*entity.Users{userManagerAsset, userHeadDepart}, fmt.Sprintf("New %v issue #%d on asset (ID: %v): %v", severity, ticket.Id, asset.Id, ticket.Title), *asset)
+	return service.repo.GetById(ticket.Id)
+}
+
+func (service *IssueTicketService) GetById(userId int64, id int64) (*entity.IssueTicket, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := service.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canView(user, ticket) {
+		return nil, errors.New("can't find record this id")
+	}
+	return ticket, nil
+}
+
+// Filter: admin/assetManager xem cả công ty, quản lý tài sản/trưởng phòng xem phòng ban mình, người khác chỉ xem ticket của mình
+func (service *IssueTicketService) Filter(userId int64, request dto.IssueTicketFilterRequest) ([]*entity.IssueTicket, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	var departmentId, visibleToUserId *int64
+	if !isCompanyManager(user) {
+		if user.DepartmentId != nil && isDepartmentManager(user) {
+			departmentId = user.DepartmentId
+		} else {
+			visibleToUserId = &user.Id
+		}
+	}
+	return service.repo.Filter(user.CompanyId, departmentId, visibleToUserId, request.Status, request.Severity, request.AssetId)
+}
+
+func (service *IssueTicketService) AddComment(userId int64, id int64, content string) (*entity.IssueTicket, error) {
+	ticket, err := service.GetById(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := service.repo.CreateComment(&entity.IssueTicketComment{TicketId: ticket.Id, UserId: userId, Content: content, CreatedAt: time.Now()}); err != nil {
+		return nil, err
+	}
+	// Báo cho các bên liên quan còn lại của ticket
+	userManagerAsset, _ := service.userRepo.GetUserAssetManageOfDepartment(ticket.Asset.DepartmentId)
+	service.notify(userId, []*entity.Users{&ticket.ReportedBy, ticket.AssignedTo, userManagerAsset}, fmt.Sprintf("New comment on issue #%d: %v", ticket.Id, ticket.Title), ticket.Asset)
+	return service.repo.GetById(ticket.Id)
+}
+
+func (service *IssueTicketService) Triage(userId int64, id int64, request dto.TriageIssueTicketRequest) (*entity.IssueTicket, error) {
+	user, ticket, err := service.getManageable(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if isFinished(ticket.Status) {
+		return nil, fmt.Errorf("issue ticket is %v", ticket.Status)
+	}
+	changes := []string{}
+	if request.Severity != "" && request.Severity != ticket.Severity {
+		changes = append(changes, fmt.Sprintf("severity %v -> %v", ticket.Severity, request.Severity))
+		ticket.Severity = request.Severity
+	}
+	if request.AssignedToId != nil {
+		assignee, err := service.userRepo.FindByUserId(*request.AssignedToId)
+		if err != nil || assignee.CompanyId != user.CompanyId {
+			return nil, errors.New("assignee not found")
+		}
+		ticket.AssignedToId = &assignee.Id
+		ticket.AssignedTo = assignee
+		changes = append(changes, "assigned to "+assignee.Email)
+	}
+	status := request.Status
+	if status == "" && ticket.Status == entity.IssueTicketOpen {
+		status = entity.IssueTicketTriaged
+	}
+	if status != "" && status != ticket.Status {
+		changes = append(changes, fmt.Sprintf("status %v -> %v", ticket.Status, status))
+		ticket.Status = status
+	}
+	now := time.Now()
+	ticket.UpdatedAt = now
+	if isFinished(ticket.Status) {
+		ticket.ClosedAt = &now
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if err = service.repo.Update(ticket, tx); err != nil {
+		return nil, err
+	}
+	if len(changes) > 0 {
+		summary := fmt.Sprintf("Issue #%d updated by %v: %v", ticket.Id, user.Email, strings.Join(changes, ", "))
+		if err = service.createLog(tx, &ticket.Asset, userId, "Issue Triaged", summary); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	if request.Note != nil && *request.Note != "" {
+		if _, err := service.repo.CreateComment(&entity.IssueTicketComment{TicketId: ticket.Id, UserId: userId, Content: *request.Note, CreatedAt: now}); err != nil {
+			return nil, err
+		}
+	}
+	if len(changes) > 0 {
+		service.notify(userId, []*entity.Users{&ticket.ReportedBy, ticket.AssignedTo}, fmt.Sprintf("Issue #%d: %v", ticket.Id, strings.Join(changes, ", ")), ticket.Asset)
+	}
+	return service.repo.GetById(ticket.Id)
+}
+
+// Convert chuyển ticket thành lịch bảo trì. Lịch bắt đầu từ hôm nay trở về trước thì chuyển tài sản sang
+// "Under Maintenance" ngay và đánh dấu đã thông báo để cron job không xử lý lại
+func (service *IssueTicketService) Convert(userId int64, id int64, request dto.ConvertIssueTicketRequest) (*entity.IssueTicket, error) {
+	user, ticket, err := service.getManageable(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if isFinished(ticket.Status) || ticket.Status == entity.IssueTicketConverted {
+		return nil, fmt.Errorf("issue ticket is %v", ticket.Status)
+	}
+	loc, _ := time.LoadLocation("Asia/Bangkok") // GMT+7
+	startDate := request.StartDate.In(loc)
+	endDate := request.EndDate.In(loc)
+	if endDate.Before(startDate) {
+		return nil, errors.New("end date must be after start date")
+	}
+	asset, err := service.assetRepo.GetAssetById(ticket.AssetId)
+	if err != nil {
+		return nil, err
+	}
+	if asset.Status == "Disposed" || asset.Status == "Retired" || asset.Status == "Under Maintenance" {
+		return nil, errors.New("can't set maintenance schedules because status")
+	}
+	timeRange, err := service.scheduleRepo.GetDateMaintenanceSchedulesInFuture(asset.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range timeRange {
+		if !(endDate.Before(r.Start) || startDate.After(r.End)) {
+			return nil, errors.New("maintenance time overlaps with existing schedule")
+		}
+	}
+	now := time.Now().In(loc)
+	endOfToday := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc)
+	startsNow := startDate.Before(endOfToday)
+
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	schedule := &entity.MaintenanceSchedules{AssetId: asset.Id, StartDate: startDate, EndDate: endDate, Cost: request.Cost}
+	if _, err = service.repo.CreateSchedule(schedule, tx); err != nil {
+		return nil, err
+	}
+	ticket.ScheduleId = &schedule.Id
+	ticket.Status = entity.IssueTicketConverted
+	ticket.UpdatedAt = now
+	if err = service.repo.Update(ticket, tx); err != nil {
+		return nil, err
+	}
+	summary := fmt.Sprintf("Issue #%d converted to maintenance schedule #%d (%v - %v) by %v", ticket.Id, schedule.Id, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"), user.Email)
+	if err = service.createLog(tx, asset, userId, "Issue Converted", summary); err != nil {
+		return nil, err
+	}
+	if startsNow {
+		if _, err = service.assetRepo.UpdateAssetLifeCycleStage(asset.Id, "Under Maintenance", tx); err != nil {
+			return nil, err
+		}
+		if err = service.repo.CreateMaintenanceNotification(&entity.MaintenanceNotifications{ScheduleId: schedule.Id, NotifyDate: now}, tx); err != nil {
+			return nil, err
+		}
+		if err = service.createLog(tx, asset, userId, "Maintenance", fmt.Sprintf("Asset %d has started maintenance", asset.Id)); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	userHeadDepart, _ := service.userRepo.GetUserHeadDepartment(asset.DepartmentId)
+	message := fmt.Sprintf("Issue #%d converted to maintenance schedule (ID: %v)", ticket.Id, schedule.Id)
+	if startsNow {
+		message += fmt.Sprintf(", asset (ID: %v) moved to 'Under Maintenance'", asset.Id)
+	}
+	service.notify(userId, []*entity.Users{&ticket.ReportedBy, asset.OnwerUser, userHeadDepart, ticket.AssignedTo}, message, *asset)
+	return service.repo.GetById(ticket.Id)
+}
+
+func (service *IssueTicketService) getManageable(userId int64, id int64) (*entity.Users, *entity.IssueTicket, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	ticket, err := service.repo.GetById(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ticket.CompanyId != user.CompanyId {
+		return nil, nil, errors.New("can't find record this id")
+	}
+	if !isCompanyManager(user) && !(isDepartmentManager(user) && user.DepartmentId != nil && *user.DepartmentId == ticket.Asset.DepartmentId) {
+		return nil, nil, errors.New("you are not allowed to manage this issue")
+	}
+	return user, ticket, nil
+}
+
+func (service *IssueTicketService) createLog(tx *gorm.DB, asset *entity.Assets, userId int64, action string, summary string) error {
+	assetLog := entity.AssetLog{
+		Action:        action,
+		Timestamp:     time.Now(),
+		ByUserId:      &userId,
+		AssetId:       asset.Id,
+		ChangeSummary: summary,
+		CompanyId:     asset.CompanyId,
+	}
+	_, err := service.assetLogRepo.Create(&assetLog, tx)
+	return err
+}
+
+func (service *IssueTicketService) notify(userId int64, users []*entity.Users, message string, asset entity.Assets) {
+	usersToNotifications := utils.ConvertUsersToNotificationsToMap(userId, users)
+	if len(usersToNotifications) == 0 {
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Println("SendNotificationToUsers panic:", r)
+			}
+		}()
+		service.NotificationService.SendNotificationToUsers(usersToNotifications, message, asset)
+	}()
+}
+
+func isCompanyManager(user *entity.Users) bool {
+	return user.Role.Slug == "admin" || user.Role.Slug == "assetManager"
+}
+
+func isDepartmentManager(user *entity.Users) bool {
+	return user.IsAssetManager || user.Role.Slug == "departmentHead"
+}
+
+func canView(user *entity.Users, ticket *entity.IssueTicket) bool {
+	if ticket.CompanyId != user.CompanyId {
+		return false
+	}
+	if isCompanyManager(user) || ticket.ReportedById == user.Id || (ticket.Asset.Owner != nil && *ticket.Asset.Owner == user.Id) {
+		return true
+	}
+	if ticket.AssignedToId != nil && *ticket.AssignedToId == user.Id {
+		return true
+	}
+	return isDepartmentManager(user) && user.DepartmentId != nil && *user.DepartmentId == ticket.Asset.DepartmentId
+}
+
+func isFinished(status string) bool {
+	return status == entity.IssueTicketResolved || status == entity.IssueTicketRejected || status == entity.IssueTicketClosed
+}
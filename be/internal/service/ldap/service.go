@@ -0,0 +1,681 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	auditLog "BE_Manage_device/internal/repository/audit_logs"
+	ldapRepo "BE_Manage_device/internal/repository/ldap"
+	location "BE_Manage_device/internal/repository/locations"
+	role "BE_Manage_device/internal/repository/role"
+	user "BE_Manage_device/internal/repository/user"
+	userRBAC "BE_Manage_device/internal/repository/user_rbac"
+	"BE_Manage_device/pkg/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultUserFilter        = "(&(objectClass=inetOrgPerson)(mail=*))"
+	defaultUniqueIdAttribute = "entryUUID"
+	defaultEmailAttribute    = "mail"
+	defaultFirstName         = "givenName"
+	defaultLastName          = "sn"
+	defaultGroupAttribute    = "memberOf"
+	defaultOrgUnitName       = "ou"
+	defaultRoleSlug          = "viewer"
+	defaultSyncIntervalHours = 24
+)
+
+type LdapService struct {
+	repo         ldapRepo.LdapRepository
+	userRepo     user.UserRepository
+	locationRepo location.LocationRepository
+	roleRepo     role.RoleRepository
+	auditLogRepo auditLog.AuditLogRepository
+	userRBACRepo userRBAC.UserRBACRepository
+	running      sync.Map // Công ty đang đồng bộ, tránh chạy chồng
+}
+
+func NewLdapService(repo ldapRepo.LdapRepository, userRepo user.UserRepository, locationRepo location.LocationRepository, roleRepo role.RoleRepository, auditLogRepo auditLog.AuditLogRepository, userRBACRepo userRBAC.UserRBACRepository) *LdapService {
+	return &LdapService{repo: repo, userRepo: userRepo, locationRepo: locationRepo, roleRepo: roleRepo, auditLogRepo: auditLogRepo, userRBACRepo: userRBACRepo}
+}
+
+// Thông tin người dùng đọc từ một entry
+type directoryUser struct {
+	id           string
+	email        string
+	firstName    string
+	lastName     string
+	roleId       int64
+	departmentId *int64
+}
+
+func (service *LdapService) GetConfig(userId int64) (*dto.LdapConfigResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	config, err := service.repo.GetByCompanyId(users.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &entity.CompanyLdap{}
+		applyDefaults(config)
+	}
+	return convertConfig(config), nil
+}
+
+// SaveConfig tạo hoặc cập nhật cấu hình, thử kết nối khi bật đồng bộ hoặc đăng nhập
+func (service *LdapService) SaveConfig(userId int64, request dto.LdapConfigRequest, ip string) (*dto.LdapConfigResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	config, err := service.repo.GetByCompanyId(users.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &entity.CompanyLdap{CompanyId: users.CompanyId}
+	}
+	if !strings.HasPrefix(request.Url, "ldap://") && !strings.HasPrefix(request.Url, "ldaps://") {
+		return nil, errors.New("url must start with ldap:// or ldaps://")
+	}
+	for _, filter := range []string{request.UserFilter, request.OrgUnitFilter} {
+		if filter == "" {
+			continue
+		}
+		if _, err := ldap.CompileFilter(filter); err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %w", filter, err)
+		}
+	}
+	if request.OrgUnitFilter != "" {
+		loc, err := service.locationRepo.GetById(request.LocationId)
+		if err != nil || loc.CompanyId != users.CompanyId {
+			return nil, errors.New("location of synchronized departments not found")
+		}
+	}
+	if request.DefaultRoleSlug == "" {
+		request.DefaultRoleSlug = defaultRoleSlug
+	}
+	slugs := []string{request.DefaultRoleSlug}
+	for _, slug := range request.RoleMapping {
+		slugs = append(slugs, slug)
+	}
+	for _, slug := range slugs {
+		if service.roleRepo.GetRoleBySlug(slug).Id == 0 {
+			return nil, fmt.Errorf("role %q not found", slug)
+		}
+	}
+	if request.SyncIntervalHours < 0 {
+		return nil, errors.New("sync interval must be positive")
+	}
+	if request.BindPassword != "" {
+		if config.BindPassword, err = utils.EncryptSecret(request.BindPassword); err != nil {
+			return nil, err
+		}
+	}
+	roleMapping, err := json.Marshal(request.RoleMapping)
+	if err != nil {
+		return nil, err
+	}
+	config.Url = request.Url
+	config.StartTls = request.StartTls
+	config.InsecureSkipVerify = request.InsecureSkipVerify
+	config.BindDn = request.BindDn
+	config.BaseDn = request.BaseDn
+	config.UserFilter = request.UserFilter
+	config.OrgUnitFilter = request.OrgUnitFilter
+	config.UniqueIdAttribute = request.UniqueIdAttribute
+	config.EmailAttribute = request.EmailAttribute
+	config.FirstNameAttribute = request.FirstNameAttribute
+	config.LastNameAttribute = request.LastNameAttribute
+	config.GroupAttribute = request.GroupAttribute
+	config.OrgUnitNameAttribute = request.OrgUnitNameAttribute
+	config.RoleMapping = string(roleMapping)
+	config.DefaultRoleSlug = request.DefaultRoleSlug
+	config.LocationId = request.LocationId
+	config.SyncEnabled = request.SyncEnabled
+	config.SyncIntervalHours = request.SyncIntervalHours
+	config.LoginEnabled = request.LoginEnabled
+	config.UpdatedAt = time.Now()
+	applyDefaults(config)
+	if config.SyncEnabled || config.LoginEnabled {
+		conn, err := service.connect(config)
+		if err != nil {
+			return nil, fmt.Errorf("cannot connect to the directory: %w", err)
+		}
+		conn.Close()
+	}
+	if err := service.repo.Save(config); err != nil {
+		return nil, err
+	}
+	entry := &entity.AuditLog{
+		Action:    entity.AuditLdapConfigUpdated,
+		ActorId:   &users.Id,
+		Detail:    fmt.Sprintf("url = %s, syncEnabled = %v, loginEnabled = %v", config.Url, config.SyncEnabled, config.LoginEnabled),
+		IpAddress: ip,
+		CreatedAt: time.Now(),
+		CompanyId: users.CompanyId,
+	}
+	if err := service.auditLogRepo.Create(entry, nil); err != nil {
+		return nil, err
+	}
+	return convertConfig(config), nil
+}
+
+// Sync đồng bộ thủ công, chạy nền và trả về báo cáo đang chạy để FE theo dõi
+func (service *LdapService) Sync(userId int64) (*dto.LdapSyncReportResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	config, err := service.repo.GetByCompanyId(users.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, errors.New("directory isn't configured")
+	}
+	report, err := service.start(config, entity.LdapSyncTriggerManual, &users.Id)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logrus.Errorf("Panic when synchronize directory of company %v: %v", config.CompanyId, r)
+				service.running.Delete(config.CompanyId)
+			}
+		}()
+		service.run(config, report)
+	}()
+	return convertReport(report), nil
+}
+
+// SyncAll chạy theo lịch, đồng bộ các công ty đã đến hạn
+func (service *LdapService) SyncAll() {
+	configs, err := service.repo.GetAllSyncEnabled()
+	if err != nil {
+		logrus.Infof("Happen error when get directory configurations at: %v", time.Now())
+		return
+	}
+	for _, config := range configs {
+		interval := time.Duration(config.SyncIntervalHours) * time.Hour
+		if config.LastSyncAt != nil && time.Since(*config.LastSyncAt) < interval {
+			continue
+		}
+		report, err := service.start(config, entity.LdapSyncTriggerSchedule, nil)
+		if err != nil {
+			logrus.Infof("Skip directory sync of company %v: %v", config.CompanyId, err)
+			continue
+		}
+		service.run(config, report)
+		logrus.Infof("Directory sync of company %v: %v, created %v, updated %v, deactivated %v users",
+			config.CompanyId, report.Status, report.UsersCreated, report.UsersUpdated, report.UsersDeactivated)
+	}
+}
+
+func (service *LdapService) GetReports(userId int64) ([]*dto.LdapSyncReportResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := service.repo.GetReports(users.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*dto.LdapSyncReportResponse, 0, len(reports))
+	for _, report := range reports {
+		res = append(res, convertReport(report))
+	}
+	return res, nil
+}
+
+func (service *LdapService) GetReportById(userId int64, id int64) (*dto.LdapSyncReportResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	report, err := service.repo.GetReportById(id)
+	if err != nil {
+		return nil, err
+	}
+	if report.CompanyId != users.CompanyId {
+		return nil, errors.New("sync report not found")
+	}
+	return convertReport(report), nil
+}
+
+// BindLogin kiểm tra mật khẩu bằng cách bind vào thư mục với DN của người dùng, false nếu công ty không bật đăng nhập LDAP
+func (service *LdapService) BindLogin(users *entity.Users, password string) (bool, error) {
+	if password == "" {
+		return false, nil
+	}
+	config, err := service.repo.GetByCompanyId(users.CompanyId)
+	if err != nil {
+		return false, err
+	}
+	if config == nil || !config.LoginEnabled {
+		return false, nil
+	}
+	conn, err := service.connect(config)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	filter := fmt.Sprintf("(&%s(%s=%s))", config.UserFilter, config.EmailAttribute, ldap.EscapeFilter(users.Email))
+	entries, err := utils.LdapSearch(conn, config.BaseDn, filter, []string{"dn"})
+	if err != nil {
+		return false, err
+	}
+	if len(entries) != 1 {
+		return false, nil
+	}
+	return utils.LdapBindUser(conn, entries[0].DN, password)
+}
+
+func (service *LdapService) start(config *entity.CompanyLdap, trigger string, triggeredById *int64) (*entity.LdapSyncReport, error) {
+	if _, loaded := service.running.LoadOrStore(config.CompanyId, true); loaded {
+		return nil, errors.New("directory sync is already running")
+	}
+	report := &entity.LdapSyncReport{
+		Trigger:       trigger,
+		Status:        entity.LdapSyncRunning,
+		StartedAt:     time.Now(),
+		TriggeredById: triggeredById,
+		CompanyId:     config.CompanyId,
+	}
+	if err := service.repo.CreateReport(report); err != nil {
+		service.running.Delete(config.CompanyId)
+		return nil, err
+	}
+	return report, nil
+}
+
+func (service *LdapService) run(config *entity.CompanyLdap, report *entity.LdapSyncReport) {
+	defer service.running.Delete(config.CompanyId)
+	var errs []string
+	err := service.sync(config, report, &errs)
+	now := time.Now()
+	report.FinishedAt = &now
+	report.Status = entity.LdapSyncSuccess
+	if err != nil {
+		report.Status = entity.LdapSyncFailed
+		errs = append([]string{err.Error()}, errs...)
+	}
+	data, _ := json.Marshal(errs)
+	report.Errors = string(data)
+	if err := service.repo.UpdateReport(report); err != nil {
+		logrus.Errorf("Happen error when save directory sync report %v: %v", report.Id, err)
+	}
+	if err := service.repo.UpdateLastSyncAt(config.Id, now); err != nil {
+		logrus.Errorf("Happen error when update last sync of company %v: %v", config.CompanyId, err)
+	}
+}
+
+// sync đọc org unit rồi người dùng, tạo/cập nhật, cuối cùng khoá người dùng không còn trong thư mục.
+// Lỗi của từng người dùng được ghi vào errs, chỉ lỗi kết nối mới làm hỏng cả lần đồng bộ
+func (service *LdapService) sync(config *entity.CompanyLdap, report *entity.LdapSyncReport, errs *[]string) error {
+	conn, err := service.connect(config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	orgUnits, err := service.syncOrgUnits(conn, config, report)
+	if err != nil {
+		return err
+	}
+	var roleMapping map[string]string
+	if err := json.Unmarshal([]byte(config.RoleMapping), &roleMapping); err != nil {
+		return err
+	}
+	roleIds := map[string]int64{}
+	for dn, slug := range roleMapping {
+		roleIds[utils.LdapNormalizeDn(dn)] = service.roleRepo.GetRoleBySlug(slug).Id
+	}
+	defaultRole := service.roleRepo.GetRoleBySlug(config.DefaultRoleSlug)
+	if defaultRole.Id == 0 {
+		return fmt.Errorf("role %q not found", config.DefaultRoleSlug)
+	}
+	attributes := []string{config.UniqueIdAttribute, config.EmailAttribute, config.FirstNameAttribute, config.LastNameAttribute, config.GroupAttribute}
+	entries, err := utils.LdapSearch(conn, config.BaseDn, config.UserFilter, attributes)
+	if err != nil {
+		return err
+	}
+	identities, err := service.repo.GetIdentities(config.CompanyId)
+	if err != nil {
+		return err
+	}
+	bySubject := map[string]*entity.UserIdentity{}
+	for _, identity := range identities {
+		bySubject[identity.Subject] = identity
+	}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		du := directoryUser{
+			id:    utils.LdapEntryId(entry, config.UniqueIdAttribute),
+			email: strings.ToLower(strings.TrimSpace(entry.GetAttributeValue(config.EmailAttribute))),
+		}
+		if du.id == "" || du.email == "" {
+			report.UsersSkipped++
+			*errs = append(*errs, fmt.Sprintf("%s: missing %s or %s", entry.DN, config.UniqueIdAttribute, config.EmailAttribute))
+			continue
+		}
+		seen[du.id] = true
+		local := strings.Split(du.email, "@")[0]
+		du.firstName = utils.ValidUserName(entry.GetAttributeValue(config.FirstNameAttribute), local)
+		du.lastName = utils.ValidUserName(entry.GetAttributeValue(config.LastNameAttribute), local)
+		// Không cấu hình ánh xạ nhóm thì giữ nguyên vai trò người dùng đã có
+		if len(roleIds) > 0 {
+			du.roleId = defaultRole.Id
+			for _, group := range entry.GetAttributeValues(config.GroupAttribute) {
+				if roleId, ok := roleIds[utils.LdapNormalizeDn(group)]; ok && roleId != 0 {
+					du.roleId = roleId
+					break
+				}
+			}
+		}
+		du.departmentId = departmentOf(utils.LdapNormalizeDn(entry.DN), orgUnits)
+		if err := service.syncUser(config, du, defaultRole.Id, bySubject[du.id], report); err != nil {
+			report.UsersSkipped++
+			*errs = append(*errs, fmt.Sprintf("%s: %v", du.email, err))
+		}
+	}
+	// Thư mục trả về rỗng thường do cấu hình sai, không khoá hàng loạt
+	if len(entries) == 0 && len(identities) > 0 {
+		*errs = append(*errs, "directory returned no users, deactivation skipped")
+		return nil
+	}
+	for _, identity := range identities {
+		if seen[identity.Subject] || identity.Suspended {
+			continue
+		}
+		err := service.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+			if err := service.repo.UpdateUser(identity.UserId, map[string]interface{}{"is_active": false}, tx); err != nil {
+				return err
+			}
+			if err := service.repo.SetIdentitySuspended(identity.Id, true, tx); err != nil {
+				return err
+			}
+			return service.repo.RevokeSessions(identity.UserId, tx)
+		})
+		if err != nil {
+			*errs = append(*errs, fmt.Sprintf("user %v: %v", identity.UserId, err))
+			continue
+		}
+		report.UsersDeactivated++
+	}
+	return nil
+}
+
+// syncOrgUnits tạo/đổi tên phòng ban theo org unit, trả về DN đã chuẩn hoá -> id phòng ban
+func (service *LdapService) syncOrgUnits(conn *ldap.Conn, config *entity.CompanyLdap, report *entity.LdapSyncReport) (map[string]int64, error) {
+	orgUnits := map[string]int64{}
+	if config.OrgUnitFilter == "" {
+		return orgUnits, nil
+	}
+	entries, err := utils.LdapSearch(conn, config.BaseDn, config.OrgUnitFilter, []string{config.UniqueIdAttribute, config.OrgUnitNameAttribute})
+	if err != nil {
+		return nil, err
+	}
+	links, err := service.repo.GetDepartmentLinks(config.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	byExternalId := map[string]*entity.LdapDepartment{}
+	for _, link := range links {
+		byExternalId[link.ExternalId] = link
+	}
+	for _, entry := range entries {
+		externalId := utils.LdapEntryId(entry, config.UniqueIdAttribute)
+		name := strings.TrimSpace(entry.GetAttributeValue(config.OrgUnitNameAttribute))
+		if externalId == "" || name == "" {
+			continue
+		}
+		link := byExternalId[externalId]
+		err := service.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+			if link == nil {
+				department, err := service.repo.FindDepartmentByName(config.CompanyId, config.LocationId, name)
+				if err != nil {
+					return err
+				}
+				if department == nil {
+					department = &entity.Departments{DepartmentName: name, LocationId: config.LocationId, CompanyId: config.CompanyId}
+					if err := service.repo.CreateDepartment(department, tx); err != nil {
+						return err
+					}
+					report.DepartmentsCreated++
+				}
+				link = &entity.LdapDepartment{CompanyId: config.CompanyId, ExternalId: externalId, DepartmentId: department.Id}
+			} else {
+				department, err := service.repo.FindDepartmentByName(config.CompanyId, config.LocationId, name)
+				if err != nil {
+					return err
+				}
+				if department == nil {
+					if err := service.repo.RenameDepartment(link.DepartmentId, name, tx); err != nil {
+						return err
+					}
+					report.DepartmentsUpdated++
+				}
+			}
+			link.Dn = entry.DN
+			return service.repo.SaveDepartmentLink(link, tx)
+		})
+		if err != nil {
+			return nil, err
+		}
+		orgUnits[utils.LdapNormalizeDn(entry.DN)] = link.DepartmentId
+	}
+	return orgUnits, nil
+}
+
+// syncUser liên kết theo định danh thư mục, sau đó theo email, cuối cùng tạo người dùng mới
+func (service *LdapService) syncUser(config *entity.CompanyLdap, du directoryUser, defaultRoleId int64, identity *entity.UserIdentity, report *entity.LdapSyncReport) error {
+	var existing *entity.Users
+	if identity != nil {
+		users, err := service.userRepo.FindByUserId(identity.UserId)
+		if err != nil {
+			return err
+		}
+		existing = users
+	} else if users, err := service.userRepo.FindByEmail(du.email); err == nil {
+		if users.CompanyId != config.CompanyId {
+			return errors.New("email belongs to another company")
+		}
+		existing = users
+	}
+	return service.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if existing == nil {
+			if du.roleId == 0 {
+				du.roleId = defaultRoleId
+			}
+			// Mật khẩu ngẫu nhiên, người dùng đăng nhập bằng mật khẩu LDAP hoặc đặt lại mật khẩu
+			random, err := utils.GenerateOpaqueToken()
+			if err != nil {
+				return err
+			}
+			hashedPassword, err := bcrypt.GenerateFromPassword([]byte(random), bcrypt.DefaultCost)
+			if err != nil {
+				return err
+			}
+			existing = &entity.Users{
+				FirstName:    du.firstName,
+				LastName:     du.lastName,
+				Password:     string(hashedPassword),
+				Email:        du.email,
+				RoleId:       du.roleId,
+				IsActive:     true,
+				DepartmentId: du.departmentId,
+				CompanyId:    config.CompanyId,
+			}
+			if err := service.repo.CreateUser(existing, tx); err != nil {
+				return err
+			}
+			if err := service.userRBACRepo.GrantCompanyAssets(existing.Id, existing.RoleId, config.CompanyId, tx); err != nil {
+				return err
+			}
+			report.UsersCreated++
+		} else {
+			updates := map[string]interface{}{}
+			if existing.FirstName != du.firstName {
+				updates["first_name"] = du.firstName
+			}
+			if existing.LastName != du.lastName {
+				updates["last_name"] = du.lastName
+			}
+			if existing.Email != du.email {
+				if other, err := service.userRepo.FindByEmail(du.email); err == nil && other.Id != existing.Id {
+					return errors.New("email is used by another user")
+				}
+				updates["email"] = du.email
+			}
+			if du.roleId != 0 && existing.RoleId != du.roleId {
+				updates["role_id"] = du.roleId
+			}
+			if du.departmentId != nil && (existing.DepartmentId == nil || *existing.DepartmentId != *du.departmentId) {
+				updates["department_id"] = *du.departmentId
+			}
+			// Chỉ mở lại tài khoản do đồng bộ khoá, không mở tài khoản bị khoá khi nghỉ việc
+			if identity != nil && identity.Suspended {
+				updates["is_active"] = true
+				if err := service.repo.SetIdentitySuspended(identity.Id, false, tx); err != nil {
+					return err
+				}
+				report.UsersReactivated++
+			}
+			if len(updates) > 0 {
+				if err := service.repo.UpdateUser(existing.Id, updates, tx); err != nil {
+					return err
+				}
+				report.UsersUpdated++
+			}
+		}
+		if identity == nil {
+			return service.repo.CreateIdentity(&entity.UserIdentity{
+				UserId:    existing.Id,
+				Provider:  entity.IdentityProviderLdap,
+				Issuer:    config.BaseDn,
+				Subject:   du.id,
+				CompanyId: config.CompanyId,
+				CreatedAt: time.Now(),
+			}, tx)
+		}
+		return nil
+	})
+}
+
+func (service *LdapService) connect(config *entity.CompanyLdap) (*ldap.Conn, error) {
+	password := ""
+	if config.BindPassword != "" {
+		var err error
+		if password, err = utils.DecryptSecret(config.BindPassword); err != nil {
+			return nil, err
+		}
+	}
+	return utils.LdapConnect(config.Url, config.StartTls, config.InsecureSkipVerify, config.BindDn, password)
+}
+
+// departmentOf phòng ban của org unit gần nhất chứa entry
+func departmentOf(dn string, orgUnits map[string]int64) *int64 {
+	var found *int64
+	longest := 0
+	for ou, departmentId := range orgUnits {
+		if len(ou) > longest && utils.LdapIsDescendant(dn, ou) {
+			id := departmentId
+			found = &id
+			longest = len(ou)
+		}
+	}
+	return found
+}
+
+func applyDefaults(config *entity.CompanyLdap) {
+	if config.UserFilter == "" {
+		config.UserFilter = defaultUserFilter
+	}
+	if config.UniqueIdAttribute == "" {
+		config.UniqueIdAttribute = defaultUniqueIdAttribute
+	}
+	if config.EmailAttribute == "" {
+		config.EmailAttribute = defaultEmailAttribute
+	}
+	if config.FirstNameAttribute == "" {
+		config.FirstNameAttribute = defaultFirstName
+	}
+	if config.LastNameAttribute == "" {
+		config.LastNameAttribute = defaultLastName
+	}
+	if config.GroupAttribute == "" {
+		config.GroupAttribute = defaultGroupAttribute
+	}
+	if config.OrgUnitNameAttribute == "" {
+		config.OrgUnitNameAttribute = defaultOrgUnitName
+	}
+	if config.DefaultRoleSlug == "" {
+		config.DefaultRoleSlug = defaultRoleSlug
+	}
+	if config.SyncIntervalHours == 0 {
+		config.SyncIntervalHours = defaultSyncIntervalHours
+	}
+}
+
+func convertConfig(config *entity.CompanyLdap) *dto.LdapConfigResponse {
+	res := &dto.LdapConfigResponse{
+		Url:                  config.Url,
+		StartTls:             config.StartTls,
+		InsecureSkipVerify:   config.InsecureSkipVerify,
+		BindDn:               config.BindDn,
+		HasBindPassword:      config.BindPassword != "",
+		BaseDn:               config.BaseDn,
+		UserFilter:           config.UserFilter,
+		OrgUnitFilter:        config.OrgUnitFilter,
+		UniqueIdAttribute:    config.UniqueIdAttribute,
+		EmailAttribute:       config.EmailAttribute,
+		FirstNameAttribute:   config.FirstNameAttribute,
+		LastNameAttribute:    config.LastNameAttribute,
+		GroupAttribute:       config.GroupAttribute,
+		OrgUnitNameAttribute: config.OrgUnitNameAttribute,
+		DefaultRoleSlug:      config.DefaultRoleSlug,
+		LocationId:           config.LocationId,
+		SyncEnabled:          config.SyncEnabled,
+		SyncIntervalHours:    config.SyncIntervalHours,
+		LoginEnabled:         config.LoginEnabled,
+		LastSyncAt:           config.LastSyncAt,
+		UpdatedAt:            config.UpdatedAt,
+	}
+	_ = json.Unmarshal([]byte(config.RoleMapping), &res.RoleMapping)
+	return res
+}
+
+func convertReport(report *entity.LdapSyncReport) *dto.LdapSyncReportResponse {
+	res := &dto.LdapSyncReportResponse{
+		Id:                 report.Id,
+		Trigger:            report.Trigger,
+		Status:             report.Status,
+		StartedAt:          report.StartedAt,
+		FinishedAt:         report.FinishedAt,
+		DepartmentsCreated: report.DepartmentsCreated,
+		DepartmentsUpdated: report.DepartmentsUpdated,
+		UsersCreated:       report.UsersCreated,
+		UsersUpdated:       report.UsersUpdated,
+		UsersDeactivated:   report.UsersDeactivated,
+		UsersReactivated:   report.UsersReactivated,
+		UsersSkipped:       report.UsersSkipped,
+		TriggeredById:      report.TriggeredById,
+		Errors:             []string{},
+	}
+	_ = json.Unmarshal([]byte(report.Errors), &res.Errors)
+	return res
+}
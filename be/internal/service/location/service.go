@@ -1,21 +1,76 @@
 package service
 
 import (
+	"BE_Manage_device/internal/domain/dto"
 	"BE_Manage_device/internal/domain/entity"
+	company "BE_Manage_device/internal/repository/company"
 	location "BE_Manage_device/internal/repository/locations"
+	user "BE_Manage_device/internal/repository/user"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"fmt"
 )
 
 type LocationService struct {
-	repo location.LocationRepository
+	repo        location.LocationRepository
+	userRepo    user.UserRepository
+	companyRepo company.CompanyRepository
 }
 
-func NewLocationService(repo location.LocationRepository) *LocationService {
-	return &LocationService{repo: repo}
+func NewLocationService(repo location.LocationRepository, userRepo user.UserRepository, companyRepo company.CompanyRepository) *LocationService {
+	return &LocationService{repo: repo, userRepo: userRepo, companyRepo: companyRepo}
 }
 
-func (service *LocationService) Create(LocationName string) (*entity.Locations, error) {
+func levelOf(locationType string) int {
+	for i, level := range entity.LocationLevels {
+		if level == locationType {
+			return i
+		}
+	}
+	return -1
+}
+
+func (service *LocationService) Create(userId int64, locationName string, locationType string, parentId *int64, latitude *float64, longitude *float64) (*entity.Locations, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	company, err := service.companyRepo.GetCompanyBySuffixEmail(utils.GetSuffixEmail(user.Email))
+	if err != nil {
+		return nil, err
+	}
+	if locationType == "" {
+		locationType = entity.LocationTypeSite
+	}
+	level := levelOf(locationType)
+	if level == -1 {
+		return nil, fmt.Errorf("location type must be one of %v", entity.LocationLevels)
+	}
+	if level == 0 && parentId != nil {
+		return nil, errors.New("a site can't have a parent location")
+	}
+	if level > 0 {
+		if parentId == nil {
+			return nil, fmt.Errorf("a %v must belong to a %v", locationType, entity.LocationLevels[level-1])
+		}
+		parent, err := service.repo.GetById(*parentId)
+		if err != nil {
+			return nil, err
+		}
+		if parent.CompanyId != company.Id {
+			return nil, errors.New("can't find parent location")
+		}
+		if levelOf(parent.LocationType) != level-1 {
+			return nil, fmt.Errorf("a %v must belong to a %v", locationType, entity.LocationLevels[level-1])
+		}
+	}
 	var location = &entity.Locations{
-		LocationName: LocationName,
+		LocationName: locationName,
+		LocationType: locationType,
+		ParentId:     parentId,
+		Latitude:     latitude,
+		Longitude:    longitude,
+		CompanyId:    company.Id,
 	}
 	locationCreate, err := service.repo.Create(location)
 	if err != nil {
@@ -24,15 +79,151 @@ func (service *LocationService) Create(LocationName string) (*entity.Locations,
 	return locationCreate, nil
 }
 
-func (service *LocationService) GetAll() ([]*entity.Locations, error) {
-	locations, err := service.repo.GetAll()
+func (service *LocationService) GetAll(userId int64) ([]*entity.Locations, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	locations, err := service.repo.GetAll(user.CompanyId)
 	if err != nil {
 		return nil, err
 	}
 	return locations, err
 }
 
-func (service *LocationService) Delete(id int64) error {
-	err := service.repo.Delete(id)
+func (service *LocationService) GetTree(userId int64) ([]*dto.LocationTreeResponse, error) {
+	locations, err := service.GetAll(userId)
+	if err != nil {
+		return nil, err
+	}
+	nodes := map[int64]*dto.LocationTreeResponse{}
+	for _, l := range locations {
+		nodes[l.Id] = &dto.LocationTreeResponse{
+			Id:           l.Id,
+			LocationName: l.LocationName,
+			LocationType: l.LocationType,
+			ParentId:     l.ParentId,
+			Latitude:     l.Latitude,
+			Longitude:    l.Longitude,
+			Children:     []*dto.LocationTreeResponse{},
+		}
+	}
+	roots := []*dto.LocationTreeResponse{}
+	// locations đã được sắp xếp theo path nên node cha luôn xuất hiện trước node con
+	for _, l := range locations {
+		node := nodes[l.Id]
+		if l.ParentId != nil {
+			if parent, ok := nodes[*l.ParentId]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots, nil
+}
+
+func (service *LocationService) GetById(userId int64, id int64) (*entity.Locations, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	location, err := service.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if location.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	return location, nil
+}
+
+func (service *LocationService) Update(userId int64, id int64, locationName string, latitude *float64, longitude *float64) (*entity.Locations, error) {
+	location, err := service.GetById(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	location.LocationName = locationName
+	location.Latitude = latitude
+	location.Longitude = longitude
+	return service.repo.Update(location)
+}
+
+func (service *LocationService) Delete(userId int64, id int64) error {
+	if _, err := service.GetById(userId, id); err != nil {
+		return err
+	}
+	children, err := service.repo.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("can't delete a location that still has child locations")
+	}
+	references, err := service.repo.CountReferences(id)
+	if err != nil {
+		return err
+	}
+	if references > 0 {
+		return errors.New("can't delete a location that is still used by departments or assets")
+	}
+	err = service.repo.Delete(id)
 	return err
 }
+
+func (service *LocationService) CountAssetsByLevel(userId int64, locationType string) ([]*dto.LocationDashboardSummary, error) {
+	if levelOf(locationType) == -1 {
+		return nil, fmt.Errorf("location type must be one of %v", entity.LocationLevels)
+	}
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	var departmentId *int64
+	if user.Role.Slug == "viewer" {
+		if user.DepartmentId == nil {
+			return []*dto.LocationDashboardSummary{}, nil
+		}
+		departmentId = user.DepartmentId
+	}
+	locations, err := service.repo.GetAll(user.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := service.repo.CountAssetsByLevel(user.CompanyId, locationType, departmentId)
+	if err != nil {
+		return nil, err
+	}
+	summaries := []*dto.LocationDashboardSummary{}
+	byLocation := map[int64]*dto.LocationDashboardSummary{}
+	for _, l := range locations {
+		if l.LocationType != locationType {
+			continue
+		}
+		summary := &dto.LocationDashboardSummary{
+			Location: dto.LocationResponse{
+				ID:           l.Id,
+				LocationName: l.LocationName,
+				LocationType: l.LocationType,
+			},
+		}
+		byLocation[l.Id] = summary
+		summaries = append(summaries, summary)
+	}
+	for _, count := range counts {
+		summary, ok := byLocation[count.LocationId]
+		if !ok {
+			continue
+		}
+		summary.TotalAssets += count.Total
+		switch count.Status {
+		case "In Use":
+			summary.Assigned += count.Total
+		case "Under Maintenance":
+			summary.UnderMaintenance += count.Total
+		case "Retired":
+			summary.Retired += count.Total
+		}
+	}
+	return summaries, nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"BE_Manage_device/config"
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	asset "BE_Manage_device/internal/repository/assets"
+	categories "BE_Manage_device/internal/repository/categories"
+	maintenancePlan "BE_Manage_device/internal/repository/maintenance_plans"
+	user "BE_Manage_device/internal/repository/user"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var intervalUnitFrequencies = map[string]string{"day": "DAILY", "week": "WEEKLY", "month": "MONTHLY", "year": "YEARLY"}
+
+type MaintenancePlanService struct {
+	repo         maintenancePlan.MaintenancePlanRepository
+	assetRepo    asset.AssetsRepository
+	categoryRepo categories.CategoriesRepository
+	userRepo     user.UserRepository
+}
+
+func NewMaintenancePlanService(repo maintenancePlan.MaintenancePlanRepository, assetRepo asset.AssetsRepository, categoryRepo categories.CategoriesRepository, userRepo user.UserRepository) *MaintenancePlanService {
+	return &MaintenancePlanService{repo: repo, assetRepo: assetRepo, categoryRepo: categoryRepo, userRepo: userRepo}
+}
+
+func (service *MaintenancePlanService) Create(userId int64, request dto.MaintenancePlanRequest) (*entity.MaintenancePlan, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	plan := &entity.MaintenancePlan{
+		CreatedById: userId,
+		CompanyId:   user.CompanyId,
+		IsActive:    true,
+	}
+	if err := service.applyRequest(plan, request); err != nil {
+		return nil, err
+	}
+	plan, err = service.repo.Create(plan)
+	if err != nil {
+		return nil, err
+	}
+	if plan.IsActive {
+		if _, _, err := service.materialize(plan); err != nil {
+			logrus.Infof("Happen error when generate schedules for plan %v: %v", plan.Id, err)
+		}
+	}
+	return service.repo.GetById(plan.Id)
+}
+
+func (service *MaintenancePlanService) GetAll(userId int64) ([]*entity.MaintenancePlan, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	return service.repo.GetAll(user.CompanyId)
+}
+
+func (service *MaintenancePlanService) GetById(userId int64, id int64) (*entity.MaintenancePlan, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := service.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if plan.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	return plan, nil
+}
+
+// Update sửa kế hoạch và sinh lại các lần bảo trì từ ngày mai trở đi, các lần đã qua/đang diễn ra giữ nguyên
+func (service *MaintenancePlanService) Update(userId int64, id int64, request dto.MaintenancePlanRequest) (*entity.MaintenancePlan, error) {
+	plan, err := service.GetById(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.applyRequest(plan, request); err != nil {
+		return nil, err
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if _, err = service.repo.Update(plan, tx); err != nil {
+		return nil, err
+	}
+	if _, err = service.repo.DeleteFutureSchedules(plan.Id, startOfTomorrow(), tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	if plan.IsActive {
+		if _, _, err := service.materialize(plan); err != nil {
+			logrus.Infof("Happen error when generate schedules for plan %v: %v", plan.Id, err)
+		}
+	}
+	return service.repo.GetById(plan.Id)
+}
+
+func (service *MaintenancePlanService) Delete(userId int64, id int64) error {
+	plan, err := service.GetById(userId, id)
+	if err != nil {
+		return err
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if _, err = service.repo.DeleteFutureSchedules(plan.Id, startOfTomorrow(), tx); err != nil {
+		return err
+	}
+	if err = service.repo.Delete(plan.Id, tx); err != nil {
+		return err
+	}
+	return tx.Commit().Error
+}
+
+func (service *MaintenancePlanService) Generate(userId int64, id int64) (*dto.GenerateMaintenancePlanResponse, error) {
+	plan, err := service.GetById(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.IsActive {
+		return nil, errors.New("plan is inactive")
+	}
+	created, skipped, err := service.materialize(plan)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.GenerateMaintenancePlanResponse{Created: created, Skipped: skipped}, nil
+}
+
+// Chạy trong cron job hằng ngày
+func (service *MaintenancePlanService) MaterializeAll() {
+	plans, err := service.repo.GetActive()
+	if err != nil {
+		logrus.Infof("Happen error when get maintenance plans at: %v", time.Now())
+		return
+	}
+	for _, p := range plans {
+		created, skipped, err := service.materialize(p)
+		if err != nil {
+			logrus.Infof("Happen error when generate schedules for plan %v: %v", p.Id, err)
+			continue
+		}
+		logrus.Infof("Maintenance plan %v: created %v schedules, skipped %v overlapping", p.Id, created, skipped)
+	}
+}
+
+// materialize sinh các MaintenanceSchedules trong khoảng [now, now + horizon),
+// bỏ qua lần đã sinh trước đó và lần bị trùng với lịch bảo trì khác của tài sản
+func (service *MaintenancePlanService) materialize(plan *entity.MaintenancePlan) (int, int, error) {
+	rule, err := utils.ParseRRule(plan.RRule)
+	if err != nil {
+		return 0, 0, err
+	}
+	now := time.Now()
+	horizon := now.AddDate(0, 0, config.MaintenancePlanHorizonDays)
+	occurrences := rule.Occurrences(plan.StartDate, now, horizon)
+	if len(occurrences) == 0 {
+		return 0, 0, nil
+	}
+	assets, err := service.repo.GetPlanAssets(plan)
+	if err != nil {
+		return 0, 0, err
+	}
+	assetIds := make([]int64, 0, len(assets))
+	for _, a := range assets {
+		assetIds = append(assetIds, a.Id)
+	}
+	existing, err := service.repo.GetSchedulesOfAssets(assetIds, now)
+	if err != nil {
+		return 0, 0, err
+	}
+	rangesByAsset := map[int64][]*entity.MaintenanceSchedules{}
+	for _, s := range existing {
+		rangesByAsset[s.AssetId] = append(rangesByAsset[s.AssetId], s)
+	}
+	schedules := []*entity.MaintenanceSchedules{}
+	skipped := 0
+	for _, a := range assets {
+		for _, start := range occurrences {
+			end := start.AddDate(0, 0, plan.DurationDays-1)
+			overlap := false
+			for _, s := range rangesByAsset[a.Id] {
+				if !(end.Before(s.StartDate) || start.After(s.EndDate)) {
+					overlap = true
+					break
+				}
+			}
+			if overlap {
+				// Lần đã sinh trước đó của chính kế hoạch này không tính là bị trùng
+				if !isGeneratedBy(rangesByAsset[a.Id], plan.Id, start) {
+					skipped++
+				}
+				continue
+			}
+			schedule := &entity.MaintenanceSchedules{
+				AssetId:   a.Id,
+				StartDate: start,
+				EndDate:   end,
+				Cost:      plan.Cost,
+				PlanId:    &plan.Id,
+			}
+			schedules = append(schedules, schedule)
+			rangesByAsset[a.Id] = append(rangesByAsset[a.Id], schedule)
+		}
+	}
+	if err := service.repo.CreateSchedules(schedules); err != nil {
+		return 0, 0, err
+	}
+	return len(schedules), skipped, nil
+}
+
+func isGeneratedBy(schedules []*entity.MaintenanceSchedules, planId int64, start time.Time) bool {
+	for _, s := range schedules {
+		if s.PlanId != nil && *s.PlanId == planId && s.StartDate.Equal(start) {
+			return true
+		}
+	}
+	return false
+}
+
+func (service *MaintenancePlanService) applyRequest(plan *entity.MaintenancePlan, request dto.MaintenancePlanRequest) error {
+	if (request.AssetId == nil) == (request.CategoryId == nil) {
+		return errors.New("plan must target exactly one of assetId or categoryId")
+	}
+	if request.AssetId != nil {
+		asset, err := service.assetRepo.GetAssetById(*request.AssetId)
+		if err != nil {
+			return err
+		}
+		if asset.CompanyId != plan.CompanyId {
+			return errors.New("asset not found")
+		}
+		if asset.Status == "Disposed" || asset.Status == "Retired" {
+			return errors.New("can't set maintenance plan because status")
+		}
+	} else {
+		categories, err := service.categoryRepo.GetAll(plan.CompanyId)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, c := range categories {
+			if c.Id == *request.CategoryId {
+				found = true
+			}
+		}
+		if !found {
+			return errors.New("category not found")
+		}
+	}
+	rrule := request.RRule
+	if rrule == "" {
+		freq, ok := intervalUnitFrequencies[request.IntervalUnit]
+		if !ok || request.IntervalValue < 1 {
+			return errors.New("either rrule or intervalValue and intervalUnit is required")
+		}
+		rrule = fmt.Sprintf("FREQ=%v;INTERVAL=%v", freq, request.IntervalValue)
+	}
+	rule, err := utils.ParseRRule(rrule)
+	if err != nil {
+		return err
+	}
+	plan.PlanName = request.PlanName
+	plan.AssetId = request.AssetId
+	plan.CategoryId = request.CategoryId
+	plan.RRule = rule.String()
+	plan.StartDate = request.StartDate
+	plan.DurationDays = request.DurationDays
+	if plan.DurationDays == 0 {
+		plan.DurationDays = 1
+	}
+	plan.Cost = request.Cost
+	if request.IsActive != nil {
+		plan.IsActive = *request.IsActive
+	}
+	return nil
+}
+
+func startOfTomorrow() time.Time {
+	loc, _ := time.LoadLocation("Asia/Bangkok") // GMT+7
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc)
+}
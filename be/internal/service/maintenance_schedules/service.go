@@ -6,9 +6,12 @@ import (
 	maintenanceSchedules "BE_Manage_device/internal/repository/maintenance_schedules"
 	user "BE_Manage_device/internal/repository/user"
 	notificationS "BE_Manage_device/internal/service/notification"
+	workflowS "BE_Manage_device/internal/service/workflows"
 	"errors"
 	"fmt"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type MaintenanceSchedulesService struct {
@@ -16,32 +19,96 @@ type MaintenanceSchedulesService struct {
 	assetRepo           asset.AssetsRepository
 	userRepository      user.UserRepository
 	NotificationService *notificationS.NotificationService
+	workflowService     *workflowS.WorkflowService
+}
+
+func NewMaintenanceSchedulesService(repo maintenanceSchedules.MaintenanceSchedulesRepository, assetRepo asset.AssetsRepository, userRepository user.UserRepository, NotificationService *notificationS.NotificationService, workflowService *workflowS.WorkflowService) *MaintenanceSchedulesService {
+	return &MaintenanceSchedulesService{repo: repo, assetRepo: assetRepo, NotificationService: NotificationService, userRepository: userRepository, workflowService: workflowService}
 }
 
-func NewMaintenanceSchedulesService(repo maintenanceSchedules.MaintenanceSchedulesRepository, assetRepo asset.AssetsRepository, userRepository user.UserRepository, NotificationService *notificationS.NotificationService) *MaintenanceSchedulesService {
-	return &MaintenanceSchedulesService{repo: repo, assetRepo: assetRepo, NotificationService: NotificationService, userRepository: userRepository}
+// schedulePayload tham số lịch bảo trì lưu lại khi gửi duyệt
+type schedulePayload struct {
+	StartDate time.Time `json:"startDate"`
+	EndDate   time.Time `json:"endDate"`
+	Cost      float64   `json:"cost"`
 }
 
-func (service *MaintenanceSchedulesService) Create(userId int64, assetId int64, startDate, endDate time.Time) (*entity.MaintenanceSchedules, error) {
+// Create công ty có cấu hình quy trình duyệt thì chỉ gửi duyệt và trả về yêu cầu duyệt,
+// lịch được tạo khi quy trình được duyệt xong
+func (service *MaintenanceSchedulesService) Create(userId int64, assetId int64, startDate, endDate time.Time, cost float64) (*entity.MaintenanceSchedules, *entity.WorkflowInstance, error) {
+	var err error
 	loc, _ := time.LoadLocation("Asia/Bangkok") // GMT+7
 	startDate = startDate.In(loc)
 	endDate = endDate.In(loc)
-	userUpdate, err := service.userRepository.FindByUserId(userId)
+	user, err := service.userRepository.FindByUserId(userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	assetCheck, err := service.checkSchedule(user.CompanyId, assetId, startDate, endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	instance, err := service.workflowService.Start(userId, assetCheck.CompanyId, entity.WorkflowEntityMaintenanceSchedule, assetCheck.Id, assetCheck.Id, assetCheck.DepartmentId, cost, schedulePayload{StartDate: startDate, EndDate: endDate, Cost: cost}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if instance != nil {
+		instance, err = service.workflowService.Submitted(instance)
+		return nil, instance, err
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	maintenanceCreate, notify, err := service.create(userId, assetCheck, startDate, endDate, cost, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, nil, fmt.Errorf("commit failed: %w", err)
+	}
+	notify()
+	return maintenanceCreate, nil, nil
+}
+
+// OnWorkflowApproved tạo lịch bảo trì khi quy trình duyệt xong, kiểm tra lại trạng thái và lịch trùng tại thời điểm duyệt
+func (service *MaintenanceSchedulesService) OnWorkflowApproved(instance *entity.WorkflowInstance, approverId int64, tx *gorm.DB) (func(), error) {
+	var payload schedulePayload
+	if err := workflowS.DecodePayload(instance, &payload); err != nil {
+		return nil, err
+	}
+	if payload.StartDate.Before(time.Now()) {
+		return nil, errors.New("start date has passed, please reject and create a new schedule")
+	}
+	assetCheck, err := service.checkSchedule(instance.CompanyId, instance.AssetId, payload.StartDate, payload.EndDate)
 	if err != nil {
 		return nil, err
 	}
+	_, notify, err := service.create(approverId, assetCheck, payload.StartDate, payload.EndDate, payload.Cost, tx)
+	return notify, err
+}
+
+func (service *MaintenanceSchedulesService) OnWorkflowRejected(instance *entity.WorkflowInstance, approverId int64, tx *gorm.DB) (func(), error) {
+	return nil, nil
+}
+
+// checkSchedule tài sản thuộc công ty, còn đặt lịch bảo trì được và không trùng lịch đã có
+func (service *MaintenanceSchedulesService) checkSchedule(companyId int64, assetId int64, startDate, endDate time.Time) (*entity.Assets, error) {
 	assetCheck, err := service.assetRepo.GetAssetById(assetId)
 	if err != nil {
 		return nil, err
 	}
+	if assetCheck.CompanyId != companyId {
+		return nil, errors.New("can't find record this id")
+	}
 	if assetCheck.Status == "Disposed" || assetCheck.Status == "Retired" || assetCheck.Status == "Under Maintenance" {
 		return nil, errors.New("can't set maintenance schedules because status")
 	}
-	maintenance := entity.MaintenanceSchedules{
-		AssetId:   assetId,
-		StartDate: startDate,
-		EndDate:   endDate,
-	}
 	timeRange, err := service.repo.GetDateMaintenanceSchedulesInFuture(assetId)
 	if err != nil {
 		return nil, err
@@ -51,33 +118,50 @@ func (service *MaintenanceSchedulesService) Create(userId int64, assetId int64,
 			return nil, errors.New("maintenance time overlaps with existing schedule")
 		}
 	}
-	maintenanceCreate, err := service.repo.Create(&maintenance)
+	return assetCheck, nil
+}
+
+// create lưu lịch bảo trì trong tx, trả về hàm gửi thông báo để gọi sau khi tx commit
+func (service *MaintenanceSchedulesService) create(userId int64, assetCheck *entity.Assets, startDate, endDate time.Time, cost float64, tx *gorm.DB) (*entity.MaintenanceSchedules, func(), error) {
+	userUpdate, err := service.userRepository.FindByUserId(userId)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	userHeadDepart, _ := service.userRepository.GetUserHeadDepartment(assetCheck.DepartmentId)
-	userManagerAsset, _ := service.userRepository.GetUserAssetManageOfDepartment(assetCheck.DepartmentId)
-	usersToNotifications := []*entity.Users{}
-	usersToNotifications = append(usersToNotifications, assetCheck.OnwerUser)
-	usersToNotifications = append(usersToNotifications, userHeadDepart)
-	usersToNotifications = append(usersToNotifications, userManagerAsset)
-	filteredUsers := []*entity.Users{}
-	for _, user := range usersToNotifications {
-		if user.Id != userUpdate.Id {
-			filteredUsers = append(filteredUsers, user)
-		}
+	maintenance := entity.MaintenanceSchedules{
+		AssetId:   assetCheck.Id,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Cost:      cost,
 	}
-	usersToNotifications = filteredUsers
-	message := fmt.Sprintf("The maintenance schedules (ID: %v) has just been created by %v", maintenance.Id, userUpdate.Email)
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				fmt.Println("SendNotificationToUsers panic:", r)
+	maintenanceCreate, err := service.repo.Create(&maintenance, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	notify := func() {
+		userHeadDepart, _ := service.userRepository.GetUserHeadDepartment(assetCheck.DepartmentId)
+		userManagerAsset, _ := service.userRepository.GetUserAssetManageOfDepartment(assetCheck.DepartmentId)
+		usersToNotifications := []*entity.Users{}
+		usersToNotifications = append(usersToNotifications, assetCheck.OnwerUser)
+		usersToNotifications = append(usersToNotifications, userHeadDepart)
+		usersToNotifications = append(usersToNotifications, userManagerAsset)
+		filteredUsers := []*entity.Users{}
+		for _, user := range usersToNotifications {
+			if user.Id != userUpdate.Id {
+				filteredUsers = append(filteredUsers, user)
 			}
+		}
+		usersToNotifications = filteredUsers
+		message := fmt.Sprintf("The maintenance schedules (ID: %v) has just been created by %v", maintenance.Id, userUpdate.Email)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Println("SendNotificationToUsers panic:", r)
+				}
+			}()
+			service.NotificationService.SendNotificationToUsers(usersToNotifications, message, *assetCheck)
 		}()
-		service.NotificationService.SendNotificationToUsers(usersToNotifications, message, *assetCheck)
-	}()
-	return maintenanceCreate, nil
+	}
+	return maintenanceCreate, notify, nil
 }
 
 func (service *MaintenanceSchedulesService) GetAllMaintenanceSchedulesByAssetId(userId int64, assetId int64) ([]*entity.MaintenanceSchedules, error) {
@@ -88,7 +172,7 @@ func (service *MaintenanceSchedulesService) GetAllMaintenanceSchedulesByAssetId(
 	return maintenances, nil
 }
 
-func (service *MaintenanceSchedulesService) Update(userId int64, id int64, startDate time.Time, endDate time.Time) (*entity.MaintenanceSchedules, error) {
+func (service *MaintenanceSchedulesService) Update(userId int64, id int64, startDate time.Time, endDate time.Time, cost float64) (*entity.MaintenanceSchedules, error) {
 	loc, _ := time.LoadLocation("Asia/Bangkok") // GMT+7
 	startDate = startDate.In(loc)
 	endDate = endDate.In(loc)
@@ -100,7 +184,7 @@ func (service *MaintenanceSchedulesService) Update(userId int64, id int64, start
 	if maintenaceUpdateOld.StartDate.Before(time.Now()) {
 		return nil, errors.New("start date <= now")
 	}
-	maintenance, err := service.repo.Update(id, startDate, endDate)
+	maintenance, err := service.repo.Update(id, startDate, endDate, cost)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	asset_log "BE_Manage_device/internal/repository/asset_log"
+	asset "BE_Manage_device/internal/repository/assets"
+	categories "BE_Manage_device/internal/repository/categories"
+	maintenanceSchedules "BE_Manage_device/internal/repository/maintenance_schedules"
+	meter "BE_Manage_device/internal/repository/meters"
+	user "BE_Manage_device/internal/repository/user"
+	notificationS "BE_Manage_device/internal/service/notification"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+type MeterService struct {
+	repo                meter.MeterRepository
+	assetRepo           asset.AssetsRepository
+	categoryRepo        categories.CategoriesRepository
+	scheduleRepo        maintenanceSchedules.MaintenanceSchedulesRepository
+	userRepo            user.UserRepository
+	assetLogRepo        asset_log.AssetsLogRepository
+	NotificationService *notificationS.NotificationService
+}
+
+func NewMeterService(repo meter.MeterRepository, assetRepo asset.AssetsRepository, categoryRepo categories.CategoriesRepository, scheduleRepo maintenanceSchedules.MaintenanceSchedulesRepository, userRepo user.UserRepository, assetLogRepo asset_log.AssetsLogRepository, NotificationService *notificationS.NotificationService) *MeterService {
+	return &MeterService{repo: repo, assetRepo: assetRepo, categoryRepo: categoryRepo, scheduleRepo: scheduleRepo, userRepo: userRepo, assetLogRepo: assetLogRepo, NotificationService: NotificationService}
+}
+
+func (service *MeterService) CreateMeter(userId int64, request dto.MeterDefinitionRequest) (*entity.MeterDefinition, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.checkCategory(user.CompanyId, request.CategoryId); err != nil {
+		return nil, err
+	}
+	meter := &entity.MeterDefinition{
+		Name:        request.Name,
+		Unit:        request.Unit,
+		CategoryId:  request.CategoryId,
+		IsActive:    true,
+		CreatedById: userId,
+		CreatedAt:   time.Now(),
+		CompanyId:   user.CompanyId,
+	}
+	for _, t := range request.Thresholds {
+		meter.Thresholds = append(meter.Thresholds, *toThreshold(&entity.MeterThreshold{IsActive: true}, t))
+	}
+	if _, err := service.repo.CreateMeter(meter, service.repo.GetDB()); err != nil {
+		return nil, err
+	}
+	return service.repo.GetMeterById(meter.Id)
+}
+
+func (service *MeterService) GetMeters(userId int64, categoryId *int64) ([]*entity.MeterDefinition, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	return service.repo.GetMeters(user.CompanyId, categoryId)
+}
+
+func (service *MeterService) GetMeterById(userId int64, id int64) (*entity.MeterDefinition, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	meter, err := service.repo.GetMeterById(id)
+	if err != nil {
+		return nil, err
+	}
+	if meter.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	return meter, nil
+}
+
+// UpdateMeter không cho đổi danh mục vì các chỉ số cũ đã gắn với tài sản của danh mục đó
+func (service *MeterService) UpdateMeter(userId int64, id int64, request dto.UpdateMeterDefinitionRequest) (*entity.MeterDefinition, error) {
+	meter, err := service.GetMeterById(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	meter.Name = request.Name
+	meter.Unit = request.Unit
+	if request.IsActive != nil {
+		meter.IsActive = *request.IsActive
+	}
+	if err := service.repo.UpdateMeter(meter); err != nil {
+		return nil, err
+	}
+	return service.repo.GetMeterById(meter.Id)
+}
+
+func (service *MeterService) AddThreshold(userId int64, meterId int64, request dto.MeterThresholdRequest) (*entity.MeterThreshold, error) {
+	meter, err := service.GetMeterById(userId, meterId)
+	if err != nil {
+		return nil, err
+	}
+	threshold := toThreshold(&entity.MeterThreshold{MeterId: meter.Id, IsActive: true}, request)
+	return service.repo.CreateThreshold(threshold)
+}
+
+func (service *MeterService) UpdateThreshold(userId int64, id int64, request dto.MeterThresholdRequest) (*entity.MeterThreshold, error) {
+	threshold, err := service.getThreshold(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	threshold = toThreshold(threshold, request)
+	if err := service.repo.UpdateThreshold(threshold); err != nil {
+		return nil, err
+	}
+	return threshold, nil
+}
+
+func (service *MeterService) DeleteThreshold(userId int64, id int64) error {
+	threshold, err := service.getThreshold(userId, id)
+	if err != nil {
+		return err
+	}
+	return service.repo.DeleteThreshold(threshold.Id)
+}
+
+// CreateReading ghi chỉ số mới cho tài sản. Chỉ số phải không giảm và sau lần đọc gần nhất.
+// Khi chỉ số vượt qua bội số của một ngưỡng thì tạo lịch bảo trì từ ngày mai và báo cho người quản lý tài sản.
+// Lần đọc đầu tiên chỉ làm mốc, không kích hoạt ngưỡng
+func (service *MeterService) CreateReading(userId int64, assetId int64, request dto.CreateMeterReadingRequest) (*entity.MeterReading, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := service.assetRepo.GetAssetById(assetId)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccessAsset(user, asset) {
+		return nil, errors.New("asset not found")
+	}
+	if asset.Status == "Disposed" || asset.Status == "Retired" {
+		return nil, errors.New("can't record meter reading because status")
+	}
+	meter, err := service.repo.GetMeterById(request.MeterId)
+	if err != nil {
+		return nil, err
+	}
+	if meter.CompanyId != asset.CompanyId || !meter.IsActive {
+		return nil, errors.New("meter not found")
+	}
+	if meter.CategoryId != asset.CategoryId {
+		return nil, errors.New("meter does not apply to this asset category")
+	}
+	now := time.Now()
+	readAt := now
+	if request.ReadAt != nil {
+		readAt = *request.ReadAt
+		if readAt.After(now) {
+			return nil, errors.New("reading time can't be in the future")
+		}
+	}
+	value := *request.Value
+	last, err := service.repo.GetLastReading(asset.Id, meter.Id)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		if readAt.Before(last.ReadAt) {
+			return nil, fmt.Errorf("reading time must be after the latest reading (%v)", last.ReadAt.Format(time.RFC3339))
+		}
+		if value < last.Value {
+			return nil, fmt.Errorf("reading must not be less than the latest reading (%v %v)", last.Value, meter.Unit)
+		}
+	}
+
+	// Các ngưỡng vượt qua trong cùng một lần đọc được gộp vào một lịch bảo trì
+	crossed := []entity.MeterThreshold{}
+	if last != nil {
+		for _, t := range meter.Thresholds {
+			if t.IsActive && t.Interval > 0 && math.Floor(last.Value/t.Interval) < math.Floor(value/t.Interval) {
+				crossed = append(crossed, t)
+			}
+		}
+	}
+	var schedule *entity.MaintenanceSchedules
+	if len(crossed) > 0 && asset.Status != "Under Maintenance" {
+		durationDays := 1
+		cost := 0.0
+		for _, t := range crossed {
+			durationDays = max(durationDays, t.DurationDays)
+			cost += t.Cost
+		}
+		start := startOfTomorrow()
+		end := start.AddDate(0, 0, durationDays-1)
+		overlap := false
+		timeRange, err := service.scheduleRepo.GetDateMaintenanceSchedulesInFuture(asset.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range timeRange {
+			if !(end.Before(r.Start) || start.After(r.End)) {
+				overlap = true
+			}
+		}
+		if !overlap {
+			schedule = &entity.MaintenanceSchedules{AssetId: asset.Id, StartDate: start, EndDate: end, Cost: cost}
+		}
+	}
+
+	reading := &entity.MeterReading{
+		AssetId:      asset.Id,
+		MeterId:      meter.Id,
+		Value:        value,
+		ReadAt:       readAt,
+		RecordedById: userId,
+		Note:         request.Note,
+		CompanyId:    asset.CompanyId,
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if _, err = service.repo.CreateReading(reading, tx); err != nil {
+		return nil, err
+	}
+	if len(crossed) == 0 {
+		if err = tx.Commit().Error; err != nil {
+			return nil, fmt.Errorf("commit failed: %w", err)
+		}
+		return service.repo.GetReadingById(reading.Id)
+	}
+	if schedule != nil {
+		schedule.MeterReadingId = &reading.Id
+		if _, err = service.repo.CreateSchedule(schedule, tx); err != nil {
+			return nil, err
+		}
+		if err = service.repo.UpdateReadingSchedule(reading.Id, schedule.Id, tx); err != nil {
+			return nil, err
+		}
+		reading.ScheduleId = &schedule.Id
+	}
+	names := []string{}
+	for _, t := range crossed {
+		names = append(names, fmt.Sprintf("%v (every %v %v)", t.Name, t.Interval, meter.Unit))
+	}
+	summary := fmt.Sprintf("Meter %v reached %v %v (reading #%d by %v), thresholds crossed: %v", meter.Name, value, meter.Unit, reading.Id, user.Email, strings.Join(names, ", "))
+	if schedule != nil {
+		summary += fmt.Sprintf(", maintenance schedule #%d created", schedule.Id)
+	}
+	assetLog := entity.AssetLog{
+		Action:        "Meter Threshold",
+		Timestamp:     now,
+		ByUserId:      &userId,
+		AssetId:       asset.Id,
+		ChangeSummary: summary,
+		CompanyId:     asset.CompanyId,
+	}
+	if _, err = service.assetLogRepo.Create(&assetLog, tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+
+	userManagerAsset, _ := service.userRepo.GetUserAssetManageOfDepartment(asset.DepartmentId)
+	userHeadDepart, _ := service.userRepo.GetUserHeadDepartment(asset.DepartmentId)
+	usersToNotifications := utils.ConvertUsersToNotificationsToMap(userId, []*entity.Users{userManagerAsset, userHeadDepart})
+	message := fmt.Sprintf("The asset (ID: %v) reached %v %v on meter %v: %v", asset.Id, value, meter.Unit, meter.Name, strings.Join(names, ", "))
+	if schedule != nil {
+		message += fmt.Sprintf(". Maintenance schedule (ID: %v) has been created", schedule.Id)
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Println("SendNotificationToUsers panic:", r)
+			}
+		}()
+		service.NotificationService.SendNotificationToUsers(usersToNotifications, message, *asset)
+	}()
+	return service.repo.GetReadingById(reading.Id)
+}
+
+func (service *MeterService) GetReadings(userId int64, assetId int64, meterId *int64) ([]*entity.MeterReading, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := service.assetRepo.GetAssetById(assetId)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccessAsset(user, asset) {
+		return nil, errors.New("asset not found")
+	}
+	return service.repo.GetReadings(asset.Id, meterId)
+}
+
+func (service *MeterService) getThreshold(userId int64, id int64) (*entity.MeterThreshold, error) {
+	threshold, err := service.repo.GetThresholdById(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := service.GetMeterById(userId, threshold.MeterId); err != nil {
+		return nil, errors.New("can't find record this id")
+	}
+	return threshold, nil
+}
+
+func (service *MeterService) checkCategory(companyId int64, categoryId int64) error {
+	categories, err := service.categoryRepo.GetAll(companyId)
+	if err != nil {
+		return err
+	}
+	for _, c := range categories {
+		if c.Id == categoryId {
+			return nil
+		}
+	}
+	return errors.New("category not found")
+}
+
+func toThreshold(threshold *entity.MeterThreshold, request dto.MeterThresholdRequest) *entity.MeterThreshold {
+	threshold.Name = request.Name
+	threshold.Interval = request.Interval
+	threshold.DurationDays = request.DurationDays
+	if threshold.DurationDays == 0 {
+		threshold.DurationDays = 1
+	}
+	threshold.Cost = request.Cost
+	if request.IsActive != nil {
+		threshold.IsActive = *request.IsActive
+	}
+	return threshold
+}
+
+// Người giữ tài sản, quản lý phòng ban của tài sản hoặc quản lý tài sản của công ty
+func canAccessAsset(user *entity.Users, asset *entity.Assets) bool {
+	if asset.CompanyId != user.CompanyId {
+		return false
+	}
+	if user.Role.Slug == "admin" || user.Role.Slug == "assetManager" || (asset.Owner != nil && *asset.Owner == user.Id) {
+		return true
+	}
+	return (user.IsAssetManager || user.Role.Slug == "departmentHead") && user.DepartmentId != nil && *user.DepartmentId == asset.DepartmentId
+}
+
+func startOfTomorrow() time.Time {
+	loc, _ := time.LoadLocation("Asia/Bangkok") // GMT+7
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc)
+}
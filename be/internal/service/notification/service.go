@@ -62,6 +62,15 @@ func (ns *NotificationService) IsOnline(userId string) bool {
 }
 
 func (service *NotificationService) SendNotificationToUsers(users []*entity.Users, message string, asset entity.Assets) error {
+	return service.sendNotification(users, message, &asset.Id)
+}
+
+// SendNotificationWithoutAsset dùng cho thông báo không gắn với tài sản cụ thể
+func (service *NotificationService) SendNotificationWithoutAsset(users []*entity.Users, message string) error {
+	return service.sendNotification(users, message, nil)
+}
+
+func (service *NotificationService) sendNotification(users []*entity.Users, message string, assetId *int64) error {
 	status := "pending"
 	typeNotify := "Info"
 	timeNotify := time.Now()
@@ -74,7 +83,7 @@ func (service *NotificationService) SendNotificationToUsers(users []*entity.User
 			Status:     &status,
 			Type:       &typeNotify,
 			UserId:     &u.Id,
-			AssetId:    &asset.Id,
+			AssetId:    assetId,
 			NotifyDate: &timeNotify,
 		}
 		_, err := service.notificationRepository.Create(&notify)
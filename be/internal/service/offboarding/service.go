@@ -0,0 +1,419 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	asset_log "BE_Manage_device/internal/repository/asset_log"
+	asset "BE_Manage_device/internal/repository/assets"
+	assignment "BE_Manage_device/internal/repository/assignments"
+	department "BE_Manage_device/internal/repository/departments"
+	offboarding "BE_Manage_device/internal/repository/offboarding"
+	user "BE_Manage_device/internal/repository/user"
+	notificationS "BE_Manage_device/internal/service/notification"
+	workflowS "BE_Manage_device/internal/service/workflows"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type OffboardingService struct {
+	repo                offboarding.OffboardingRepository
+	assignmentRepo      assignment.AssignmentRepository
+	assetRepo           asset.AssetsRepository
+	assetLogRepo        asset_log.AssetsLogRepository
+	userRepo            user.UserRepository
+	departmentRepo      department.DepartmentsRepository
+	workflowService     *workflowS.WorkflowService
+	NotificationService *notificationS.NotificationService
+}
+
+func NewOffboardingService(repo offboarding.OffboardingRepository, assignmentRepo assignment.AssignmentRepository, assetRepo asset.AssetsRepository, assetLogRepo asset_log.AssetsLogRepository, userRepo user.UserRepository, departmentRepo department.DepartmentsRepository, workflowService *workflowS.WorkflowService, NotificationService *notificationS.NotificationService) *OffboardingService {
+	return &OffboardingService{repo: repo, assignmentRepo: assignmentRepo, assetRepo: assetRepo, assetLogRepo: assetLogRepo, userRepo: userRepo, departmentRepo: departmentRepo, workflowService: workflowService, NotificationService: NotificationService}
+}
+
+// Start mở quy trình nghỉ việc và sinh checklist thu hồi cho mọi tài sản người dùng đang giữ.
+// Đã có quy trình đang mở thì bổ sung tài sản mới nhận vào checklist
+func (service *OffboardingService) Start(adminId int64, userId int64) (*dto.OffboardingResponse, error) {
+	var err error
+	admin, err := service.userRepo.FindByUserId(adminId)
+	if err != nil {
+		return nil, err
+	}
+	leaver, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if leaver.CompanyId != admin.CompanyId {
+		return nil, errors.New("user not found")
+	}
+	if leaver.Id == admin.Id {
+		return nil, errors.New("can't offboard yourself")
+	}
+	current, err := service.repo.GetOpenOfUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	existing := map[int64]bool{}
+	if current == nil {
+		current = &entity.Offboarding{
+			UserId:      userId,
+			Status:      entity.OffboardingInProgress,
+			StartedById: adminId,
+			StartedAt:   time.Now(),
+			CompanyId:   admin.CompanyId,
+		}
+		if _, err = service.repo.Create(current, tx); err != nil {
+			return nil, err
+		}
+	} else {
+		full, err := service.repo.GetById(current.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range full.Items {
+			existing[item.AssetId] = true
+		}
+	}
+	assignments, err := service.repo.GetHeldAssignments(userId)
+	if err != nil {
+		return nil, err
+	}
+	items := []*entity.OffboardingItem{}
+	for _, a := range assignments {
+		if existing[a.AssetId] {
+			continue
+		}
+		items = append(items, &entity.OffboardingItem{
+			OffboardingId: current.Id,
+			AssetId:       a.AssetId,
+			AssignmentId:  a.Id,
+			Status:        entity.OffboardingItemPending,
+		})
+	}
+	if err = service.repo.CreateItems(items, tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return service.GetById(adminId, current.Id)
+}
+
+func (service *OffboardingService) GetAll(adminId int64, status *string) ([]dto.OffboardingResponse, error) {
+	admin, err := service.userRepo.FindByUserId(adminId)
+	if err != nil {
+		return nil, err
+	}
+	offboardings, err := service.repo.GetAll(admin.CompanyId, status)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.OffboardingResponse, 0, len(offboardings))
+	for _, o := range offboardings {
+		res = append(res, utils.ConvertOffboardingToResponse(o, nil))
+	}
+	return res, nil
+}
+
+// GetById trả về checklist kèm các yêu cầu điều chuyển chưa kết thúc của người nghỉ việc
+func (service *OffboardingService) GetById(adminId int64, id int64) (*dto.OffboardingResponse, error) {
+	offboarding, err := service.get(adminId, id)
+	if err != nil {
+		return nil, err
+	}
+	var pendingRequests []entity.RequestTransfer
+	if offboarding.Status == entity.OffboardingInProgress {
+		pendingRequests, err = service.repo.GetOpenRequestTransfers(offboarding.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	res := utils.ConvertOffboardingToResponse(offboarding, pendingRequests)
+	return &res, nil
+}
+
+func (service *OffboardingService) UpdateItem(adminId int64, id int64, itemId int64, request dto.UpdateOffboardingItemRequest) (*dto.OffboardingResponse, error) {
+	offboarding, err := service.get(adminId, id)
+	if err != nil {
+		return nil, err
+	}
+	if offboarding.Status != entity.OffboardingInProgress {
+		return nil, errors.New("offboarding is already closed")
+	}
+	item, err := service.repo.GetItemById(itemId)
+	if err != nil {
+		return nil, err
+	}
+	if item.OffboardingId != offboarding.Id {
+		return nil, errors.New("item not found")
+	}
+	now := time.Now()
+	item.Status = request.Status
+	item.Note = request.Note
+	item.CheckedById = &adminId
+	item.CheckedAt = &now
+	if request.Status == entity.OffboardingItemPending {
+		item.CheckedById = nil
+		item.CheckedAt = nil
+	}
+	if err := service.repo.UpdateItem(item); err != nil {
+		return nil, err
+	}
+	return service.GetById(adminId, id)
+}
+
+// Complete bàn giao toàn bộ tài sản, huỷ các yêu cầu điều chuyển đang mở, khoá tài khoản và
+// thu hồi mọi phiên đăng nhập trong một transaction, sau đó ký biên bản
+func (service *OffboardingService) Complete(adminId int64, id int64, request dto.CompleteOffboardingRequest) (*dto.OffboardingResponse, error) {
+	var err error
+	if (request.TargetUserId == nil) == (request.TargetDepartmentId == nil) {
+		return nil, errors.New("request must contain exactly one of targetUserId or targetDepartmentId")
+	}
+	offboarding, err := service.get(adminId, id)
+	if err != nil {
+		return nil, err
+	}
+	if offboarding.Status != entity.OffboardingInProgress {
+		return nil, errors.New("offboarding is already closed")
+	}
+	inChecklist := map[int64]bool{}
+	for _, item := range offboarding.Items {
+		if item.Status == entity.OffboardingItemPending {
+			return nil, fmt.Errorf("asset %v is not checked in return checklist", item.Asset.AssetName)
+		}
+		inChecklist[item.AssetId] = true
+	}
+	assignments, err := service.repo.GetHeldAssignments(offboarding.UserId)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range assignments {
+		if !inChecklist[a.AssetId] {
+			return nil, fmt.Errorf("asset %v is not in return checklist, start offboarding again to refresh", a.Asset.AssetName)
+		}
+	}
+	admin, err := service.userRepo.FindByUserId(adminId)
+	if err != nil {
+		return nil, err
+	}
+	holder, departmentId, err := service.resolveTarget(offboarding, request)
+	if err != nil {
+		return nil, err
+	}
+	pendingRequests, err := service.repo.GetOpenRequestTransfers(offboarding.UserId)
+	if err != nil {
+		return nil, err
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	reason := fmt.Sprintf("Offboarding #%v", offboarding.Id)
+	for _, a := range assignments {
+		if _, err = service.assignmentRepo.Update(a.Id, adminId, a.AssetId, &holder.Id, &departmentId, reason, tx); err != nil {
+			return nil, err
+		}
+		if _, err = service.assetRepo.UpdateAssetOwner(a.AssetId, holder.Id, tx); err != nil {
+			return nil, err
+		}
+		assetLog := entity.AssetLog{
+			Timestamp:     time.Now(),
+			Action:        "Transfer",
+			AssetId:       a.AssetId,
+			ByUserId:      &adminId,
+			AssignUserId:  &holder.Id,
+			CompanyId:     offboarding.CompanyId,
+			ChangeSummary: fmt.Sprintf("Offboarding of user %v: transfer to user %v\n", offboarding.User.Email, holder.Email),
+		}
+		if a.Asset.DepartmentId != departmentId {
+			if _, err = service.assetRepo.UpdateAssetDepartment(a.AssetId, departmentId, tx); err != nil {
+				return nil, err
+			}
+			assetLog.FromDepartmentId = &a.Asset.DepartmentId
+			assetLog.ToDepartmentId = &departmentId
+		}
+		if _, err = service.assetLogRepo.Create(&assetLog, tx); err != nil {
+			return nil, err
+		}
+	}
+	for i := range pendingRequests {
+		if err = service.workflowService.Cancel(adminId, entity.WorkflowEntityTransferRequest, pendingRequests[i].Id, "Requester offboarded", tx); err != nil {
+			return nil, err
+		}
+		if err = service.repo.CancelRequestTransfer(&pendingRequests[i], adminId, reason, tx); err != nil {
+			return nil, err
+		}
+	}
+	if err = service.repo.DeactivateUser(offboarding.UserId, tx); err != nil {
+		return nil, err
+	}
+	if err = service.repo.RevokeSessions(offboarding.UserId, tx); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	offboarding.Status = entity.OffboardingCompleted
+	offboarding.CompletedById = &adminId
+	offboarding.CompletedBy = admin
+	offboarding.CompletedAt = &now
+	offboarding.TargetUserId = request.TargetUserId
+	offboarding.TargetDepartmentId = request.TargetDepartmentId
+	offboarding.TargetUser = holder
+	offboarding.Note = request.Note
+	if request.TargetDepartmentId != nil {
+		offboarding.TargetUser = nil
+		offboarding.TargetDepartment, err = service.departmentRepo.GetDepartmentById(departmentId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	offboarding.Signature = utils.SignDocument(buildDocument(offboarding).Content())
+	if err = service.repo.Update(offboarding, tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	if len(assignments) > 0 {
+		message := fmt.Sprintf("%v assets of %v %v have been handed over to you", len(assignments), offboarding.User.FirstName, offboarding.User.LastName)
+		usersToNotifications := utils.ConvertUsersToNotificationsToMap(adminId, []*entity.Users{holder})
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Println("SendNotificationToUsers panic:", r)
+				}
+			}()
+			service.NotificationService.SendNotificationWithoutAsset(usersToNotifications, message)
+		}()
+	}
+	return service.GetById(adminId, id)
+}
+
+func (service *OffboardingService) Cancel(adminId int64, id int64) (*dto.OffboardingResponse, error) {
+	offboarding, err := service.get(adminId, id)
+	if err != nil {
+		return nil, err
+	}
+	if offboarding.Status != entity.OffboardingInProgress {
+		return nil, errors.New("offboarding is already closed")
+	}
+	offboarding.Status = entity.OffboardingCancelled
+	if err := service.repo.Update(offboarding, service.repo.GetDB()); err != nil {
+		return nil, err
+	}
+	return service.GetById(adminId, id)
+}
+
+// ExportPDF xuất biên bản đã ký, dựng lại nội dung từ DB và kiểm tra chữ ký trước khi xuất
+func (service *OffboardingService) ExportPDF(adminId int64, id int64) ([]byte, error) {
+	offboarding, err := service.get(adminId, id)
+	if err != nil {
+		return nil, err
+	}
+	if offboarding.Status != entity.OffboardingCompleted {
+		return nil, errors.New("offboarding is not completed")
+	}
+	document := buildDocument(offboarding)
+	if !utils.VerifyDocumentSignature(document.Content(), offboarding.Signature) {
+		return nil, errors.New("offboarding summary doesn't match its signature")
+	}
+	return utils.GenerateSignedPDF(document, offboarding.Signature)
+}
+
+func (service *OffboardingService) get(adminId int64, id int64) (*entity.Offboarding, error) {
+	admin, err := service.userRepo.FindByUserId(adminId)
+	if err != nil {
+		return nil, err
+	}
+	offboarding, err := service.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if offboarding.CompanyId != admin.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	return offboarding, nil
+}
+
+// resolveTarget trả về người nhận tài sản và phòng ban mới. Bàn giao về phòng ban thì người nhận là quản lý tài sản của phòng ban
+func (service *OffboardingService) resolveTarget(offboarding *entity.Offboarding, request dto.CompleteOffboardingRequest) (*entity.Users, int64, error) {
+	if request.TargetUserId != nil {
+		holder, err := service.userRepo.FindByUserId(*request.TargetUserId)
+		if err != nil {
+			return nil, 0, err
+		}
+		if holder.CompanyId != offboarding.CompanyId || !holder.IsActive {
+			return nil, 0, errors.New("target user not found")
+		}
+		if holder.Id == offboarding.UserId {
+			return nil, 0, errors.New("target user is the offboarded user")
+		}
+		if holder.DepartmentId == nil {
+			return nil, 0, errors.New("target user don't have department")
+		}
+		return holder, *holder.DepartmentId, nil
+	}
+	department, err := service.departmentRepo.GetDepartmentById(*request.TargetDepartmentId)
+	if err != nil {
+		return nil, 0, err
+	}
+	if department.CompanyId != offboarding.CompanyId {
+		return nil, 0, errors.New("department not found")
+	}
+	holder, err := service.userRepo.GetUserAssetManageOfDepartment(department.Id)
+	if err != nil {
+		return nil, 0, errors.New("department don't have asset manager")
+	}
+	if holder.Id == offboarding.UserId {
+		return nil, 0, errors.New("offboarded user is the asset manager of target department, assign a new asset manager first")
+	}
+	return holder, department.Id, nil
+}
+
+func buildDocument(offboarding *entity.Offboarding) utils.SignedDocument {
+	target := ""
+	if offboarding.TargetUser != nil {
+		target = fmt.Sprintf("%v %v <%v>", offboarding.TargetUser.FirstName, offboarding.TargetUser.LastName, offboarding.TargetUser.Email)
+	} else if offboarding.TargetDepartment != nil {
+		target = "Department pool: " + offboarding.TargetDepartment.DepartmentName
+	}
+	completedBy, completedAt := "", ""
+	if offboarding.CompletedBy != nil {
+		completedBy = offboarding.CompletedBy.Email
+	}
+	if offboarding.CompletedAt != nil {
+		completedAt = utils.FormatDocumentTime(*offboarding.CompletedAt)
+	}
+	document := utils.SignedDocument{
+		Title: fmt.Sprintf("Offboarding summary #%v", offboarding.Id),
+		Fields: [][2]string{
+			{"Employee", fmt.Sprintf("%v %v <%v>", offboarding.User.FirstName, offboarding.User.LastName, offboarding.User.Email)},
+			{"Started by", offboarding.StartedBy.Email},
+			{"Started at", utils.FormatDocumentTime(offboarding.StartedAt)},
+			{"Completed by", completedBy},
+			{"Completed at", completedAt},
+			{"Assets handed to", target},
+			{"Note", offboarding.Note},
+		},
+		Columns: []string{"Asset ID", "Asset", "Serial number", "Return", "Note"},
+		Widths:  []float64{20, 55, 40, 25, 50},
+	}
+	for _, item := range offboarding.Items {
+		document.Rows = append(document.Rows, []string{fmt.Sprint(item.AssetId), item.Asset.AssetName, item.Asset.SerialNumber, item.Status, item.Note})
+	}
+	return document
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"BE_Manage_device/config"
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	reliability "BE_Manage_device/internal/repository/reliability"
+	user "BE_Manage_device/internal/repository/user"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+const hoursPerYear = 24 * 365
+
+type ReliabilityService struct {
+	repo     reliability.ReliabilityRepository
+	userRepo user.UserRepository
+}
+
+func NewReliabilityService(repo reliability.ReliabilityRepository, userRepo user.UserRepository) *ReliabilityService {
+	return &ReliabilityService{repo: repo, userRepo: userRepo}
+}
+
+// Số liệu thô của một tài sản (hoặc cộng dồn của một nhóm)
+type reliabilityStats struct {
+	observed    float64 // Số giờ theo dõi (từ ngày đưa vào sử dụng đến nay/ngày ngừng sử dụng)
+	downtime    float64 // Số giờ nằm bảo trì (gộp các khoảng chồng nhau)
+	failures    int     // Số lần hỏng: bảo trì không theo kế hoạch + log "Maintenance" không gắn với lịch nào
+	planned     int
+	repairHours float64 // Tổng thời gian sửa của các lần hỏng có lịch
+	repairs     int
+}
+
+func (service *ReliabilityService) GetReport(userId int64, request dto.ReliabilityFilterRequest) (*dto.ReliabilityReportResponse, error) {
+	assets, err := service.load(userId, nil, request.CategoryId, request.DepartmentId)
+	if err != nil {
+		return nil, err
+	}
+	categories := []*dto.CategoryReliabilityResponse{}
+	categoryStats := map[int64]*reliabilityStats{}
+	categoryIndex := map[int64]*dto.CategoryReliabilityResponse{}
+	for _, a := range assets {
+		c, ok := categoryIndex[a.response.CategoryId]
+		if !ok {
+			c = &dto.CategoryReliabilityResponse{CategoryId: a.response.CategoryId, CategoryName: a.response.CategoryName}
+			categoryIndex[c.CategoryId] = c
+			categoryStats[c.CategoryId] = &reliabilityStats{}
+			categories = append(categories, c)
+		}
+		c.AssetCount++
+		if a.response.ReplacementCandidate {
+			c.ReplacementCandidates++
+		}
+		categoryStats[c.CategoryId].add(a.stats)
+	}
+	for _, c := range categories {
+		s := categoryStats[c.CategoryId]
+		c.Failures = s.failures
+		c.DowntimeHours = roundMetric(s.downtime)
+		c.DowntimePercent = s.downtimePercent()
+		c.MtbfHours = s.mtbf()
+		c.MttrHours = s.mttr()
+		c.FailuresPerYear = s.failuresPerYear()
+	}
+	sort.SliceStable(categories, func(i, j int) bool { return categories[i].FailuresPerYear > categories[j].FailuresPerYear })
+
+	res := make([]*dto.AssetReliabilityResponse, 0, len(assets))
+	for _, a := range assets {
+		if request.CandidatesOnly && !a.response.ReplacementCandidate {
+			continue
+		}
+		res = append(res, a.response)
+	}
+	sortAssets(res, request.SortBy, request.Order)
+	if request.Limit > 0 && len(res) > request.Limit {
+		res = res[:request.Limit]
+	}
+	return &dto.ReliabilityReportResponse{
+		FailureRateThreshold: config.ReliabilityFailureThreshold,
+		Assets:               res,
+		Categories:           categories,
+	}, nil
+}
+
+func (service *ReliabilityService) GetAssetReliability(userId int64, assetId int64) (*dto.AssetReliabilityResponse, error) {
+	assets, err := service.load(userId, []int64{assetId}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(assets) == 0 {
+		return nil, errors.New("can't find asset")
+	}
+	return assets[0].response, nil
+}
+
+// GetSummary tổng hợp độ tin cậy của các tài sản đang hiển thị trên dashboard
+func (service *ReliabilityService) GetSummary(userId int64, assetIds []int64) (*dto.ReliabilitySummary, error) {
+	assets, err := service.load(userId, assetIds, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	total := &reliabilityStats{}
+	candidates := []*dto.AssetReliabilityResponse{}
+	for _, a := range assets {
+		total.add(a.stats)
+		if a.response.ReplacementCandidate {
+			candidates = append(candidates, a.response)
+		}
+	}
+	summary := &dto.ReliabilitySummary{
+		FailureRateThreshold:  config.ReliabilityFailureThreshold,
+		MtbfHours:             total.mtbf(),
+		MttrHours:             total.mttr(),
+		DowntimePercent:       total.downtimePercent(),
+		ReplacementCandidates: len(candidates),
+	}
+	sortAssets(candidates, "failureRate", "desc")
+	if len(candidates) > 5 {
+		candidates = candidates[:5]
+	}
+	summary.TopCandidates = candidates
+	return summary, nil
+}
+
+type assetReliability struct {
+	stats    *reliabilityStats
+	response *dto.AssetReliabilityResponse
+}
+
+func (service *ReliabilityService) load(userId int64, assetIds []int64, categoryId, departmentId *int64) ([]*assetReliability, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	departmentId, ok := utils.ViewerDepartmentScope(user, departmentId)
+	if !ok {
+		return []*assetReliability{}, nil
+	}
+	rows, err := service.repo.GetAssets(user.CompanyId, assetIds, categoryId, departmentId)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.AssetId)
+	}
+	now := time.Now()
+	schedules, err := service.repo.GetFinishedSchedules(ids, now)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := service.repo.GetMaintenanceLogs(ids)
+	if err != nil {
+		return nil, err
+	}
+	schedulesByAsset := map[int64][]*entity.MaintenanceSchedules{}
+	for _, s := range schedules {
+		schedulesByAsset[s.AssetId] = append(schedulesByAsset[s.AssetId], s)
+	}
+	logsByAsset := map[int64][]*entity.AssetLog{}
+	for _, l := range logs {
+		logsByAsset[l.AssetId] = append(logsByAsset[l.AssetId], l)
+	}
+	res := make([]*assetReliability, 0, len(rows))
+	for _, r := range rows {
+		stats := computeStats(r, schedulesByAsset[r.AssetId], logsByAsset[r.AssetId], now)
+		res = append(res, &assetReliability{stats: stats, response: toResponse(r, stats)})
+	}
+	return res, nil
+}
+
+func computeStats(row *reliability.AssetReliabilityRow, schedules []*entity.MaintenanceSchedules, logs []*entity.AssetLog, now time.Time) *reliabilityStats {
+	start := row.PurchaseDate
+	if row.AcquisitionDate != nil {
+		start = *row.AcquisitionDate
+	}
+	end := now
+	if row.RetiredAt != nil && row.RetiredAt.Before(now) {
+		end = *row.RetiredAt
+	}
+	stats := &reliabilityStats{observed: math.Max(end.Sub(start).Hours(), 0)}
+	intervals := [][2]time.Time{}
+	for _, s := range schedules {
+		if s.PlanId != nil || s.MeterReadingId != nil {
+			stats.planned++
+		} else {
+			stats.failures++
+			stats.repairHours += math.Max(s.EndDate.Sub(s.StartDate).Hours(), 0)
+			stats.repairs++
+		}
+		from, to := s.StartDate, s.EndDate
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			intervals = append(intervals, [2]time.Time{from, to})
+		}
+	}
+	// Log bảo trì không nằm trong lịch nào (dữ liệu cũ) vẫn tính là một lần hỏng, không rõ thời gian sửa
+	for _, l := range logs {
+		matched := false
+		for _, s := range schedules {
+			if !l.Timestamp.Before(s.StartDate) && !l.Timestamp.After(s.EndDate.Add(24*time.Hour)) {
+				matched = true
+				break
+			}
+		}
+		if !matched && !l.Timestamp.Before(start) && !l.Timestamp.After(end) {
+			stats.failures++
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i][0].Before(intervals[j][0]) })
+	var current *[2]time.Time
+	for i := range intervals {
+		iv := intervals[i]
+		if current != nil && !iv[0].After(current[1]) {
+			if iv[1].After(current[1]) {
+				current[1] = iv[1]
+			}
+			continue
+		}
+		if current != nil {
+			stats.downtime += current[1].Sub(current[0]).Hours()
+		}
+		current = &iv
+	}
+	if current != nil {
+		stats.downtime += current[1].Sub(current[0]).Hours()
+	}
+	return stats
+}
+
+func toResponse(row *reliability.AssetReliabilityRow, stats *reliabilityStats) *dto.AssetReliabilityResponse {
+	failuresPerYear := stats.failuresPerYear()
+	return &dto.AssetReliabilityResponse{
+		AssetId:              row.AssetId,
+		AssetName:            row.AssetName,
+		SerialNumber:         row.SerialNumber,
+		Status:               row.Status,
+		CategoryId:           row.CategoryId,
+		CategoryName:         row.CategoryName,
+		DepartmentId:         row.DepartmentId,
+		DepartmentName:       row.DepartmentName,
+		ObservedHours:        roundMetric(stats.observed),
+		Failures:             stats.failures,
+		PlannedMaintenances:  stats.planned,
+		DowntimeHours:        roundMetric(stats.downtime),
+		DowntimePercent:      stats.downtimePercent(),
+		MtbfHours:            stats.mtbf(),
+		MttrHours:            stats.mttr(),
+		FailuresPerYear:      failuresPerYear,
+		ReplacementCandidate: stats.failures > 0 && failuresPerYear > config.ReliabilityFailureThreshold,
+	}
+}
+
+func (s *reliabilityStats) add(other *reliabilityStats) {
+	s.observed += other.observed
+	s.downtime += other.downtime
+	s.failures += other.failures
+	s.planned += other.planned
+	s.repairHours += other.repairHours
+	s.repairs += other.repairs
+}
+
+// MTBF = thời gian hoạt động (theo dõi - nằm bảo trì) / số lần hỏng
+func (s *reliabilityStats) mtbf() *float64 {
+	if s.failures == 0 {
+		return nil
+	}
+	v := roundMetric(math.Max(s.observed-s.downtime, 0) / float64(s.failures))
+	return &v
+}
+
+// MTTR = tổng thời gian sửa / số lần sửa có lịch
+func (s *reliabilityStats) mttr() *float64 {
+	if s.repairs == 0 {
+		return nil
+	}
+	v := roundMetric(s.repairHours / float64(s.repairs))
+	return &v
+}
+
+func (s *reliabilityStats) downtimePercent() float64 {
+	if s.observed <= 0 {
+		return 0
+	}
+	return roundMetric(s.downtime / s.observed * 100)
+}
+
+func (s *reliabilityStats) failuresPerYear() float64 {
+	if s.observed <= 0 {
+		return 0
+	}
+	return roundMetric(float64(s.failures) / (s.observed / hoursPerYear))
+}
+
+// MTBF/MTTR nil được xếp cuối
+func sortAssets(assets []*dto.AssetReliabilityResponse, sortBy string, order string) {
+	value := func(a *dto.AssetReliabilityResponse) (float64, bool) {
+		switch sortBy {
+		case "failures":
+			return float64(a.Failures), true
+		case "mtbf":
+			if a.MtbfHours == nil {
+				return 0, false
+			}
+			return *a.MtbfHours, true
+		case "mttr":
+			if a.MttrHours == nil {
+				return 0, false
+			}
+			return *a.MttrHours, true
+		case "downtime":
+			return a.DowntimePercent, true
+		default:
+			return a.FailuresPerYear, true
+		}
+	}
+	sort.SliceStable(assets, func(i, j int) bool {
+		vi, oki := value(assets[i])
+		vj, okj := value(assets[j])
+		if oki != okj {
+			return oki
+		}
+		if order == "asc" {
+			return vi < vj
+		}
+		return vi > vj
+	})
+}
+
+func roundMetric(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -1,64 +1,151 @@
 package service
 
 import (
+	"BE_Manage_device/config"
 	"BE_Manage_device/internal/domain/dto"
 	"BE_Manage_device/internal/domain/entity"
 	"BE_Manage_device/internal/domain/filter"
 	asset "BE_Manage_device/internal/repository/assets"
+	categories "BE_Manage_device/internal/repository/categories"
 	request_transfer "BE_Manage_device/internal/repository/request_transfer"
 	user "BE_Manage_device/internal/repository/user"
 	assignmentS "BE_Manage_device/internal/service/assignment"
+	notificationS "BE_Manage_device/internal/service/notification"
+	workflowS "BE_Manage_device/internal/service/workflows"
 	"BE_Manage_device/pkg/utils"
 
 	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 type RequestTransferService struct {
-	repo              request_transfer.RequestTransferRepository
-	assignmentService *assignmentS.AssignmentService
-	userRepo          user.UserRepository
-	assetRepo         asset.AssetsRepository
+	repo                request_transfer.RequestTransferRepository
+	assignmentService   *assignmentS.AssignmentService
+	userRepo            user.UserRepository
+	assetRepo           asset.AssetsRepository
+	categoryRepo        categories.CategoriesRepository
+	notificationService *notificationS.NotificationService
+	workflowService     *workflowS.WorkflowService
 }
 
-func NewRequestTransferService(repo request_transfer.RequestTransferRepository, assignmentService *assignmentS.AssignmentService, userRepo user.UserRepository, assetRepo asset.AssetsRepository) *RequestTransferService {
-	return &RequestTransferService{repo: repo, assignmentService: assignmentService, userRepo: userRepo, assetRepo: assetRepo}
+func NewRequestTransferService(repo request_transfer.RequestTransferRepository, assignmentService *assignmentS.AssignmentService, userRepo user.UserRepository, assetRepo asset.AssetsRepository, categoryRepo categories.CategoriesRepository, notificationService *notificationS.NotificationService, workflowService *workflowS.WorkflowService) *RequestTransferService {
+	return &RequestTransferService{repo: repo, assignmentService: assignmentService, userRepo: userRepo, assetRepo: assetRepo, categoryRepo: categoryRepo, notificationService: notificationService, workflowService: workflowService}
 }
 
-func (service *RequestTransferService) Create(userId int64, categoryId int64, description string) (*entity.RequestTransfer, error) {
+func (service *RequestTransferService) Create(userId int64, request dto.CreateRequestTransferRequest) (*entity.RequestTransfer, error) {
+	var err error
 	user, err := service.userRepo.FindByUserId(userId)
 	if err != nil {
 		return nil, err
 	}
+	if user.DepartmentId == nil {
+		return nil, errors.New("user don't have department")
+	}
+	if (request.AssetId == nil) == (request.CategoryId == nil) {
+		return nil, errors.New("request must target exactly one of assetId or categoryId")
+	}
+	if request.NeededBy != nil && request.NeededBy.Before(time.Now()) {
+		return nil, errors.New("neededBy must be in the future")
+	}
 	requestTransfer := entity.RequestTransfer{
 		UserId:      userId,
-		CategoryId:  categoryId,
-		Status:      "Pending",
-		Description: description,
+		Quantity:    request.Quantity,
+		NeededBy:    request.NeededBy,
+		Status:      entity.RequestTransferPending,
+		Description: request.Description,
 		CompanyId:   user.CompanyId,
 	}
-	requestTransferCreate, err := service.repo.Create(&requestTransfer)
+	if requestTransfer.Quantity == 0 {
+		requestTransfer.Quantity = 1
+	}
+	if request.AssetId != nil {
+		asset, err := service.assetRepo.GetAssetById(*request.AssetId)
+		if err != nil {
+			return nil, err
+		}
+		if asset.CompanyId != user.CompanyId {
+			return nil, errors.New("asset not found")
+		}
+		if asset.Status == "Retired" || asset.Status == "Disposed" {
+			return nil, errors.New("can't request transfer because asset status")
+		}
+		if asset.DepartmentId == *user.DepartmentId {
+			return nil, errors.New("asset department same request department")
+		}
+		// Yêu cầu tài sản cụ thể thì số lượng luôn là 1
+		requestTransfer.AssetId = &asset.Id
+		requestTransfer.CategoryId = asset.CategoryId
+		requestTransfer.Quantity = 1
+	} else {
+		categories, err := service.categoryRepo.GetAll(user.CompanyId)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, c := range categories {
+			if c.Id == *request.CategoryId {
+				found = true
+			}
+		}
+		if !found {
+			return nil, errors.New("category not found")
+		}
+		requestTransfer.CategoryId = *request.CategoryId
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if _, err = service.repo.Create(&requestTransfer, tx); err != nil {
+		return nil, err
+	}
+	if err = service.addHistory(&requestTransfer, "", entity.RequestTransferPending, &userId, "Request created", tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	requestCreate, err := service.repo.GetRequestTransferById(requestTransfer.Id)
 	if err != nil {
 		return nil, err
 	}
-	return requestTransferCreate, err
+	managers, err := service.repo.GetTransferManagers(requestCreate.CompanyId)
+	if err == nil {
+		service.notify(userId, managers, requestCreate, fmt.Sprintf("New transfer request #%v from %v %v: %v", requestCreate.Id, user.FirstName, user.LastName, requestCreate.Description))
+	}
+	return requestCreate, nil
 }
 
-func (service *RequestTransferService) Accept(userId int64, id int64, assetId int64) (*entity.RequestTransfer, error) {
+// Accept gán tài sản cho yêu cầu. Công ty có cấu hình quy trình duyệt thì chỉ gửi duyệt và trả về yêu cầu duyệt,
+// việc điều chuyển được thực hiện khi quy trình được duyệt xong
+func (service *RequestTransferService) Accept(userId int64, id int64, assetId *int64) (*entity.RequestTransfer, *entity.WorkflowInstance, error) {
 	var err error
-	requestCheck, err := service.repo.GetRequestTransferById(id)
+	requestCheck, err := service.getVisibleRequest(userId, id)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if requestCheck.Status == "Deny" {
-		return nil, errors.New("can't change request")
+	if requestCheck.Status == entity.RequestTransferInApproval {
+		return nil, nil, errors.New("request is waiting for approval")
 	}
-	assetCheck, err := service.assetRepo.GetAssetById(assetId)
-	if err != nil {
-		return nil, err
+	if assetId == nil {
+		if requestCheck.AssetId == nil {
+			return nil, nil, errors.New("assetId is required for category request")
+		}
+		assetId = requestCheck.AssetId
 	}
-	if assetCheck.DepartmentId == *requestCheck.User.DepartmentId {
-		return nil, errors.New("asset department same request department")
+	assetCheck, err := service.checkAccept(requestCheck, *assetId)
+	if err != nil {
+		return nil, nil, err
 	}
 	tx := service.repo.GetDB().Begin()
 	defer func() {
@@ -69,38 +156,194 @@ func (service *RequestTransferService) Accept(userId int64, id int64, assetId in
 			tx.Rollback()
 		}
 	}()
-	request, err := service.repo.UpdateStatusConfirm(id, tx)
+	instance, err := service.workflowService.Start(userId, requestCheck.CompanyId, entity.WorkflowEntityTransferRequest, requestCheck.Id, assetCheck.Id, assetCheck.DepartmentId, assetCheck.Cost, nil, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if instance == nil {
+		var notify func()
+		if notify, err = service.transfer(userId, requestCheck, assetCheck, tx); err != nil {
+			return nil, nil, err
+		}
+		if err = tx.Commit().Error; err != nil {
+			return nil, nil, fmt.Errorf("commit failed: %w", err)
+		}
+		notify()
+		request, err := service.repo.GetRequestTransferById(requestCheck.Id)
+		return request, nil, err
+	}
+	if err = service.changeStatus(requestCheck, entity.RequestTransferInApproval, &userId, fmt.Sprintf("Asset %v submitted for approval", assetCheck.AssetName), tx); err != nil {
+		return nil, nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, nil, fmt.Errorf("commit failed: %w", err)
+	}
+	instance, err = service.workflowService.Submitted(instance)
+	return nil, instance, err
+}
+
+// OnWorkflowApproved thực hiện điều chuyển khi quy trình duyệt xong
+func (service *RequestTransferService) OnWorkflowApproved(instance *entity.WorkflowInstance, approverId int64, tx *gorm.DB) (func(), error) {
+	requestCheck, err := service.repo.GetRequestTransferById(instance.EntityId)
 	if err != nil {
 		return nil, err
 	}
-	assignment, err := service.assignmentService.Repo.GetAssignmentByAssetId(assetId)
+	assetCheck, err := service.checkAccept(requestCheck, instance.AssetId)
 	if err != nil {
 		return nil, err
 	}
+	return service.transfer(approverId, requestCheck, assetCheck, tx)
+}
+
+func (service *RequestTransferService) OnWorkflowRejected(instance *entity.WorkflowInstance, approverId int64, tx *gorm.DB) (func(), error) {
+	requestCheck, err := service.repo.GetRequestTransferById(instance.EntityId)
+	if err != nil {
+		return nil, err
+	}
+	// Đã giao một phần thì quay lại trạng thái Approved để tiếp tục giao tài sản khác
+	status := entity.RequestTransferDenied
+	if requestCheck.FulfilledQuantity > 0 {
+		status = entity.RequestTransferApproved
+	}
+	note := "Rejected in approval workflow"
+	if instance.RejectReason != nil {
+		note += ": " + *instance.RejectReason
+	}
+	return nil, service.changeStatus(requestCheck, status, &approverId, note, tx)
+}
+
+func (service *RequestTransferService) checkAccept(requestCheck *entity.RequestTransfer, assetId int64) (*entity.Assets, error) {
+	switch requestCheck.Status {
+	case entity.RequestTransferPending, entity.RequestTransferApproved, entity.RequestTransferInApproval:
+	default:
+		return nil, errors.New("can't change request")
+	}
+	assetCheck, err := service.assetRepo.GetAssetById(assetId)
+	if err != nil {
+		return nil, err
+	}
+	if assetCheck.CompanyId != requestCheck.CompanyId {
+		return nil, errors.New("asset not found")
+	}
+	if assetCheck.Status == "Retired" || assetCheck.Status == "Disposed" {
+		return nil, errors.New("can't transfer because asset status")
+	}
+	if requestCheck.AssetId != nil && *requestCheck.AssetId != assetId {
+		return nil, errors.New("request targets another asset")
+	}
+	if requestCheck.AssetId == nil && assetCheck.CategoryId != requestCheck.CategoryId {
+		return nil, errors.New("asset category doesn't match request category")
+	}
 	if requestCheck.User.DepartmentId == nil {
 		return nil, errors.New("user don't have department")
 	}
+	if assetCheck.DepartmentId == *requestCheck.User.DepartmentId {
+		return nil, errors.New("asset department same request department")
+	}
+	return assetCheck, nil
+}
+
+// transfer điều chuyển một tài sản cho yêu cầu trong tx, đủ số lượng thì chuyển sang Fulfilled.
+// Trả về hàm gửi thông báo để gọi sau khi tx commit
+func (service *RequestTransferService) transfer(userId int64, requestCheck *entity.RequestTransfer, assetCheck *entity.Assets, tx *gorm.DB) (func(), error) {
+	assignment, err := service.assignmentService.Repo.GetAssignmentByAssetId(assetCheck.Id)
+	if err != nil {
+		return nil, err
+	}
 	userAssign, err := service.userRepo.GetUserAssetManageOfDepartment(*requestCheck.User.DepartmentId)
 	if err != nil {
 		return nil, err
 	}
-	_, err = service.assignmentService.Update(userId, assignment.Id, &userAssign.Id, requestCheck.User.DepartmentId)
+	_, notifyAssignment, err := service.assignmentService.UpdateInTx(userId, assignment.Id, &userAssign.Id, requestCheck.User.DepartmentId, fmt.Sprintf("Transfer request #%v", requestCheck.Id), tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = service.repo.CreateTransferredAsset(&entity.RequestTransferAsset{
+		RequestId:     requestCheck.Id,
+		AssetId:       assetCheck.Id,
+		ByUserId:      userId,
+		TransferredAt: time.Now(),
+	}, tx); err != nil {
+		return nil, err
+	}
+	if err = service.repo.IncreaseFulfilledQuantity(requestCheck.Id, tx); err != nil {
+		return nil, err
+	}
+	fulfilled := requestCheck.FulfilledQuantity + 1
+	note := fmt.Sprintf("Asset %v transferred (%v/%v)", assetCheck.AssetName, fulfilled, requestCheck.Quantity)
+	if requestCheck.Status != entity.RequestTransferApproved {
+		if err = service.changeStatus(requestCheck, entity.RequestTransferApproved, &userId, note, tx); err != nil {
+			return nil, err
+		}
+	}
+	if fulfilled >= requestCheck.Quantity {
+		if err = service.changeStatus(requestCheck, entity.RequestTransferFulfilled, &userId, note, tx); err != nil {
+			return nil, err
+		}
+	}
+	notify := func() {
+		notifyAssignment()
+		request, err := service.repo.GetRequestTransferById(requestCheck.Id)
+		if err == nil {
+			service.notify(userId, []*entity.Users{&request.User}, request, fmt.Sprintf("Your transfer request #%v: %v", request.Id, note))
+		}
+	}
+	return notify, nil
+}
+
+func (service *RequestTransferService) Deny(userId int64, id int64, reason string) (*entity.RequestTransfer, error) {
+	var err error
+	requestCheck, err := service.getVisibleRequest(userId, id)
 	if err != nil {
 		return nil, err
 	}
+	if requestCheck.Status != entity.RequestTransferPending && requestCheck.Status != entity.RequestTransferInApproval {
+		return nil, errors.New("can't change request")
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	// Đang chờ duyệt thì huỷ luôn quy trình
+	if err = service.workflowService.Cancel(userId, entity.WorkflowEntityTransferRequest, id, "Request denied", tx); err != nil {
+		return nil, err
+	}
+	if err = service.changeStatus(requestCheck, entity.RequestTransferDenied, &userId, reason, tx); err != nil {
+		return nil, err
+	}
 	if err = tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("commit failed: %w", err)
 	}
+	request, err := service.repo.GetRequestTransferById(id)
+	if err != nil {
+		return nil, err
+	}
+	message := fmt.Sprintf("Your transfer request #%v has been denied", request.Id)
+	if reason != "" {
+		message += ": " + reason
+	}
+	service.notify(userId, []*entity.Users{&request.User}, request, message)
 	return request, nil
 }
 
-func (service *RequestTransferService) Deny(userId int64, id int64) (*entity.RequestTransfer, error) {
+// Cancel người tạo tự huỷ yêu cầu, các tài sản đã giao trước đó giữ nguyên
+func (service *RequestTransferService) Cancel(userId int64, id int64, reason string) (*entity.RequestTransfer, error) {
 	var err error
 	requestCheck, err := service.repo.GetRequestTransferById(id)
 	if err != nil {
 		return nil, err
 	}
-	if requestCheck.Status == "Confirm" {
+	if requestCheck.UserId != userId {
+		return nil, errors.New("only requester can cancel request")
+	}
+	switch requestCheck.Status {
+	case entity.RequestTransferPending, entity.RequestTransferInApproval, entity.RequestTransferApproved:
+	default:
 		return nil, errors.New("can't change request")
 	}
 	tx := service.repo.GetDB().Begin()
@@ -112,22 +355,111 @@ func (service *RequestTransferService) Deny(userId int64, id int64) (*entity.Req
 			tx.Rollback()
 		}
 	}()
-	request, err := service.repo.UpdateStatusDeny(id, tx)
-	if err != nil {
+	if err = service.workflowService.Cancel(userId, entity.WorkflowEntityTransferRequest, id, "Request cancelled", tx); err != nil {
+		return nil, err
+	}
+	if err = service.changeStatus(requestCheck, entity.RequestTransferCancelled, &userId, reason, tx); err != nil {
 		return nil, err
 	}
 	if err = tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("commit failed: %w", err)
 	}
-	return request, nil
+	return service.repo.GetRequestTransferById(id)
 }
 
-func (service *RequestTransferService) GetRequestTransferById(userId int64, id int64) (*entity.RequestTransfer, error) {
-	request, err := service.repo.GetRequestTransferById(id)
+func (service *RequestTransferService) GetRequestTransferById(userId int64, id int64) (*dto.RequestTransferDetailResponse, error) {
+	request, err := service.getVisibleRequest(userId, id)
 	if err != nil {
 		return nil, err
 	}
-	return request, nil
+	histories, err := service.repo.GetHistories(id)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := service.repo.GetTransferredAssets(id)
+	if err != nil {
+		return nil, err
+	}
+	res := utils.ConvertRequestTransferToDetailResponse(request, histories, assets)
+	return &res, nil
+}
+
+func (service *RequestTransferService) GetMine(userId int64, status *string) ([]dto.RequestTransferResponse, error) {
+	requests, err := service.repo.GetByUserId(userId, status)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ConvertRequestTransfersToResponses(requests), nil
+}
+
+func (service *RequestTransferService) AddComment(userId int64, id int64, content string) (*entity.RequestTransferComment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.New("content is required")
+	}
+	request, err := service.getVisibleRequest(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := service.repo.CreateComment(&entity.RequestTransferComment{
+		RequestId: id,
+		UserId:    userId,
+		Content:   content,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Người tạo bình luận thì báo người xử lý, ngược lại báo người tạo
+	var usersToNotify []*entity.Users
+	if userId == request.UserId {
+		usersToNotify, err = service.repo.GetTransferManagers(request.CompanyId)
+		if err != nil {
+			logrus.Infof("Happen error when get transfer managers of company %v: %v", request.CompanyId, err)
+		}
+	} else {
+		usersToNotify = []*entity.Users{&request.User}
+	}
+	service.notify(userId, usersToNotify, request, fmt.Sprintf("New comment on transfer request #%v from %v %v: %v", request.Id, comment.User.FirstName, comment.User.LastName, content))
+	return comment, nil
+}
+
+func (service *RequestTransferService) GetComments(userId int64, id int64) ([]*entity.RequestTransferComment, error) {
+	if _, err := service.getVisibleRequest(userId, id); err != nil {
+		return nil, err
+	}
+	return service.repo.GetComments(id)
+}
+
+// SendSlaReminders chạy trong cron job, nhắc người xử lý các yêu cầu chờ quá TransferRequestSlaHours
+func (service *RequestTransferService) SendSlaReminders() {
+	now := time.Now()
+	requests, err := service.repo.GetPendingBefore(now.Add(-time.Duration(config.TransferRequestSlaHours) * time.Hour))
+	if err != nil {
+		logrus.Infof("Happen error when get pending transfer requests at: %v", now)
+		return
+	}
+	managersByCompany := map[int64][]*entity.Users{}
+	for i := range requests {
+		request := &requests[i]
+		managers, ok := managersByCompany[request.CompanyId]
+		if !ok {
+			managers, err = service.repo.GetTransferManagers(request.CompanyId)
+			if err != nil {
+				logrus.Infof("Happen error when get transfer managers of company %v: %v", request.CompanyId, err)
+				continue
+			}
+			managersByCompany[request.CompanyId] = managers
+		}
+		message := fmt.Sprintf("Transfer request #%v from %v %v has been pending for more than %v hours", request.Id, request.User.FirstName, request.User.LastName, config.TransferRequestSlaHours)
+		if request.NeededBy != nil {
+			message += fmt.Sprintf(", needed by %v", request.NeededBy.Format("2006-01-02"))
+		}
+		service.notify(0, managers, request, message)
+		if err := service.repo.UpdateLastReminder(request.Id, now); err != nil {
+			logrus.Infof("Happen error when update reminder of transfer request %v: %v", request.Id, err)
+		}
+	}
 }
 
 func (service *RequestTransferService) Filter(userId int64, status *string) ([]dto.RequestTransferResponse, error) {
@@ -153,3 +485,71 @@ func (service *RequestTransferService) Filter(userId int64, status *string) ([]d
 
 	return requestRes, nil
 }
+
+// getVisibleRequest người tạo hoặc người có quyền transfer-assets full trong công ty mới xem được
+func (service *RequestTransferService) getVisibleRequest(userId int64, id int64) (*entity.RequestTransfer, error) {
+	request, err := service.repo.GetRequestTransferById(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.UserId == userId {
+		return request, nil
+	}
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.CompanyId != request.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	canManage, err := utils.UserHasPermission(service.repo.GetDB(), userId, []string{"transfer-assets"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, errors.New("you don't have permission to view this request")
+	}
+	return request, nil
+}
+
+func (service *RequestTransferService) changeStatus(request *entity.RequestTransfer, status string, byUserId *int64, note string, tx *gorm.DB) error {
+	if err := service.repo.UpdateStatus(request.Id, status, tx); err != nil {
+		return err
+	}
+	if err := service.addHistory(request, request.Status, status, byUserId, note, tx); err != nil {
+		return err
+	}
+	request.Status = status
+	return nil
+}
+
+func (service *RequestTransferService) addHistory(request *entity.RequestTransfer, from string, to string, byUserId *int64, note string, tx *gorm.DB) error {
+	return service.repo.CreateHistory(&entity.RequestTransferHistory{
+		RequestId:  request.Id,
+		FromStatus: from,
+		ToStatus:   to,
+		ByUserId:   byUserId,
+		Note:       note,
+		CreatedAt:  time.Now(),
+	}, tx)
+}
+
+// notify gửi thông báo, yêu cầu theo loại chưa có tài sản thì gửi không gắn tài sản
+func (service *RequestTransferService) notify(actorId int64, users []*entity.Users, request *entity.RequestTransfer, message string) {
+	usersToNotifications := utils.ConvertUsersToNotificationsToMap(actorId, users)
+	if len(usersToNotifications) == 0 {
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Println("SendNotificationToUsers panic:", r)
+			}
+		}()
+		if request.AssetId != nil {
+			service.notificationService.SendNotificationToUsers(usersToNotifications, message, entity.Assets{Id: *request.AssetId})
+		} else {
+			service.notificationService.SendNotificationWithoutAsset(usersToNotifications, message)
+		}
+	}()
+}
@@ -0,0 +1,1188 @@
+package service
+
+import (
+	"BE_Manage_device/config"
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	auditLog "BE_Manage_device/internal/repository/audit_logs"
+	department "BE_Manage_device/internal/repository/departments"
+	location "BE_Manage_device/internal/repository/locations"
+	role "BE_Manage_device/internal/repository/role"
+	scimRepo "BE_Manage_device/internal/repository/scim"
+	user "BE_Manage_device/internal/repository/user"
+	userRBAC "BE_Manage_device/internal/repository/user_rbac"
+	userSession "BE_Manage_device/internal/repository/user_session"
+	"BE_Manage_device/pkg/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const defaultRoleSlug = "viewer"
+
+// ScimError lỗi trả về cho IdP theo định dạng SCIM
+type ScimError struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+func (e *ScimError) Error() string {
+	return e.Detail
+}
+
+func scimError(status int, scimType string, format string, args ...interface{}) *ScimError {
+	return &ScimError{Status: status, ScimType: scimType, Detail: fmt.Sprintf(format, args...)}
+}
+
+// Cột của bảng users ứng với thuộc tính dùng trong filter /Users
+var userFilterColumns = map[string]string{
+	"id":              "users.id",
+	"username":        "users.email",
+	"emails":          "users.email",
+	"emails.value":    "users.email",
+	"externalid":      "user_identities.subject",
+	"name.givenname":  "users.first_name",
+	"name.familyname": "users.last_name",
+	"active":          "users.is_active",
+}
+
+var enterpriseDepartment = strings.ToLower(dto.ScimEnterpriseDeptPath)
+
+type ScimService struct {
+	repo            scimRepo.ScimRepository
+	userRepo        user.UserRepository
+	userSessionRepo userSession.UsersSessionRepository
+	departmentRepo  department.DepartmentsRepository
+	locationRepo    location.LocationRepository
+	roleRepo        role.RoleRepository
+	auditLogRepo    auditLog.AuditLogRepository
+	userRBACRepo    userRBAC.UserRBACRepository
+}
+
+func NewScimService(repo scimRepo.ScimRepository, userRepo user.UserRepository, userSessionRepo userSession.UsersSessionRepository, departmentRepo department.DepartmentsRepository, locationRepo location.LocationRepository, roleRepo role.RoleRepository, auditLogRepo auditLog.AuditLogRepository, userRBACRepo userRBAC.UserRBACRepository) *ScimService {
+	return &ScimService{repo: repo, userRepo: userRepo, userSessionRepo: userSessionRepo, departmentRepo: departmentRepo, locationRepo: locationRepo, roleRepo: roleRepo, auditLogRepo: auditLogRepo, userRBACRepo: userRBACRepo}
+}
+
+// Thay đổi trên người dùng gom từ POST/PUT/PATCH, nil là giữ nguyên
+type userChanges struct {
+	email      *string
+	firstName  *string
+	lastName   *string
+	active     *bool
+	externalId *string
+	password   *string
+	roleSlug   *string
+	department *string // Chuỗi rỗng: bỏ khỏi phòng ban
+}
+
+// Nhóm SCIM là phòng ban hoặc vai trò
+type scimGroup struct {
+	department *entity.Departments
+	role       *entity.Roles
+}
+
+func (service *ScimService) CreateToken(userId int64, request dto.CreateScimTokenRequest, ip string) (*dto.ScimTokenResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if request.LocationId != nil {
+		location, err := service.locationRepo.GetById(*request.LocationId)
+		if err != nil || location.CompanyId != users.CompanyId {
+			return nil, errors.New("location not found")
+		}
+	}
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	scimToken := &entity.ScimToken{
+		Name:        request.Name,
+		TokenHash:   utils.HashToken(token),
+		Prefix:      token[:8],
+		LocationId:  request.LocationId,
+		CreatedById: users.Id,
+		CreatedAt:   time.Now(),
+		CompanyId:   users.CompanyId,
+	}
+	if err := service.repo.CreateToken(scimToken); err != nil {
+		return nil, err
+	}
+	if err := service.audit(entity.AuditScimTokenCreated, &users.Id, nil, fmt.Sprintf("token = %s (%s)", scimToken.Name, scimToken.Prefix), ip, users.CompanyId); err != nil {
+		return nil, err
+	}
+	res := convertToken(scimToken)
+	res.Token = token
+	return res, nil
+}
+
+func (service *ScimService) GetTokens(userId int64) ([]*dto.ScimTokenResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := service.repo.GetTokens(users.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*dto.ScimTokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		res = append(res, convertToken(token))
+	}
+	return res, nil
+}
+
+func (service *ScimService) RevokeToken(userId int64, id int64, ip string) error {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return err
+	}
+	token, err := service.repo.GetTokenById(id)
+	if err != nil || token.CompanyId != users.CompanyId {
+		return errors.New("can't find record this id")
+	}
+	if token.RevokedAt != nil {
+		return errors.New("token is already revoked")
+	}
+	if err := service.repo.RevokeToken(id, time.Now()); err != nil {
+		return err
+	}
+	return service.audit(entity.AuditScimTokenRevoked, &users.Id, nil, fmt.Sprintf("token = %s (%s)", token.Name, token.Prefix), ip, users.CompanyId)
+}
+
+func (service *ScimService) ListUsers(token *entity.ScimToken, request dto.ScimListRequest) (*dto.ScimListResponse, error) {
+	filters, err := utils.ParseScimFilter(request.Filter)
+	if err != nil {
+		return nil, scimError(http.StatusBadRequest, "invalidFilter", "%s", err.Error())
+	}
+	conditions := make([]scimRepo.UserCondition, 0, len(filters))
+	for _, filter := range filters {
+		column, ok := userFilterColumns[filter.Attribute]
+		if !ok {
+			return nil, scimError(http.StatusBadRequest, "invalidFilter", "filtering on %s is not supported", filter.Attribute)
+		}
+		value := filter.Value
+		switch filter.Attribute {
+		case "id":
+			// id không phải số thì không khớp người dùng nào
+			id, _ := strconv.ParseInt(fmt.Sprint(value), 10, 64)
+			value = id
+		case "active":
+			if _, ok := value.(bool); !ok && filter.Operator != "pr" {
+				return nil, scimError(http.StatusBadRequest, "invalidFilter", "active must be compared with true or false")
+			}
+		}
+		if (filter.Operator == "co" || filter.Operator == "sw" || filter.Operator == "ew") && filter.Attribute != "id" && filter.Attribute != "active" {
+			if _, ok := value.(string); !ok {
+				return nil, scimError(http.StatusBadRequest, "invalidFilter", "%s requires a string value", filter.Operator)
+			}
+		}
+		conditions = append(conditions, scimRepo.UserCondition{Column: column, Operator: filter.Operator, Value: value})
+	}
+	startIndex, count := pagination(request)
+	users, total, err := service.repo.FindUsers(token.CompanyId, conditions, startIndex-1, count)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := service.convertUsers(token.CompanyId, users)
+	if err != nil {
+		return nil, err
+	}
+	return listResponse(total, startIndex, resources, len(resources)), nil
+}
+
+func (service *ScimService) GetUser(token *entity.ScimToken, id string) (*dto.ScimUser, error) {
+	users, err := service.findUser(token, id)
+	if err != nil {
+		return nil, err
+	}
+	return service.convertUser(token.CompanyId, users)
+}
+
+func (service *ScimService) CreateUser(token *entity.ScimToken, request dto.ScimUser, ip string) (*dto.ScimUser, error) {
+	if strings.TrimSpace(request.UserName) == "" {
+		return nil, scimError(http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+	changes := changesFromResource(request)
+	users, _, err := service.apply(token, nil, changes, ip)
+	if err != nil {
+		return nil, err
+	}
+	return service.convertUser(token.CompanyId, users)
+}
+
+// ReplaceUser PUT chỉ ghi đè các thuộc tính được gửi, bool trả về cho biết người dùng vừa bị khoá
+func (service *ScimService) ReplaceUser(token *entity.ScimToken, id string, request dto.ScimUser, ip string) (*dto.ScimUser, bool, error) {
+	existing, err := service.findUser(token, id)
+	if err != nil {
+		return nil, false, err
+	}
+	if strings.TrimSpace(request.UserName) == "" {
+		return nil, false, scimError(http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+	users, deprovisioned, err := service.apply(token, existing, changesFromResource(request), ip)
+	if err != nil {
+		return nil, false, err
+	}
+	res, err := service.convertUser(token.CompanyId, users)
+	return res, deprovisioned, err
+}
+
+func (service *ScimService) PatchUser(token *entity.ScimToken, id string, request dto.ScimPatchRequest, ip string) (*dto.ScimUser, bool, error) {
+	existing, err := service.findUser(token, id)
+	if err != nil {
+		return nil, false, err
+	}
+	changes := userChanges{}
+	for _, operation := range request.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "replace" && op != "remove" {
+			return nil, false, scimError(http.StatusBadRequest, "invalidSyntax", "unsupported op %q", operation.Op)
+		}
+		values, err := patchValues(operation)
+		if err != nil {
+			return nil, false, err
+		}
+		for path, value := range values {
+			if err := changes.patch(op, path, value); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	users, deprovisioned, err := service.apply(token, existing, changes, ip)
+	if err != nil {
+		return nil, false, err
+	}
+	res, err := service.convertUser(token.CompanyId, users)
+	return res, deprovisioned, err
+}
+
+// DeleteUser khoá tài khoản thay vì xoá để giữ lịch sử bàn giao, cấp phát tài sản
+func (service *ScimService) DeleteUser(token *entity.ScimToken, id string, ip string) (int64, error) {
+	existing, err := service.findUser(token, id)
+	if err != nil {
+		return 0, err
+	}
+	inactive := false
+	if _, _, err := service.apply(token, existing, userChanges{active: &inactive}, ip); err != nil {
+		return 0, err
+	}
+	return existing.Id, nil
+}
+
+func (service *ScimService) ListGroups(token *entity.ScimToken, request dto.ScimListRequest) (*dto.ScimListResponse, error) {
+	filters, err := utils.ParseScimFilter(request.Filter)
+	if err != nil {
+		return nil, scimError(http.StatusBadRequest, "invalidFilter", "%s", err.Error())
+	}
+	groups, err := service.allGroups(token.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	matched := make([]scimGroup, 0, len(groups))
+	for _, group := range groups {
+		ok, err := group.matches(filters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, group)
+		}
+	}
+	startIndex, count := pagination(request)
+	resources := []*dto.ScimGroup{}
+	excludeMembers := strings.Contains(strings.ToLower(request.ExcludedAttributes), "members")
+	for i := startIndex - 1; i < len(matched) && len(resources) < count; i++ {
+		resource, err := service.convertGroup(token.CompanyId, matched[i], excludeMembers)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	return listResponse(int64(len(matched)), startIndex, resources, len(resources)), nil
+}
+
+func (service *ScimService) GetGroup(token *entity.ScimToken, id string, excludedAttributes string) (*dto.ScimGroup, error) {
+	group, err := service.findGroup(token, id)
+	if err != nil {
+		return nil, err
+	}
+	return service.convertGroup(token.CompanyId, *group, strings.Contains(strings.ToLower(excludedAttributes), "members"))
+}
+
+// CreateGroup tạo phòng ban mới tại vị trí gắn với token
+func (service *ScimService) CreateGroup(token *entity.ScimToken, request dto.ScimGroup) (*dto.ScimGroup, error) {
+	name := strings.TrimSpace(request.DisplayName)
+	if name == "" {
+		return nil, scimError(http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+	groups, err := service.allGroups(token.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		if strings.EqualFold(group.displayName(), name) {
+			return nil, scimError(http.StatusConflict, "uniqueness", "group %s already exists", name)
+		}
+	}
+	if token.LocationId == nil {
+		return nil, scimError(http.StatusBadRequest, "invalidValue", "set a location on the SCIM token to create departments")
+	}
+	memberIds, err := memberIdsOf(request.Members)
+	if err != nil {
+		return nil, err
+	}
+	newDepartment := &entity.Departments{DepartmentName: name, LocationId: *token.LocationId, CompanyId: token.CompanyId}
+	err = service.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := service.repo.CreateDepartment(newDepartment, tx); err != nil {
+			return err
+		}
+		return service.setMembers(token.CompanyId, scimGroup{department: newDepartment}, "add", memberIds, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return service.convertGroup(token.CompanyId, scimGroup{department: newDepartment}, false)
+}
+
+func (service *ScimService) ReplaceGroup(token *entity.ScimToken, id string, request dto.ScimGroup) (*dto.ScimGroup, error) {
+	group, err := service.findGroup(token, id)
+	if err != nil {
+		return nil, err
+	}
+	memberIds, err := memberIdsOf(request.Members)
+	if err != nil {
+		return nil, err
+	}
+	err = service.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := service.rename(token.CompanyId, group, request.DisplayName, tx); err != nil {
+			return err
+		}
+		return service.setMembers(token.CompanyId, *group, "replace", memberIds, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return service.convertGroup(token.CompanyId, *group, false)
+}
+
+// PatchGroup đổi tên và thêm/bớt thành viên, thêm vào phòng ban thì người dùng rời phòng ban cũ
+func (service *ScimService) PatchGroup(token *entity.ScimToken, id string, request dto.ScimPatchRequest) error {
+	group, err := service.findGroup(token, id)
+	if err != nil {
+		return err
+	}
+	return service.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, operation := range request.Operations {
+			op := strings.ToLower(operation.Op)
+			if op != "add" && op != "replace" && op != "remove" {
+				return scimError(http.StatusBadRequest, "invalidSyntax", "unsupported op %q", operation.Op)
+			}
+			values, err := patchValues(operation)
+			if err != nil {
+				return err
+			}
+			for path, value := range values {
+				attribute, err := utils.ParseScimPath(path)
+				if err != nil {
+					return scimError(http.StatusBadRequest, "invalidPath", "%s", err.Error())
+				}
+				switch attribute.Attribute {
+				case "displayname":
+					var name string
+					if op == "remove" || json.Unmarshal(value, &name) != nil {
+						return scimError(http.StatusBadRequest, "invalidValue", "displayName must be a string")
+					}
+					if err := service.rename(token.CompanyId, group, name, tx); err != nil {
+						return err
+					}
+				case "members", "members.value":
+					var memberIds []int64
+					if len(attribute.Filters) > 0 {
+						// members[value eq "12"]
+						for _, filter := range attribute.Filters {
+							if filter.Attribute != "value" || filter.Operator != "eq" {
+								return scimError(http.StatusBadRequest, "invalidFilter", "only members[value eq \"id\"] is supported")
+							}
+							id, err := strconv.ParseInt(fmt.Sprint(filter.Value), 10, 64)
+							if err != nil {
+								return scimError(http.StatusBadRequest, "invalidValue", "invalid member %v", filter.Value)
+							}
+							memberIds = append(memberIds, id)
+						}
+					} else if len(value) > 0 && string(value) != "null" {
+						var members []dto.ScimMultiValue
+						if err := json.Unmarshal(value, &members); err != nil {
+							return scimError(http.StatusBadRequest, "invalidValue", "members must be a list")
+						}
+						if memberIds, err = memberIdsOf(members); err != nil {
+							return err
+						}
+					} else if op == "remove" {
+						// Bỏ toàn bộ thành viên
+						op = "replace"
+					}
+					if err := service.setMembers(token.CompanyId, *group, op, memberIds, tx); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteGroup xoá phòng ban không còn được tham chiếu, vai trò là cố định nên không xoá được
+func (service *ScimService) DeleteGroup(token *entity.ScimToken, id string) error {
+	group, err := service.findGroup(token, id)
+	if err != nil {
+		return err
+	}
+	if group.role != nil {
+		return scimError(http.StatusBadRequest, "mutability", "roles can't be deleted")
+	}
+	hasReferences, err := service.departmentRepo.HasReferences(group.department.Id)
+	if err != nil {
+		return err
+	}
+	if hasReferences {
+		return scimError(http.StatusBadRequest, "", "department still has users, assets or pending requests")
+	}
+	return service.departmentRepo.Delete(group.department.Id)
+}
+
+// apply tạo mới (existing nil) hoặc cập nhật người dùng, bool trả về cho biết người dùng vừa bị khoá
+func (service *ScimService) apply(token *entity.ScimToken, existing *entity.Users, changes userChanges, ip string) (*entity.Users, bool, error) {
+	var roleId int64
+	if changes.roleSlug != nil {
+		slug := *changes.roleSlug
+		if slug == "" {
+			slug = defaultRoleSlug
+		}
+		role := service.roleRepo.GetRoleBySlug(slug)
+		if role == nil || role.Id == 0 {
+			return nil, false, scimError(http.StatusBadRequest, "invalidValue", "unknown role %s", slug)
+		}
+		roleId = role.Id
+	}
+	var departmentId *int64
+	if changes.department != nil && *changes.department != "" {
+		dep, err := service.findDepartmentByName(token, *changes.department)
+		if err != nil {
+			return nil, false, err
+		}
+		departmentId = &dep.Id
+	}
+	if changes.email != nil {
+		email := strings.ToLower(strings.TrimSpace(*changes.email))
+		if _, err := mail.ParseAddress(email); err != nil {
+			return nil, false, scimError(http.StatusBadRequest, "invalidValue", "userName must be an email address")
+		}
+		if other, err := service.userRepo.FindByEmail(email); err == nil && (existing == nil || other.Id != existing.Id) {
+			return nil, false, scimError(http.StatusConflict, "uniqueness", "userName %s is already in use", email)
+		}
+		changes.email = &email
+	}
+	var hashedPassword string
+	if changes.password != nil && *changes.password != "" {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(*changes.password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, false, err
+		}
+		hashedPassword = string(hashed)
+	}
+	deprovisioned, provisioned := false, existing == nil
+	users := existing
+	err := service.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if existing == nil {
+			if roleId == 0 {
+				role := service.roleRepo.GetRoleBySlug(defaultRoleSlug)
+				if role == nil || role.Id == 0 {
+					return errors.New("default role not found")
+				}
+				roleId = role.Id
+			}
+			if hashedPassword == "" {
+				// Mật khẩu ngẫu nhiên, người dùng đăng nhập qua SSO hoặc đặt lại mật khẩu
+				random, err := utils.GenerateOpaqueToken()
+				if err != nil {
+					return err
+				}
+				hashed, err := bcrypt.GenerateFromPassword([]byte(random), bcrypt.DefaultCost)
+				if err != nil {
+					return err
+				}
+				hashedPassword = string(hashed)
+			}
+			local := strings.Split(*changes.email, "@")[0]
+			users = &entity.Users{
+				FirstName:    utils.ValidUserName(deref(changes.firstName), local),
+				LastName:     utils.ValidUserName(deref(changes.lastName), local),
+				Password:     hashedPassword,
+				Email:        *changes.email,
+				RoleId:       roleId,
+				IsActive:     changes.active == nil || *changes.active,
+				DepartmentId: departmentId,
+				CompanyId:    token.CompanyId,
+			}
+			if err := service.repo.CreateUser(users, tx); err != nil {
+				return err
+			}
+			if err := service.userRBACRepo.GrantCompanyAssets(users.Id, users.RoleId, token.CompanyId, tx); err != nil {
+				return err
+			}
+		} else {
+			updates := map[string]interface{}{}
+			if changes.email != nil && *changes.email != existing.Email {
+				updates["email"] = *changes.email
+			}
+			if changes.firstName != nil {
+				if name := utils.ValidUserName(*changes.firstName, existing.FirstName); name != existing.FirstName {
+					updates["first_name"] = name
+				}
+			}
+			if changes.lastName != nil {
+				if name := utils.ValidUserName(*changes.lastName, existing.LastName); name != existing.LastName {
+					updates["last_name"] = name
+				}
+			}
+			if roleId != 0 && roleId != existing.RoleId {
+				updates["role_id"] = roleId
+			}
+			if changes.department != nil {
+				if departmentId == nil && existing.DepartmentId != nil {
+					updates["department_id"] = nil
+				} else if departmentId != nil && (existing.DepartmentId == nil || *existing.DepartmentId != *departmentId) {
+					updates["department_id"] = *departmentId
+				}
+			}
+			if hashedPassword != "" {
+				updates["password"] = hashedPassword
+			}
+			if changes.active != nil && *changes.active != existing.IsActive {
+				updates["is_active"] = *changes.active
+				deprovisioned = !*changes.active
+			}
+			if len(updates) > 0 {
+				if err := service.repo.UpdateUser(existing.Id, updates, tx); err != nil {
+					return err
+				}
+			}
+		}
+		if changes.externalId != nil {
+			return service.saveExternalId(token.CompanyId, users.Id, *changes.externalId, tx)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if deprovisioned {
+		if err := service.revokeSessions(users.Id); err != nil {
+			return nil, false, err
+		}
+		if err := service.audit(entity.AuditScimUserDeprovisioned, nil, &users.Id, fmt.Sprintf("email = %s", users.Email), ip, token.CompanyId); err != nil {
+			return nil, false, err
+		}
+	}
+	if provisioned {
+		if err := service.audit(entity.AuditScimUserProvisioned, nil, &users.Id, fmt.Sprintf("email = %s", users.Email), ip, token.CompanyId); err != nil {
+			return nil, false, err
+		}
+	}
+	users, err = service.userRepo.FindByUserId(users.Id)
+	if err != nil {
+		return nil, false, err
+	}
+	return users, deprovisioned, nil
+}
+
+// revokeSessions thu hồi phiên đăng nhập giống khi đăng xuất, cache Redis do handler xoá
+func (service *ScimService) revokeSessions(userId int64) error {
+	for service.userSessionRepo.CheckUserInSession(userId) {
+		session, err := service.userSessionRepo.FindByUserIdInSession(userId)
+		if err != nil {
+			return err
+		}
+		if err := service.userSessionRepo.UpdateIsRevoked(session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *ScimService) saveExternalId(companyId, userId int64, externalId string, tx *gorm.DB) error {
+	if externalId == "" {
+		return service.repo.DeleteIdentity(companyId, userId, tx)
+	}
+	identities, err := service.repo.GetIdentities(companyId, []int64{userId})
+	if err != nil {
+		return err
+	}
+	identity := &entity.UserIdentity{
+		UserId:    userId,
+		Provider:  entity.IdentityProviderScim,
+		Issuer:    strconv.FormatInt(companyId, 10),
+		CompanyId: companyId,
+		CreatedAt: time.Now(),
+	}
+	if len(identities) > 0 {
+		identity = identities[0]
+	}
+	if identity.Subject == externalId {
+		return nil
+	}
+	identity.Subject = externalId
+	return service.repo.SaveIdentity(identity, tx)
+}
+
+func (service *ScimService) findUser(token *entity.ScimToken, id string) (*entity.Users, error) {
+	userId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, scimError(http.StatusNotFound, "", "user %s not found", id)
+	}
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil || users.CompanyId != token.CompanyId || users.IsSystemUser {
+		return nil, scimError(http.StatusNotFound, "", "user %s not found", id)
+	}
+	return users, nil
+}
+
+// findDepartmentByName trùng tên ở nhiều vị trí thì ưu tiên vị trí gắn với token
+func (service *ScimService) findDepartmentByName(token *entity.ScimToken, name string) (*entity.Departments, error) {
+	departments, err := service.repo.FindDepartmentsByName(token.CompanyId, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	if len(departments) == 1 {
+		return departments[0], nil
+	}
+	for _, dep := range departments {
+		if token.LocationId != nil && dep.LocationId == *token.LocationId {
+			return dep, nil
+		}
+	}
+	if len(departments) == 0 {
+		return nil, scimError(http.StatusBadRequest, "invalidValue", "department %s not found", name)
+	}
+	return nil, scimError(http.StatusBadRequest, "invalidValue", "department %s exists in several locations", name)
+}
+
+func (service *ScimService) allGroups(companyId int64) ([]scimGroup, error) {
+	departments, err := service.departmentRepo.GetAll(companyId)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]scimGroup, 0, len(departments))
+	for _, dep := range departments {
+		groups = append(groups, scimGroup{department: dep})
+	}
+	for _, role := range service.roleRepo.GetAllRole() {
+		groups = append(groups, scimGroup{role: role})
+	}
+	return groups, nil
+}
+
+func (service *ScimService) findGroup(token *entity.ScimToken, id string) (*scimGroup, error) {
+	if value, ok := strings.CutPrefix(id, dto.ScimGroupPrefixDept); ok {
+		departmentId, err := strconv.ParseInt(value, 10, 64)
+		if err == nil {
+			dep, err := service.departmentRepo.GetDepartmentById(departmentId)
+			if err == nil && dep.CompanyId == token.CompanyId && dep.MergedIntoId == nil {
+				return &scimGroup{department: dep}, nil
+			}
+		}
+	}
+	if slug, ok := strings.CutPrefix(id, dto.ScimGroupPrefixRole); ok {
+		role := service.roleRepo.GetRoleBySlug(slug)
+		if role != nil && role.Id != 0 {
+			return &scimGroup{role: role}, nil
+		}
+	}
+	return nil, scimError(http.StatusNotFound, "", "group %s not found", id)
+}
+
+func (service *ScimService) rename(companyId int64, group *scimGroup, name string, tx *gorm.DB) error {
+	name = strings.TrimSpace(name)
+	if name == "" || name == group.displayName() {
+		return nil
+	}
+	if group.role != nil {
+		return scimError(http.StatusBadRequest, "mutability", "roles can't be renamed")
+	}
+	departments, err := service.repo.FindDepartmentsByName(companyId, name)
+	if err != nil {
+		return err
+	}
+	for _, dep := range departments {
+		if dep.Id != group.department.Id && dep.LocationId == group.department.LocationId {
+			return scimError(http.StatusConflict, "uniqueness", "department %s already exists", name)
+		}
+	}
+	if err := service.repo.RenameDepartment(group.department.Id, name, tx); err != nil {
+		return err
+	}
+	group.department.DepartmentName = name
+	return nil
+}
+
+// setMembers op add/remove/replace trên danh sách người dùng của phòng ban hoặc vai trò
+func (service *ScimService) setMembers(companyId int64, group scimGroup, op string, memberIds []int64, tx *gorm.DB) error {
+	current, err := service.memberIds(companyId, group)
+	if err != nil {
+		return err
+	}
+	var added, removed []int64
+	switch op {
+	case "add":
+		added = memberIds
+	case "remove":
+		removed = memberIds
+	case "replace":
+		added = memberIds
+		for _, id := range current {
+			if !slices.Contains(memberIds, id) {
+				removed = append(removed, id)
+			}
+		}
+	}
+	// Chỉ bỏ người đang là thành viên, không đụng tới phòng ban/vai trò khác của họ
+	removed = slices.DeleteFunc(removed, func(id int64) bool { return !slices.Contains(current, id) })
+	added = slices.DeleteFunc(added, func(id int64) bool { return slices.Contains(current, id) })
+	for _, id := range added {
+		users, err := service.userRepo.FindByUserId(id)
+		if err != nil || users.CompanyId != companyId || users.IsSystemUser {
+			return scimError(http.StatusBadRequest, "invalidValue", "member %d not found", id)
+		}
+	}
+	if group.department != nil {
+		if len(added) > 0 {
+			if err := service.repo.UpdateUsers(companyId, added, map[string]interface{}{"department_id": group.department.Id}, tx); err != nil {
+				return err
+			}
+		}
+		if len(removed) > 0 {
+			return service.repo.UpdateUsers(companyId, removed, map[string]interface{}{"department_id": nil}, tx)
+		}
+		return nil
+	}
+	if len(added) > 0 {
+		if err := service.repo.UpdateUsers(companyId, added, map[string]interface{}{"role_id": group.role.Id}, tx); err != nil {
+			return err
+		}
+	}
+	if len(removed) > 0 {
+		role := service.roleRepo.GetRoleBySlug(defaultRoleSlug)
+		if role == nil || role.Id == 0 {
+			return errors.New("default role not found")
+		}
+		if role.Id == group.role.Id {
+			return scimError(http.StatusBadRequest, "mutability", "members can't be removed from the default role")
+		}
+		return service.repo.UpdateUsers(companyId, removed, map[string]interface{}{"role_id": role.Id}, tx)
+	}
+	return nil
+}
+
+func (service *ScimService) memberIds(companyId int64, group scimGroup) ([]int64, error) {
+	if group.department != nil {
+		return service.repo.GetUserIdsByDepartment(group.department.Id)
+	}
+	return service.repo.GetUserIdsByRole(companyId, group.role.Id)
+}
+
+func (service *ScimService) convertUsers(companyId int64, users []*entity.Users) ([]*dto.ScimUser, error) {
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.Id)
+	}
+	externalIds := map[int64]string{}
+	if len(ids) > 0 {
+		identities, err := service.repo.GetIdentities(companyId, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, identity := range identities {
+			externalIds[identity.UserId] = identity.Subject
+		}
+	}
+	res := make([]*dto.ScimUser, 0, len(users))
+	for _, u := range users {
+		res = append(res, convertUser(u, externalIds[u.Id]))
+	}
+	return res, nil
+}
+
+func (service *ScimService) convertUser(companyId int64, users *entity.Users) (*dto.ScimUser, error) {
+	res, err := service.convertUsers(companyId, []*entity.Users{users})
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+func (service *ScimService) convertGroup(companyId int64, group scimGroup, excludeMembers bool) (*dto.ScimGroup, error) {
+	res := &dto.ScimGroup{
+		Schemas:     []string{dto.ScimGroupSchema},
+		Id:          group.id(),
+		DisplayName: group.displayName(),
+		Meta:        &dto.ScimMeta{ResourceType: dto.ScimResourceTypeGroup, Location: scimBaseUrl() + "/Groups/" + group.id()},
+	}
+	if excludeMembers {
+		return res, nil
+	}
+	ids, err := service.memberIds(companyId, group)
+	if err != nil {
+		return nil, err
+	}
+	res.Members = make([]dto.ScimMultiValue, 0, len(ids))
+	for _, id := range ids {
+		value := strconv.FormatInt(id, 10)
+		res.Members = append(res.Members, dto.ScimMultiValue{Value: value, Ref: scimBaseUrl() + "/Users/" + value})
+	}
+	return res, nil
+}
+
+func (service *ScimService) audit(action string, actorId *int64, targetUserId *int64, detail string, ip string, companyId int64) error {
+	return service.auditLogRepo.Create(&entity.AuditLog{
+		Action:       action,
+		ActorId:      actorId,
+		TargetUserId: targetUserId,
+		Detail:       detail,
+		IpAddress:    ip,
+		CreatedAt:    time.Now(),
+		CompanyId:    companyId,
+	}, nil)
+}
+
+func (group scimGroup) id() string {
+	if group.department != nil {
+		return dto.ScimGroupPrefixDept + strconv.FormatInt(group.department.Id, 10)
+	}
+	return dto.ScimGroupPrefixRole + group.role.Slug
+}
+
+func (group scimGroup) displayName() string {
+	if group.department != nil {
+		return group.department.DepartmentName
+	}
+	return group.role.Title
+}
+
+// matches lọc nhóm theo id, displayName
+func (group scimGroup) matches(filters []utils.ScimFilter) (bool, error) {
+	for _, filter := range filters {
+		var actual string
+		switch filter.Attribute {
+		case "id":
+			actual = group.id()
+		case "displayname":
+			actual = group.displayName()
+		default:
+			return false, scimError(http.StatusBadRequest, "invalidFilter", "filtering on %s is not supported", filter.Attribute)
+		}
+		expected, _ := filter.Value.(string)
+		actual, expected = strings.ToLower(actual), strings.ToLower(expected)
+		var ok bool
+		switch filter.Operator {
+		case "eq":
+			ok = actual == expected
+		case "ne":
+			ok = actual != expected
+		case "co":
+			ok = strings.Contains(actual, expected)
+		case "sw":
+			ok = strings.HasPrefix(actual, expected)
+		case "ew":
+			ok = strings.HasSuffix(actual, expected)
+		case "pr":
+			ok = actual != ""
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// patch ghi nhận một thao tác PATCH trên người dùng, thuộc tính không hỗ trợ được bỏ qua
+func (changes *userChanges) patch(op string, path string, value json.RawMessage) error {
+	attribute, err := utils.ParseScimPath(path)
+	if err != nil {
+		return scimError(http.StatusBadRequest, "invalidPath", "%s", err.Error())
+	}
+	remove := op == "remove"
+	switch attribute.Attribute {
+	case "active":
+		if remove {
+			return scimError(http.StatusBadRequest, "mutability", "active can't be removed")
+		}
+		active, err := patchBool(value)
+		if err != nil {
+			return err
+		}
+		changes.active = &active
+	case "username":
+		if remove {
+			return scimError(http.StatusBadRequest, "mutability", "userName can't be removed")
+		}
+		email, err := patchString(value)
+		if err != nil {
+			return err
+		}
+		changes.email = &email
+	case "externalid":
+		externalId := ""
+		if !remove {
+			if externalId, err = patchString(value); err != nil {
+				return err
+			}
+		}
+		changes.externalId = &externalId
+	case "name.givenname":
+		if !remove {
+			name, err := patchString(value)
+			if err != nil {
+				return err
+			}
+			changes.firstName = &name
+		}
+	case "name.familyname":
+		if !remove {
+			name, err := patchString(value)
+			if err != nil {
+				return err
+			}
+			changes.lastName = &name
+		}
+	case "name":
+		var name dto.ScimName
+		if !remove {
+			if err := json.Unmarshal(value, &name); err != nil {
+				return scimError(http.StatusBadRequest, "invalidValue", "name must be an object")
+			}
+			changes.setName(&name)
+		}
+	case "password":
+		if !remove {
+			password, err := patchString(value)
+			if err != nil {
+				return err
+			}
+			changes.password = &password
+		}
+	case "roles", "roles.value":
+		slug := ""
+		if !remove {
+			if slug, err = patchMultiValue(value); err != nil {
+				return err
+			}
+		}
+		changes.roleSlug = &slug
+	case enterpriseDepartment:
+		name := ""
+		if !remove {
+			if name, err = patchString(value); err != nil {
+				return err
+			}
+		}
+		changes.department = &name
+	case "groups":
+		return scimError(http.StatusBadRequest, "mutability", "groups is read-only, update the group members instead")
+	}
+	return nil
+}
+
+func (changes *userChanges) setName(name *dto.ScimName) {
+	if name == nil {
+		return
+	}
+	if name.GivenName != "" {
+		changes.firstName = &name.GivenName
+	}
+	if name.FamilyName != "" {
+		changes.lastName = &name.FamilyName
+	}
+}
+
+func changesFromResource(request dto.ScimUser) userChanges {
+	changes := userChanges{email: &request.UserName, active: request.Active}
+	changes.setName(request.Name)
+	if request.ExternalId != "" {
+		changes.externalId = &request.ExternalId
+	}
+	if request.Password != "" {
+		changes.password = &request.Password
+	}
+	if len(request.Roles) > 0 {
+		slug := primaryValue(request.Roles)
+		changes.roleSlug = &slug
+	}
+	if request.Enterprise != nil {
+		changes.department = &request.Enterprise.Department
+	}
+	return changes
+}
+
+// patchValues thao tác không có path thì value là object gồm nhiều thuộc tính (kiểu Entra ID)
+func patchValues(operation dto.ScimPatchOperation) (map[string]json.RawMessage, error) {
+	if operation.Path != "" {
+		return map[string]json.RawMessage{operation.Path: operation.Value}, nil
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(operation.Value, &object); err != nil {
+		return nil, scimError(http.StatusBadRequest, "noTarget", "path is required when value is not an object")
+	}
+	values := map[string]json.RawMessage{}
+	for key, value := range object {
+		if strings.EqualFold(key, dto.ScimEnterpriseSchema) {
+			var extension map[string]json.RawMessage
+			if err := json.Unmarshal(value, &extension); err != nil {
+				return nil, scimError(http.StatusBadRequest, "invalidValue", "%s must be an object", key)
+			}
+			for subKey, subValue := range extension {
+				values[dto.ScimEnterpriseSchema+":"+subKey] = subValue
+			}
+			continue
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+func patchString(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", scimError(http.StatusBadRequest, "invalidValue", "expected a string value")
+	}
+	return s, nil
+}
+
+// patchBool chấp nhận cả "True"/"False" dạng chuỗi mà một số IdP gửi
+func patchBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	s, err := patchString(value)
+	if err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+	return false, scimError(http.StatusBadRequest, "invalidValue", "expected a boolean value")
+}
+
+func patchMultiValue(value json.RawMessage) (string, error) {
+	var values []dto.ScimMultiValue
+	if err := json.Unmarshal(value, &values); err == nil {
+		return primaryValue(values), nil
+	}
+	return patchString(value)
+}
+
+func primaryValue(values []dto.ScimMultiValue) string {
+	for _, value := range values {
+		if value.Primary {
+			return value.Value
+		}
+	}
+	if len(values) > 0 {
+		return values[0].Value
+	}
+	return ""
+}
+
+func memberIdsOf(members []dto.ScimMultiValue) ([]int64, error) {
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(member.Value, 10, 64)
+		if err != nil {
+			return nil, scimError(http.StatusBadRequest, "invalidValue", "invalid member %s", member.Value)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func pagination(request dto.ScimListRequest) (int, int) {
+	startIndex := request.StartIndex
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count := dto.ScimDefaultPageSize
+	if request.Count != nil {
+		count = max(*request.Count, 0)
+	}
+	return startIndex, min(count, dto.ScimMaxPageSize)
+}
+
+func listResponse(total int64, startIndex int, resources interface{}, itemsPerPage int) *dto.ScimListResponse {
+	return &dto.ScimListResponse{
+		Schemas:      []string{dto.ScimListSchema},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: itemsPerPage,
+		Resources:    resources,
+	}
+}
+
+func convertUser(users *entity.Users, externalId string) *dto.ScimUser {
+	id := strconv.FormatInt(users.Id, 10)
+	active := users.IsActive
+	res := &dto.ScimUser{
+		Schemas:     []string{dto.ScimUserSchema, dto.ScimEnterpriseSchema},
+		Id:          id,
+		ExternalId:  externalId,
+		UserName:    users.Email,
+		Name:        &dto.ScimName{Formatted: users.FirstName + " " + users.LastName, GivenName: users.FirstName, FamilyName: users.LastName},
+		DisplayName: users.FirstName + " " + users.LastName,
+		Emails:      []dto.ScimMultiValue{{Value: users.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta:        &dto.ScimMeta{ResourceType: dto.ScimResourceTypeUser, Location: scimBaseUrl() + "/Users/" + id},
+	}
+	if users.Role.Id != 0 {
+		res.Roles = []dto.ScimMultiValue{{Value: users.Role.Slug, Display: users.Role.Title, Primary: true}}
+		res.Groups = append(res.Groups, dto.ScimMultiValue{Value: dto.ScimGroupPrefixRole + users.Role.Slug, Display: users.Role.Title})
+	}
+	if users.DepartmentId != nil {
+		res.Enterprise = &dto.ScimEnterpriseUser{Department: users.Department.DepartmentName}
+		res.Groups = append(res.Groups, dto.ScimMultiValue{Value: dto.ScimGroupPrefixDept + strconv.FormatInt(*users.DepartmentId, 10), Display: users.Department.DepartmentName})
+	}
+	return res
+}
+
+func convertToken(token *entity.ScimToken) *dto.ScimTokenResponse {
+	return &dto.ScimTokenResponse{
+		Id:          token.Id,
+		Name:        token.Name,
+		Prefix:      token.Prefix,
+		LocationId:  token.LocationId,
+		CreatedById: token.CreatedById,
+		CreatedAt:   token.CreatedAt,
+		LastUsedAt:  token.LastUsedAt,
+		RevokedAt:   token.RevokedAt,
+		BaseUrl:     scimBaseUrl(),
+	}
+}
+
+func scimBaseUrl() string {
+	return strings.TrimSuffix(config.BASE_URL_BACKEND, "/") + "/scim/v2"
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func strPtr(s string) *string { return &s }
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestUserChangesPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       string
+		path     string
+		value    string
+		want     userChanges
+		wantType string // scimType của lỗi, rỗng nếu không lỗi
+	}{
+		{name: "deactivate", op: "replace", path: "active", value: `false`, want: userChanges{active: boolPtr(false)}},
+		{name: "active as string", op: "replace", path: "active", value: `"True"`, want: userChanges{active: boolPtr(true)}},
+		{name: "active not boolean", op: "replace", path: "active", value: `"maybe"`, wantType: "invalidValue"},
+		{name: "active can't be removed", op: "remove", path: "active", wantType: "mutability"},
+		{name: "userName with schema prefix", op: "replace", path: "urn:ietf:params:scim:schemas:core:2.0:User:userName", value: `"alice@acme.com"`, want: userChanges{email: strPtr("alice@acme.com")}},
+		{name: "userName can't be removed", op: "remove", path: "userName", wantType: "mutability"},
+		{name: "remove externalId", op: "remove", path: "externalId", want: userChanges{externalId: strPtr("")}},
+		{name: "given name", op: "add", path: "name.givenName", value: `"Alice"`, want: userChanges{firstName: strPtr("Alice")}},
+		{name: "name object", op: "replace", path: "name", value: `{"givenName":"Alice","familyName":"Nguyen"}`, want: userChanges{firstName: strPtr("Alice"), lastName: strPtr("Nguyen")}},
+		{name: "name not an object", op: "replace", path: "name", value: `"Alice"`, wantType: "invalidValue"},
+		{name: "primary role", op: "replace", path: "roles", value: `[{"value":"viewer"},{"value":"assetManager","primary":true}]`, want: userChanges{roleSlug: strPtr("assetManager")}},
+		{name: "role as string", op: "replace", path: "roles.value", value: `"viewer"`, want: userChanges{roleSlug: strPtr("viewer")}},
+		{name: "enterprise department", op: "replace", path: dto.ScimEnterpriseDeptPath, value: `"IT"`, want: userChanges{department: strPtr("IT")}},
+		{name: "remove department", op: "remove", path: dto.ScimEnterpriseDeptPath, want: userChanges{department: strPtr("")}},
+		{name: "groups are read-only", op: "add", path: "groups", value: `[{"value":"1"}]`, wantType: "mutability"},
+		{name: "unsupported attribute ignored", op: "replace", path: "nickName", value: `"Al"`, want: userChanges{}},
+		{name: "invalid path", op: "replace", path: `emails[type eq "work"`, value: `"a@acme.com"`, wantType: "invalidPath"},
+		{name: "string expected", op: "replace", path: "userName", value: `42`, wantType: "invalidValue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var changes userChanges
+			var value json.RawMessage
+			if tt.value != "" {
+				value = json.RawMessage(tt.value)
+			}
+			err := changes.patch(tt.op, tt.path, value)
+			if tt.wantType != "" {
+				var scimErr *ScimError
+				if !errors.As(err, &scimErr) || scimErr.ScimType != tt.wantType {
+					t.Fatalf("patch() error = %v, want scimType %q", err, tt.wantType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("patch() error = %v", err)
+			}
+			if !reflect.DeepEqual(changes, tt.want) {
+				t.Errorf("patch() changes = %+v, want %+v", changes, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation dto.ScimPatchOperation
+		want      map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "with path",
+			operation: dto.ScimPatchOperation{Op: "replace", Path: "active", Value: json.RawMessage(`false`)},
+			want:      map[string]string{"active": `false`},
+		},
+		{
+			name:      "object without path",
+			operation: dto.ScimPatchOperation{Op: "replace", Value: json.RawMessage(`{"active":false,"name.givenName":"Alice"}`)},
+			want:      map[string]string{"active": `false`, "name.givenName": `"Alice"`},
+		},
+		{
+			name:      "enterprise extension is flattened",
+			operation: dto.ScimPatchOperation{Op: "replace", Value: json.RawMessage(`{"` + dto.ScimEnterpriseSchema + `":{"department":"IT"}}`)},
+			want:      map[string]string{dto.ScimEnterpriseSchema + ":department": `"IT"`},
+		},
+		{
+			name:      "value must be an object without path",
+			operation: dto.ScimPatchOperation{Op: "replace", Value: json.RawMessage(`"Alice"`)},
+			wantErr:   true,
+		},
+		{
+			name:      "extension must be an object",
+			operation: dto.ScimPatchOperation{Op: "replace", Value: json.RawMessage(`{"` + dto.ScimEnterpriseSchema + `":"IT"}`)},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := patchValues(tt.operation)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("patchValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			values := map[string]string{}
+			for key, value := range got {
+				values[key] = string(value)
+			}
+			if !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("patchValues() = %v, want %v", values, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	auditLog "BE_Manage_device/internal/repository/audit_logs"
+	department "BE_Manage_device/internal/repository/departments"
+	role "BE_Manage_device/internal/repository/role"
+	serviceAccountRepo "BE_Manage_device/internal/repository/service_accounts"
+	user "BE_Manage_device/internal/repository/user"
+	userRBAC "BE_Manage_device/internal/repository/user_rbac"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultRoleSlug     = "viewer"
+	systemUserLastName  = "Service account"
+	systemUserEmailHost = "service-accounts.invalid" // Tên miền không nhận được thư, không đặt lại mật khẩu được
+)
+
+type ServiceAccountService struct {
+	repo           serviceAccountRepo.ServiceAccountRepository
+	userRepo       user.UserRepository
+	roleRepo       role.RoleRepository
+	departmentRepo department.DepartmentsRepository
+	auditLogRepo   auditLog.AuditLogRepository
+	userRBACRepo   userRBAC.UserRBACRepository
+}
+
+func NewServiceAccountService(repo serviceAccountRepo.ServiceAccountRepository, userRepo user.UserRepository, roleRepo role.RoleRepository, departmentRepo department.DepartmentsRepository, auditLogRepo auditLog.AuditLogRepository, userRBACRepo userRBAC.UserRBACRepository) *ServiceAccountService {
+	return &ServiceAccountService{repo: repo, userRepo: userRepo, roleRepo: roleRepo, departmentRepo: departmentRepo, auditLogRepo: auditLogRepo, userRBACRepo: userRBACRepo}
+}
+
+// Create tạo service account cùng người dùng hệ thống mang vai trò được chọn
+func (service *ServiceAccountService) Create(userId int64, request dto.CreateServiceAccountRequest, ip string) (*dto.ServiceAccountResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	slug := request.RoleSlug
+	if slug == "" {
+		slug = defaultRoleSlug
+	}
+	role := service.roleRepo.GetRoleBySlug(slug)
+	if role == nil || role.Id == 0 {
+		return nil, fmt.Errorf("role %s not found", slug)
+	}
+	if request.DepartmentId != nil {
+		dep, err := service.departmentRepo.GetDepartmentById(*request.DepartmentId)
+		if err != nil || dep.CompanyId != users.CompanyId || dep.MergedIntoId != nil {
+			return nil, errors.New("department not found")
+		}
+	}
+	random, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(random), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	systemUser := &entity.Users{
+		FirstName:    utils.ValidUserName(request.Name, systemUserLastName),
+		LastName:     systemUserLastName,
+		Password:     string(hashedPassword),
+		Email:        fmt.Sprintf("svc-%s@%s", random[:12], systemUserEmailHost),
+		RoleId:       role.Id,
+		IsActive:     true,
+		DepartmentId: request.DepartmentId,
+		CompanyId:    users.CompanyId,
+		IsSystemUser: true,
+	}
+	account := &entity.ServiceAccount{
+		Name:        request.Name,
+		Description: request.Description,
+		CreatedById: users.Id,
+		CreatedAt:   time.Now(),
+		CompanyId:   users.CompanyId,
+	}
+	err = service.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := service.repo.CreateUser(systemUser, tx); err != nil {
+			return err
+		}
+		if err := service.userRBACRepo.GrantCompanyAssets(systemUser.Id, role.Id, users.CompanyId, tx); err != nil {
+			return err
+		}
+		account.UserId = systemUser.Id
+		return service.repo.CreateAccount(account, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := service.audit(entity.AuditServiceAccountCreated, users, &systemUser.Id, fmt.Sprintf("serviceAccount = %s, role = %s", account.Name, slug), ip); err != nil {
+		return nil, err
+	}
+	account.User = *systemUser
+	account.User.Role = *role
+	return convertAccount(account), nil
+}
+
+func (service *ServiceAccountService) GetAll(userId int64) ([]*dto.ServiceAccountResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := service.repo.GetAccounts(users.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*dto.ServiceAccountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		res = append(res, convertAccount(account))
+	}
+	return res, nil
+}
+
+func (service *ServiceAccountService) GetById(userId int64, id int64) (*dto.ServiceAccountResponse, error) {
+	_, account, err := service.findAccount(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	return convertAccount(account), nil
+}
+
+// Disable vô hiệu hoá service account: thu hồi mọi key và khoá người dùng hệ thống
+func (service *ServiceAccountService) Disable(userId int64, id int64, ip string) error {
+	users, account, err := service.findAccount(userId, id)
+	if err != nil {
+		return err
+	}
+	if account.DisabledAt != nil {
+		return errors.New("service account is already disabled")
+	}
+	now := time.Now()
+	err = service.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := service.repo.DisableAccount(account.Id, now, tx); err != nil {
+			return err
+		}
+		if err := service.repo.RevokeKeys(account.Id, now, tx); err != nil {
+			return err
+		}
+		return service.repo.DeactivateUser(account.UserId, tx)
+	})
+	if err != nil {
+		return err
+	}
+	return service.audit(entity.AuditServiceAccountDisabled, users, &account.UserId, fmt.Sprintf("serviceAccount = %s", account.Name), ip)
+}
+
+// CreateKey scope phải là quyền mà vai trò của service account đang có
+func (service *ServiceAccountService) CreateKey(userId int64, id int64, request dto.CreateApiKeyRequest, ip string) (*dto.ApiKeyResponse, error) {
+	users, account, err := service.findAccount(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if account.DisabledAt != nil {
+		return nil, errors.New("service account is disabled")
+	}
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiresAt must be in the future")
+	}
+	slugs := []string{}
+	for _, slug := range request.Scopes {
+		slug = strings.TrimSpace(slug)
+		if slug != "" && !slices.Contains(slugs, slug) {
+			slugs = append(slugs, slug)
+		}
+	}
+	if len(slugs) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	permissions, err := service.repo.GetPermissionsBySlugs(slugs)
+	if err != nil {
+		return nil, err
+	}
+	rolePermissionIds, err := service.repo.GetRolePermissionIds(account.User.RoleId)
+	if err != nil {
+		return nil, err
+	}
+	scopes := make([]entity.ApiKeyScope, 0, len(permissions))
+	for _, slug := range slugs {
+		index := slices.IndexFunc(permissions, func(p *entity.Permission) bool { return p.Slug == slug })
+		if index < 0 {
+			return nil, fmt.Errorf("permission %s not found", slug)
+		}
+		if !slices.Contains(rolePermissionIds, permissions[index].Id) {
+			return nil, fmt.Errorf("role %s doesn't grant %s", account.User.Role.Slug, slug)
+		}
+		scopes = append(scopes, entity.ApiKeyScope{PermissionId: permissions[index].Id})
+	}
+	secret, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	plainKey := entity.ApiKeyPrefix + secret
+	key := &entity.ApiKey{
+		ServiceAccountId: account.Id,
+		Name:             request.Name,
+		KeyHash:          utils.HashToken(plainKey),
+		Prefix:           plainKey[:len(entity.ApiKeyPrefix)+8],
+		ExpiresAt:        request.ExpiresAt,
+		CreatedById:      users.Id,
+		CreatedAt:        time.Now(),
+		CompanyId:        users.CompanyId,
+		Scopes:           scopes,
+	}
+	if err := service.repo.CreateKey(key, service.repo.GetDB()); err != nil {
+		return nil, err
+	}
+	for i := range key.Scopes {
+		key.Scopes[i].Permission.Slug = slugs[i]
+	}
+	if err := service.audit(entity.AuditApiKeyCreated, users, &account.UserId, fmt.Sprintf("serviceAccount = %s, key = %s (%s), scopes = %s", account.Name, key.Name, key.Prefix, strings.Join(slugs, ",")), ip); err != nil {
+		return nil, err
+	}
+	res := convertKey(key)
+	res.Key = plainKey
+	return res, nil
+}
+
+func (service *ServiceAccountService) GetKeys(userId int64, id int64) ([]*dto.ApiKeyResponse, error) {
+	_, account, err := service.findAccount(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := service.repo.GetKeys(account.Id)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*dto.ApiKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		res = append(res, convertKey(key))
+	}
+	return res, nil
+}
+
+func (service *ServiceAccountService) RevokeKey(userId int64, id int64, keyId int64, ip string) error {
+	users, account, err := service.findAccount(userId, id)
+	if err != nil {
+		return err
+	}
+	key, err := service.repo.GetKeyById(keyId)
+	if err != nil || key.ServiceAccountId != account.Id {
+		return errors.New("can't find record this id")
+	}
+	if key.RevokedAt != nil {
+		return errors.New("API key is already revoked")
+	}
+	if err := service.repo.RevokeKey(key.Id, time.Now()); err != nil {
+		return err
+	}
+	return service.audit(entity.AuditApiKeyRevoked, users, &account.UserId, fmt.Sprintf("serviceAccount = %s, key = %s (%s)", account.Name, key.Name, key.Prefix), ip)
+}
+
+func (service *ServiceAccountService) findAccount(userId int64, id int64) (*entity.Users, *entity.ServiceAccount, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	account, err := service.repo.GetAccountById(id)
+	if err != nil || account.CompanyId != users.CompanyId {
+		return nil, nil, errors.New("can't find record this id")
+	}
+	return users, account, nil
+}
+
+func (service *ServiceAccountService) audit(action string, actor *entity.Users, targetUserId *int64, detail string, ip string) error {
+	return service.auditLogRepo.Create(&entity.AuditLog{
+		Action:       action,
+		ActorId:      &actor.Id,
+		TargetUserId: targetUserId,
+		Detail:       detail,
+		IpAddress:    ip,
+		CreatedAt:    time.Now(),
+		CompanyId:    actor.CompanyId,
+	}, nil)
+}
+
+func convertAccount(account *entity.ServiceAccount) *dto.ServiceAccountResponse {
+	return &dto.ServiceAccountResponse{
+		Id:           account.Id,
+		Name:         account.Name,
+		Description:  account.Description,
+		UserId:       account.UserId,
+		RoleSlug:     account.User.Role.Slug,
+		DepartmentId: account.User.DepartmentId,
+		CreatedById:  account.CreatedById,
+		CreatedAt:    account.CreatedAt,
+		DisabledAt:   account.DisabledAt,
+	}
+}
+
+func convertKey(key *entity.ApiKey) *dto.ApiKeyResponse {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, scope.Permission.Slug)
+	}
+	return &dto.ApiKeyResponse{
+		Id:               key.Id,
+		ServiceAccountId: key.ServiceAccountId,
+		Name:             key.Name,
+		Prefix:           key.Prefix,
+		Scopes:           scopes,
+		ExpiresAt:        key.ExpiresAt,
+		LastUsedAt:       key.LastUsedAt,
+		RevokedAt:        key.RevokedAt,
+		CreatedById:      key.CreatedById,
+		CreatedAt:        key.CreatedAt,
+	}
+}
@@ -0,0 +1,468 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	auditLog "BE_Manage_device/internal/repository/audit_logs"
+	company "BE_Manage_device/internal/repository/company"
+	department "BE_Manage_device/internal/repository/departments"
+	role "BE_Manage_device/internal/repository/role"
+	sso "BE_Manage_device/internal/repository/sso"
+	user "BE_Manage_device/internal/repository/user"
+	"BE_Manage_device/pkg/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	stateTtl         = 10 * time.Minute
+	defaultScopes    = "openid email profile"
+	defaultRoleSlug  = "viewer"
+	defaultEmail     = "email"
+	defaultFirstName = "given_name"
+	defaultLastName  = "family_name"
+)
+
+type SsoService struct {
+	repo           sso.SsoRepository
+	userRepo       user.UserRepository
+	companyRepo    company.CompanyRepository
+	departmentRepo department.DepartmentsRepository
+	roleRepo       role.RoleRepository
+	auditLogRepo   auditLog.AuditLogRepository
+}
+
+func NewSsoService(repo sso.SsoRepository, userRepo user.UserRepository, companyRepo company.CompanyRepository, departmentRepo department.DepartmentsRepository, roleRepo role.RoleRepository, auditLogRepo auditLog.AuditLogRepository) *SsoService {
+	return &SsoService{repo: repo, userRepo: userRepo, companyRepo: companyRepo, departmentRepo: departmentRepo, roleRepo: roleRepo, auditLogRepo: auditLogRepo}
+}
+
+func (service *SsoService) GetConfig(userId int64) (*dto.SsoConfigResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	config, err := service.repo.GetByCompanyId(users.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return &dto.SsoConfigResponse{Scopes: defaultScopes, EmailClaim: defaultEmail, FirstNameClaim: defaultFirstName, LastNameClaim: defaultLastName, DefaultRoleSlug: defaultRoleSlug}, nil
+	}
+	return convertConfig(config), nil
+}
+
+// SaveConfig tạo hoặc cập nhật cấu hình OIDC, kiểm tra IdP khi bật
+func (service *SsoService) SaveConfig(userId int64, request dto.SsoConfigRequest, ip string) (*dto.SsoConfigResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	config, err := service.repo.GetByCompanyId(users.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &entity.CompanySso{CompanyId: users.CompanyId}
+	}
+	for value, departmentId := range request.DepartmentMapping {
+		dep, err := service.departmentRepo.GetDepartmentById(departmentId)
+		if err != nil || dep.CompanyId != users.CompanyId {
+			return nil, fmt.Errorf("department %v of claim value %q not found", departmentId, value)
+		}
+	}
+	if request.DefaultRoleSlug == "" {
+		request.DefaultRoleSlug = defaultRoleSlug
+	}
+	slugs := []string{request.DefaultRoleSlug}
+	for _, slug := range request.RoleMapping {
+		slugs = append(slugs, slug)
+	}
+	for _, slug := range slugs {
+		if service.roleRepo.GetRoleBySlug(slug).Id == 0 {
+			return nil, fmt.Errorf("role %q not found", slug)
+		}
+	}
+	if request.ClientSecret != "" {
+		if config.ClientSecret, err = utils.EncryptSecret(request.ClientSecret); err != nil {
+			return nil, err
+		}
+	}
+	if request.Enabled {
+		if config.ClientSecret == "" {
+			return nil, errors.New("client secret is required")
+		}
+		if _, err := utils.FetchOidcDiscovery(request.Issuer); err != nil {
+			return nil, fmt.Errorf("cannot read OpenID configuration of the issuer: %w", err)
+		}
+	}
+	departmentMapping, err := json.Marshal(request.DepartmentMapping)
+	if err != nil {
+		return nil, err
+	}
+	roleMapping, err := json.Marshal(request.RoleMapping)
+	if err != nil {
+		return nil, err
+	}
+	config.Issuer = strings.TrimSuffix(request.Issuer, "/")
+	config.ClientId = request.ClientId
+	config.RedirectUri = request.RedirectUri
+	config.Scopes = normalizeScopes(request.Scopes)
+	config.EmailClaim = orDefault(request.EmailClaim, defaultEmail)
+	config.FirstNameClaim = orDefault(request.FirstNameClaim, defaultFirstName)
+	config.LastNameClaim = orDefault(request.LastNameClaim, defaultLastName)
+	config.DepartmentClaim = request.DepartmentClaim
+	config.RoleClaim = request.RoleClaim
+	config.DepartmentMapping = string(departmentMapping)
+	config.RoleMapping = string(roleMapping)
+	config.DefaultRoleSlug = request.DefaultRoleSlug
+	config.Enabled = request.Enabled
+	config.AutoProvision = request.AutoProvision
+	config.DisablePasswordLogin = request.DisablePasswordLogin
+	config.UpdatedAt = time.Now()
+	if err := service.repo.Save(config); err != nil {
+		return nil, err
+	}
+	detail := fmt.Sprintf("issuer = %s, enabled = %v, disablePasswordLogin = %v", config.Issuer, config.Enabled, config.DisablePasswordLogin)
+	if err := service.audit(users.CompanyId, &users.Id, 0, entity.AuditSsoConfigUpdated, detail, ip); err != nil {
+		return nil, err
+	}
+	return convertConfig(config), nil
+}
+
+// Authorize tìm công ty theo đuôi email và tạo URL đăng nhập IdP với PKCE
+func (service *SsoService) Authorize(email string) (*dto.SsoAuthorizeResponse, error) {
+	company, err := service.companyRepo.GetCompanyBySuffixEmail(utils.GetSuffixEmail(email))
+	if err != nil {
+		return nil, errors.New("this email company don't register")
+	}
+	config, err := service.getEnabled(company.Id)
+	if err != nil {
+		return nil, err
+	}
+	discovery, err := utils.FetchOidcDiscovery(config.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	state, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, challenge, err := utils.GeneratePkce()
+	if err != nil {
+		return nil, err
+	}
+	loginState := &entity.SsoLoginState{
+		CompanyId:    company.Id,
+		StateHash:    utils.HashToken(state),
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(stateTtl),
+	}
+	if err := service.repo.CreateState(loginState); err != nil {
+		return nil, err
+	}
+	return &dto.SsoAuthorizeResponse{
+		AuthorizationUrl: utils.OidcAuthorizationUrl(discovery, config.ClientId, config.RedirectUri, config.Scopes, state, nonce, challenge),
+		ExpiresAt:        loginState.ExpiresAt,
+	}, nil
+}
+
+// Authenticate đổi code lấy id_token, liên kết hoặc tạo người dùng. provisioned = true khi vừa tạo tài khoản
+func (service *SsoService) Authenticate(state string, code string, ip string) (*entity.Users, bool, error) {
+	loginState, err := service.repo.GetStateByHash(utils.HashToken(state))
+	if err != nil {
+		return nil, false, errors.New("invalid state")
+	}
+	if loginState.UsedAt != nil || time.Now().After(loginState.ExpiresAt) {
+		return nil, false, errors.New("login session has expired, please try again")
+	}
+	ok, err := service.repo.MarkStateUsed(loginState.Id, time.Now())
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		return nil, false, errors.New("login session was already used")
+	}
+	config, err := service.getEnabled(loginState.CompanyId)
+	if err != nil {
+		return nil, false, err
+	}
+	secret, err := utils.DecryptSecret(config.ClientSecret)
+	if err != nil {
+		return nil, false, err
+	}
+	discovery, err := utils.FetchOidcDiscovery(config.Issuer)
+	if err != nil {
+		return nil, false, err
+	}
+	idToken, err := utils.ExchangeOidcCode(discovery, config.ClientId, secret, config.RedirectUri, code, loginState.CodeVerifier)
+	if err != nil {
+		return nil, false, err
+	}
+	claims, err := utils.VerifyOidcIdToken(discovery, idToken, config.ClientId, loginState.Nonce)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid id_token: %w", err)
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, false, errors.New("id_token has no subject")
+	}
+	users, provisioned, err := service.resolveUser(config, discovery.Issuer, subject, claims, ip)
+	if err != nil {
+		return nil, false, err
+	}
+	if !users.IsActive {
+		return nil, false, errors.New("your account is deactivated")
+	}
+	if err := service.audit(users.CompanyId, &users.Id, users.Id, entity.AuditSsoLogin, "", ip); err != nil {
+		return nil, false, err
+	}
+	return users, provisioned, nil
+}
+
+// PasswordLoginDisabled công ty chỉ cho đăng nhập SSO, admin vẫn đăng nhập bằng mật khẩu để xử lý khi IdP lỗi
+func (service *SsoService) PasswordLoginDisabled(users *entity.Users) (bool, error) {
+	config, err := service.repo.GetByCompanyId(users.CompanyId)
+	if err != nil {
+		return false, err
+	}
+	if config == nil || !config.Enabled || !config.DisablePasswordLogin {
+		return false, nil
+	}
+	return users.Role.Slug != "admin", nil
+}
+
+// resolveUser tìm người dùng theo tài khoản IdP đã liên kết, sau đó theo email, cuối cùng tạo mới nếu cho phép
+func (service *SsoService) resolveUser(config *entity.CompanySso, issuer, subject string, claims jwt.MapClaims, ip string) (*entity.Users, bool, error) {
+	identity, err := service.repo.GetIdentity(entity.IdentityProviderOidc, issuer, subject)
+	if err != nil {
+		return nil, false, err
+	}
+	if identity != nil {
+		if err := service.repo.UpdateIdentityLogin(identity.Id, time.Now()); err != nil {
+			return nil, false, err
+		}
+		users, err := service.userRepo.FindByUserId(identity.UserId)
+		if err != nil {
+			return nil, false, err
+		}
+		if users.CompanyId != config.CompanyId {
+			return nil, false, errors.New("account belongs to another company")
+		}
+		return users, false, nil
+	}
+	email := strings.ToLower(firstClaim(claims, config.EmailClaim))
+	if email == "" {
+		return nil, false, errors.New("id_token has no email")
+	}
+	// Liên kết hoặc tạo tài khoản theo email chỉ khi IdP xác nhận email (thiếu claim coi như chưa xác nhận)
+	// và email thuộc đuôi email đã đăng ký của công ty
+	if verified, _ := claims["email_verified"].(bool); !verified {
+		return nil, false, errors.New("email isn't verified by the identity provider")
+	}
+	company, err := service.companyRepo.GetCompanyById(config.CompanyId)
+	if err != nil {
+		return nil, false, err
+	}
+	if !strings.EqualFold(utils.GetSuffixEmail(email), company.Email) {
+		return nil, false, errors.New("email doesn't belong to this company")
+	}
+	provisioned := false
+	users, err := service.userRepo.FindByEmail(email)
+	if err != nil {
+		if !config.AutoProvision {
+			return nil, false, errors.New("account doesn't exist, please contact your administrator")
+		}
+		if users, err = service.provision(config, email, claims); err != nil {
+			return nil, false, err
+		}
+		provisioned = true
+	}
+	if users.CompanyId != config.CompanyId {
+		return nil, false, errors.New("account belongs to another company")
+	}
+	now := time.Now()
+	link := &entity.UserIdentity{
+		UserId:      users.Id,
+		Provider:    entity.IdentityProviderOidc,
+		Issuer:      issuer,
+		Subject:     subject,
+		CompanyId:   config.CompanyId,
+		CreatedAt:   now,
+		LastLoginAt: &now,
+	}
+	if err := service.repo.CreateIdentity(link, service.repo.GetDB()); err != nil {
+		return nil, false, err
+	}
+	if provisioned {
+		if err := service.audit(config.CompanyId, nil, users.Id, entity.AuditSsoUserProvisioned, "issuer = "+issuer, ip); err != nil {
+			return nil, false, err
+		}
+	}
+	return users, provisioned, nil
+}
+
+// provision tạo tài khoản lần đầu đăng nhập, phòng ban và vai trò lấy theo ánh xạ claim
+func (service *SsoService) provision(config *entity.CompanySso, email string, claims jwt.MapClaims) (*entity.Users, error) {
+	var departmentMapping map[string]int64
+	var roleMapping map[string]string
+	if err := json.Unmarshal([]byte(config.DepartmentMapping), &departmentMapping); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(config.RoleMapping), &roleMapping); err != nil {
+		return nil, err
+	}
+	slug := config.DefaultRoleSlug
+	if config.RoleClaim != "" {
+		for _, value := range utils.OidcClaimValues(claims, config.RoleClaim) {
+			if mapped, ok := roleMapping[value]; ok {
+				slug = mapped
+				break
+			}
+		}
+	}
+	roles := service.roleRepo.GetRoleBySlug(slug)
+	if roles.Id == 0 {
+		return nil, fmt.Errorf("role %q not found", slug)
+	}
+	var departmentId *int64
+	if config.DepartmentClaim != "" {
+		for _, value := range utils.OidcClaimValues(claims, config.DepartmentClaim) {
+			if mapped, ok := departmentMapping[value]; ok {
+				departmentId = &mapped
+				break
+			}
+		}
+	}
+	// Mật khẩu ngẫu nhiên không ai biết, người dùng đăng nhập qua SSO hoặc đặt lại mật khẩu
+	random, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(random), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	local := strings.Split(email, "@")[0]
+	users := &entity.Users{
+		FirstName:    claimName(claims, config.FirstNameClaim, local),
+		LastName:     claimName(claims, config.LastNameClaim, local),
+		Password:     string(hashedPassword),
+		Email:        email,
+		RoleId:       roles.Id,
+		IsActive:     true,
+		DepartmentId: departmentId,
+		CompanyId:    config.CompanyId,
+	}
+	if err := service.userRepo.Create(users); err != nil {
+		return nil, err
+	}
+	return service.userRepo.FindByUserId(users.Id)
+}
+
+func (service *SsoService) getEnabled(companyId int64) (*entity.CompanySso, error) {
+	config, err := service.repo.GetByCompanyId(companyId)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil || !config.Enabled {
+		return nil, errors.New("single sign-on isn't enabled for this company")
+	}
+	return config, nil
+}
+
+func (service *SsoService) audit(companyId int64, actorId *int64, targetUserId int64, action string, detail string, ip string) error {
+	entry := &entity.AuditLog{
+		Action:    action,
+		ActorId:   actorId,
+		Detail:    detail,
+		IpAddress: ip,
+		CreatedAt: time.Now(),
+		CompanyId: companyId,
+	}
+	if targetUserId != 0 {
+		entry.TargetUserId = &targetUserId
+	}
+	return service.auditLogRepo.Create(entry, nil)
+}
+
+func convertConfig(config *entity.CompanySso) *dto.SsoConfigResponse {
+	res := &dto.SsoConfigResponse{
+		Issuer:               config.Issuer,
+		ClientId:             config.ClientId,
+		HasClientSecret:      config.ClientSecret != "",
+		RedirectUri:          config.RedirectUri,
+		Scopes:               config.Scopes,
+		EmailClaim:           config.EmailClaim,
+		FirstNameClaim:       config.FirstNameClaim,
+		LastNameClaim:        config.LastNameClaim,
+		DepartmentClaim:      config.DepartmentClaim,
+		RoleClaim:            config.RoleClaim,
+		DefaultRoleSlug:      config.DefaultRoleSlug,
+		Enabled:              config.Enabled,
+		AutoProvision:        config.AutoProvision,
+		DisablePasswordLogin: config.DisablePasswordLogin,
+		UpdatedAt:            config.UpdatedAt,
+	}
+	_ = json.Unmarshal([]byte(config.DepartmentMapping), &res.DepartmentMapping)
+	_ = json.Unmarshal([]byte(config.RoleMapping), &res.RoleMapping)
+	return res
+}
+
+// normalizeScopes luôn có openid, bỏ trùng lặp
+func normalizeScopes(scopes string) string {
+	if strings.TrimSpace(scopes) == "" {
+		return defaultScopes
+	}
+	result := []string{"openid"}
+	for _, scope := range strings.Fields(scopes) {
+		duplicated := false
+		for _, s := range result {
+			if s == scope {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			result = append(result, scope)
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+func orDefault(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return value
+}
+
+func firstClaim(claims jwt.MapClaims, name string) string {
+	values := utils.OidcClaimValues(claims, name)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(values[0])
+}
+
+// claimName tên phải dài 2-256 ký tự theo ràng buộc của bảng users
+func claimName(claims jwt.MapClaims, name string, fallback string) string {
+	value := firstClaim(claims, name)
+	if len([]rune(value)) < 2 || len([]rune(value)) > 256 {
+		value = fallback
+	}
+	for len([]rune(value)) < 2 {
+		value += "_"
+	}
+	return value
+}
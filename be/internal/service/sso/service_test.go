@@ -0,0 +1,542 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	auditLog "BE_Manage_device/internal/repository/audit_logs"
+	company "BE_Manage_device/internal/repository/company"
+	role "BE_Manage_device/internal/repository/role"
+	sso "BE_Manage_device/internal/repository/sso"
+	user "BE_Manage_device/internal/repository/user"
+	"BE_Manage_device/pkg/utils"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	testClientId     = "asset-app"
+	testClientSecret = "client-secret"
+	testRedirectUri  = "https://app.example.com/sso/callback"
+)
+
+// stubIdp IdP giả phục vụ discovery, JWKS và token endpoint, kiểm tra PKCE như IdP thật
+type stubIdp struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	mu     sync.Mutex
+	codes  map[string]stubGrant
+	// claims của id_token kế tiếp, mutate dùng để làm sai claim
+	claims jwt.MapClaims
+	mutate func(jwt.MapClaims)
+}
+
+type stubGrant struct {
+	challenge string
+	nonce     string
+}
+
+func newStubIdp(t *testing.T) *stubIdp {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &stubIdp{key: key, codes: map[string]stubGrant{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kid": "stub",
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", idp.token)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// login giả lập người dùng đăng nhập ở IdP: đọc URL authorize và trả về code
+func (idp *stubIdp) login(t *testing.T, authorizationUrl string) (state string, code string) {
+	t.Helper()
+	parsed, err := url.Parse(authorizationUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	if !strings.HasPrefix(authorizationUrl, idp.server.URL+"/authorize?") {
+		t.Fatalf("authorization url %q doesn't point to the IdP", authorizationUrl)
+	}
+	if query.Get("client_id") != testClientId || query.Get("redirect_uri") != testRedirectUri || query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("unexpected authorization request %v", query)
+	}
+	code, err = utils.GenerateOpaqueToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp.mu.Lock()
+	idp.codes[code] = stubGrant{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	idp.mu.Unlock()
+	return query.Get("state"), code
+}
+
+func (idp *stubIdp) token(w http.ResponseWriter, r *http.Request) {
+	clientId, secret, ok := r.BasicAuth()
+	if !ok || clientId != testClientId || secret != testClientSecret {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != testRedirectUri {
+		http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+		return
+	}
+	idp.mu.Lock()
+	grant, ok := idp.codes[r.PostForm.Get("code")]
+	delete(idp.codes, r.PostForm.Get("code"))
+	idp.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+	claims := jwt.MapClaims{
+		"iss":   idp.server.URL,
+		"aud":   testClientId,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": grant.nonce,
+	}
+	for name, value := range idp.claims {
+		claims[name] = value
+	}
+	if idp.mutate != nil {
+		idp.mutate(claims)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "stub"
+	signed, err := token.SignedString(idp.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+type fakeSsoRepo struct {
+	sso.SsoRepository
+	config     *entity.CompanySso
+	states     []*entity.SsoLoginState
+	identities []*entity.UserIdentity
+}
+
+func (r *fakeSsoRepo) GetByCompanyId(companyId int64) (*entity.CompanySso, error) {
+	if r.config == nil || r.config.CompanyId != companyId {
+		return nil, nil
+	}
+	return r.config, nil
+}
+
+func (r *fakeSsoRepo) CreateState(state *entity.SsoLoginState) error {
+	state.Id = int64(len(r.states) + 1)
+	r.states = append(r.states, state)
+	return nil
+}
+
+func (r *fakeSsoRepo) GetStateByHash(stateHash string) (*entity.SsoLoginState, error) {
+	for _, state := range r.states {
+		if state.StateHash == stateHash {
+			copied := *state
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeSsoRepo) MarkStateUsed(id int64, at time.Time) (bool, error) {
+	for _, state := range r.states {
+		if state.Id == id && state.UsedAt == nil {
+			state.UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeSsoRepo) GetIdentity(provider, issuer, subject string) (*entity.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Issuer == issuer && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeSsoRepo) CreateIdentity(identity *entity.UserIdentity, tx *gorm.DB) error {
+	identity.Id = int64(len(r.identities) + 1)
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *fakeSsoRepo) UpdateIdentityLogin(id int64, at time.Time) error {
+	return nil
+}
+
+func (r *fakeSsoRepo) GetDB() *gorm.DB {
+	return nil
+}
+
+type fakeUserRepo struct {
+	user.UserRepository
+	users []*entity.Users
+}
+
+func (r *fakeUserRepo) FindByEmail(email string) (*entity.Users, error) {
+	for _, users := range r.users {
+		if users.Email == email {
+			return users, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) FindByUserId(userId int64) (*entity.Users, error) {
+	for _, users := range r.users {
+		if users.Id == userId {
+			return users, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) Create(users *entity.Users) error {
+	users.Id = int64(len(r.users) + 100)
+	r.users = append(r.users, users)
+	return nil
+}
+
+type fakeCompanyRepo struct {
+	company.CompanyRepository
+	companies []*entity.Company
+}
+
+func (r *fakeCompanyRepo) GetCompanyById(id int64) (*entity.Company, error) {
+	for _, c := range r.companies {
+		if c.Id == id {
+			return c, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeCompanyRepo) GetCompanyBySuffixEmail(email string) (*entity.Company, error) {
+	for _, c := range r.companies {
+		if c.Email == email {
+			return c, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeRoleRepo struct {
+	role.RoleRepository
+}
+
+func (r *fakeRoleRepo) GetRoleBySlug(roleSlug string) *entity.Roles {
+	switch roleSlug {
+	case "viewer":
+		return &entity.Roles{Id: 3, Slug: roleSlug}
+	case "assetManager":
+		return &entity.Roles{Id: 2, Slug: roleSlug}
+	}
+	return &entity.Roles{}
+}
+
+type fakeAuditLogRepo struct {
+	auditLog.AuditLogRepository
+	entries []*entity.AuditLog
+}
+
+func (r *fakeAuditLogRepo) Create(entry *entity.AuditLog, tx *gorm.DB) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+type ssoFixture struct {
+	idp     *stubIdp
+	service *SsoService
+	repo    *fakeSsoRepo
+	users   *fakeUserRepo
+	audit   *fakeAuditLogRepo
+}
+
+func newSsoFixture(t *testing.T, autoProvision bool) *ssoFixture {
+	t.Helper()
+	idp := newStubIdp(t)
+	secret, err := utils.EncryptSecret(testClientSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &ssoFixture{
+		idp: idp,
+		repo: &fakeSsoRepo{config: &entity.CompanySso{
+			CompanyId:         1,
+			Issuer:            idp.server.URL,
+			ClientId:          testClientId,
+			ClientSecret:      secret,
+			RedirectUri:       testRedirectUri,
+			Scopes:            defaultScopes,
+			EmailClaim:        defaultEmail,
+			FirstNameClaim:    defaultFirstName,
+			LastNameClaim:     defaultLastName,
+			RoleClaim:         "groups",
+			DepartmentMapping: "{}",
+			RoleMapping:       `{"it-admins":"assetManager"}`,
+			DefaultRoleSlug:   defaultRoleSlug,
+			Enabled:           true,
+			AutoProvision:     autoProvision,
+		}},
+		users: &fakeUserRepo{users: []*entity.Users{
+			{Id: 1, Email: "alice@acme.com", FirstName: "Alice", LastName: "Nguyen", IsActive: true, CompanyId: 1},
+			{Id: 2, Email: "bob@other.com", FirstName: "Bob", LastName: "Tran", IsActive: true, CompanyId: 2},
+		}},
+		audit: &fakeAuditLogRepo{},
+	}
+	companies := &fakeCompanyRepo{companies: []*entity.Company{{Id: 1, Email: "acme.com"}, {Id: 2, Email: "other.com"}}}
+	f.service = NewSsoService(f.repo, f.users, companies, nil, &fakeRoleRepo{}, f.audit)
+	return f
+}
+
+// login chạy trọn luồng: Authorize, đăng nhập ở IdP rồi callback
+func (f *ssoFixture) login(t *testing.T, email string) (*entity.Users, bool, error) {
+	t.Helper()
+	res, err := f.service.Authorize(email)
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	state, code := f.idp.login(t, res.AuthorizationUrl)
+	return f.service.Authenticate(state, code, "127.0.0.1")
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name            string
+		autoProvision   bool
+		claims          jwt.MapClaims
+		mutate          func(jwt.MapClaims)
+		tamper          func(*fakeSsoRepo)
+		wantErr         string
+		wantUserId      int64
+		wantProvisioned bool
+	}{
+		{
+			name:       "links existing user by verified email",
+			claims:     jwt.MapClaims{"sub": "idp-alice", "email": "Alice@acme.com", "email_verified": true},
+			wantUserId: 1,
+		},
+		{
+			name:            "provisions new user when auto-provision is on",
+			autoProvision:   true,
+			claims:          jwt.MapClaims{"sub": "idp-dan", "email": "dan@acme.com", "email_verified": true, "given_name": "Dan", "family_name": "Pham", "groups": []string{"staff", "it-admins"}},
+			wantProvisioned: true,
+		},
+		{
+			name:    "rejects unknown user when auto-provision is off",
+			claims:  jwt.MapClaims{"sub": "idp-dan", "email": "dan@acme.com", "email_verified": true},
+			wantErr: "account doesn't exist",
+		},
+		{
+			name:    "rejects bad nonce",
+			claims:  jwt.MapClaims{"sub": "idp-alice", "email": "alice@acme.com", "email_verified": true},
+			mutate:  func(c jwt.MapClaims) { c["nonce"] = "replayed-nonce" },
+			wantErr: "invalid nonce",
+		},
+		{
+			name:    "rejects wrong audience",
+			claims:  jwt.MapClaims{"sub": "idp-alice", "email": "alice@acme.com", "email_verified": true},
+			mutate:  func(c jwt.MapClaims) { c["aud"] = "another-app" },
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "rejects wrong issuer",
+			claims:  jwt.MapClaims{"sub": "idp-alice", "email": "alice@acme.com", "email_verified": true},
+			mutate:  func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "rejects wrong PKCE verifier",
+			claims:  jwt.MapClaims{"sub": "idp-alice", "email": "alice@acme.com", "email_verified": true},
+			tamper:  func(r *fakeSsoRepo) { r.states[len(r.states)-1].CodeVerifier = "guessed-verifier" },
+			wantErr: "invalid_grant",
+		},
+		{
+			name:    "rejects missing email_verified",
+			claims:  jwt.MapClaims{"sub": "idp-alice", "email": "alice@acme.com"},
+			wantErr: "isn't verified",
+		},
+		{
+			name:    "rejects unverified email",
+			claims:  jwt.MapClaims{"sub": "idp-alice", "email": "alice@acme.com", "email_verified": false},
+			wantErr: "isn't verified",
+		},
+		{
+			name:    "rejects email of another company",
+			claims:  jwt.MapClaims{"sub": "idp-bob", "email": "bob@other.com", "email_verified": true},
+			wantErr: "doesn't belong to this company",
+		},
+		{
+			name:          "doesn't provision email outside the company domain",
+			autoProvision: true,
+			claims:        jwt.MapClaims{"sub": "idp-eve", "email": "eve@gmail.com", "email_verified": true},
+			wantErr:       "doesn't belong to this company",
+		},
+		{
+			name:    "rejects expired login session",
+			claims:  jwt.MapClaims{"sub": "idp-alice", "email": "alice@acme.com", "email_verified": true},
+			tamper:  func(r *fakeSsoRepo) { r.states[len(r.states)-1].ExpiresAt = time.Now().Add(-time.Minute) },
+			wantErr: "expired",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSsoFixture(t, tt.autoProvision)
+			f.idp.claims = tt.claims
+			f.idp.mutate = tt.mutate
+			res, err := f.service.Authorize("alice@acme.com")
+			if err != nil {
+				t.Fatalf("Authorize() error = %v", err)
+			}
+			state, code := f.idp.login(t, res.AuthorizationUrl)
+			if tt.tamper != nil {
+				tt.tamper(f.repo)
+			}
+			users, provisioned, err := f.service.Authenticate(state, code, "127.0.0.1")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Authenticate() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(f.repo.identities) != 0 {
+					t.Errorf("identity linked on failed login: %+v", f.repo.identities[0])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if provisioned != tt.wantProvisioned {
+				t.Errorf("provisioned = %v, want %v", provisioned, tt.wantProvisioned)
+			}
+			if tt.wantUserId != 0 && users.Id != tt.wantUserId {
+				t.Errorf("user id = %v, want %v", users.Id, tt.wantUserId)
+			}
+			if len(f.repo.identities) != 1 || f.repo.identities[0].UserId != users.Id || f.repo.identities[0].Issuer != f.idp.server.URL {
+				t.Errorf("identity not linked: %+v", f.repo.identities)
+			}
+		})
+	}
+}
+
+func TestAuthenticateProvisionedUser(t *testing.T) {
+	f := newSsoFixture(t, true)
+	f.idp.claims = jwt.MapClaims{"sub": "idp-dan", "email": "dan@acme.com", "email_verified": true, "given_name": "Dan", "family_name": "Pham", "groups": []string{"staff", "it-admins"}}
+	users, provisioned, err := f.login(t, "dan@acme.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !provisioned || users.CompanyId != 1 || users.RoleId != 2 || users.FirstName != "Dan" || users.LastName != "Pham" || !users.IsActive {
+		t.Errorf("unexpected provisioned user: %+v", users)
+	}
+	actions := []string{}
+	for _, entry := range f.audit.entries {
+		actions = append(actions, entry.Action)
+	}
+	if strings.Join(actions, ",") != entity.AuditSsoUserProvisioned+","+entity.AuditSsoLogin {
+		t.Errorf("audit actions = %v", actions)
+	}
+}
+
+func TestAuthenticateLinkedIdentity(t *testing.T) {
+	f := newSsoFixture(t, false)
+	f.idp.claims = jwt.MapClaims{"sub": "idp-alice", "email": "alice@acme.com", "email_verified": true}
+	if _, _, err := f.login(t, "alice@acme.com"); err != nil {
+		t.Fatal(err)
+	}
+	// Lần sau tìm theo sub đã liên kết, email ở IdP đổi cũng không ảnh hưởng
+	f.idp.claims = jwt.MapClaims{"sub": "idp-alice", "email": "alice.nguyen@acme.com"}
+	users, provisioned, err := f.login(t, "alice@acme.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if users.Id != 1 || provisioned || len(f.repo.identities) != 1 {
+		t.Errorf("user = %v, provisioned = %v, identities = %v", users.Id, provisioned, len(f.repo.identities))
+	}
+}
+
+func TestAuthenticateReplayedState(t *testing.T) {
+	f := newSsoFixture(t, false)
+	f.idp.claims = jwt.MapClaims{"sub": "idp-alice", "email": "alice@acme.com", "email_verified": true}
+	res, err := f.service.Authorize("alice@acme.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code := f.idp.login(t, res.AuthorizationUrl)
+	if _, _, err := f.service.Authenticate(state, code, "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	_, replayCode := f.idp.login(t, res.AuthorizationUrl)
+	if _, _, err := f.service.Authenticate(state, replayCode, "127.0.0.1"); err == nil {
+		t.Fatal("replayed state was accepted")
+	}
+	if _, _, err := f.service.Authenticate("unknown-state", replayCode, "127.0.0.1"); err == nil || err.Error() != "invalid state" {
+		t.Fatalf("unknown state error = %v", err)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	f := newSsoFixture(t, false)
+	if _, err := f.service.Authorize("someone@unknown.com"); err == nil {
+		t.Error("Authorize() accepted an unregistered domain")
+	}
+	f.repo.config.Enabled = false
+	if _, err := f.service.Authorize("alice@acme.com"); err == nil {
+		t.Error("Authorize() accepted a company with SSO disabled")
+	}
+	f.repo.config.Enabled = true
+	res, err := f.service.Authorize("alice@acme.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, _ := url.ParseQuery(res.AuthorizationUrl[strings.Index(res.AuthorizationUrl, "?")+1:])
+	state := f.repo.states[len(f.repo.states)-1]
+	if state.StateHash != utils.HashToken(query.Get("state")) || state.StateHash == query.Get("state") {
+		t.Error("state must be stored as a hash")
+	}
+	sum := sha256.Sum256([]byte(state.CodeVerifier))
+	if query.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		t.Error("code_challenge isn't the S256 of the stored verifier")
+	}
+	if query.Get("nonce") != state.Nonce {
+		t.Error("nonce isn't stored with the state")
+	}
+}
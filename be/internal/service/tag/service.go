@@ -0,0 +1,262 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"BE_Manage_device/internal/domain/filter"
+	asset_log "BE_Manage_device/internal/repository/asset_log"
+	asset "BE_Manage_device/internal/repository/assets"
+	tag "BE_Manage_device/internal/repository/tags"
+	user "BE_Manage_device/internal/repository/user"
+	assetS "BE_Manage_device/internal/service/asset"
+
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const defaultSearchLimit = 10
+
+type TagService struct {
+	repo          tag.TagRepository
+	assetRepo     asset.AssetsRepository
+	userRepo      user.UserRepository
+	assetLogRepo  asset_log.AssetsLogRepository
+	assetsService *assetS.AssetsService
+}
+
+func NewTagService(repo tag.TagRepository, assetRepo asset.AssetsRepository, userRepo user.UserRepository, assetLogRepo asset_log.AssetsLogRepository, assetsService *assetS.AssetsService) *TagService {
+	return &TagService{repo: repo, assetRepo: assetRepo, userRepo: userRepo, assetLogRepo: assetLogRepo, assetsService: assetsService}
+}
+
+func (service *TagService) Create(userId int64, tagName string) (*entity.Tags, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	tagNames := filter.NormalizeTagNames([]string{tagName})
+	if len(tagNames) == 0 {
+		return nil, errors.New("tag name can't be empty")
+	}
+	tagCreate, err := service.repo.Create(&entity.Tags{TagName: tagNames[0], CompanyId: user.CompanyId}, service.repo.GetDB())
+	if err != nil {
+		if strings.Contains(err.Error(), "idx_tag_company") {
+			return nil, fmt.Errorf("This tag already exists for this company")
+		}
+		return nil, err
+	}
+	return tagCreate, nil
+}
+
+// Search trả về các tag bắt đầu bằng keyword, dùng cho autocomplete
+func (service *TagService) Search(userId int64, keyword string, limit int) ([]*entity.Tags, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	return service.repo.Search(user.CompanyId, strings.ToLower(strings.TrimSpace(keyword)), limit)
+}
+
+func (service *TagService) Delete(userId int64, id int64) error {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return err
+	}
+	tag, err := service.repo.GetById(id)
+	if err != nil {
+		return err
+	}
+	if tag.CompanyId != user.CompanyId {
+		return errors.New("can't find record this id")
+	}
+	return service.repo.Delete(id)
+}
+
+// SetAssetTags thay toàn bộ tag của một tài sản
+func (service *TagService) SetAssetTags(userId int64, assetId int64, tagNames []string) (*entity.Assets, error) {
+	var err error
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := service.assetRepo.GetAssetById(assetId)
+	if err != nil {
+		return nil, err
+	}
+	if asset.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find asset")
+	}
+	if err = service.assetsService.CheckPermissionForManager(userId, asset.DepartmentId); err != nil {
+		return nil, err
+	}
+	tagNames = filter.NormalizeTagNames(tagNames)
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	tags, err := service.getOrCreateTags(user.CompanyId, tagNames, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = service.repo.RemoveAllFromAsset(assetId, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = service.repo.AddToAssets([]int64{assetId}, tagIds(tags), tx)
+	if err != nil {
+		return nil, err
+	}
+	changeSummary := "Remove all tags"
+	if len(tagNames) > 0 {
+		changeSummary = "Set tags: " + strings.Join(tagNames, ", ")
+	}
+	_, err = service.assetLogRepo.Create(&entity.AssetLog{
+		Action:        "Update",
+		Timestamp:     time.Now(),
+		ByUserId:      &userId,
+		ChangeSummary: changeSummary,
+		AssetId:       assetId,
+		CompanyId:     user.CompanyId,
+	}, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return service.assetRepo.GetAssetById(assetId)
+}
+
+// BulkUpdate thêm hoặc bỏ tag cho toàn bộ tài sản khớp với bộ lọc
+func (service *TagService) BulkUpdate(userId int64, action string, tagNames []string, assetFilter filter.AssetFilter) (int, []string, error) {
+	var err error
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return 0, nil, err
+	}
+	tagNames = filter.NormalizeTagNames(tagNames)
+	if len(tagNames) == 0 {
+		return 0, nil, errors.New("tag names is required")
+	}
+	assetFilter.CompanyId = user.CompanyId
+	// Không phải admin thì chỉ được gắn tag cho tài sản thuộc phòng ban của mình
+	if user.Role.Slug != "admin" {
+		if user.DepartmentId == nil {
+			return 0, tagNames, nil
+		}
+		deptStr := strconv.FormatInt(*user.DepartmentId, 10)
+		assetFilter.DepartmentId = &deptStr
+	}
+	var assets []entity.Assets
+	db := service.assetRepo.GetDB()
+	if err = assetFilter.ApplyFilter(db.Model(&entity.Assets{}), userId).Find(&assets).Error; err != nil {
+		return 0, nil, err
+	}
+	assetIds := []int64{}
+	seen := map[int64]bool{}
+	for _, a := range assets {
+		if seen[a.Id] {
+			continue
+		}
+		seen[a.Id] = true
+		assetIds = append(assetIds, a.Id)
+	}
+	if len(assetIds) == 0 {
+		return 0, tagNames, nil
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	var changeSummary string
+	if action == "add" {
+		var tags []*entity.Tags
+		tags, err = service.getOrCreateTags(user.CompanyId, tagNames, tx)
+		if err != nil {
+			return 0, nil, err
+		}
+		err = service.repo.AddToAssets(assetIds, tagIds(tags), tx)
+		changeSummary = "Add tags: " + strings.Join(tagNames, ", ")
+	} else {
+		var tags []*entity.Tags
+		tags, err = service.repo.GetByNames(user.CompanyId, tagNames)
+		if err != nil {
+			return 0, nil, err
+		}
+		err = service.repo.RemoveFromAssets(assetIds, tagIds(tags), tx)
+		changeSummary = "Remove tags: " + strings.Join(tagNames, ", ")
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, assetId := range assetIds {
+		_, err = service.assetLogRepo.Create(&entity.AssetLog{
+			Action:        "Update",
+			Timestamp:     time.Now(),
+			ByUserId:      &userId,
+			ChangeSummary: changeSummary,
+			AssetId:       assetId,
+			CompanyId:     user.CompanyId,
+		}, tx)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		return 0, nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return len(assetIds), tagNames, nil
+}
+
+func (service *TagService) getOrCreateTags(companyId int64, tagNames []string, tx *gorm.DB) ([]*entity.Tags, error) {
+	if len(tagNames) == 0 {
+		return []*entity.Tags{}, nil
+	}
+	tags, err := service.repo.GetByNames(companyId, tagNames)
+	if err != nil {
+		return nil, err
+	}
+	existed := map[string]bool{}
+	for _, t := range tags {
+		existed[t.TagName] = true
+	}
+	for _, name := range tagNames {
+		if existed[name] {
+			continue
+		}
+		tagCreate, err := service.repo.Create(&entity.Tags{TagName: name, CompanyId: companyId}, tx)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tagCreate)
+	}
+	return tags, nil
+}
+
+func tagIds(tags []*entity.Tags) []int64 {
+	ids := make([]int64, 0, len(tags))
+	for _, t := range tags {
+		ids = append(ids, t.Id)
+	}
+	return ids
+}
+
+func (service *TagService) GetAssetById(assetId int64) (*entity.Assets, error) {
+	return service.assetRepo.GetAssetById(assetId)
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	tco "BE_Manage_device/internal/repository/tco"
+	user "BE_Manage_device/internal/repository/user"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+type TcoService struct {
+	repo     tco.TcoRepository
+	userRepo user.UserRepository
+}
+
+func NewTcoService(repo tco.TcoRepository, userRepo user.UserRepository) *TcoService {
+	return &TcoService{repo: repo, userRepo: userRepo}
+}
+
+func (service *TcoService) GetAssetTco(userId int64, assetId int64) (*dto.AssetTcoResponse, error) {
+	rows, err := service.loadAssets(userId, &assetId, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("can't find asset")
+	}
+	return rows[0], nil
+}
+
+func (service *TcoService) GetAssets(userId int64, request dto.TcoFilterRequest) ([]*dto.AssetTcoResponse, error) {
+	assets, err := service.loadAssets(userId, nil, request.CategoryId, request.DepartmentId)
+	if err != nil {
+		return nil, err
+	}
+	value := func(a *dto.AssetTcoResponse) float64 {
+		switch request.SortBy {
+		case "annual", "average":
+			return a.AnnualCost
+		case "maintenance":
+			return a.MaintenanceCost
+		case "purchase":
+			return a.PurchaseCost
+		case "count":
+			return float64(a.MaintenanceCount)
+		default:
+			return a.TotalCost
+		}
+	}
+	sort.SliceStable(assets, func(i, j int) bool {
+		if request.Order == "asc" {
+			return value(assets[i]) < value(assets[j])
+		}
+		return value(assets[i]) > value(assets[j])
+	})
+	return limit(assets, request.Limit), nil
+}
+
+func (service *TcoService) GetByCategory(userId int64, request dto.TcoFilterRequest) ([]*dto.TcoGroupResponse, error) {
+	assets, err := service.loadAssets(userId, nil, request.CategoryId, request.DepartmentId)
+	if err != nil {
+		return nil, err
+	}
+	groups := groupBy(assets, func(a *dto.AssetTcoResponse) (int64, string) { return a.CategoryId, a.CategoryName })
+	return limit(sortGroups(groups, request.SortBy, request.Order), request.Limit), nil
+}
+
+func (service *TcoService) GetByDepartment(userId int64, request dto.TcoFilterRequest) ([]*dto.TcoGroupResponse, error) {
+	assets, err := service.loadAssets(userId, nil, request.CategoryId, request.DepartmentId)
+	if err != nil {
+		return nil, err
+	}
+	groups := groupBy(assets, func(a *dto.AssetTcoResponse) (int64, string) { return a.DepartmentId, a.DepartmentName })
+	return limit(sortGroups(groups, request.SortBy, request.Order), request.Limit), nil
+}
+
+func (service *TcoService) loadAssets(userId int64, assetId, categoryId, departmentId *int64) ([]*dto.AssetTcoResponse, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	departmentId, ok := utils.ViewerDepartmentScope(user, departmentId)
+	if !ok {
+		return []*dto.AssetTcoResponse{}, nil
+	}
+	rows, err := service.repo.GetAssetCosts(user.CompanyId, assetId, categoryId, departmentId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	res := make([]*dto.AssetTcoResponse, 0, len(rows))
+	for _, r := range rows {
+		res = append(res, computeTco(r, now))
+	}
+	return res, nil
+}
+
+// TCO = giá mua (theo hóa đơn nếu có) + chi phí bảo trì - giá trị thu hồi khi ngừng sử dụng/thanh lý
+func computeTco(row *tco.AssetCostRow, now time.Time) *dto.AssetTcoResponse {
+	purchase := row.Cost
+	if row.BillAmount != nil {
+		purchase = *row.BillAmount
+	}
+	residual := 0.0
+	if (row.Status == "Retired" || row.Status == "Disposed") && row.ResidualValue != nil {
+		residual = *row.ResidualValue
+	}
+	ownedUntil := now
+	if row.RetiredAt != nil && row.RetiredAt.Before(now) {
+		ownedUntil = *row.RetiredAt
+	}
+	yearsOwned := math.Max(ownedUntil.Sub(row.PurchaseDate).Hours()/24/365, 0)
+	total := purchase + row.MaintenanceCost - residual
+	return &dto.AssetTcoResponse{
+		AssetId:          row.AssetId,
+		AssetName:        row.AssetName,
+		SerialNumber:     row.SerialNumber,
+		Status:           row.Status,
+		CategoryId:       row.CategoryId,
+		CategoryName:     row.CategoryName,
+		DepartmentId:     row.DepartmentId,
+		DepartmentName:   row.DepartmentName,
+		PurchaseCost:     utils.RoundAmount(purchase),
+		MaintenanceCost:  utils.RoundAmount(row.MaintenanceCost),
+		MaintenanceCount: row.MaintenanceCount,
+		ResidualValue:    utils.RoundAmount(residual),
+		TotalCost:        utils.RoundAmount(total),
+		YearsOwned:       utils.RoundAmount(yearsOwned),
+		// Tài sản dùng chưa đủ một năm thì chi phí năm bằng tổng chi phí
+		AnnualCost: utils.RoundAmount(total / math.Max(yearsOwned, 1)),
+	}
+}
+
+func groupBy(assets []*dto.AssetTcoResponse, key func(*dto.AssetTcoResponse) (int64, string)) []*dto.TcoGroupResponse {
+	groups := []*dto.TcoGroupResponse{}
+	byId := map[int64]*dto.TcoGroupResponse{}
+	for _, a := range assets {
+		id, name := key(a)
+		group, ok := byId[id]
+		if !ok {
+			group = &dto.TcoGroupResponse{Id: id, Name: name}
+			byId[id] = group
+			groups = append(groups, group)
+		}
+		group.AssetCount++
+		group.PurchaseCost += a.PurchaseCost
+		group.MaintenanceCost += a.MaintenanceCost
+		group.ResidualValue += a.ResidualValue
+		group.TotalCost += a.TotalCost
+	}
+	for _, g := range groups {
+		g.PurchaseCost = utils.RoundAmount(g.PurchaseCost)
+		g.MaintenanceCost = utils.RoundAmount(g.MaintenanceCost)
+		g.ResidualValue = utils.RoundAmount(g.ResidualValue)
+		g.TotalCost = utils.RoundAmount(g.TotalCost)
+		g.AverageCost = utils.RoundAmount(g.TotalCost / float64(g.AssetCount))
+	}
+	return groups
+}
+
+func sortGroups(groups []*dto.TcoGroupResponse, sortBy, order string) []*dto.TcoGroupResponse {
+	value := func(g *dto.TcoGroupResponse) float64 {
+		switch sortBy {
+		case "average", "annual":
+			return g.AverageCost
+		case "maintenance":
+			return g.MaintenanceCost
+		case "purchase":
+			return g.PurchaseCost
+		case "count":
+			return float64(g.AssetCount)
+		default:
+			return g.TotalCost
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if order == "asc" {
+			return value(groups[i]) < value(groups[j])
+		}
+		return value(groups[i]) > value(groups[j])
+	})
+	return groups
+}
+
+func limit[T any](items []T, n int) []T {
+	if n > 0 && n < len(items) {
+		return items[:n]
+	}
+	return items
+}
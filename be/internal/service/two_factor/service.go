@@ -0,0 +1,457 @@
+package service
+
+import (
+	"BE_Manage_device/config"
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	auditLog "BE_Manage_device/internal/repository/audit_logs"
+	company "BE_Manage_device/internal/repository/company"
+	twoFactor "BE_Manage_device/internal/repository/two_factor"
+	user "BE_Manage_device/internal/repository/user"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	challengeTtl         = 5 * time.Minute
+	maxChallengeAttempts = 5
+	recoveryCodeCount    = 10
+)
+
+type TwoFactorService struct {
+	repo         twoFactor.TwoFactorRepository
+	userRepo     user.UserRepository
+	companyRepo  company.CompanyRepository
+	auditLogRepo auditLog.AuditLogRepository
+}
+
+func NewTwoFactorService(repo twoFactor.TwoFactorRepository, userRepo user.UserRepository, companyRepo company.CompanyRepository, auditLogRepo auditLog.AuditLogRepository) *TwoFactorService {
+	return &TwoFactorService{repo: repo, userRepo: userRepo, companyRepo: companyRepo, auditLogRepo: auditLogRepo}
+}
+
+func (service *TwoFactorService) Status(userId int64) (*dto.TwoFactorStatusResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	company, err := service.companyRepo.GetCompanyById(users.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+	res := &dto.TwoFactorStatusResponse{Enforced: company.RequireTwoFactor}
+	tf, err := service.repo.GetByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if tf != nil && tf.Enabled {
+		res.Enabled = true
+		res.EnabledAt = tf.EnabledAt
+		if res.RecoveryCodesLeft, err = service.repo.CountRecoveryCodes(userId); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// BeginEnrollment sinh secret mới, 2FA chỉ được bật sau khi người dùng nhập đúng mã từ ứng dụng xác thực
+func (service *TwoFactorService) BeginEnrollment(userId int64) (*dto.TwoFactorEnrollmentResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	tf, err := service.repo.GetByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if tf != nil && tf.Enabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	secret, err := utils.GenerateTotpSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := utils.EncryptSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	if tf == nil {
+		tf = &entity.UserTwoFactor{UserId: userId}
+	}
+	tf.Secret = encrypted
+	tf.LastUsedStep = 0
+	tf.CreatedAt = time.Now()
+	if err := service.repo.Save(tf, service.repo.GetDB()); err != nil {
+		return nil, err
+	}
+	uri := utils.TotpUri(config.TwoFactorIssuer, users.Email, secret)
+	qr, err := utils.TotpQrDataUrl(uri)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TwoFactorEnrollmentResponse{Secret: secret, OtpauthUrl: uri, QrCode: qr}, nil
+}
+
+// ConfirmEnrollment bật 2FA khi mã TOTP hợp lệ và trả về mã khôi phục
+func (service *TwoFactorService) ConfirmEnrollment(userId int64, code string, ip string) (*dto.TwoFactorRecoveryCodesResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	tf, err := service.repo.GetByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if tf == nil {
+		return nil, errors.New("start two-factor enrollment first")
+	}
+	if tf.Enabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	codes, err := service.enable(users, tf, code, ip)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable tắt 2FA, không cho phép khi công ty bắt buộc
+func (service *TwoFactorService) Disable(userId int64, code string, ip string) error {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return err
+	}
+	company, err := service.companyRepo.GetCompanyById(users.CompanyId)
+	if err != nil {
+		return err
+	}
+	if company.RequireTwoFactor {
+		return errors.New("two-factor authentication is required by your company")
+	}
+	tf, err := service.getEnabled(userId)
+	if err != nil {
+		return err
+	}
+	if err := service.checkCode(users, tf, code, ip); err != nil {
+		return err
+	}
+	return service.remove(users, &users.Id, entity.AuditTwoFactorDisabled, "Disabled by the user", ip)
+}
+
+// RegenerateRecoveryCodes thay toàn bộ mã khôi phục cũ
+func (service *TwoFactorService) RegenerateRecoveryCodes(userId int64, code string, ip string) (*dto.TwoFactorRecoveryCodesResponse, error) {
+	var err error
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	tf, err := service.getEnabled(userId)
+	if err != nil {
+		return nil, err
+	}
+	if err = service.checkCode(users, tf, code, ip); err != nil {
+		return nil, err
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	codes, err := service.replaceRecoveryCodes(userId, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = service.audit(tx, users.CompanyId, &users.Id, users.Id, entity.AuditTwoFactorRecoveryRenewed, "", ip); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return &dto.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Reset quản trị viên xoá 2FA của người dùng bị mất thiết bị, lần đăng nhập sau phải đăng ký lại nếu công ty bắt buộc
+func (service *TwoFactorService) Reset(adminId int64, userId int64, reason string, ip string) error {
+	admin, err := service.userRepo.FindByUserId(adminId)
+	if err != nil {
+		return err
+	}
+	target, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return err
+	}
+	if target.CompanyId != admin.CompanyId {
+		return errors.New("user not found")
+	}
+	tf, err := service.repo.GetByUserId(userId)
+	if err != nil {
+		return err
+	}
+	if tf == nil {
+		return errors.New("user hasn't set up two-factor authentication")
+	}
+	return service.remove(target, &admin.Id, entity.AuditTwoFactorReset, reason, ip)
+}
+
+// SetEnforcement bật/tắt bắt buộc 2FA cho toàn công ty
+func (service *TwoFactorService) SetEnforcement(adminId int64, required bool, ip string) error {
+	admin, err := service.userRepo.FindByUserId(adminId)
+	if err != nil {
+		return err
+	}
+	if err := service.companyRepo.UpdateRequireTwoFactor(admin.CompanyId, required); err != nil {
+		return err
+	}
+	return service.audit(nil, admin.CompanyId, &admin.Id, 0, entity.AuditTwoFactorEnforcementChange, fmt.Sprintf("required = %v", required), ip)
+}
+
+func (service *TwoFactorService) GetAuditLogs(userId int64, request dto.AuditLogFilterRequest) ([]dto.AuditLogResponse, error) {
+	users, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := service.auditLogRepo.Filter(users.CompanyId, request.Action, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ConvertAuditLogsToResponses(logs), nil
+}
+
+// NeedsChallenge kiểm tra sau khi đúng mật khẩu: required khi phải nhập mã, setup khi phải đăng ký 2FA trước
+func (service *TwoFactorService) NeedsChallenge(users *entity.Users) (bool, bool, error) {
+	tf, err := service.repo.GetByUserId(users.Id)
+	if err != nil {
+		return false, false, err
+	}
+	if tf != nil && tf.Enabled {
+		return true, false, nil
+	}
+	company, err := service.companyRepo.GetCompanyById(users.CompanyId)
+	if err != nil {
+		return false, false, err
+	}
+	return company.RequireTwoFactor, company.RequireTwoFactor, nil
+}
+
+func (service *TwoFactorService) CreateChallenge(userId int64, setup bool) (*dto.TwoFactorChallengeResponse, error) {
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	challenge := &entity.TwoFactorChallenge{
+		UserId:    userId,
+		TokenHash: utils.HashToken(token),
+		Setup:     setup,
+		ExpiresAt: time.Now().Add(challengeTtl),
+	}
+	if err := service.repo.CreateChallenge(challenge); err != nil {
+		return nil, err
+	}
+	return &dto.TwoFactorChallengeResponse{ChallengeToken: token, SetupRequired: setup, ExpiresAt: challenge.ExpiresAt}, nil
+}
+
+// BeginChallengeEnrollment đăng ký 2FA trong lúc đăng nhập khi công ty bắt buộc
+func (service *TwoFactorService) BeginChallengeEnrollment(token string) (*dto.TwoFactorEnrollmentResponse, error) {
+	challenge, err := service.getChallenge(token)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.Setup {
+		return nil, errors.New("two-factor authentication is already set up")
+	}
+	return service.BeginEnrollment(challenge.UserId)
+}
+
+// VerifyChallenge kiểm tra mã của bước xác thực thứ hai, trả về người dùng và mã khôi phục nếu vừa đăng ký
+func (service *TwoFactorService) VerifyChallenge(token string, code string, ip string) (int64, []string, error) {
+	challenge, err := service.getChallenge(token)
+	if err != nil {
+		return 0, nil, err
+	}
+	users, err := service.userRepo.FindByUserId(challenge.UserId)
+	if err != nil {
+		return 0, nil, err
+	}
+	tf, err := service.repo.GetByUserId(users.Id)
+	if err != nil {
+		return 0, nil, err
+	}
+	var codes []string
+	switch {
+	case tf != nil && tf.Enabled:
+		err = service.checkCode(users, tf, code, ip)
+	case challenge.Setup && tf != nil:
+		codes, err = service.enable(users, tf, code, ip)
+	default:
+		err = errors.New("start two-factor enrollment first")
+	}
+	if err != nil {
+		if e := service.repo.IncreaseChallengeAttempts(challenge.Id); e != nil {
+			return 0, nil, e
+		}
+		return 0, nil, err
+	}
+	ok, err := service.repo.MarkChallengeUsed(challenge.Id, time.Now())
+	if err != nil {
+		return 0, nil, err
+	}
+	if !ok {
+		return 0, nil, errors.New("challenge was already used")
+	}
+	return users.Id, codes, nil
+}
+
+func (service *TwoFactorService) getChallenge(token string) (*entity.TwoFactorChallenge, error) {
+	challenge, err := service.repo.GetChallengeByHash(utils.HashToken(token))
+	if err != nil {
+		return nil, errors.New("invalid challenge")
+	}
+	if challenge.UsedAt != nil || time.Now().After(challenge.ExpiresAt) || challenge.Attempts >= maxChallengeAttempts {
+		return nil, errors.New("challenge has expired, please login again")
+	}
+	return challenge, nil
+}
+
+func (service *TwoFactorService) getEnabled(userId int64) (*entity.UserTwoFactor, error) {
+	tf, err := service.repo.GetByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	if tf == nil || !tf.Enabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+	return tf, nil
+}
+
+// checkCode chấp nhận mã TOTP chưa dùng hoặc mã khôi phục chưa dùng
+func (service *TwoFactorService) checkCode(users *entity.Users, tf *entity.UserTwoFactor, code string, ip string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == 6 {
+		secret, err := utils.DecryptSecret(tf.Secret)
+		if err != nil {
+			return err
+		}
+		step, ok := utils.ValidateTotp(secret, code, time.Now(), tf.LastUsedStep)
+		if !ok {
+			return errors.New("invalid two-factor code")
+		}
+		// Cập nhật có điều kiện để hai request đồng thời không dùng được cùng một mã
+		updated, err := service.repo.UpdateLastUsedStep(users.Id, step)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return errors.New("two-factor code was already used")
+		}
+		return nil
+	}
+	used, err := service.repo.UseRecoveryCode(users.Id, utils.HashToken(strings.ToLower(code)), time.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errors.New("invalid two-factor code")
+	}
+	return service.audit(nil, users.CompanyId, &users.Id, users.Id, entity.AuditTwoFactorRecoveryUsed, "", ip)
+}
+
+func (service *TwoFactorService) enable(users *entity.Users, tf *entity.UserTwoFactor, code string, ip string) ([]string, error) {
+	var err error
+	secret, err := utils.DecryptSecret(tf.Secret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := utils.ValidateTotp(secret, strings.TrimSpace(code), time.Now(), tf.LastUsedStep)
+	if !ok {
+		return nil, errors.New("invalid two-factor code")
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	now := time.Now()
+	tf.Enabled = true
+	tf.EnabledAt = &now
+	tf.LastUsedStep = step
+	if err = service.repo.Save(tf, tx); err != nil {
+		return nil, err
+	}
+	codes, err := service.replaceRecoveryCodes(users.Id, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = service.audit(tx, users.CompanyId, &users.Id, users.Id, entity.AuditTwoFactorEnabled, "", ip); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return codes, nil
+}
+
+func (service *TwoFactorService) remove(target *entity.Users, actorId *int64, action string, detail string, ip string) error {
+	var err error
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if err = service.repo.Delete(target.Id, tx); err != nil {
+		return err
+	}
+	if err = service.audit(tx, target.CompanyId, actorId, target.Id, action, detail, ip); err != nil {
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+	return nil
+}
+
+func (service *TwoFactorService) replaceRecoveryCodes(userId int64, tx *gorm.DB) ([]string, error) {
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, c := range codes {
+		hashes = append(hashes, utils.HashToken(c))
+	}
+	if err := service.repo.ReplaceRecoveryCodes(userId, hashes, tx); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// audit targetUserId = 0 khi thao tác không gắn với người dùng cụ thể
+func (service *TwoFactorService) audit(tx *gorm.DB, companyId int64, actorId *int64, targetUserId int64, action string, detail string, ip string) error {
+	entry := &entity.AuditLog{
+		Action:    action,
+		ActorId:   actorId,
+		Detail:    detail,
+		IpAddress: ip,
+		CreatedAt: time.Now(),
+		CompanyId: companyId,
+	}
+	if targetUserId != 0 {
+		entry.TargetUserId = &targetUserId
+	}
+	return service.auditLogRepo.Create(entry, tx)
+}
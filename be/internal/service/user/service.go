@@ -2,6 +2,7 @@ package service
 
 import (
 	"BE_Manage_device/config"
+	"BE_Manage_device/internal/domain/dto"
 	"BE_Manage_device/internal/domain/entity"
 	asset "BE_Manage_device/internal/repository/assets"
 	company "BE_Manage_device/internal/repository/company"
@@ -10,6 +11,9 @@ import (
 	userRBAC "BE_Manage_device/internal/repository/user_rbac"
 	userSession "BE_Manage_device/internal/repository/user_session"
 	emailS "BE_Manage_device/internal/service/email"
+	ldapS "BE_Manage_device/internal/service/ldap"
+	ssoS "BE_Manage_device/internal/service/sso"
+	twoFactorS "BE_Manage_device/internal/service/two_factor"
 	"BE_Manage_device/pkg/utils"
 
 	"errors"
@@ -19,6 +23,7 @@ import (
 
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -31,10 +36,16 @@ type UserService struct {
 	assetRepo          asset.AssetsRepository
 	userRBACRepository userRBAC.UserRBACRepository
 	CompanyRepo        company.CompanyRepository
+	twoFactorService   *twoFactorS.TwoFactorService
+	ssoService         *ssoS.SsoService
+	ldapService        *ldapS.LdapService
 }
 
-func NewUserService(repo user.UserRepository, emailService *emailS.EmailService, userSessionRepo userSession.UsersSessionRepository, roleRepository role.RoleRepository, assetRepo asset.AssetsRepository, userRBACRepository userRBAC.UserRBACRepository, CompanyRepo company.CompanyRepository) *UserService {
-	return &UserService{repo: repo, emailService: emailService, userSessionRepo: userSessionRepo, roleRepository: roleRepository, assetRepo: assetRepo, userRBACRepository: userRBACRepository, CompanyRepo: CompanyRepo}
+// ErrPasswordLoginDisabled công ty chỉ cho phép đăng nhập bằng SSO
+var ErrPasswordLoginDisabled = errors.New("password login is disabled for your company, please sign in with SSO")
+
+func NewUserService(repo user.UserRepository, emailService *emailS.EmailService, userSessionRepo userSession.UsersSessionRepository, roleRepository role.RoleRepository, assetRepo asset.AssetsRepository, userRBACRepository userRBAC.UserRBACRepository, CompanyRepo company.CompanyRepository, twoFactorService *twoFactorS.TwoFactorService, ssoService *ssoS.SsoService, ldapService *ldapS.LdapService) *UserService {
+	return &UserService{repo: repo, emailService: emailService, userSessionRepo: userSessionRepo, roleRepository: roleRepository, assetRepo: assetRepo, userRBACRepository: userRBACRepository, CompanyRepo: CompanyRepo, twoFactorService: twoFactorService, ssoService: ssoService, ldapService: ldapService}
 }
 
 func (service *UserService) Register(firstName, lastName, password, email, redirectUrl string) (*entity.Users, error) {
@@ -66,27 +77,113 @@ func (service *UserService) Register(firstName, lastName, password, email, redir
 	return users, nil
 }
 
-func (service *UserService) Login(email string, password string) (*entity.Users, string, string, error) {
-	user, err := service.repo.FindByEmail(email)
+// Login kiểm tra mật khẩu. Người dùng bật 2FA (hoặc công ty bắt buộc) nhận challenge thay vì token,
+// token chỉ được cấp sau khi CompleteTwoFactorLogin thành công
+func (service *UserService) Login(email string, password string) (*entity.Users, string, string, *dto.TwoFactorChallengeResponse, error) {
+	// Tài khoản bị khoá (offboarding, SCIM, LDAP) không đăng nhập được
+	user, err := service.repo.FindByEmailForLogin(email)
 	if err != nil {
-		return nil, "", "", errors.New("email dont; have")
+		return nil, "", "", nil, errors.New("email dont; have")
+	}
+	// Service account chỉ xác thực bằng API key
+	if user.IsSystemUser {
+		return nil, "", "", nil, errors.New("invalid email or password")
 	}
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, "", "", errors.New("invalid email or password")
+		// Công ty bật đăng nhập LDAP thì kiểm tra mật khẩu trên thư mục
+		ok, ldapErr := service.ldapService.BindLogin(user, password)
+		if ldapErr != nil {
+			logrus.Errorf("Happened error when bind to directory. Error: %v", ldapErr)
+		}
+		if !ok {
+			return nil, "", "", nil, errors.New("invalid email or password")
+		}
+	}
+	disabled, err := service.ssoService.PasswordLoginDisabled(user)
+	if err != nil {
+		return nil, "", "", nil, err
+	}
+	if disabled {
+		return nil, "", "", nil, ErrPasswordLoginDisabled
+	}
+	required, setup, err := service.twoFactorService.NeedsChallenge(user)
+	if err != nil {
+		return nil, "", "", nil, err
+	}
+	if required {
+		challenge, err := service.twoFactorService.CreateChallenge(user.Id, setup)
+		if err != nil {
+			return nil, "", "", nil, err
+		}
+		return user, "", "", challenge, nil
+	}
+	accessToken, refreshToken, err := service.issueTokens(user)
+	if err != nil {
+		return nil, "", "", nil, err
 	}
+	return user, accessToken, refreshToken, nil, nil
+}
 
-	accessToken, refreshToken, err := utils.GenerateTokens(user.Id, email)
+// SetupTwoFactorLogin trả về QR đăng ký 2FA cho challenge của người dùng chưa đăng ký
+func (service *UserService) SetupTwoFactorLogin(challengeToken string) (*dto.TwoFactorEnrollmentResponse, error) {
+	return service.twoFactorService.BeginChallengeEnrollment(challengeToken)
+}
+
+// CompleteTwoFactorLogin bước thứ hai của đăng nhập, trả về mã khôi phục nếu vừa đăng ký 2FA
+func (service *UserService) CompleteTwoFactorLogin(challengeToken string, code string, ip string) (*entity.Users, string, string, []string, error) {
+	userId, recoveryCodes, err := service.twoFactorService.VerifyChallenge(challengeToken, code, ip)
+	if err != nil {
+		return nil, "", "", nil, err
+	}
+	user, err := service.repo.FindByUserId(userId)
+	if err != nil {
+		return nil, "", "", nil, err
+	}
+	if !user.IsActive {
+		return nil, "", "", nil, errors.New("account is inactive")
+	}
+	accessToken, refreshToken, err := service.issueTokens(user)
+	if err != nil {
+		return nil, "", "", nil, err
+	}
+	return user, accessToken, refreshToken, recoveryCodes, nil
+}
+
+// BeginSsoLogin trả về URL đăng nhập IdP của công ty theo email
+func (service *UserService) BeginSsoLogin(email string) (*dto.SsoAuthorizeResponse, error) {
+	return service.ssoService.Authorize(email)
+}
+
+// CompleteSsoLogin cấp token sau khi IdP xác thực, xác thực nhiều lớp do IdP đảm nhận
+func (service *UserService) CompleteSsoLogin(state string, code string, ip string) (*entity.Users, string, string, error) {
+	user, provisioned, err := service.ssoService.Authenticate(state, code, ip)
 	if err != nil {
 		return nil, "", "", err
 	}
+	if provisioned {
+		go service.SetRole(user.Id, user.RoleId)
+	}
+	accessToken, refreshToken, err := service.issueTokens(user)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return user, accessToken, refreshToken, nil
+}
+
+// issueTokens cấp token và thay phiên đăng nhập cũ
+func (service *UserService) issueTokens(user *entity.Users) (string, string, error) {
+	accessToken, refreshToken, err := utils.GenerateTokens(user.Id, user.Email)
+	if err != nil {
+		return "", "", err
+	}
 	if service.userSessionRepo.CheckUserInSession(user.Id) {
 		userSession, err := service.userSessionRepo.FindByUserIdInSession(user.Id)
 		if err != nil {
-			return nil, "", "", err
+			return "", "", err
 		}
 		err = service.userSessionRepo.UpdateIsRevoked(userSession)
 		if err != nil {
-			return nil, "", "", err
+			return "", "", err
 		}
 	}
 	userSession := entity.UsersSessions{
@@ -100,10 +197,10 @@ func (service *UserService) Login(email string, password string) (*entity.Users,
 	err = service.userSessionRepo.Create(&userSession, tx)
 	if err != nil {
 		tx.Rollback()
-		return nil, "", "", err
+		return "", "", err
 	}
 	tx.Commit()
-	return user, accessToken, refreshToken, nil
+	return accessToken, refreshToken, nil
 }
 
 func (service *UserService) Activate(token string) error {
@@ -197,6 +294,11 @@ func (service *UserService) CheckRefreshToken(token string) bool {
 	if userSession.IsRevoked {
 		return false
 	}
+	// Tài khoản đã bị khoá thì không được làm mới token
+	user, err := service.repo.FindByUserId(userSession.UserId)
+	if err != nil || !user.IsActive {
+		return false
+	}
 	return true
 }
 
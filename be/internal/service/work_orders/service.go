@@ -0,0 +1,468 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	asset_log "BE_Manage_device/internal/repository/asset_log"
+	asset "BE_Manage_device/internal/repository/assets"
+	consumable "BE_Manage_device/internal/repository/consumables"
+	maintenanceSchedules "BE_Manage_device/internal/repository/maintenance_schedules"
+	user "BE_Manage_device/internal/repository/user"
+	workOrder "BE_Manage_device/internal/repository/work_orders"
+	notificationS "BE_Manage_device/internal/service/notification"
+	"BE_Manage_device/pkg/utils"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WorkOrderService struct {
+	repo                workOrder.WorkOrderRepository
+	consumableRepo      consumable.ConsumableRepository
+	scheduleRepo        maintenanceSchedules.MaintenanceSchedulesRepository
+	assetRepo           asset.AssetsRepository
+	userRepo            user.UserRepository
+	assetLogRepo        asset_log.AssetsLogRepository
+	NotificationService *notificationS.NotificationService
+}
+
+func NewWorkOrderService(repo workOrder.WorkOrderRepository, consumableRepo consumable.ConsumableRepository, scheduleRepo maintenanceSchedules.MaintenanceSchedulesRepository, assetRepo asset.AssetsRepository, userRepo user.UserRepository, assetLogRepo asset_log.AssetsLogRepository, NotificationService *notificationS.NotificationService) *WorkOrderService {
+	return &WorkOrderService{repo: repo, consumableRepo: consumableRepo, scheduleRepo: scheduleRepo, assetRepo: assetRepo, userRepo: userRepo, assetLogRepo: assetLogRepo, NotificationService: NotificationService}
+}
+
+func (service *WorkOrderService) CreateConsumable(userId int64, request dto.CreateConsumableRequest) (*entity.Consumable, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	return service.consumableRepo.Create(&entity.Consumable{
+		Name:      request.Name,
+		Unit:      request.Unit,
+		Quantity:  request.Quantity,
+		UnitCost:  request.UnitCost,
+		CompanyId: user.CompanyId,
+	})
+}
+
+func (service *WorkOrderService) GetConsumables(userId int64) ([]*entity.Consumable, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	return service.consumableRepo.GetAll(user.CompanyId)
+}
+
+// Nhập thêm (delta > 0) hoặc điều chỉnh giảm (delta < 0) tồn kho
+func (service *WorkOrderService) AdjustConsumable(userId int64, id int64, delta float64) (*entity.Consumable, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	item, err := service.consumableRepo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if item.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	if err := service.consumableRepo.AdjustQuantity(id, delta, service.consumableRepo.GetDB()); err != nil {
+		return nil, err
+	}
+	return service.consumableRepo.GetById(id)
+}
+
+func (service *WorkOrderService) Create(userId int64, request dto.CreateWorkOrderRequest) (*entity.WorkOrder, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := service.scheduleRepo.GetMaintenanceSchedulesById(request.ScheduleId)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Asset.CompanyId != user.CompanyId {
+		return nil, errors.New("maintenance schedule not found")
+	}
+	if err := service.checkTechnician(user.CompanyId, request.TechnicianUserId, request.VendorName); err != nil {
+		return nil, err
+	}
+	workOrder := &entity.WorkOrder{
+		ScheduleId:       schedule.Id,
+		AssetId:          schedule.AssetId,
+		Title:            request.Title,
+		Description:      request.Description,
+		Status:           entity.WorkOrderOpen,
+		TechnicianUserId: request.TechnicianUserId,
+		VendorName:       request.VendorName,
+		LaborRate:        request.LaborRate,
+		CreatedById:      userId,
+		CreatedAt:        time.Now(),
+		CompanyId:        user.CompanyId,
+	}
+	for _, t := range request.Tasks {
+		workOrder.Tasks = append(workOrder.Tasks, entity.WorkOrderTask{Description: t})
+	}
+	if _, err := service.repo.Create(workOrder, service.repo.GetDB()); err != nil {
+		return nil, err
+	}
+	return service.repo.GetById(workOrder.Id)
+}
+
+func (service *WorkOrderService) GetById(userId int64, id int64) (*entity.WorkOrder, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	workOrder, err := service.repo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if workOrder.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	return workOrder, nil
+}
+
+func (service *WorkOrderService) Filter(userId int64, request dto.WorkOrderFilterRequest) ([]*entity.WorkOrder, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	return service.repo.Filter(user.CompanyId, request.Status, request.ScheduleId, request.AssetId, request.TechnicianUserId)
+}
+
+func (service *WorkOrderService) Update(userId int64, id int64, request dto.UpdateWorkOrderRequest) (*entity.WorkOrder, error) {
+	workOrder, err := service.getEditable(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = service.checkTechnician(workOrder.CompanyId, request.TechnicianUserId, request.VendorName); err != nil {
+		return nil, err
+	}
+	workOrder.Title = request.Title
+	workOrder.Description = request.Description
+	if request.Status != "" {
+		workOrder.Status = request.Status
+	}
+	workOrder.TechnicianUserId = request.TechnicianUserId
+	workOrder.VendorName = request.VendorName
+	workOrder.LaborHours = request.LaborHours
+	workOrder.LaborRate = request.LaborRate
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	notify := func() {}
+	// Huỷ phiếu thì hoàn vật tư vào kho và trả tài sản về "In Use" nếu không còn phiếu nào đang mở
+	if workOrder.Status == entity.WorkOrderCancelled {
+		for _, part := range workOrder.Parts {
+			if err = service.consumableRepo.AdjustQuantity(part.ConsumableId, part.Quantity, tx); err != nil {
+				return nil, err
+			}
+			if err = service.repo.DeletePart(part.Id, tx); err != nil {
+				return nil, err
+			}
+		}
+		workOrder.PartsCost = 0
+	}
+	if err = service.repo.Update(workOrder, tx); err != nil {
+		return nil, err
+	}
+	if workOrder.Status == entity.WorkOrderCancelled {
+		var byUser *entity.Users
+		byUser, err = service.userRepo.FindByUserId(userId)
+		if err != nil {
+			return nil, err
+		}
+		notify, err = service.releaseAsset(userId, workOrder, fmt.Sprintf("Work order %d cancelled by %v", workOrder.Id, byUser.Email), time.Now(), tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	notify()
+	return service.repo.GetById(id)
+}
+
+func (service *WorkOrderService) AddTask(userId int64, id int64, description string) (*entity.WorkOrder, error) {
+	if _, err := service.getEditable(userId, id); err != nil {
+		return nil, err
+	}
+	if _, err := service.repo.CreateTask(&entity.WorkOrderTask{WorkOrderId: id, Description: description}); err != nil {
+		return nil, err
+	}
+	return service.repo.GetById(id)
+}
+
+func (service *WorkOrderService) UpdateTask(userId int64, id int64, taskId int64, isDone bool) (*entity.WorkOrder, error) {
+	if _, err := service.getEditable(userId, id); err != nil {
+		return nil, err
+	}
+	task, err := service.repo.GetTaskById(taskId)
+	if err != nil {
+		return nil, err
+	}
+	if task.WorkOrderId != id {
+		return nil, errors.New("task does not belong to this work order")
+	}
+	task.IsDone = isDone
+	task.DoneById, task.DoneAt = nil, nil
+	if isDone {
+		now := time.Now()
+		task.DoneById, task.DoneAt = &userId, &now
+	}
+	if err := service.repo.UpdateTask(task); err != nil {
+		return nil, err
+	}
+	return service.repo.GetById(id)
+}
+
+// AddPart ghi nhận vật tư sử dụng và trừ tồn kho
+func (service *WorkOrderService) AddPart(userId int64, id int64, consumableId int64, quantity float64) (*entity.WorkOrder, error) {
+	workOrder, err := service.getEditable(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	item, err := service.consumableRepo.GetById(consumableId)
+	if err != nil {
+		return nil, err
+	}
+	if item.CompanyId != workOrder.CompanyId {
+		return nil, errors.New("consumable not found")
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if err = service.consumableRepo.AdjustQuantity(item.Id, -quantity, tx); err != nil {
+		return nil, fmt.Errorf("%v: %w", item.Name, err)
+	}
+	part := &entity.WorkOrderPart{WorkOrderId: id, ConsumableId: item.Id, Quantity: quantity, UnitCost: item.UnitCost}
+	if _, err = service.repo.CreatePart(part, tx); err != nil {
+		return nil, err
+	}
+	workOrder.PartsCost += quantity * item.UnitCost
+	if err = service.repo.Update(workOrder, tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return service.repo.GetById(id)
+}
+
+// RemovePart hoàn lại vật tư vào kho
+func (service *WorkOrderService) RemovePart(userId int64, id int64, partId int64) (*entity.WorkOrder, error) {
+	workOrder, err := service.getEditable(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	part, err := service.repo.GetPartById(partId)
+	if err != nil {
+		return nil, err
+	}
+	if part.WorkOrderId != id {
+		return nil, errors.New("part does not belong to this work order")
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if err = service.consumableRepo.AdjustQuantity(part.ConsumableId, part.Quantity, tx); err != nil {
+		return nil, err
+	}
+	if err = service.repo.DeletePart(part.Id, tx); err != nil {
+		return nil, err
+	}
+	workOrder.PartsCost -= part.Quantity * part.UnitCost
+	if err = service.repo.Update(workOrder, tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return service.repo.GetById(id)
+}
+
+// Complete hoàn thành phiếu công việc: tính chi phí, cập nhật chi phí/ngày kết thúc thực tế của lịch bảo trì
+// và chuyển tài sản khỏi trạng thái "Under Maintenance" nếu không còn phiếu nào đang mở
+func (service *WorkOrderService) Complete(userId int64, id int64, completionNotes string, laborHours *float64, photos []*multipart.FileHeader) (*entity.WorkOrder, error) {
+	workOrder, err := service.getEditable(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	byUser, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	uploader := utils.NewSupabaseUploader()
+	workOrderPhotos := []*entity.WorkOrderPhoto{}
+	uploaded := []string{}
+	defer func() {
+		// Lưu không thành công thì xoá ảnh đã upload để không để lại file mồ côi
+		if err != nil {
+			uploader.DeleteUrls(uploaded...)
+		}
+	}()
+	for _, photo := range photos {
+		path := fmt.Sprintf("work-orders/%d/%d_%s", workOrder.Id, time.Now().UnixNano(), photo.Filename)
+		var url string
+		url, err = uploadPhoto(uploader, path, photo)
+		if err != nil {
+			return nil, err
+		}
+		uploaded = append(uploaded, url)
+		workOrderPhotos = append(workOrderPhotos, &entity.WorkOrderPhoto{WorkOrderId: workOrder.Id, Url: url, CreatedAt: time.Now()})
+	}
+
+	now := time.Now()
+	if laborHours != nil {
+		workOrder.LaborHours = *laborHours
+	}
+	workOrder.Status = entity.WorkOrderCompleted
+	workOrder.CompletionNotes = completionNotes
+	workOrder.CompletedAt = &now
+	workOrder.TotalCost = workOrder.LaborHours*workOrder.LaborRate + workOrder.PartsCost
+
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if err = service.repo.Update(workOrder, tx); err != nil {
+		return nil, err
+	}
+	if err = service.repo.CreatePhotos(workOrderPhotos, tx); err != nil {
+		return nil, err
+	}
+	scheduleCost, err := service.repo.SumCompletedCost(workOrder.ScheduleId, tx)
+	if err != nil {
+		return nil, err
+	}
+	// Kết thúc sớm hơn dự kiến thì lấy thời điểm hoàn thành làm ngày kết thúc thực tế
+	var endDate *time.Time
+	if !now.Before(workOrder.Schedule.StartDate) && now.Before(workOrder.Schedule.EndDate) {
+		endDate = &now
+	}
+	if err = service.repo.UpdateScheduleCompletion(workOrder.ScheduleId, scheduleCost, endDate, tx); err != nil {
+		return nil, err
+	}
+	notify, err := service.releaseAsset(userId, workOrder, fmt.Sprintf("Work order %d completed by %v", workOrder.Id, byUser.Email), now, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	notify()
+	return service.repo.GetById(id)
+}
+
+// releaseAsset ghi log bảo trì và chuyển tài sản khỏi trạng thái "Under Maintenance" nếu không còn phiếu nào đang mở,
+// hàm trả về gửi thông báo sau khi tx commit
+func (service *WorkOrderService) releaseAsset(userId int64, workOrder *entity.WorkOrder, changeSummary string, now time.Time, tx *gorm.DB) (func(), error) {
+	openCount, err := service.repo.CountOpenByAsset(workOrder.AssetId, tx)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := service.assetRepo.GetAssetById(workOrder.AssetId)
+	if err != nil {
+		return nil, err
+	}
+	movedToInUse := openCount == 0 && asset.Status == "Under Maintenance"
+	if movedToInUse {
+		if _, err = service.assetRepo.UpdateAssetLifeCycleStage(asset.Id, "In Use", tx); err != nil {
+			return nil, err
+		}
+		changeSummary += ", asset moved to 'In Use'"
+	}
+	assetLog := entity.AssetLog{
+		Action:        "Maintenance",
+		Timestamp:     now,
+		ByUserId:      &userId,
+		AssetId:       asset.Id,
+		ChangeSummary: changeSummary,
+		CompanyId:     asset.CompanyId,
+	}
+	if _, err = service.assetLogRepo.Create(&assetLog, tx); err != nil {
+		return nil, err
+	}
+	return func() {
+		if !movedToInUse {
+			return
+		}
+		userHeadDepart, _ := service.userRepo.GetUserHeadDepartment(asset.DepartmentId)
+		userManagerAsset, _ := service.userRepo.GetUserAssetManageOfDepartment(asset.DepartmentId)
+		usersToNotifications := utils.ConvertUsersToNotificationsToMap(userId, []*entity.Users{asset.OnwerUser, userHeadDepart, userManagerAsset})
+		message := fmt.Sprintf("The asset (ID: %v) moved to 'In Use'", asset.Id)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Println("SendNotificationToUsers panic:", r)
+				}
+			}()
+			service.NotificationService.SendNotificationToUsers(usersToNotifications, message, *asset)
+		}()
+	}, nil
+}
+
+func uploadPhoto(uploader *utils.SupabaseUploader, path string, photo *multipart.FileHeader) (string, error) {
+	file, err := photo.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return uploader.Upload(path, file, photo.Header.Get("Content-Type"))
+}
+
+func (service *WorkOrderService) getEditable(userId int64, id int64) (*entity.WorkOrder, error) {
+	workOrder, err := service.GetById(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if workOrder.Status == entity.WorkOrderCompleted || workOrder.Status == entity.WorkOrderCancelled {
+		return nil, fmt.Errorf("work order is %v", workOrder.Status)
+	}
+	return workOrder, nil
+}
+
+// Kỹ thuật viên là người dùng nội bộ cùng công ty hoặc đơn vị bên ngoài, không đồng thời cả hai
+func (service *WorkOrderService) checkTechnician(companyId int64, technicianUserId *int64, vendorName *string) error {
+	if technicianUserId != nil && vendorName != nil && *vendorName != "" {
+		return errors.New("assign either an internal technician or a vendor, not both")
+	}
+	if technicianUserId != nil {
+		technician, err := service.userRepo.FindByUserId(*technicianUserId)
+		if err != nil {
+			return err
+		}
+		if technician.CompanyId != companyId {
+			return errors.New("technician not found")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,613 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/dto"
+	"BE_Manage_device/internal/domain/entity"
+	user "BE_Manage_device/internal/repository/user"
+	workflow "BE_Manage_device/internal/repository/workflows"
+	notificationS "BE_Manage_device/internal/service/notification"
+	"BE_Manage_device/pkg/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WorkflowHandler thực hiện nghiệp vụ khi quy trình kết thúc, trong cùng tx với bước duyệt cuối cùng.
+// Trả lỗi thì bước duyệt không được ghi nhận, hàm trả về (có thể nil) được gọi sau khi tx commit để gửi thông báo
+type WorkflowHandler interface {
+	OnWorkflowApproved(instance *entity.WorkflowInstance, approverId int64, tx *gorm.DB) (func(), error)
+	OnWorkflowRejected(instance *entity.WorkflowInstance, approverId int64, tx *gorm.DB) (func(), error)
+}
+
+// ErrOwnRequest người tạo yêu cầu không được tự duyệt hoặc từ chối yêu cầu của mình
+var ErrOwnRequest = errors.New("you can't approve or reject your own request")
+
+type WorkflowService struct {
+	repo                workflow.WorkflowRepository
+	userRepo            user.UserRepository
+	NotificationService *notificationS.NotificationService
+	handlers            map[string]WorkflowHandler
+}
+
+func NewWorkflowService(repo workflow.WorkflowRepository, userRepo user.UserRepository, NotificationService *notificationS.NotificationService) *WorkflowService {
+	return &WorkflowService{repo: repo, userRepo: userRepo, NotificationService: NotificationService, handlers: map[string]WorkflowHandler{}}
+}
+
+func (service *WorkflowService) RegisterHandler(entityType string, handler WorkflowHandler) {
+	service.handlers[entityType] = handler
+}
+
+func (service *WorkflowService) CreateDefinition(userId int64, request dto.WorkflowDefinitionRequest) (*entity.WorkflowDefinition, error) {
+	var err error
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := service.toSteps(user.CompanyId, request.Steps)
+	if err != nil {
+		return nil, err
+	}
+	definition := &entity.WorkflowDefinition{
+		Name:        request.Name,
+		EntityType:  request.EntityType,
+		IsActive:    request.IsActive == nil || *request.IsActive,
+		CreatedById: userId,
+		CreatedAt:   time.Now(),
+		CompanyId:   user.CompanyId,
+		Steps:       steps,
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if _, err = service.repo.CreateDefinition(definition, tx); err != nil {
+		return nil, err
+	}
+	if definition.IsActive {
+		if err = service.repo.DeactivateOthers(definition.CompanyId, definition.EntityType, definition.Id, tx); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return service.repo.GetDefinitionById(definition.Id)
+}
+
+func (service *WorkflowService) GetDefinitions(userId int64, entityType *string) ([]*entity.WorkflowDefinition, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	return service.repo.GetDefinitions(user.CompanyId, entityType)
+}
+
+func (service *WorkflowService) GetDefinitionById(userId int64, id int64) (*entity.WorkflowDefinition, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	definition, err := service.repo.GetDefinitionById(id)
+	if err != nil {
+		return nil, err
+	}
+	if definition.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	return definition, nil
+}
+
+// UpdateDefinition thay toàn bộ các bước, yêu cầu đang chờ duyệt vẫn chạy theo các bước cũ
+func (service *WorkflowService) UpdateDefinition(userId int64, id int64, request dto.WorkflowDefinitionRequest) (*entity.WorkflowDefinition, error) {
+	definition, err := service.GetDefinitionById(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := service.toSteps(definition.CompanyId, request.Steps)
+	if err != nil {
+		return nil, err
+	}
+	definition.Name = request.Name
+	definition.EntityType = request.EntityType
+	if request.IsActive != nil {
+		definition.IsActive = *request.IsActive
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if err = service.repo.UpdateDefinition(definition, tx); err != nil {
+		return nil, err
+	}
+	if err = service.repo.ReplaceSteps(definition.Id, steps, tx); err != nil {
+		return nil, err
+	}
+	if definition.IsActive {
+		if err = service.repo.DeactivateOthers(definition.CompanyId, definition.EntityType, definition.Id, tx); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	return service.repo.GetDefinitionById(definition.Id)
+}
+
+func (service *WorkflowService) DeleteDefinition(userId int64, id int64) error {
+	definition, err := service.GetDefinitionById(userId, id)
+	if err != nil {
+		return err
+	}
+	return service.repo.DeleteDefinition(definition.Id)
+}
+
+// Start tạo yêu cầu duyệt trong tx của nghiệp vụ (nil thì tự mở transaction). Trả về nil nếu công ty chưa cấu hình quy trình
+// hoặc giá trị nghiệp vụ không đạt ngưỡng của bước nào, khi đó nghiệp vụ được thực hiện ngay
+func (service *WorkflowService) Start(requestedById int64, companyId int64, entityType string, entityId int64, assetId int64, departmentId int64, cost float64, payload any, tx *gorm.DB) (*entity.WorkflowInstance, error) {
+	definition, err := service.repo.GetActiveDefinition(companyId, entityType)
+	if err != nil || definition == nil {
+		return nil, err
+	}
+	pending, err := service.repo.GetPendingInstanceOfEntity(entityType, entityId)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, fmt.Errorf("already waiting for approval (workflow instance #%d)", pending.Id)
+	}
+	steps := []entity.WorkflowInstanceStep{}
+	for _, s := range definition.Steps {
+		if s.MinCost != nil && cost < *s.MinCost {
+			continue
+		}
+		steps = append(steps, entity.WorkflowInstanceStep{
+			StepOrder:      s.StepOrder,
+			Name:           s.Name,
+			ApproverType:   s.ApproverType,
+			ApproverRole:   s.ApproverRole,
+			ApproverUserId: s.ApproverUserId,
+			Status:         entity.WorkflowPending,
+		})
+	}
+	if len(steps) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].StepOrder < steps[j].StepOrder })
+	if tx == nil {
+		tx = service.repo.GetDB()
+	}
+	payloadJson, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	instance := &entity.WorkflowInstance{
+		DefinitionId:     definition.Id,
+		EntityType:       entityType,
+		EntityId:         entityId,
+		AssetId:          assetId,
+		DepartmentId:     departmentId,
+		Cost:             cost,
+		Payload:          string(payloadJson),
+		Status:           entity.WorkflowPending,
+		CurrentStepOrder: steps[0].StepOrder,
+		RequestedById:    requestedById,
+		CreatedAt:        time.Now(),
+		CompanyId:        companyId,
+		Steps:            steps,
+	}
+	err = tx.Transaction(func(tx *gorm.DB) error {
+		if _, err := service.repo.CreateInstance(instance, tx); err != nil {
+			return err
+		}
+		return service.repo.CreateAction(&entity.WorkflowAction{InstanceId: instance.Id, Action: "submitted", ByUserId: requestedById, CreatedAt: instance.CreatedAt}, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// Submitted gọi sau khi tx tạo yêu cầu đã commit: báo cho người duyệt bước đầu tiên
+// và trả về yêu cầu đầy đủ để handler phản hồi 202
+func (service *WorkflowService) Submitted(instance *entity.WorkflowInstance) (*entity.WorkflowInstance, error) {
+	loaded, err := service.repo.GetInstanceById(instance.Id)
+	if err != nil {
+		return nil, err
+	}
+	service.notifyCurrentApprovers(loaded)
+	return loaded, nil
+}
+
+// DecodePayload đọc lại tham số nghiệp vụ đã lưu khi gửi duyệt
+func DecodePayload(instance *entity.WorkflowInstance, payload any) error {
+	return json.Unmarshal([]byte(instance.Payload), payload)
+}
+
+// Approve duyệt một bước đang chờ của người dùng. Khi tất cả các bước song song đã duyệt thì chuyển sang nhóm tiếp theo,
+// hết bước thì thực hiện nghiệp vụ
+func (service *WorkflowService) Approve(userId int64, id int64, comment string) (*entity.WorkflowInstance, error) {
+	var err error
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	// Khoá yêu cầu để hai người duyệt cùng lúc không cùng thấy mình là bước cuối
+	instance, err := service.getPending(user, id, tx)
+	if err != nil {
+		return nil, err
+	}
+	if instance.RequestedById == user.Id {
+		err = ErrOwnRequest
+		return nil, err
+	}
+	step := service.findApprovableStep(user, instance)
+	if step == nil {
+		err = errors.New("you are not an approver of the current step")
+		return nil, err
+	}
+	now := time.Now()
+	step.Status = entity.WorkflowApproved
+	step.ActedById = &userId
+	step.ActedAt = &now
+	step.Comment = comment
+
+	groupDone, nextOrder := nextStepOrder(instance, step)
+	final := groupDone && nextOrder == 0
+	if groupDone && !final {
+		instance.CurrentStepOrder = nextOrder
+	}
+	var afterCommit func()
+	if final {
+		instance.Status = entity.WorkflowApproved
+		instance.FinishedAt = &now
+		if handler, ok := service.handlers[instance.EntityType]; ok {
+			if afterCommit, err = handler.OnWorkflowApproved(instance, userId, tx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = service.repo.UpdateInstanceStep(step, tx); err != nil {
+		return nil, err
+	}
+	if err = service.repo.CreateAction(&entity.WorkflowAction{InstanceId: instance.Id, InstanceStepId: &step.Id, Action: "approved", ByUserId: userId, Comment: comment, CreatedAt: now}, tx); err != nil {
+		return nil, err
+	}
+	if groupDone {
+		if err = service.repo.UpdateInstance(instance, tx); err != nil {
+			return nil, err
+		}
+	}
+	if final {
+		if err = service.repo.CreateAction(&entity.WorkflowAction{InstanceId: instance.Id, Action: "completed", ByUserId: userId, CreatedAt: now}, tx); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	if afterCommit != nil {
+		afterCommit()
+	}
+	instance, err = service.repo.GetInstanceById(instance.Id)
+	if err != nil {
+		return nil, err
+	}
+	if final {
+		service.notifyRequester(userId, instance, fmt.Sprintf("Your request #%d (%v) has been approved", instance.Id, instance.Definition.Name))
+	} else if groupDone {
+		service.notifyCurrentApprovers(instance)
+	}
+	return instance, nil
+}
+
+// nextStepOrder sau khi duyệt step: groupDone khi các bước song song của nhóm hiện tại đã duyệt hết,
+// nextOrder là nhóm đang chờ kế tiếp (0 nếu không còn bước nào)
+func nextStepOrder(instance *entity.WorkflowInstance, step *entity.WorkflowInstanceStep) (bool, int) {
+	groupDone := true
+	nextOrder := 0
+	for _, s := range instance.Steps {
+		if s.Status != entity.WorkflowPending || s.Id == step.Id {
+			continue
+		}
+		if s.StepOrder == instance.CurrentStepOrder {
+			groupDone = false
+		} else if s.StepOrder > instance.CurrentStepOrder && (nextOrder == 0 || s.StepOrder < nextOrder) {
+			nextOrder = s.StepOrder
+		}
+	}
+	return groupDone, nextOrder
+}
+
+// Reject từ chối yêu cầu kèm lý do, các bước còn lại không cần duyệt nữa
+func (service *WorkflowService) Reject(userId int64, id int64, reason string) (*entity.WorkflowInstance, error) {
+	var err error
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	tx := service.repo.GetDB().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+	instance, err := service.getPending(user, id, tx)
+	if err != nil {
+		return nil, err
+	}
+	if instance.RequestedById == user.Id {
+		err = ErrOwnRequest
+		return nil, err
+	}
+	step := service.findApprovableStep(user, instance)
+	if step == nil {
+		err = errors.New("you are not an approver of the current step")
+		return nil, err
+	}
+	now := time.Now()
+	step.Status = entity.WorkflowRejected
+	step.ActedById = &userId
+	step.ActedAt = &now
+	step.Comment = reason
+	instance.Status = entity.WorkflowRejected
+	instance.RejectReason = &reason
+	instance.FinishedAt = &now
+	var afterCommit func()
+	if handler, ok := service.handlers[instance.EntityType]; ok {
+		if afterCommit, err = handler.OnWorkflowRejected(instance, userId, tx); err != nil {
+			return nil, err
+		}
+	}
+	if err = service.repo.UpdateInstanceStep(step, tx); err != nil {
+		return nil, err
+	}
+	if err = service.repo.SkipPendingSteps(instance.Id, tx); err != nil {
+		return nil, err
+	}
+	if err = service.repo.UpdateInstance(instance, tx); err != nil {
+		return nil, err
+	}
+	if err = service.repo.CreateAction(&entity.WorkflowAction{InstanceId: instance.Id, InstanceStepId: &step.Id, Action: "rejected", ByUserId: userId, Comment: reason, CreatedAt: now}, tx); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+	if afterCommit != nil {
+		afterCommit()
+	}
+	instance, err = service.repo.GetInstanceById(instance.Id)
+	if err != nil {
+		return nil, err
+	}
+	service.notifyRequester(userId, instance, fmt.Sprintf("Your request #%d (%v) has been rejected: %v", instance.Id, instance.Definition.Name, reason))
+	return instance, nil
+}
+
+// Cancel huỷ yêu cầu đang chờ duyệt của nghiệp vụ (nếu có) trong tx của nghiệp vụ
+func (service *WorkflowService) Cancel(userId int64, entityType string, entityId int64, reason string, tx *gorm.DB) error {
+	instance, err := service.repo.GetPendingInstanceOfEntity(entityType, entityId)
+	if err != nil || instance == nil {
+		return err
+	}
+	now := time.Now()
+	instance.Status = entity.WorkflowCancelled
+	instance.FinishedAt = &now
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := service.repo.SkipPendingSteps(instance.Id, tx); err != nil {
+			return err
+		}
+		if err := service.repo.UpdateInstance(instance, tx); err != nil {
+			return err
+		}
+		return service.repo.CreateAction(&entity.WorkflowAction{InstanceId: instance.Id, Action: "cancelled", ByUserId: userId, Comment: reason, CreatedAt: now}, tx)
+	})
+}
+
+// GetInstances quản lý tài sản của công ty xem được tất cả, người khác chỉ xem yêu cầu mình gửi hoặc mình là người duyệt
+func (service *WorkflowService) GetInstances(userId int64, request dto.WorkflowInstanceFilterRequest) ([]*entity.WorkflowInstance, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	status := request.Status
+	if request.AwaitingMe {
+		pending := entity.WorkflowPending
+		status = &pending
+	}
+	instances, err := service.repo.FilterInstances(user.CompanyId, status, request.EntityType)
+	if err != nil {
+		return nil, err
+	}
+	res := []*entity.WorkflowInstance{}
+	for _, instance := range instances {
+		if request.AwaitingMe {
+			if service.findApprovableStep(user, instance) != nil {
+				res = append(res, instance)
+			}
+			continue
+		}
+		if service.canView(user, instance) {
+			res = append(res, instance)
+		}
+	}
+	return res, nil
+}
+
+func (service *WorkflowService) GetInstanceById(userId int64, id int64) (*entity.WorkflowInstance, error) {
+	user, err := service.userRepo.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := service.repo.GetInstanceById(id)
+	if err != nil {
+		return nil, err
+	}
+	if instance.CompanyId != user.CompanyId || !service.canView(user, instance) {
+		return nil, errors.New("can't find record this id")
+	}
+	return instance, nil
+}
+
+// getPending khoá và đọc lại yêu cầu trong tx, trạng thái các bước lấy tại thời điểm giữ khoá
+func (service *WorkflowService) getPending(user *entity.Users, id int64, tx *gorm.DB) (*entity.WorkflowInstance, error) {
+	instance, err := service.repo.LockInstanceById(id, tx)
+	if err != nil {
+		return nil, err
+	}
+	if instance.CompanyId != user.CompanyId {
+		return nil, errors.New("can't find record this id")
+	}
+	if instance.Status != entity.WorkflowPending {
+		return nil, errors.New("request is not waiting for approval")
+	}
+	return instance, nil
+}
+
+// findApprovableStep bước đang chờ ở nhóm hiện tại mà người dùng được duyệt.
+// Một người chỉ duyệt một bước mỗi lần để các bước song song vẫn cần người khác,
+// người tạo yêu cầu không bao giờ là người duyệt
+func (service *WorkflowService) findApprovableStep(user *entity.Users, instance *entity.WorkflowInstance) *entity.WorkflowInstanceStep {
+	if instance.Status != entity.WorkflowPending || instance.RequestedById == user.Id {
+		return nil
+	}
+	for i := range instance.Steps {
+		step := &instance.Steps[i]
+		if step.Status == entity.WorkflowPending && step.StepOrder == instance.CurrentStepOrder && service.isApprover(user, step, instance) {
+			return step
+		}
+	}
+	return nil
+}
+
+func (service *WorkflowService) isApprover(user *entity.Users, step *entity.WorkflowInstanceStep, instance *entity.WorkflowInstance) bool {
+	switch step.ApproverType {
+	case entity.WorkflowApproverRole:
+		return step.ApproverRole != nil && user.Role.Slug == *step.ApproverRole
+	case entity.WorkflowApproverUser:
+		return step.ApproverUserId != nil && *step.ApproverUserId == user.Id
+	case entity.WorkflowApproverDepartmentHead:
+		head, err := service.userRepo.GetUserHeadDepartment(instance.DepartmentId)
+		return err == nil && head.Id == user.Id
+	}
+	return false
+}
+
+func (service *WorkflowService) canView(user *entity.Users, instance *entity.WorkflowInstance) bool {
+	if user.Role.Slug == "admin" || user.Role.Slug == "assetManager" || instance.RequestedById == user.Id {
+		return true
+	}
+	for i := range instance.Steps {
+		step := &instance.Steps[i]
+		if (step.ActedById != nil && *step.ActedById == user.Id) || service.isApprover(user, step, instance) {
+			return true
+		}
+	}
+	return false
+}
+
+func (service *WorkflowService) approversOf(step *entity.WorkflowInstanceStep, instance *entity.WorkflowInstance) []*entity.Users {
+	switch step.ApproverType {
+	case entity.WorkflowApproverRole:
+		if step.ApproverRole != nil {
+			users, _ := service.repo.GetUsersByRole(instance.CompanyId, *step.ApproverRole)
+			return users
+		}
+	case entity.WorkflowApproverUser:
+		if step.ApproverUserId != nil {
+			user, err := service.userRepo.FindByUserId(*step.ApproverUserId)
+			if err == nil {
+				return []*entity.Users{user}
+			}
+		}
+	case entity.WorkflowApproverDepartmentHead:
+		head, err := service.userRepo.GetUserHeadDepartment(instance.DepartmentId)
+		if err == nil {
+			return []*entity.Users{head}
+		}
+	}
+	return nil
+}
+
+func (service *WorkflowService) notifyCurrentApprovers(instance *entity.WorkflowInstance) {
+	approvers := []*entity.Users{}
+	for i := range instance.Steps {
+		step := &instance.Steps[i]
+		if step.Status == entity.WorkflowPending && step.StepOrder == instance.CurrentStepOrder {
+			approvers = append(approvers, service.approversOf(step, instance)...)
+		}
+	}
+	usersToNotifications := utils.ConvertUsersToNotificationsToMap(instance.RequestedById, approvers)
+	message := fmt.Sprintf("Request #%d (%v) of asset (ID: %v) is waiting for your approval", instance.Id, instance.Definition.Name, instance.AssetId)
+	service.send(usersToNotifications, message, instance.Asset)
+}
+
+func (service *WorkflowService) notifyRequester(userId int64, instance *entity.WorkflowInstance, message string) {
+	usersToNotifications := utils.ConvertUsersToNotificationsToMap(userId, []*entity.Users{&instance.RequestedBy})
+	service.send(usersToNotifications, message, instance.Asset)
+}
+
+func (service *WorkflowService) send(users []*entity.Users, message string, asset entity.Assets) {
+	if len(users) == 0 {
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Println("SendNotificationToUsers panic:", r)
+			}
+		}()
+		service.NotificationService.SendNotificationToUsers(users, message, asset)
+	}()
+}
+
+func (service *WorkflowService) toSteps(companyId int64, requests []dto.WorkflowStepRequest) ([]entity.WorkflowStep, error) {
+	steps := []entity.WorkflowStep{}
+	for _, r := range requests {
+		step := entity.WorkflowStep{StepOrder: r.StepOrder, Name: r.Name, ApproverType: r.ApproverType, MinCost: r.MinCost}
+		switch r.ApproverType {
+		case entity.WorkflowApproverRole:
+			if r.ApproverRole == nil || *r.ApproverRole == "" {
+				return nil, fmt.Errorf("step %q requires approverRole", r.Name)
+			}
+			step.ApproverRole = r.ApproverRole
+		case entity.WorkflowApproverUser:
+			if r.ApproverUserId == nil {
+				return nil, fmt.Errorf("step %q requires approverUserId", r.Name)
+			}
+			approver, err := service.userRepo.FindByUserId(*r.ApproverUserId)
+			if err != nil || approver.CompanyId != companyId {
+				return nil, fmt.Errorf("approver of step %q not found", r.Name)
+			}
+			step.ApproverUserId = r.ApproverUserId
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
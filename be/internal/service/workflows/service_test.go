@@ -0,0 +1,120 @@
+package service
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"testing"
+)
+
+func workflowStep(id int64, order int, status string) entity.WorkflowInstanceStep {
+	return entity.WorkflowInstanceStep{Id: id, StepOrder: order, Status: status}
+}
+
+func TestNextStepOrder(t *testing.T) {
+	pending, approved := entity.WorkflowPending, entity.WorkflowApproved
+	tests := []struct {
+		name          string
+		current       int
+		steps         []entity.WorkflowInstanceStep
+		approve       int64
+		wantGroupDone bool
+		wantNext      int
+	}{
+		{
+			name:          "single step finishes the workflow",
+			current:       1,
+			steps:         []entity.WorkflowInstanceStep{workflowStep(1, 1, pending)},
+			approve:       1,
+			wantGroupDone: true,
+		},
+		{
+			name:          "sequential steps move to the next order",
+			current:       1,
+			steps:         []entity.WorkflowInstanceStep{workflowStep(1, 1, pending), workflowStep(2, 2, pending)},
+			approve:       1,
+			wantGroupDone: true,
+			wantNext:      2,
+		},
+		{
+			name:     "parallel step still waiting",
+			current:  1,
+			steps:    []entity.WorkflowInstanceStep{workflowStep(1, 1, pending), workflowStep(2, 1, pending), workflowStep(3, 2, pending)},
+			approve:  1,
+			wantNext: 2,
+		},
+		{
+			name:          "last parallel step completes the group",
+			current:       1,
+			steps:         []entity.WorkflowInstanceStep{workflowStep(1, 1, approved), workflowStep(2, 1, pending), workflowStep(3, 3, pending)},
+			approve:       2,
+			wantGroupDone: true,
+			wantNext:      3,
+		},
+		{
+			name:          "picks the lowest following order",
+			current:       1,
+			steps:         []entity.WorkflowInstanceStep{workflowStep(1, 1, pending), workflowStep(2, 5, pending), workflowStep(3, 3, pending)},
+			approve:       1,
+			wantGroupDone: true,
+			wantNext:      3,
+		},
+		{
+			name:          "last group finishes the workflow",
+			current:       2,
+			steps:         []entity.WorkflowInstanceStep{workflowStep(1, 1, approved), workflowStep(2, 2, pending)},
+			approve:       2,
+			wantGroupDone: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := &entity.WorkflowInstance{CurrentStepOrder: tt.current, Steps: tt.steps}
+			var step *entity.WorkflowInstanceStep
+			for i := range instance.Steps {
+				if instance.Steps[i].Id == tt.approve {
+					step = &instance.Steps[i]
+				}
+			}
+			groupDone, next := nextStepOrder(instance, step)
+			if groupDone != tt.wantGroupDone || next != tt.wantNext {
+				t.Errorf("nextStepOrder() = (%v, %v), want (%v, %v)", groupDone, next, tt.wantGroupDone, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestFindApprovableStep(t *testing.T) {
+	managerRole, otherRole := "assetManager", "admin"
+	roleStep := func(id int64, order int, role *string) entity.WorkflowInstanceStep {
+		step := workflowStep(id, order, entity.WorkflowPending)
+		step.ApproverType = entity.WorkflowApproverRole
+		step.ApproverRole = role
+		return step
+	}
+	manager := &entity.Users{Id: 1, Role: entity.Roles{Slug: managerRole}}
+	tests := []struct {
+		name        string
+		status      string
+		requestedBy int64
+		steps       []entity.WorkflowInstanceStep
+		want        int64 // Id của bước được duyệt, 0 nếu không có
+	}{
+		{name: "approver of the current step", status: entity.WorkflowPending, requestedBy: 2, steps: []entity.WorkflowInstanceStep{roleStep(1, 1, &managerRole)}, want: 1},
+		{name: "requester can't approve own request", status: entity.WorkflowPending, requestedBy: 1, steps: []entity.WorkflowInstanceStep{roleStep(1, 1, &managerRole)}},
+		{name: "step of another role", status: entity.WorkflowPending, requestedBy: 2, steps: []entity.WorkflowInstanceStep{roleStep(1, 1, &otherRole)}},
+		{name: "step of a later order", status: entity.WorkflowPending, requestedBy: 2, steps: []entity.WorkflowInstanceStep{roleStep(1, 1, &otherRole), roleStep(2, 2, &managerRole)}},
+		{name: "instance already approved", status: entity.WorkflowApproved, requestedBy: 2, steps: []entity.WorkflowInstanceStep{roleStep(1, 1, &managerRole)}},
+	}
+	service := &WorkflowService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := &entity.WorkflowInstance{Status: tt.status, RequestedById: tt.requestedBy, CurrentStepOrder: 1, Steps: tt.steps}
+			var got int64
+			if step := service.findApprovableStep(manager, instance); step != nil {
+				got = step.Id
+			}
+			if got != tt.want {
+				t.Errorf("findApprovableStep() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
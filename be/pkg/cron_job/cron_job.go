@@ -7,8 +7,13 @@ import (
 	company "BE_Manage_device/internal/repository/company"
 	monthlySummary "BE_Manage_device/internal/repository/monthly_summary"
 	user "BE_Manage_device/internal/repository/user"
+	chargebackS "BE_Manage_device/internal/service/chargeback"
 	emailS "BE_Manage_device/internal/service/email"
+	handoverS "BE_Manage_device/internal/service/handovers"
+	ldapS "BE_Manage_device/internal/service/ldap"
+	maintenancePlanS "BE_Manage_device/internal/service/maintenance_plans"
 	notificationS "BE_Manage_device/internal/service/notification"
+	requestTransferS "BE_Manage_device/internal/service/request_transfer"
 	"BE_Manage_device/pkg/utils"
 	"fmt"
 	"log"
@@ -18,7 +23,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func InitCronJobs(db *gorm.DB, emailService *emailS.EmailService, assetsRepository asset.AssetsRepository, userRepository user.UserRepository, notificationsService *notificationS.NotificationService, assetsLogRepository asset_log.AssetsLogRepository, billRepository bill.BillsRepository, monthlySummaryRepository monthlySummary.MonthlySummaryRepository, companyRepository company.CompanyRepository) {
+func InitCronJobs(db *gorm.DB, emailService *emailS.EmailService, assetsRepository asset.AssetsRepository, userRepository user.UserRepository, notificationsService *notificationS.NotificationService, assetsLogRepository asset_log.AssetsLogRepository, billRepository bill.BillsRepository, monthlySummaryRepository monthlySummary.MonthlySummaryRepository, companyRepository company.CompanyRepository, chargebackService *chargebackS.ChargebackService, maintenancePlanService *maintenancePlanS.MaintenancePlanService, requestTransferService *requestTransferS.RequestTransferService, handoverService *handoverS.HandoverService, ldapService *ldapS.LdapService) {
 	c := cron.New(cron.WithLocation(time.FixedZone("Asia/Ho_Chi_Minh", 7*3600)))
 
 	_, err := c.AddFunc("0 8 * * *", func() {
@@ -68,5 +73,49 @@ func InitCronJobs(db *gorm.DB, emailService *emailS.EmailService, assetsReposito
 		log.Fatalf("❌ Failed to schedule create monthly summary cron job: %v", err)
 	}
 
+	_, err = c.AddFunc("30 0 1 * *", func() {
+		// Phân bổ chi phí cho tháng trước
+		now := time.Now().In(c.Location())
+		lastMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1)
+		log.Printf("🔔 Running department chargeback for %02d/%d", lastMonth.Month(), lastMonth.Year())
+		chargebackService.ComputeAllCompanies(int64(lastMonth.Month()), int64(lastMonth.Year()))
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to schedule department chargeback cron job: %v", err)
+	}
+
+	_, err = c.AddFunc("0 7 * * *", func() {
+		log.Println("🔔 Running maintenance plan materialization at 7:00 AM")
+		maintenancePlanService.MaterializeAll()
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to schedule maintenance plan cron job: %v", err)
+	}
+
+	_, err = c.AddFunc("0 * * * *", func() {
+		log.Println("🔔 Running transfer request SLA reminder")
+		requestTransferService.SendSlaReminders()
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to schedule transfer request reminder cron job: %v", err)
+	}
+
+	_, err = c.AddFunc("0 * * * *", func() {
+		log.Println("🔔 Running handover acknowledgement reminder")
+		handoverService.SendReminders()
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to schedule handover reminder cron job: %v", err)
+	}
+
+	_, err = c.AddFunc("15 * * * *", func() {
+		// Mỗi công ty tự cấu hình chu kỳ, job chỉ đồng bộ công ty đã đến hạn
+		log.Println("🔔 Running directory sync")
+		ldapService.SyncAll()
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to schedule directory sync cron job: %v", err)
+	}
+
 	c.Start()
 }
@@ -30,3 +30,8 @@ func CurrentAssetValue(
 	currentValue := math.Max(originalCost-accumulatedDepreciation, salvageValue)
 	return currentValue
 }
+
+// RoundAmount làm tròn số tiền đến 2 chữ số thập phân
+func RoundAmount(v float64) float64 {
+	return math.Round(v*100) / 100
+}
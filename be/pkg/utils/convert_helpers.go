@@ -3,6 +3,8 @@ package utils
 import (
 	"BE_Manage_device/internal/domain/dto"
 	"BE_Manage_device/internal/domain/entity"
+	"strings"
+	"time"
 )
 
 func ConvertUserToUserResponse(user *entity.Users) dto.UserResponse {
@@ -37,10 +39,15 @@ func ConvertUsersToUserResponses(users []*entity.Users) []dto.UserResponse {
 }
 
 func ConvertRequestTransferToResponse(rt *entity.RequestTransfer) dto.RequestTransferResponse {
-	return dto.RequestTransferResponse{
-		Id:          rt.Id,
-		Status:      rt.Status,
-		Description: rt.Description,
+	res := dto.RequestTransferResponse{
+		Id:                rt.Id,
+		Status:            rt.Status,
+		Description:       rt.Description,
+		Quantity:          rt.Quantity,
+		FulfilledQuantity: rt.FulfilledQuantity,
+		NeededBy:          rt.NeededBy,
+		CreatedAt:         rt.CreatedAt,
+		UpdatedAt:         rt.UpdatedAt,
 		User: dto.UserResponseInRequestTransfer{
 			Id:           rt.User.Id,
 			FirstName:    rt.User.FirstName,
@@ -53,6 +60,65 @@ func ConvertRequestTransferToResponse(rt *entity.RequestTransfer) dto.RequestTra
 			CategoryName: rt.Category.CategoryName,
 		},
 	}
+	if rt.Asset != nil {
+		asset := convertAssetInRequestTransfer(rt.Asset)
+		res.Asset = &asset
+	}
+	return res
+}
+
+func convertAssetInRequestTransfer(asset *entity.Assets) dto.AssetResponseInMaintenanceSchedules {
+	return dto.AssetResponseInMaintenanceSchedules{
+		Id:             asset.Id,
+		AssetName:      asset.AssetName,
+		Status:         asset.Status,
+		FileAttachment: derefString(asset.FileAttachment),
+		ImageUpload:    derefString(asset.ImageUpload),
+	}
+}
+
+func ConvertRequestTransferToDetailResponse(rt *entity.RequestTransfer, histories []*entity.RequestTransferHistory, assets []*entity.RequestTransferAsset) dto.RequestTransferDetailResponse {
+	res := dto.RequestTransferDetailResponse{
+		RequestTransferResponse: ConvertRequestTransferToResponse(rt),
+		Histories:               []dto.RequestTransferHistoryResponse{},
+		TransferredAssets:       []dto.RequestTransferAssetResponse{},
+	}
+	for _, h := range histories {
+		historyRes := dto.RequestTransferHistoryResponse{
+			Id:         h.Id,
+			FromStatus: h.FromStatus,
+			ToStatus:   h.ToStatus,
+			Note:       h.Note,
+			CreatedAt:  h.CreatedAt,
+		}
+		if h.ByUser != nil {
+			byUser := convertUserInAssetLog(h.ByUser)
+			historyRes.ByUser = &byUser
+		}
+		res.Histories = append(res.Histories, historyRes)
+	}
+	for _, a := range assets {
+		res.TransferredAssets = append(res.TransferredAssets, dto.RequestTransferAssetResponse{
+			Id:            a.Id,
+			Asset:         convertAssetInRequestTransfer(&a.Asset),
+			ByUserId:      a.ByUserId,
+			TransferredAt: a.TransferredAt,
+		})
+	}
+	return res
+}
+
+func ConvertRequestTransferCommentsToResponses(comments []*entity.RequestTransferComment) []dto.RequestTransferCommentResponse {
+	res := make([]dto.RequestTransferCommentResponse, 0, len(comments))
+	for _, c := range comments {
+		res = append(res, dto.RequestTransferCommentResponse{
+			Id:        c.Id,
+			User:      convertUserInAssetLog(&c.User),
+			Content:   c.Content,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+	return res
 }
 
 func ConvertRequestTransfersToResponses(rts []entity.RequestTransfer) []dto.RequestTransferResponse {
@@ -65,7 +131,8 @@ func ConvertRequestTransfersToResponses(rts []entity.RequestTransfer) []dto.Requ
 
 func ConvertAssignmentToResponse(assignment *entity.Assignments) dto.AssignmentResponse {
 	return dto.AssignmentResponse{
-		Id: assignment.Id,
+		Id:                 assignment.Id,
+		HandoverReceiptUrl: assignment.HandoverReceiptUrl,
 		UserAssigned: dto.UsersAssignmentResponse{
 			Id:        assignment.UserAssigned.Id,
 			FirstName: assignment.UserAssigned.FirstName,
@@ -103,6 +170,23 @@ func derefString(s *string) string {
 	}
 	return ""
 }
+
+// ValidUserName tên phải dài 2-256 ký tự theo ràng buộc của bảng users:
+// quá ngắn thì dùng fallback, quá dài thì cắt bớt
+func ValidUserName(value, fallback string) string {
+	value = strings.TrimSpace(value)
+	if len([]rune(value)) < 2 {
+		value = strings.TrimSpace(fallback)
+	}
+	if len([]rune(value)) > 256 {
+		value = string([]rune(value)[:256])
+	}
+	for len([]rune(value)) < 2 {
+		value += "_"
+	}
+	return value
+}
+
 func derefInt64(i *int64) int64 {
 	if i != nil {
 		return *i
@@ -118,11 +202,52 @@ func ConvertAssignmentsToResponses(assignments []entity.Assignments) []dto.Assig
 	return res
 }
 
+func ConvertAssignmentHistoriesToResponses(histories []*entity.AssignmentHistory) []dto.AssignmentHistoryResponse {
+	res := make([]dto.AssignmentHistoryResponse, 0, len(histories))
+	for _, h := range histories {
+		historyRes := dto.AssignmentHistoryResponse{
+			Id:           h.Id,
+			AssignmentId: h.AssignmentId,
+			Asset: dto.UserAssignmentAssetResponse{
+				Id:             h.Asset.Id,
+				AssetName:      h.Asset.AssetName,
+				Status:         h.Asset.Status,
+				FileAttachment: derefString(h.Asset.FileAttachment),
+				ImageUpload:    derefString(h.Asset.ImageUpload),
+			},
+			UserAssign: dto.UsersAssignmentResponse{
+				Id:        h.UserAssign.Id,
+				FirstName: h.UserAssign.FirstName,
+				LastName:  h.UserAssign.LastName,
+				Email:     h.UserAssign.Email,
+			},
+			DepartmentId: h.DepartmentId,
+			Reason:       h.Reason,
+			StartedAt:    h.StartedAt,
+			EndedAt:      h.EndedAt,
+		}
+		if h.UserAssigned != nil {
+			historyRes.UserAssigned = &dto.UsersAssignmentResponse{
+				Id:        h.UserAssigned.Id,
+				FirstName: h.UserAssigned.FirstName,
+				LastName:  h.UserAssigned.LastName,
+				Email:     h.UserAssigned.Email,
+			}
+		}
+		if h.Department != nil {
+			historyRes.Department = h.Department.DepartmentName
+		}
+		res = append(res, historyRes)
+	}
+	return res
+}
+
 func ConvertMaintenanceSchedulesToResponses(maintenanceSchedules *entity.MaintenanceSchedules) dto.MaintenanceSchedulesResponse {
 	return dto.MaintenanceSchedulesResponse{
 		Id:        maintenanceSchedules.Id,
 		StartDate: maintenanceSchedules.StartDate.Format("2006-01-02"),
 		EndDate:   maintenanceSchedules.EndDate.Format("2006-01-02"),
+		Cost:      maintenanceSchedules.Cost,
 		Asset: dto.AssetResponseInMaintenanceSchedules{
 			Id:             maintenanceSchedules.AssetId,
 			AssetName:      maintenanceSchedules.Asset.AssetName,
@@ -204,6 +329,8 @@ func ConvertAssetToResponse(asset entity.Assets) dto.AssetResponse {
 				LocationName: asset.Department.Location.LocationName,
 			},
 		},
+		Room: ConvertLocationToResponse(asset.Room),
+		Tags: ConvertTagsToResponses(asset.Tags),
 	}
 	if asset.OnwerUser != nil {
 		assetResponse.Owner = dto.OwnerResponse{
@@ -234,3 +361,548 @@ func ConvertBillsToResponsesArray(bills []*entity.Bill) []dto.BillResponse {
 	}
 	return res
 }
+
+func ConvertAssetTemplateToResponse(template *entity.AssetTemplate) dto.AssetTemplateResponse {
+	return dto.AssetTemplateResponse{
+		Id:                 template.Id,
+		TemplateName:       template.TemplateName,
+		AssetName:          template.AssetName,
+		Cost:               template.Cost,
+		WarrantyMonths:     template.WarrantyMonths,
+		AnnualDepreciation: template.AnnualDepreciation,
+		ResidualValue:      template.ResidualValue,
+		UsefulLife:         template.UsefulLife,
+		Category: dto.CategoryResponse{
+			ID:           template.Category.Id,
+			CategoryName: template.Category.CategoryName,
+		},
+	}
+}
+
+func ConvertAssetTemplatesToResponses(templates []*entity.AssetTemplate) []dto.AssetTemplateResponse {
+	res := make([]dto.AssetTemplateResponse, 0, len(templates))
+	for _, t := range templates {
+		res = append(res, ConvertAssetTemplateToResponse(t))
+	}
+	return res
+}
+
+func ConvertLocationToResponse(location *entity.Locations) *dto.LocationResponse {
+	if location == nil {
+		return nil
+	}
+	return &dto.LocationResponse{
+		ID:           location.Id,
+		LocationName: location.LocationName,
+		LocationType: location.LocationType,
+	}
+}
+
+func ConvertTagsToResponses(tags []entity.Tags) []dto.TagResponse {
+	res := make([]dto.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, dto.TagResponse{Id: tag.Id, TagName: tag.TagName})
+	}
+	return res
+}
+
+func ConvertAssetRelationToResponse(relation *entity.AssetRelation) dto.AssetRelationResponse {
+	return dto.AssetRelationResponse{
+		Id:           relation.Id,
+		FromAssetId:  relation.FromAssetId,
+		ToAssetId:    relation.ToAssetId,
+		RelationType: relation.RelationType,
+	}
+}
+
+func ConvertDepartmentChargebackToResponse(chargeback *entity.DepartmentChargeback) *dto.DepartmentChargebackResponse {
+	return &dto.DepartmentChargebackResponse{
+		DepartmentId:       chargeback.DepartmentId,
+		DepartmentName:     chargeback.Department.DepartmentName,
+		AssetCount:         chargeback.AssetCount,
+		DepreciationAmount: chargeback.DepreciationAmount,
+		MaintenanceAmount:  chargeback.MaintenanceAmount,
+		TotalAmount:        chargeback.TotalAmount,
+	}
+}
+
+func ConvertChargebackLineToResponse(line *entity.ChargebackLine) *dto.ChargebackLineResponse {
+	return &dto.ChargebackLineResponse{
+		DepartmentId:       line.DepartmentId,
+		DepartmentName:     line.Department.DepartmentName,
+		AssetId:            line.AssetId,
+		AssetName:          line.Asset.AssetName,
+		SerialNumber:       line.Asset.SerialNumber,
+		DaysHeld:           line.DaysHeld,
+		DepreciationAmount: line.DepreciationAmount,
+		MaintenanceAmount:  line.MaintenanceAmount,
+		TotalAmount:        RoundAmount(line.DepreciationAmount + line.MaintenanceAmount),
+	}
+}
+
+func ConvertMaintenancePlanToResponse(plan *entity.MaintenancePlan) *dto.MaintenancePlanResponse {
+	res := &dto.MaintenancePlanResponse{
+		Id:              plan.Id,
+		PlanName:        plan.PlanName,
+		AssetId:         plan.AssetId,
+		CategoryId:      plan.CategoryId,
+		RRule:           plan.RRule,
+		StartDate:       plan.StartDate.Format("2006-01-02"),
+		DurationDays:    plan.DurationDays,
+		Cost:            plan.Cost,
+		IsActive:        plan.IsActive,
+		NextOccurrences: []string{},
+	}
+	if plan.Asset != nil {
+		res.AssetName = plan.Asset.AssetName
+	}
+	if plan.Category != nil {
+		res.CategoryName = plan.Category.CategoryName
+	}
+	// 5 lần bảo trì sắp tới
+	if rule, err := ParseRRule(plan.RRule); err == nil && plan.IsActive {
+		now := time.Now()
+		for _, o := range rule.Occurrences(plan.StartDate, now, now.AddDate(5, 0, 0)) {
+			if len(res.NextOccurrences) == 5 {
+				break
+			}
+			res.NextOccurrences = append(res.NextOccurrences, o.Format("2006-01-02"))
+		}
+	}
+	return res
+}
+
+func ConvertMaintenancePlansToResponses(plans []*entity.MaintenancePlan) []*dto.MaintenancePlanResponse {
+	res := make([]*dto.MaintenancePlanResponse, 0, len(plans))
+	for _, p := range plans {
+		res = append(res, ConvertMaintenancePlanToResponse(p))
+	}
+	return res
+}
+
+func ConvertConsumableToResponse(consumable *entity.Consumable) dto.ConsumableResponse {
+	return dto.ConsumableResponse{
+		Id:       consumable.Id,
+		Name:     consumable.Name,
+		Unit:     consumable.Unit,
+		Quantity: consumable.Quantity,
+		UnitCost: consumable.UnitCost,
+	}
+}
+
+func ConvertWorkOrderToResponse(workOrder *entity.WorkOrder) dto.WorkOrderResponse {
+	res := dto.WorkOrderResponse{
+		Id:                workOrder.Id,
+		ScheduleId:        workOrder.ScheduleId,
+		ScheduleStartDate: workOrder.Schedule.StartDate.Format("2006-01-02"),
+		ScheduleEndDate:   workOrder.Schedule.EndDate.Format("2006-01-02"),
+		Asset: dto.AssetResponseInMaintenanceSchedules{
+			Id:             workOrder.AssetId,
+			AssetName:      workOrder.Asset.AssetName,
+			Status:         workOrder.Asset.Status,
+			FileAttachment: derefString(workOrder.Asset.FileAttachment),
+			ImageUpload:    derefString(workOrder.Asset.ImageUpload),
+		},
+		Title:           workOrder.Title,
+		Description:     workOrder.Description,
+		Status:          workOrder.Status,
+		VendorName:      workOrder.VendorName,
+		LaborHours:      workOrder.LaborHours,
+		LaborRate:       workOrder.LaborRate,
+		PartsCost:       workOrder.PartsCost,
+		TotalCost:       workOrder.TotalCost,
+		CompletionNotes: workOrder.CompletionNotes,
+		CompletedAt:     workOrder.CompletedAt,
+		Tasks:           []dto.WorkOrderTaskResponse{},
+		Parts:           []dto.WorkOrderPartResponse{},
+		Photos:          []string{},
+	}
+	if workOrder.Technician != nil {
+		res.Technician = &dto.UserResponseInAssetLog{
+			Id:        workOrder.Technician.Id,
+			FirstName: workOrder.Technician.FirstName,
+			LastName:  workOrder.Technician.LastName,
+			Email:     workOrder.Technician.Email,
+		}
+	}
+	for _, t := range workOrder.Tasks {
+		res.Tasks = append(res.Tasks, dto.WorkOrderTaskResponse{
+			Id:          t.Id,
+			Description: t.Description,
+			IsDone:      t.IsDone,
+			DoneById:    t.DoneById,
+			DoneAt:      t.DoneAt,
+		})
+	}
+	for _, p := range workOrder.Parts {
+		res.Parts = append(res.Parts, dto.WorkOrderPartResponse{
+			Id:             p.Id,
+			ConsumableId:   p.ConsumableId,
+			ConsumableName: p.Consumable.Name,
+			Unit:           p.Consumable.Unit,
+			Quantity:       p.Quantity,
+			UnitCost:       p.UnitCost,
+		})
+	}
+	for _, p := range workOrder.Photos {
+		res.Photos = append(res.Photos, p.Url)
+	}
+	return res
+}
+
+func ConvertCalendarFeedToResponse(feed *entity.CalendarFeed) dto.CalendarFeedResponse {
+	res := dto.CalendarFeedResponse{
+		Id:             feed.Id,
+		Name:           feed.Name,
+		UserId:         feed.UserId,
+		DepartmentId:   feed.DepartmentId,
+		CreatedAt:      feed.CreatedAt,
+		LastAccessedAt: feed.LastAccessedAt,
+		RevokedAt:      feed.RevokedAt,
+	}
+	if feed.Department != nil {
+		res.DepartmentName = feed.Department.DepartmentName
+	}
+	return res
+}
+
+func ConvertIssueTicketToResponse(ticket *entity.IssueTicket) dto.IssueTicketResponse {
+	res := dto.IssueTicketResponse{
+		Id: ticket.Id,
+		Asset: dto.AssetResponseInMaintenanceSchedules{
+			Id:             ticket.AssetId,
+			AssetName:      ticket.Asset.AssetName,
+			Status:         ticket.Asset.Status,
+			FileAttachment: derefString(ticket.Asset.FileAttachment),
+			ImageUpload:    derefString(ticket.Asset.ImageUpload),
+		},
+		ReportedBy:  convertUserInAssetLog(&ticket.ReportedBy),
+		Source:      ticket.Source,
+		Title:       ticket.Title,
+		Description: ticket.Description,
+		Severity:    ticket.Severity,
+		Status:      ticket.Status,
+		ScheduleId:  ticket.ScheduleId,
+		CreatedAt:   ticket.CreatedAt,
+		UpdatedAt:   ticket.UpdatedAt,
+		ClosedAt:    ticket.ClosedAt,
+		Photos:      []string{},
+		Comments:    []dto.IssueTicketCommentResponse{},
+	}
+	if ticket.AssignedTo != nil {
+		assignedTo := convertUserInAssetLog(ticket.AssignedTo)
+		res.AssignedTo = &assignedTo
+	}
+	for _, p := range ticket.Photos {
+		res.Photos = append(res.Photos, p.Url)
+	}
+	for _, c := range ticket.Comments {
+		res.Comments = append(res.Comments, dto.IssueTicketCommentResponse{
+			Id:        c.Id,
+			User:      convertUserInAssetLog(&c.User),
+			Content:   c.Content,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+	return res
+}
+
+func convertUserInAssetLog(user *entity.Users) dto.UserResponseInAssetLog {
+	return dto.UserResponseInAssetLog{
+		Id:        user.Id,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Email:     user.Email,
+	}
+}
+
+func ConvertInspectionTemplateToResponse(template *entity.InspectionTemplate) dto.InspectionTemplateResponse {
+	res := dto.InspectionTemplateResponse{
+		Id:           template.Id,
+		Name:         template.Name,
+		CategoryId:   template.CategoryId,
+		CategoryName: template.Category.CategoryName,
+		IntervalDays: template.IntervalDays,
+		IsActive:     template.IsActive,
+		Items:        []dto.InspectionTemplateItemResponse{},
+	}
+	for _, item := range template.Items {
+		res.Items = append(res.Items, dto.InspectionTemplateItemResponse{
+			Id:          item.Id,
+			Label:       item.Label,
+			Description: item.Description,
+			SortOrder:   item.SortOrder,
+		})
+	}
+	return res
+}
+
+func ConvertInspectionToResponse(inspection *entity.Inspection) dto.InspectionResponse {
+	res := dto.InspectionResponse{
+		Id:                 inspection.Id,
+		AssetId:            inspection.AssetId,
+		AssetName:          inspection.Asset.AssetName,
+		TemplateId:         inspection.TemplateId,
+		TemplateName:       inspection.Template.Name,
+		Inspector:          convertUserInAssetLog(&inspection.Inspector),
+		InspectedAt:        inspection.InspectedAt,
+		Passed:             inspection.Passed,
+		ConditionGrade:     inspection.ConditionGrade,
+		Notes:              inspection.Notes,
+		InspectorSignature: inspection.InspectorSignature,
+		WitnessName:        inspection.WitnessName,
+		WitnessSignature:   inspection.WitnessSignature,
+		ScheduleId:         inspection.ScheduleId,
+		Items:              []dto.InspectionItemResultResponse{},
+	}
+	for _, item := range inspection.Items {
+		res.Items = append(res.Items, dto.InspectionItemResultResponse{
+			Id:             item.Id,
+			TemplateItemId: item.TemplateItemId,
+			Label:          item.Label,
+			Passed:         item.Passed,
+			Note:           item.Note,
+		})
+	}
+	return res
+}
+
+func ConvertMeterThresholdToResponse(threshold *entity.MeterThreshold) dto.MeterThresholdResponse {
+	return dto.MeterThresholdResponse{
+		Id:           threshold.Id,
+		MeterId:      threshold.MeterId,
+		Name:         threshold.Name,
+		Interval:     threshold.Interval,
+		DurationDays: threshold.DurationDays,
+		Cost:         threshold.Cost,
+		IsActive:     threshold.IsActive,
+	}
+}
+
+func ConvertMeterDefinitionToResponse(meter *entity.MeterDefinition) dto.MeterDefinitionResponse {
+	res := dto.MeterDefinitionResponse{
+		Id:           meter.Id,
+		Name:         meter.Name,
+		Unit:         meter.Unit,
+		CategoryId:   meter.CategoryId,
+		CategoryName: meter.Category.CategoryName,
+		IsActive:     meter.IsActive,
+		Thresholds:   []dto.MeterThresholdResponse{},
+	}
+	for i := range meter.Thresholds {
+		res.Thresholds = append(res.Thresholds, ConvertMeterThresholdToResponse(&meter.Thresholds[i]))
+	}
+	return res
+}
+
+func ConvertMeterReadingToResponse(reading *entity.MeterReading) dto.MeterReadingResponse {
+	return dto.MeterReadingResponse{
+		Id:         reading.Id,
+		AssetId:    reading.AssetId,
+		MeterId:    reading.MeterId,
+		MeterName:  reading.Meter.Name,
+		Unit:       reading.Meter.Unit,
+		Value:      reading.Value,
+		ReadAt:     reading.ReadAt,
+		RecordedBy: convertUserInAssetLog(&reading.RecordedBy),
+		Note:       reading.Note,
+		ScheduleId: reading.ScheduleId,
+	}
+}
+
+func ConvertWorkflowDefinitionToResponse(definition *entity.WorkflowDefinition) dto.WorkflowDefinitionResponse {
+	res := dto.WorkflowDefinitionResponse{
+		Id:         definition.Id,
+		Name:       definition.Name,
+		EntityType: definition.EntityType,
+		IsActive:   definition.IsActive,
+		CreatedAt:  definition.CreatedAt,
+		Steps:      []dto.WorkflowStepResponse{},
+	}
+	for _, step := range definition.Steps {
+		res.Steps = append(res.Steps, dto.WorkflowStepResponse{
+			Id:             step.Id,
+			StepOrder:      step.StepOrder,
+			Name:           step.Name,
+			ApproverType:   step.ApproverType,
+			ApproverRole:   step.ApproverRole,
+			ApproverUserId: step.ApproverUserId,
+			MinCost:        step.MinCost,
+		})
+	}
+	return res
+}
+
+func ConvertWorkflowInstanceToResponse(instance *entity.WorkflowInstance) dto.WorkflowInstanceResponse {
+	res := dto.WorkflowInstanceResponse{
+		Id:               instance.Id,
+		DefinitionId:     instance.DefinitionId,
+		DefinitionName:   instance.Definition.Name,
+		EntityType:       instance.EntityType,
+		EntityId:         instance.EntityId,
+		AssetId:          instance.AssetId,
+		AssetName:        instance.Asset.AssetName,
+		DepartmentId:     instance.DepartmentId,
+		Cost:             instance.Cost,
+		Status:           instance.Status,
+		CurrentStepOrder: instance.CurrentStepOrder,
+		RequestedBy:      convertUserInAssetLog(&instance.RequestedBy),
+		RejectReason:     instance.RejectReason,
+		CreatedAt:        instance.CreatedAt,
+		FinishedAt:       instance.FinishedAt,
+		Steps:            []dto.WorkflowInstanceStepResponse{},
+		Actions:          []dto.WorkflowActionResponse{},
+	}
+	for _, step := range instance.Steps {
+		stepRes := dto.WorkflowInstanceStepResponse{
+			Id:             step.Id,
+			StepOrder:      step.StepOrder,
+			Name:           step.Name,
+			ApproverType:   step.ApproverType,
+			ApproverRole:   step.ApproverRole,
+			ApproverUserId: step.ApproverUserId,
+			Status:         step.Status,
+			ActedAt:        step.ActedAt,
+			Comment:        step.Comment,
+		}
+		if step.ActedBy != nil {
+			actedBy := convertUserInAssetLog(step.ActedBy)
+			stepRes.ActedBy = &actedBy
+		}
+		res.Steps = append(res.Steps, stepRes)
+	}
+	for _, action := range instance.Actions {
+		res.Actions = append(res.Actions, dto.WorkflowActionResponse{
+			Id:             action.Id,
+			InstanceStepId: action.InstanceStepId,
+			Action:         action.Action,
+			ByUser:         convertUserInAssetLog(&action.ByUser),
+			Comment:        action.Comment,
+			CreatedAt:      action.CreatedAt,
+		})
+	}
+	return res
+}
+
+func ConvertOffboardingToResponse(offboarding *entity.Offboarding, pendingRequests []entity.RequestTransfer) dto.OffboardingResponse {
+	res := dto.OffboardingResponse{
+		Id:                 offboarding.Id,
+		User:               convertUserInAssetLog(&offboarding.User),
+		Status:             offboarding.Status,
+		StartedBy:          convertUserInAssetLog(&offboarding.StartedBy),
+		StartedAt:          offboarding.StartedAt,
+		CompletedAt:        offboarding.CompletedAt,
+		TargetDepartmentId: offboarding.TargetDepartmentId,
+		Note:               offboarding.Note,
+		Signature:          offboarding.Signature,
+		Items:              []dto.OffboardingItemResponse{},
+		PendingRequests:    ConvertRequestTransfersToResponses(pendingRequests),
+	}
+	if offboarding.CompletedBy != nil {
+		completedBy := convertUserInAssetLog(offboarding.CompletedBy)
+		res.CompletedBy = &completedBy
+	}
+	if offboarding.TargetUser != nil {
+		targetUser := convertUserInAssetLog(offboarding.TargetUser)
+		res.TargetUser = &targetUser
+	}
+	for _, item := range offboarding.Items {
+		res.Items = append(res.Items, dto.OffboardingItemResponse{
+			Id:           item.Id,
+			Asset:        convertAssetInRequestTransfer(&item.Asset),
+			AssignmentId: item.AssignmentId,
+			Status:       item.Status,
+			Note:         item.Note,
+			CheckedById:  item.CheckedById,
+			CheckedAt:    item.CheckedAt,
+		})
+	}
+	return res
+}
+
+func ConvertHandoverToResponse(handover *entity.HandoverAcknowledgement) dto.HandoverResponse {
+	return dto.HandoverResponse{
+		Id:             handover.Id,
+		AssignmentId:   handover.AssignmentId,
+		Asset:          convertAssetInRequestTransfer(&handover.Asset),
+		User:           convertUserInAssetLog(&handover.User),
+		AssignedBy:     convertUserInAssetLog(&handover.AssignedBy),
+		Status:         handover.Status,
+		CreatedAt:      handover.CreatedAt,
+		AcceptedAt:     handover.AcceptedAt,
+		IpAddress:      handover.IpAddress,
+		ConditionGrade: handover.ConditionGrade,
+		ConditionNote:  handover.ConditionNote,
+		SignatureUrl:   handover.SignatureUrl,
+		ReceiptUrl:     handover.ReceiptUrl,
+		Signature:      handover.Signature,
+	}
+}
+
+func ConvertHandoversToResponses(handovers []*entity.HandoverAcknowledgement) []dto.HandoverResponse {
+	res := make([]dto.HandoverResponse, 0, len(handovers))
+	for _, h := range handovers {
+		res = append(res, ConvertHandoverToResponse(h))
+	}
+	return res
+}
+
+// ConvertDepartmentRestructureToResponse items là danh sách dự kiến khi còn Draft, danh sách đã chuyển khi đã áp dụng
+func ConvertDepartmentRestructureToResponse(restructure *entity.DepartmentRestructure, items []*entity.DepartmentRestructureItem, withItems bool) dto.DepartmentRestructureResponse {
+	res := dto.DepartmentRestructureResponse{
+		Id:                   restructure.Id,
+		Type:                 restructure.Type,
+		Status:               restructure.Status,
+		SourceDepartmentId:   restructure.SourceDepartmentId,
+		SourceDepartmentName: restructure.SourceDepartment.DepartmentName,
+		TargetDepartmentId:   restructure.TargetDepartmentId,
+		Name:                 restructure.Name,
+		OldName:              restructure.OldName,
+		CreatedBy:            convertUserInAssetLog(&restructure.CreatedBy),
+		CreatedAt:            restructure.CreatedAt,
+		AppliedAt:            restructure.AppliedAt,
+		UndoDeadline:         restructure.UndoDeadline,
+		UndoneAt:             restructure.UndoneAt,
+		Summary:              map[string]int{},
+	}
+	if restructure.TargetDepartment != nil {
+		res.TargetDepartmentName = restructure.TargetDepartment.DepartmentName
+	}
+	for _, item := range items {
+		res.Summary[item.EntityType]++
+		if withItems {
+			res.Items = append(res.Items, dto.DepartmentRestructureItemResponse{
+				EntityType:       item.EntityType,
+				EntityId:         item.EntityId,
+				Label:            item.Label,
+				FromDepartmentId: item.FromDepartmentId,
+				ToDepartmentId:   item.ToDepartmentId,
+				Reverted:         item.Reverted,
+			})
+		}
+	}
+	return res
+}
+
+func ConvertAuditLogsToResponses(logs []*entity.AuditLog) []dto.AuditLogResponse {
+	res := make([]dto.AuditLogResponse, 0, len(logs))
+	for _, l := range logs {
+		item := dto.AuditLogResponse{
+			Id:        l.Id,
+			Action:    l.Action,
+			Detail:    l.Detail,
+			IpAddress: l.IpAddress,
+			CreatedAt: l.CreatedAt,
+		}
+		if l.Actor != nil {
+			actor := convertUserInAssetLog(l.Actor)
+			item.Actor = &actor
+		}
+		if l.TargetUser != nil {
+			target := convertUserInAssetLog(l.TargetUser)
+			item.TargetUser = &target
+		}
+		res = append(res, item)
+	}
+	return res
+}
@@ -6,6 +6,7 @@ import (
 
 	"BE_Manage_device/pkg/interfaces"
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -151,6 +152,54 @@ func (s *SupabaseUploader) Delete(objectPath string) error {
 	return nil
 }
 
+func (s *SupabaseUploader) Copy(sourcePath string, destinationPath string) (string, error) {
+	// Supabase copy nhận đường dẫn tương đối trong bucket
+	body, err := json.Marshal(map[string]interface{}{
+		"bucketId":       s.Bucket,
+		"sourceKey":      sourcePath,
+		"destinationKey": destinationPath,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	url := fmt.Sprintf("https://%s.supabase.co/storage/v1/object/copy", s.ProjectRef)
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.ApiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call supabase: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("copy failed: %s", string(b))
+	}
+
+	publicURL := fmt.Sprintf("https://%s.supabase.co/storage/v1/object/public/%s/%s", s.ProjectRef, s.Bucket, destinationPath)
+	return publicURL, nil
+}
+
+// DeleteUrls xoá các file đã upload theo URL public, dùng để dọn file khi thao tác lỗi
+func (s *SupabaseUploader) DeleteUrls(urls ...string) {
+	for _, url := range urls {
+		path, ok := ExtractFilePath(url)
+		if !ok {
+			continue
+		}
+		if err := s.Delete(path); err != nil {
+			log.Error("Happened error when delete uploaded file. Error", err)
+		}
+	}
+}
+
 func ExtractFilePath(url string) (string, bool) {
 	sep := "/public/"
 	idx := strings.Index(url, sep)
@@ -500,3 +549,26 @@ func GetSuffixEmail(email string) string {
 	}
 	return parts[1]
 }
+
+// DecodeImageDataUrl tách ảnh dạng data URL (data:image/png;base64,...) thành nội dung và content type
+func DecodeImageDataUrl(dataUrl string) ([]byte, string, error) {
+	header, data, ok := strings.Cut(dataUrl, ",")
+	if !ok || !strings.HasPrefix(header, "data:image/") || !strings.HasSuffix(header, ";base64") {
+		return nil, "", errors.New("signature must be a base64 image data url")
+	}
+	content, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, "", errors.New("signature must be a base64 image data url")
+	}
+	return content, strings.TrimSuffix(strings.TrimPrefix(header, "data:"), ";base64"), nil
+}
+
+// UploadImageDataUrl upload ảnh dạng data URL (thường là chữ ký vẽ tay) và trả về url
+func UploadImageDataUrl(path string, dataUrl string) (string, error) {
+	content, contentType, err := DecodeImageDataUrl(dataUrl)
+	if err != nil {
+		return "", err
+	}
+	extension := strings.TrimPrefix(contentType, "image/")
+	return NewSupabaseUploader().UploadReader(path+"."+extension, bytes.NewReader(content), contentType)
+}
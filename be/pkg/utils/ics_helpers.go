@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ICSEvent một VEVENT trong feed. UID phải ổn định để lịch nhận cập nhật/huỷ thay vì tạo sự kiện mới
+type ICSEvent struct {
+	UID          string
+	Summary      string
+	Description  string
+	Start        time.Time
+	End          time.Time // Với sự kiện cả ngày: ngày kết thúc (không tính)
+	AllDay       bool
+	Cancelled    bool // Sự kiện vẫn còn trong dữ liệu nhưng không còn hiệu lực (vd tài sản đã thanh lý)
+	Sequence     int64
+	LastModified time.Time
+}
+
+// GenerateICS tạo nội dung VCALENDAR theo RFC 5545 (CRLF, gấp dòng 75 octet).
+// Feed là bản chụp đầy đủ (METHOD:PUBLISH): lịch đăng ký thay toàn bộ sự kiện mỗi lần làm mới,
+// sự kiện không còn trong feed sẽ bị xoá khỏi lịch của người dùng
+func GenerateICS(calendarName string, events []ICSEvent) []byte {
+	var b strings.Builder
+	writeLine := func(line string) {
+		b.WriteString(foldICSLine(line))
+		b.WriteString("\r\n")
+	}
+	now := time.Now().UTC().Format("20060102T150405Z")
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//BE_Manage_device//Asset Calendar//EN")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("METHOD:PUBLISH")
+	writeLine("X-WR-CALNAME:" + escapeICSText(calendarName))
+	// Gợi ý lịch làm mới thường xuyên để lịch bảo trì bị xoá sớm biến mất
+	writeLine("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeLine("X-PUBLISHED-TTL:PT1H")
+	for _, e := range events {
+		writeLine("BEGIN:VEVENT")
+		writeLine("UID:" + e.UID)
+		stamp := now
+		if !e.LastModified.IsZero() {
+			stamp = e.LastModified.UTC().Format("20060102T150405Z")
+			writeLine("LAST-MODIFIED:" + stamp)
+		}
+		writeLine("DTSTAMP:" + stamp)
+		if e.AllDay {
+			writeLine("DTSTART;VALUE=DATE:" + e.Start.Format("20060102"))
+			writeLine("DTEND;VALUE=DATE:" + e.End.Format("20060102"))
+		} else {
+			writeLine("DTSTART:" + e.Start.UTC().Format("20060102T150405Z"))
+			writeLine("DTEND:" + e.End.UTC().Format("20060102T150405Z"))
+		}
+		writeLine(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+		writeLine("SUMMARY:" + escapeICSText(e.Summary))
+		if e.Description != "" {
+			writeLine("DESCRIPTION:" + escapeICSText(e.Description))
+		}
+		if e.Cancelled {
+			writeLine("STATUS:CANCELLED")
+		} else {
+			writeLine("STATUS:CONFIRMED")
+		}
+		writeLine("TRANSP:TRANSPARENT")
+		writeLine("END:VEVENT")
+	}
+	writeLine("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+func escapeICSText(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, ";", `\;`)
+	s = strings.ReplaceAll(s, ",", `\,`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+// Gấp dòng dài hơn 75 octet, không cắt giữa ký tự UTF-8
+func foldICSLine(line string) string {
+	if len(line) <= 75 {
+		return line
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
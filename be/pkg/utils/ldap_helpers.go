@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const ldapTimeout = 15 * time.Second
+
+// LdapConnect mở kết nối (ldap://, ldaps:// hoặc StartTLS) và bind bằng tài khoản dịch vụ
+func LdapConnect(rawUrl string, startTls, insecureSkipVerify bool, bindDn, bindPassword string) (*ldap.Conn, error) {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{ServerName: parsed.Hostname(), InsecureSkipVerify: insecureSkipVerify}
+	conn, err := ldap.DialURL(rawUrl, ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(ldapTimeout)
+	if startTls && parsed.Scheme == "ldap" {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if bindDn != "" {
+		if err := conn.Bind(bindDn, bindPassword); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// LdapBindUser kiểm tra mật khẩu người dùng, không cho bind ẩn danh bằng mật khẩu rỗng
+func LdapBindUser(conn *ldap.Conn, dn, password string) (bool, error) {
+	if dn == "" || password == "" {
+		return false, nil
+	}
+	err := conn.Bind(dn, password)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LdapNormalizeDn chuẩn hoá DN về chữ thường, bỏ khoảng trắng để so sánh
+func LdapNormalizeDn(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(dn))
+	}
+	rdns := make([]string, 0, len(parsed.RDNs))
+	for _, rdn := range parsed.RDNs {
+		parts := make([]string, 0, len(rdn.Attributes))
+		for _, attr := range rdn.Attributes {
+			parts = append(parts, strings.ToLower(attr.Type)+"="+strings.ToLower(attr.Value))
+		}
+		rdns = append(rdns, strings.Join(parts, "+"))
+	}
+	return strings.Join(rdns, ",")
+}
+
+// LdapIsDescendant dn nằm trong nhánh parent (cả hai đã chuẩn hoá)
+func LdapIsDescendant(dn, parent string) bool {
+	return parent != "" && len(dn) > len(parent) && strings.HasSuffix(dn, ","+parent)
+}
+
+// LdapEntryId định danh bất biến của entry, objectGUID dạng nhị phân được đổi sang hex
+func LdapEntryId(entry *ldap.Entry, attribute string) string {
+	raw := entry.GetRawAttributeValue(attribute)
+	if len(raw) == 0 {
+		return ""
+	}
+	if !utf8.Valid(raw) {
+		return hex.EncodeToString(raw)
+	}
+	return string(raw)
+}
+
+// LdapSearch tìm có phân trang để không bị giới hạn kích thước của máy chủ
+func LdapSearch(conn *ldap.Conn, baseDn, filter string, attributes []string) ([]*ldap.Entry, error) {
+	if filter == "" {
+		return nil, errors.New("filter is required")
+	}
+	request := ldap.NewSearchRequest(baseDn, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, int(ldapTimeout.Seconds()), false, filter, attributes, nil)
+	result, err := conn.SearchWithPaging(request, 500)
+	if err != nil {
+		return nil, err
+	}
+	return result.Entries, nil
+}
@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var oidcClient = &http.Client{Timeout: 10 * time.Second}
+
+type OidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+type oidcJwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// FetchOidcDiscovery đọc .well-known/openid-configuration của IdP
+func FetchOidcDiscovery(issuer string) (*OidcDiscovery, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	var discovery OidcDiscovery
+	if err := oidcGetJson(issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("issuer mismatch: %s", discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksUri == "" {
+		return nil, errors.New("incomplete OpenID configuration")
+	}
+	return &discovery, nil
+}
+
+// GeneratePkce sinh code_verifier và code_challenge (S256)
+func GeneratePkce() (string, string, error) {
+	verifier, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func OidcAuthorizationUrl(discovery *OidcDiscovery, clientId, redirectUri, scopes, state, nonce, codeChallenge string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", clientId)
+	query.Set("redirect_uri", redirectUri)
+	query.Set("scope", scopes)
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode()
+}
+
+// ExchangeOidcCode đổi authorization code lấy id_token (client_secret_basic)
+func ExchangeOidcCode(discovery *OidcDiscovery, clientId, clientSecret, redirectUri, code, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectUri)
+	form.Set("code_verifier", codeVerifier)
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(clientId), url.QueryEscape(clientSecret))
+	res, err := oidcClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %d: %s", res.StatusCode, string(body))
+	}
+	var token struct {
+		IdToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", err
+	}
+	if token.IdToken == "" {
+		return "", errors.New("token endpoint didn't return an id_token")
+	}
+	return token.IdToken, nil
+}
+
+// VerifyOidcIdToken kiểm tra chữ ký theo JWKS, issuer, audience, hạn dùng và nonce của id_token
+func VerifyOidcIdToken(discovery *OidcDiscovery, idToken, clientId, nonce string) (jwt.MapClaims, error) {
+	var jwks struct {
+		Keys []oidcJwk `json:"keys"`
+	}
+	if err := oidcGetJson(discovery.JwksUri, &jwks); err != nil {
+		return nil, err
+	}
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		for _, key := range jwks.Keys {
+			if kid == "" || key.Kid == kid {
+				return key.publicKey()
+			}
+		}
+		return nil, fmt.Errorf("signing key %q not found", kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(clientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if value, _ := claims["nonce"].(string); value != nonce {
+		return nil, errors.New("invalid nonce")
+	}
+	return claims, nil
+}
+
+// OidcClaimValues trả về giá trị claim dạng chuỗi hoặc mảng chuỗi (ví dụ groups)
+func OidcClaimValues(claims jwt.MapClaims, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func (key oidcJwk) publicKey() (interface{}, error) {
+	switch key.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch key.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", key.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(key.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", key.Kty)
+}
+
+func oidcGetJson(endpoint string, out interface{}) error {
+	res, err := oidcClient.Get(endpoint)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", endpoint, res.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(out)
+}
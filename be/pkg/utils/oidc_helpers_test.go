@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// newJwksServer IdP giả chỉ phục vụ JWKS với một khoá RSA
+func newJwksServer(t *testing.T, kid string, key *rsa.PrivateKey) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kid": kid,
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func signIdToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestVerifyOidcIdToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newJwksServer(t, "key-1", key)
+	discovery := &OidcDiscovery{Issuer: "https://idp.example.com", JwksUri: server.URL}
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   "https://idp.example.com",
+			"aud":   "client-1",
+			"sub":   "user-1",
+			"nonce": "nonce-1",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"iat":   time.Now().Unix(),
+		}
+	}
+	tests := []struct {
+		name    string
+		method  jwt.SigningMethod
+		kid     string
+		key     interface{}
+		mutate  func(jwt.MapClaims)
+		wantErr bool
+	}{
+		{name: "valid token", wantErr: false},
+		{name: "audience array", mutate: func(c jwt.MapClaims) { c["aud"] = []string{"other", "client-1"} }, wantErr: false},
+		{name: "bad nonce", mutate: func(c jwt.MapClaims) { c["nonce"] = "nonce-2" }, wantErr: true},
+		{name: "missing nonce", mutate: func(c jwt.MapClaims) { delete(c, "nonce") }, wantErr: true},
+		{name: "wrong audience", mutate: func(c jwt.MapClaims) { c["aud"] = "client-2" }, wantErr: true},
+		{name: "wrong issuer", mutate: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, wantErr: true},
+		{name: "expired", mutate: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, wantErr: true},
+		{name: "missing expiry", mutate: func(c jwt.MapClaims) { delete(c, "exp") }, wantErr: true},
+		{name: "unknown key id", kid: "key-2", wantErr: true},
+		{name: "signed by another key", key: otherKey, wantErr: true},
+		{name: "symmetric algorithm", method: jwt.SigningMethodHS256, key: []byte("secret"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			if tt.mutate != nil {
+				tt.mutate(claims)
+			}
+			method, kid, signingKey := tt.method, tt.kid, tt.key
+			if method == nil {
+				method = jwt.SigningMethodRS256
+			}
+			if kid == "" {
+				kid = "key-1"
+			}
+			if signingKey == nil {
+				signingKey = key
+			}
+			got, err := VerifyOidcIdToken(discovery, signIdToken(t, method, kid, signingKey, claims), "client-1", "nonce-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyOidcIdToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got["sub"] != "user-1" {
+				t.Errorf("VerifyOidcIdToken() sub = %v, want user-1", got["sub"])
+			}
+		})
+	}
+}
+
+func TestGeneratePkce(t *testing.T) {
+	verifier, challenge, err := GeneratePkce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	if challenge != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		t.Errorf("challenge %q isn't the S256 of verifier %q", challenge, verifier)
+	}
+	other, _, err := GeneratePkce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == verifier {
+		t.Error("GeneratePkce() returned the same verifier twice")
+	}
+}
+
+func TestOidcAuthorizationUrl(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		wantBase string
+	}{
+		{name: "plain endpoint", endpoint: "https://idp.example.com/authorize", wantBase: "https://idp.example.com/authorize"},
+		{name: "endpoint with query", endpoint: "https://idp.example.com/authorize?tenant=acme", wantBase: "https://idp.example.com/authorize"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := OidcAuthorizationUrl(&OidcDiscovery{AuthorizationEndpoint: tt.endpoint}, "client-1", "https://app.example.com/callback", "openid email", "state-1", "nonce-1", "challenge-1")
+			parsed, err := url.Parse(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if base := parsed.Scheme + "://" + parsed.Host + parsed.Path; base != tt.wantBase {
+				t.Errorf("base = %q, want %q", base, tt.wantBase)
+			}
+			query := parsed.Query()
+			want := map[string]string{
+				"response_type":         "code",
+				"client_id":             "client-1",
+				"redirect_uri":          "https://app.example.com/callback",
+				"scope":                 "openid email",
+				"state":                 "state-1",
+				"nonce":                 "nonce-1",
+				"code_challenge":        "challenge-1",
+				"code_challenge_method": "S256",
+			}
+			for name, value := range want {
+				if query.Get(name) != value {
+					t.Errorf("%s = %q, want %q", name, query.Get(name), value)
+				}
+			}
+		})
+	}
+}
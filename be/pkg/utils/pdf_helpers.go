@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"BE_Manage_device/config"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/phpdave11/gofpdf"
+)
+
+// SignedDocument nội dung một biên bản PDF. Chữ ký được tính trên Content() nên
+// dựng lại được từ dữ liệu trong DB để kiểm tra
+type SignedDocument struct {
+	Title   string
+	Fields  [][2]string // Cặp nhãn - giá trị ở đầu biên bản
+	Columns []string
+	Widths  []float64 // Độ rộng cột (mm), tổng không quá 190
+	Rows    [][]string
+
+	SignatureImage     []byte // Ảnh chữ ký vẽ tay in cuối biên bản, không nằm trong Content()
+	SignatureImageType string // PNG hoặc JPG
+}
+
+func (doc SignedDocument) Content() string {
+	var b strings.Builder
+	b.WriteString(doc.Title + "\n")
+	for _, f := range doc.Fields {
+		b.WriteString(f[0] + ": " + f[1] + "\n")
+	}
+	b.WriteString(strings.Join(doc.Columns, "|") + "\n")
+	for _, row := range doc.Rows {
+		b.WriteString(strings.Join(row, "|") + "\n")
+	}
+	return b.String()
+}
+
+// SignDocument ký nội dung bằng HMAC-SHA256 với DocumentSigningKey
+func SignDocument(content string) string {
+	mac := hmac.New(sha256.New, []byte(config.DocumentSigningKey))
+	mac.Write([]byte(content))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifyDocumentSignature(content string, signature string) bool {
+	return hmac.Equal([]byte(SignDocument(content)), []byte(strings.ToLower(signature)))
+}
+
+// FormatDocumentTime định dạng thời gian cố định theo GMT+7 để chữ ký không phụ thuộc múi giờ server
+func FormatDocumentTime(t time.Time) string {
+	return t.In(time.FixedZone("GMT+7", 7*3600)).Format("2006-01-02 15:04:05")
+}
+
+func GenerateSignedPDF(doc SignedDocument, signature string) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(0, 10, tr(doc.Title))
+	pdf.Ln(12)
+	pdf.SetFont("Arial", "", 10)
+	for _, f := range doc.Fields {
+		pdf.SetFont("Arial", "B", 10)
+		pdf.Cell(45, 7, tr(f[0]))
+		pdf.SetFont("Arial", "", 10)
+		pdf.MultiCell(0, 7, tr(f[1]), "", "L", false)
+	}
+	if len(doc.Columns) > 0 {
+		pdf.Ln(4)
+		pdf.SetFont("Arial", "B", 10)
+		for i, col := range doc.Columns {
+			pdf.CellFormat(doc.Widths[i], 8, tr(col), "1", 0, "L", false, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Arial", "", 9)
+		for _, row := range doc.Rows {
+			for i, value := range row {
+				pdf.CellFormat(doc.Widths[i], 7, tr(value), "1", 0, "L", false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+	}
+	if len(doc.SignatureImage) > 0 {
+		pdf.Ln(6)
+		pdf.SetFont("Arial", "B", 10)
+		pdf.Cell(0, 7, "Recipient signature")
+		pdf.Ln(8)
+		options := gofpdf.ImageOptions{ImageType: doc.SignatureImageType, ReadDpi: true}
+		pdf.RegisterImageOptionsReader("signature", options, bytes.NewReader(doc.SignatureImage))
+		if err := pdf.Error(); err != nil {
+			return nil, err
+		}
+		pdf.ImageOptions("signature", pdf.GetX(), pdf.GetY(), 60, 0, true, options, 0, "")
+	}
+	pdf.Ln(8)
+	pdf.SetFont("Courier", "", 8)
+	pdf.MultiCell(0, 5, "Signature (HMAC-SHA256): "+signature, "", "L", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
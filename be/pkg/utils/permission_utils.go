@@ -14,17 +14,64 @@ func UserHasPermission(db *gorm.DB, userId int64, permSlug []string, accessLevel
 	if err != nil {
 		return false, err
 	}
+	return RoleHasPermission(user.Role.RolePermissions, permSlug, accessLevel), nil
+}
 
-	for _, rolePerm := range user.Role.RolePermissions {
+// RoleHasPermission accessLevel nil nghĩa là chỉ chấp nhận quyền "full"
+func RoleHasPermission(rolePermissions []entity.RolePermission, permSlug []string, accessLevel []string) bool {
+	for _, rolePerm := range rolePermissions {
 		if accessLevel == nil {
 			if slices.Contains(permSlug, rolePerm.Permission.Slug) && rolePerm.AccessLevel == "full" {
-				return true, nil
+				return true
 			}
 		} else {
 			if slices.Contains(permSlug, rolePerm.Permission.Slug) && slices.Contains(accessLevel, rolePerm.AccessLevel) {
-				return true, nil
+				return true
 			}
 		}
 	}
-	return false, nil
+	return false
+}
+
+// ApiKeyHasPermission quyền của API key là phần giao giữa scope của key và vai trò của service account
+func ApiKeyHasPermission(db *gorm.DB, apiKeyId int64, userId int64, permSlug []string, accessLevel []string) (bool, error) {
+	var scopes []string
+	err := db.Model(entity.ApiKeyScope{}).
+		Joins("JOIN permissions ON permissions.id = api_key_scopes.permission_id").
+		Where("api_key_scopes.api_key_id = ?", apiKeyId).
+		Pluck("permissions.slug", &scopes).Error
+	if err != nil {
+		return false, err
+	}
+	var user entity.Users
+	if err := db.Preload("Role.RolePermissions.Permission").First(&user, userId).Error; err != nil {
+		return false, err
+	}
+	return ApiKeyAllows(scopes, user.Role.RolePermissions, permSlug, accessLevel), nil
+}
+
+// ApiKeyAllows route chỉ được gọi khi key có scope ứng với quyền cần thiết và vai trò vẫn còn quyền đó
+func ApiKeyAllows(scopes []string, rolePermissions []entity.RolePermission, permSlug []string, accessLevel []string) bool {
+	granted := []string{}
+	for _, slug := range permSlug {
+		if slices.Contains(scopes, slug) {
+			granted = append(granted, slug)
+		}
+	}
+	if len(granted) == 0 {
+		return false
+	}
+	return RoleHasPermission(rolePermissions, granted, accessLevel)
+}
+
+// ViewerDepartmentScope viewer chỉ xem được tài sản thuộc phòng ban mình.
+// Trả về phòng ban dùng để lọc, false nếu viewer không được xem gì
+func ViewerDepartmentScope(user *entity.Users, departmentId *int64) (*int64, bool) {
+	if user.Role.Slug != "viewer" {
+		return departmentId, true
+	}
+	if user.DepartmentId == nil || (departmentId != nil && *departmentId != *user.DepartmentId) {
+		return nil, false
+	}
+	return user.DepartmentId, true
 }
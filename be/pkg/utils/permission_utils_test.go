@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"testing"
+)
+
+func rolePermission(slug string, accessLevel string) entity.RolePermission {
+	return entity.RolePermission{AccessLevel: accessLevel, Permission: entity.Permission{Slug: slug}}
+}
+
+func TestRoleHasPermission(t *testing.T) {
+	rolePermissions := []entity.RolePermission{
+		rolePermission("manage-assets", "limited"),
+		rolePermission("integrations", "full"),
+	}
+	tests := []struct {
+		name        string
+		permSlug    []string
+		accessLevel []string
+		want        bool
+	}{
+		{name: "full access required by default", permSlug: []string{"integrations"}, want: true},
+		{name: "limited is not full", permSlug: []string{"manage-assets"}, want: false},
+		{name: "limited accepted", permSlug: []string{"manage-assets"}, accessLevel: []string{"full", "limited"}, want: true},
+		{name: "any of several permissions", permSlug: []string{"view-reports", "integrations"}, want: true},
+		{name: "missing permission", permSlug: []string{"view-reports"}, accessLevel: []string{"full", "limited"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RoleHasPermission(rolePermissions, tt.permSlug, tt.accessLevel); got != tt.want {
+				t.Errorf("RoleHasPermission() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApiKeyAllows(t *testing.T) {
+	rolePermissions := []entity.RolePermission{
+		rolePermission("manage-assets", "full"),
+		rolePermission("view-reports", "limited"),
+	}
+	tests := []struct {
+		name        string
+		scopes      []string
+		permSlug    []string
+		accessLevel []string
+		want        bool
+	}{
+		{name: "scope and role grant the permission", scopes: []string{"manage-assets"}, permSlug: []string{"manage-assets"}, want: true},
+		{name: "role grants but key has no scope", scopes: []string{"view-reports"}, permSlug: []string{"manage-assets"}, want: false},
+		{name: "scope no longer granted by role", scopes: []string{"integrations"}, permSlug: []string{"integrations"}, want: false},
+		{name: "no scopes", scopes: nil, permSlug: []string{"manage-assets"}, want: false},
+		{name: "access level still applies", scopes: []string{"view-reports"}, permSlug: []string{"view-reports"}, want: false},
+		{name: "limited access accepted", scopes: []string{"view-reports"}, permSlug: []string{"view-reports"}, accessLevel: []string{"full", "limited"}, want: true},
+		{name: "only the scoped permission counts", scopes: []string{"view-reports"}, permSlug: []string{"manage-assets", "view-reports"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApiKeyAllows(tt.scopes, rolePermissions, tt.permSlug, tt.accessLevel); got != tt.want {
+				t.Errorf("ApiKeyAllows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestViewerDepartmentScope(t *testing.T) {
+	own, other := int64(1), int64(2)
+	viewer := &entity.Users{DepartmentId: &own, Role: entity.Roles{Slug: "viewer"}}
+	viewerWithoutDepartment := &entity.Users{Role: entity.Roles{Slug: "viewer"}}
+	admin := &entity.Users{Role: entity.Roles{Slug: "admin"}}
+	tests := []struct {
+		name         string
+		user         *entity.Users
+		departmentId *int64
+		want         *int64
+		wantOk       bool
+	}{
+		{name: "viewer is limited to own department", user: viewer, want: &own, wantOk: true},
+		{name: "viewer asks for own department", user: viewer, departmentId: &own, want: &own, wantOk: true},
+		{name: "viewer asks for other department", user: viewer, departmentId: &other, wantOk: false},
+		{name: "viewer without department", user: viewerWithoutDepartment, wantOk: false},
+		{name: "admin keeps the filter", user: admin, departmentId: &other, want: &other, wantOk: true},
+		{name: "admin without filter", user: admin, want: nil, wantOk: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ViewerDepartmentScope(tt.user, tt.departmentId)
+			if ok != tt.wantOk {
+				t.Fatalf("ViewerDepartmentScope() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("ViewerDepartmentScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RRule hỗ trợ một tập con của RFC 5545: FREQ, INTERVAL, COUNT, UNTIL
+type RRule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    *time.Time
+}
+
+var rruleFrequencies = []string{"DAILY", "WEEKLY", "MONTHLY", "YEARLY"}
+
+// ParseRRule đọc chuỗi dạng "FREQ=MONTHLY;INTERVAL=3;COUNT=4" (có thể có tiền tố "RRULE:")
+func ParseRRule(s string) (*RRule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errors.New("rrule is empty")
+	}
+	rule := &RRule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rrule part %q", part)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", value)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", value)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseRRuleDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", value)
+			}
+			rule.Until = &until
+		default:
+			return nil, fmt.Errorf("unsupported rrule part %q, only FREQ, INTERVAL, COUNT and UNTIL are supported", key)
+		}
+	}
+	valid := false
+	for _, f := range rruleFrequencies {
+		if rule.Freq == f {
+			valid = true
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("FREQ must be one of %v", rruleFrequencies)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, errors.New("COUNT and UNTIL can't be used together")
+	}
+	return rule, nil
+}
+
+func parseRRuleDate(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid date")
+}
+
+func (r *RRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences trả về các lần lặp bắt đầu từ dtstart nằm trong [from, to).
+// Ngày không tồn tại (vd: 31/02 khi lặp hàng tháng từ ngày 31) bị bỏ qua như RFC 5545.
+func (r *RRule) Occurrences(dtstart, from, to time.Time) []time.Time {
+	occurrences := []time.Time{}
+	count := 0
+	for i := 0; ; i++ {
+		var next time.Time
+		switch r.Freq {
+		case "DAILY":
+			next = dtstart.AddDate(0, 0, i*r.Interval)
+		case "WEEKLY":
+			next = dtstart.AddDate(0, 0, 7*i*r.Interval)
+		case "MONTHLY":
+			next = dtstart.AddDate(0, i*r.Interval, 0)
+		case "YEARLY":
+			next = dtstart.AddDate(i*r.Interval, 0, 0)
+		default:
+			return occurrences
+		}
+		// Kiểm tra giới hạn trước khi bỏ qua ngày không tồn tại, ngày bị dồn sang tháng sau vẫn muộn hơn lần lặp trước
+		if !next.Before(to) || (r.Until != nil && next.After(*r.Until)) {
+			return occurrences
+		}
+		if (r.Freq == "MONTHLY" || r.Freq == "YEARLY") && next.Day() != dtstart.Day() {
+			continue
+		}
+		count++
+		if r.Count > 0 && count > r.Count {
+			return occurrences
+		}
+		if !next.Before(from) {
+			occurrences = append(occurrences, next)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func rruleDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+}
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "monthly with interval", input: "FREQ=MONTHLY;INTERVAL=3", want: "FREQ=MONTHLY;INTERVAL=3"},
+		{name: "rrule prefix and lower case", input: "RRULE:freq=weekly;COUNT=4", want: "FREQ=WEEKLY;COUNT=4"},
+		{name: "until", input: "FREQ=DAILY;UNTIL=20250110", want: "FREQ=DAILY;UNTIL=20250110T000000Z"},
+		{name: "empty", input: "", wantErr: true},
+		{name: "unknown frequency", input: "FREQ=HOURLY", wantErr: true},
+		{name: "unsupported part", input: "FREQ=WEEKLY;BYDAY=MO", wantErr: true},
+		{name: "invalid interval", input: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "count with until", input: "FREQ=DAILY;COUNT=2;UNTIL=20250110", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		from    time.Time
+		to      time.Time
+		want    []time.Time
+	}{
+		{
+			name:    "daily in window",
+			rule:    "FREQ=DAILY;INTERVAL=2",
+			dtstart: rruleDate(2025, 1, 1),
+			from:    rruleDate(2025, 1, 4),
+			to:      rruleDate(2025, 1, 10),
+			want:    []time.Time{rruleDate(2025, 1, 5), rruleDate(2025, 1, 7), rruleDate(2025, 1, 9)},
+		},
+		{
+			name:    "weekly",
+			rule:    "FREQ=WEEKLY",
+			dtstart: rruleDate(2025, 1, 6),
+			from:    rruleDate(2025, 1, 1),
+			to:      rruleDate(2025, 1, 21),
+			want:    []time.Time{rruleDate(2025, 1, 6), rruleDate(2025, 1, 13), rruleDate(2025, 1, 20)},
+		},
+		{
+			name:    "monthly skips missing days",
+			rule:    "FREQ=MONTHLY",
+			dtstart: rruleDate(2025, 1, 31),
+			from:    rruleDate(2025, 1, 1),
+			to:      rruleDate(2025, 6, 1),
+			want:    []time.Time{rruleDate(2025, 1, 31), rruleDate(2025, 3, 31), rruleDate(2025, 5, 31)},
+		},
+		{
+			name:    "missing day after the window ends the loop",
+			rule:    "FREQ=MONTHLY",
+			dtstart: rruleDate(2025, 1, 31),
+			from:    rruleDate(2025, 1, 1),
+			to:      rruleDate(2025, 2, 15),
+			want:    []time.Time{rruleDate(2025, 1, 31)},
+		},
+		{
+			name:    "yearly leap day",
+			rule:    "FREQ=YEARLY",
+			dtstart: rruleDate(2024, 2, 29),
+			from:    rruleDate(2024, 1, 1),
+			to:      rruleDate(2030, 1, 1),
+			want:    []time.Time{rruleDate(2024, 2, 29), rruleDate(2028, 2, 29)},
+		},
+		{
+			name:    "count counts occurrences before the window",
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: rruleDate(2025, 1, 1),
+			from:    rruleDate(2025, 1, 2),
+			to:      rruleDate(2025, 2, 1),
+			want:    []time.Time{rruleDate(2025, 1, 2), rruleDate(2025, 1, 3)},
+		},
+		{
+			name:    "until is inclusive",
+			rule:    "FREQ=DAILY;UNTIL=20250103T090000Z",
+			dtstart: rruleDate(2025, 1, 1),
+			from:    rruleDate(2025, 1, 1),
+			to:      rruleDate(2025, 2, 1),
+			want:    []time.Time{rruleDate(2025, 1, 1), rruleDate(2025, 1, 2), rruleDate(2025, 1, 3)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := rule.Occurrences(tt.dtstart, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Occurrences()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
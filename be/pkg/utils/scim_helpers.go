@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"BE_Manage_device/internal/domain/entity"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const scimCoreSchemaPrefix = "urn:ietf:params:scim:schemas:core:2.0:"
+
+// ScimFilter một điều kiện của filter SCIM, Attribute đã chuẩn hoá chữ thường
+type ScimFilter struct {
+	Attribute string
+	Operator  string
+	Value     interface{} // string, bool, float64 hoặc nil
+}
+
+// ScimPath đường dẫn của thao tác PATCH, ví dụ members[value eq "12"] hoặc name.givenName
+type ScimPath struct {
+	Attribute string // Đã bỏ tiền tố schema core, chữ thường, gồm cả thuộc tính con
+	Filters   []ScimFilter
+}
+
+// ParseScimFilter hỗ trợ các điều kiện eq, ne, co, sw, ew, pr nối bằng and
+func ParseScimFilter(filter string) ([]ScimFilter, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return nil, nil
+	}
+	tokens, err := scimTokens(filter)
+	if err != nil {
+		return nil, err
+	}
+	var filters []ScimFilter
+	for i := 0; i < len(tokens); {
+		if i > 0 {
+			if !strings.EqualFold(tokens[i], "and") {
+				return nil, fmt.Errorf("unsupported logical operator %q", tokens[i])
+			}
+			i++
+		}
+		if i+1 >= len(tokens) {
+			return nil, errors.New("incomplete filter")
+		}
+		attribute, err := ParseScimPath(tokens[i])
+		if err != nil {
+			return nil, err
+		}
+		operator := strings.ToLower(tokens[i+1])
+		if operator == "pr" {
+			filters = append(filters, ScimFilter{Attribute: attribute.Attribute, Operator: operator})
+			i += 2
+			continue
+		}
+		switch operator {
+		case "eq", "ne", "co", "sw", "ew":
+		default:
+			return nil, fmt.Errorf("unsupported operator %q", tokens[i+1])
+		}
+		if i+2 >= len(tokens) {
+			return nil, errors.New("incomplete filter")
+		}
+		value, err := scimValue(tokens[i+2])
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, ScimFilter{Attribute: attribute.Attribute, Operator: operator, Value: value})
+		i += 3
+	}
+	return filters, nil
+}
+
+// ParseScimPath tách phần lọc trong [] ra khỏi đường dẫn thuộc tính
+func ParseScimPath(path string) (ScimPath, error) {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(strings.ToLower(path), strings.ToLower(scimCoreSchemaPrefix)) {
+		// urn:...:core:2.0:User:userName -> userName
+		rest := path[len(scimCoreSchemaPrefix):]
+		if idx := strings.Index(rest, ":"); idx >= 0 {
+			path = rest[idx+1:]
+		}
+	}
+	var result ScimPath
+	open := strings.Index(path, "[")
+	if open < 0 {
+		result.Attribute = strings.ToLower(path)
+		return result, nil
+	}
+	closing := strings.LastIndex(path, "]")
+	if closing < open {
+		return result, fmt.Errorf("invalid path %q", path)
+	}
+	filters, err := ParseScimFilter(path[open+1 : closing])
+	if err != nil {
+		return result, err
+	}
+	result.Attribute = strings.ToLower(path[:open] + path[closing+1:])
+	result.Filters = filters
+	return result, nil
+}
+
+// FindScimToken tìm token còn hiệu lực và ghi nhận lần dùng gần nhất
+func FindScimToken(db *gorm.DB, token string) (*entity.ScimToken, error) {
+	var scimToken entity.ScimToken
+	err := db.Model(entity.ScimToken{}).Where("token_hash = ? AND revoked_at IS NULL", HashToken(token)).First(&scimToken).Error
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	db.Model(entity.ScimToken{}).Where("id = ?", scimToken.Id).Update("last_used_at", now)
+	scimToken.LastUsedAt = &now
+	return &scimToken, nil
+}
+
+// scimTokens tách filter theo khoảng trắng, giữ nguyên chuỗi trong "" và phần trong []
+func scimTokens(filter string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	inQuote, escaped, depth := false, false, 0
+	for _, r := range filter {
+		switch {
+		case escaped:
+			escaped = false
+		case inQuote && r == '\\':
+			escaped = true
+		case r == '"':
+			inQuote = !inQuote
+		case !inQuote && r == '[':
+			depth++
+		case !inQuote && r == ']':
+			depth--
+		case !inQuote && depth == 0 && (r == ' ' || r == '\t'):
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+			continue
+		case !inQuote && depth == 0 && (r == '(' || r == ')'):
+			return nil, errors.New("grouping is not supported")
+		}
+		current.WriteRune(r)
+	}
+	if inQuote || depth != 0 {
+		return nil, errors.New("unterminated filter")
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+func scimValue(token string) (interface{}, error) {
+	if strings.HasPrefix(token, `"`) {
+		if len(token) < 2 || !strings.HasSuffix(token, `"`) {
+			return nil, fmt.Errorf("invalid value %s", token)
+		}
+		return strings.ReplaceAll(strings.ReplaceAll(token[1:len(token)-1], `\"`, `"`), `\\`, `\`), nil
+	}
+	switch strings.ToLower(token) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	var number float64
+	if _, err := fmt.Sscan(token, &number); err != nil {
+		return nil, fmt.Errorf("invalid value %s", token)
+	}
+	return number, nil
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseScimFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		want    []ScimFilter
+		wantErr bool
+	}{
+		{name: "empty", filter: "  ", want: nil},
+		{name: "eq string", filter: `userName eq "alice@acme.com"`, want: []ScimFilter{{Attribute: "username", Operator: "eq", Value: "alice@acme.com"}}},
+		{name: "operator case insensitive", filter: `userName EQ "Alice"`, want: []ScimFilter{{Attribute: "username", Operator: "eq", Value: "Alice"}}},
+		{name: "quoted value with spaces and escapes", filter: `displayName eq "IT \"Ops\" team"`, want: []ScimFilter{{Attribute: "displayname", Operator: "eq", Value: `IT "Ops" team`}}},
+		{name: "boolean and number", filter: `active eq true and meta.version ne 2`, want: []ScimFilter{
+			{Attribute: "active", Operator: "eq", Value: true},
+			{Attribute: "meta.version", Operator: "ne", Value: float64(2)},
+		}},
+		{name: "null value", filter: `externalId eq null`, want: []ScimFilter{{Attribute: "externalid", Operator: "eq", Value: nil}}},
+		{name: "present", filter: `externalId pr and userName sw "a"`, want: []ScimFilter{
+			{Attribute: "externalid", Operator: "pr"},
+			{Attribute: "username", Operator: "sw", Value: "a"},
+		}},
+		{name: "schema prefixed attribute", filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName co "acme"`, want: []ScimFilter{{Attribute: "username", Operator: "co", Value: "acme"}}},
+		{name: "or not supported", filter: `userName eq "a" or userName eq "b"`, wantErr: true},
+		{name: "grouping not supported", filter: `(userName eq "a")`, wantErr: true},
+		{name: "unknown operator", filter: `userName gt "a"`, wantErr: true},
+		{name: "missing value", filter: `userName eq`, wantErr: true},
+		{name: "unterminated quote", filter: `userName eq "alice`, wantErr: true},
+		{name: "invalid value", filter: `userName eq alice`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScimFilter(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseScimFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseScimFilter() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScimPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    ScimPath
+		wantErr bool
+	}{
+		{name: "simple", path: "active", want: ScimPath{Attribute: "active"}},
+		{name: "sub attribute", path: "name.givenName", want: ScimPath{Attribute: "name.givenname"}},
+		{name: "core schema prefix", path: "urn:ietf:params:scim:schemas:core:2.0:User:userName", want: ScimPath{Attribute: "username"}},
+		{name: "extension keeps its urn", path: "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department", want: ScimPath{Attribute: "urn:ietf:params:scim:schemas:extension:enterprise:2.0:user:department"}},
+		{name: "value filter", path: `members[value eq "12"]`, want: ScimPath{Attribute: "members", Filters: []ScimFilter{{Attribute: "value", Operator: "eq", Value: "12"}}}},
+		{name: "value filter with sub attribute", path: `emails[type eq "work"].value`, want: ScimPath{Attribute: "emails.value", Filters: []ScimFilter{{Attribute: "type", Operator: "eq", Value: "work"}}}},
+		{name: "unclosed bracket", path: `members[value eq "12"`, wantErr: true},
+		{name: "invalid filter", path: `members[value gt "12"]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScimPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseScimPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseScimPath() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
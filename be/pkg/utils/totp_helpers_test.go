@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// Secret "12345678901234567890" của bộ test vector RFC 6238 (SHA1), mã 6 số là 6 chữ số cuối
+const rfcTotpSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTotp(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		at       int64
+		lastStep int64
+		wantStep int64
+		wantOk   bool
+	}{
+		{name: "rfc vector 59", secret: rfcTotpSecret, code: "287082", at: 59, wantStep: 1, wantOk: true},
+		{name: "rfc vector 1111111109", secret: rfcTotpSecret, code: "081804", at: 1111111109, wantStep: 37037036, wantOk: true},
+		{name: "rfc vector 1234567890", secret: rfcTotpSecret, code: "005924", at: 1234567890, wantStep: 41152263, wantOk: true},
+		{name: "rfc vector 2000000000", secret: rfcTotpSecret, code: "279037", at: 2000000000, wantStep: 66666666, wantOk: true},
+		{name: "lowercase secret", secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", code: "287082", at: 59, wantStep: 1, wantOk: true},
+		{name: "previous period within skew", secret: rfcTotpSecret, code: "287082", at: 89, wantStep: 1, wantOk: true},
+		{name: "next period within skew", secret: rfcTotpSecret, code: "081804", at: 1111111109 - 30, wantStep: 37037036, wantOk: true},
+		{name: "outside skew", secret: rfcTotpSecret, code: "287082", at: 120},
+		{name: "replayed step", secret: rfcTotpSecret, code: "287082", at: 59, lastStep: 1},
+		{name: "older step used", secret: rfcTotpSecret, code: "287082", at: 59, lastStep: 0, wantStep: 1, wantOk: true},
+		{name: "wrong code", secret: rfcTotpSecret, code: "287083", at: 59},
+		{name: "short code", secret: rfcTotpSecret, code: "87082", at: 59},
+		{name: "long code", secret: rfcTotpSecret, code: "94287082", at: 59},
+		{name: "invalid secret", secret: "not-base32!", code: "287082", at: 59},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTotp(tt.secret, tt.code, time.Unix(tt.at, 0), tt.lastStep)
+			if ok != tt.wantOk || step != tt.wantStep {
+				t.Errorf("ValidateTotp() = (%v, %v), want (%v, %v)", step, ok, tt.wantStep, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestTotpRoundTrip(t *testing.T) {
+	secret, err := GenerateTotpSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	code, err := totpCode(secret, now.Unix()/totpPeriod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	step, ok := ValidateTotp(secret, code, now, 0)
+	if !ok {
+		t.Fatal("generated code was rejected")
+	}
+	if _, ok := ValidateTotp(secret, code, now, step); ok {
+		t.Error("code was accepted twice")
+	}
+}
+
+func TestEncryptSecret(t *testing.T) {
+	encrypted, err := EncryptSecret(rfcTotpSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encrypted == rfcTotpSecret {
+		t.Fatal("secret stored in plain text")
+	}
+	plain, err := DecryptSecret(encrypted)
+	if err != nil || plain != rfcTotpSecret {
+		t.Errorf("DecryptSecret() = %q, %v", plain, err)
+	}
+	if _, err := DecryptSecret(encrypted[:len(encrypted)-4] + "AAAA"); err == nil {
+		t.Error("tampered secret was decrypted")
+	}
+}